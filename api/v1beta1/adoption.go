@@ -0,0 +1,27 @@
+package v1beta1
+
+// AdoptionPolicy decides how objects are treated that exist in Grafana before a cr imports them, like
+// dashboards without the grafana-operator tag or datasources with the same name
+// +kubebuilder:validation:Enum=overwrite;adopt;fail
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyOverwrite replaces existing objects
+	AdoptionPolicyOverwrite AdoptionPolicy = "overwrite"
+	// AdoptionPolicyAdopt replaces existing objects and records their original version in the status
+	AdoptionPolicyAdopt AdoptionPolicy = "adopt"
+	// AdoptionPolicyFail keeps existing objects and reports them as conflict
+	AdoptionPolicyFail AdoptionPolicy = "fail"
+)
+
+// AdoptedResource is an object that existed in Grafana before it was adopted by a cr
+type AdoptedResource struct {
+	// namespace and name of the Grafana instance
+	Instance string `json:"instance"`
+
+	// uid of the object before it was adopted
+	UID string `json:"uid"`
+
+	// version of the object before it was adopted, the version history of dashboards allows to restore it
+	Version int64 `json:"version"`
+}
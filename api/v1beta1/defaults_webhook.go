@@ -0,0 +1,153 @@
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"strings"
+	"time"
+)
+
+// annotations of namespaces that set the defaults of the dashboards, datasources and folders created in them
+const (
+	// label selector of the instances, e.g. dashboards=grafana
+	DefaultInstanceSelectorAnnotation = "grafana.integreatly.org/default-instance-selector"
+	// name of the GrafanaFolder dashboards are imported into, the folder is titled after the namespace
+	DefaultFolderAnnotation = "grafana.integreatly.org/default-folder"
+	// resync period, e.g. 10m
+	DefaultResyncPeriodAnnotation = "grafana.integreatly.org/default-resync-period"
+)
+
+//+kubebuilder:webhook:path=/mutate-grafana-integreatly-org-v1beta1-grafanadashboard,mutating=true,failurePolicy=fail,sideEffects=None,groups=grafana.integreatly.org,resources=grafanadashboards,verbs=create;update,versions=v1beta1,name=mgrafanadashboard.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-grafana-integreatly-org-v1beta1-grafanadatasource,mutating=true,failurePolicy=fail,sideEffects=None,groups=grafana.integreatly.org,resources=grafanadatasources,verbs=create;update,versions=v1beta1,name=mgrafanadatasource.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-grafana-integreatly-org-v1beta1-grafanafolder,mutating=true,failurePolicy=fail,sideEffects=None,groups=grafana.integreatly.org,resources=grafanafolders,verbs=create;update,versions=v1beta1,name=mgrafanafolder.kb.io,admissionReviewVersions=v1
+
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+func (in *GrafanaDatasource) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		WithDefaulter(&namespaceDefaulter{client: mgr.GetClient()}).
+		Complete()
+}
+
+func (in *GrafanaFolder) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		WithDefaulter(&namespaceDefaulter{client: mgr.GetClient()}).
+		Complete()
+}
+
+// namespaceDefaulter defaults dashboards, datasources and folders from the annotations of their namespace
+type namespaceDefaulter struct {
+	client client.Reader
+}
+
+var _ admission.CustomDefaulter = &namespaceDefaulter{}
+
+// Default sets the fields left empty to the defaults of the namespace, dashboards with inline json without
+// uid get the uid the operator generates for them
+func (d *namespaceDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	object, ok := obj.(client.Object)
+	if !ok || object.GetNamespace() == "" || object.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	namespace := &v1.Namespace{}
+	err := d.client.Get(ctx, types.NamespacedName{Name: object.GetNamespace()}, namespace)
+	if err != nil {
+		return fmt.Errorf("error getting the defaults of namespace %s: %w", object.GetNamespace(), err)
+	}
+	defaults, err := getNamespaceDefaults(namespace)
+	if err != nil {
+		return err
+	}
+
+	switch cr := obj.(type) {
+	case *GrafanaDashboard:
+		defaults.apply(&cr.Spec.InstanceSelector, &cr.Spec.ResyncPolicy)
+		if cr.Spec.FolderRef == "" {
+			cr.Spec.FolderRef = defaults.folder
+		}
+		if cr.Name != "" {
+			cr.Spec.Json = setDashboardUID(cr.Spec.Json, cr.GeneratedUID())
+		}
+	case *GrafanaDatasource:
+		defaults.apply(&cr.Spec.InstanceSelector, &cr.Spec.ResyncPolicy)
+	case *GrafanaFolder:
+		defaults.apply(&cr.Spec.InstanceSelector, &cr.Spec.ResyncPolicy)
+		if cr.Spec.Title == "" && cr.Name == defaults.folder {
+			cr.Spec.Title = namespace.Name
+		}
+	}
+	return nil
+}
+
+type namespaceDefaults struct {
+	instanceSelector *metav1.LabelSelector
+	folder           string
+	resyncPeriod     *metav1.Duration
+}
+
+// getNamespaceDefaults parses the annotations of a namespace, invalid annotations reject the resources of
+// the namespace until they are fixed
+func getNamespaceDefaults(namespace *v1.Namespace) (namespaceDefaults, error) {
+	defaults := namespaceDefaults{
+		folder: namespace.Annotations[DefaultFolderAnnotation],
+	}
+	if selector, ok := namespace.Annotations[DefaultInstanceSelectorAnnotation]; ok {
+		labelSelector, err := metav1.ParseToLabelSelector(selector)
+		if err != nil {
+			return defaults, fmt.Errorf("invalid annotation %s of namespace %s: %w", DefaultInstanceSelectorAnnotation, namespace.Name, err)
+		}
+		defaults.instanceSelector = labelSelector
+	}
+	if period, ok := namespace.Annotations[DefaultResyncPeriodAnnotation]; ok {
+		duration, err := time.ParseDuration(period)
+		if err != nil || duration <= 0 {
+			return defaults, fmt.Errorf("invalid annotation %s of namespace %s: %q is not a positive duration", DefaultResyncPeriodAnnotation, namespace.Name, period)
+		}
+		defaults.resyncPeriod = &metav1.Duration{Duration: duration}
+	}
+	return defaults, nil
+}
+
+func (d namespaceDefaults) apply(instanceSelector **metav1.LabelSelector, resyncPolicy *ResyncPolicy) {
+	if *instanceSelector == nil && d.instanceSelector != nil {
+		*instanceSelector = d.instanceSelector.DeepCopy()
+	}
+	if resyncPolicy.ResyncPeriod == nil && d.resyncPeriod != nil {
+		resyncPolicy.ResyncPeriod = d.resyncPeriod.DeepCopy()
+	}
+}
+
+// setDashboardUID adds the uid to dashboard json without uid, the json is otherwise kept as written. Invalid
+// json is left to the validating webhook.
+func setDashboardUID(content string, uid string) string {
+	var dashboard map[string]interface{}
+	if json.Unmarshal([]byte(content), &dashboard) != nil || dashboard == nil {
+		return content
+	}
+	if _, ok := dashboard["uid"]; ok {
+		return content
+	}
+
+	start := strings.IndexByte(content, '{') + 1
+	rest := content[start:]
+	// the whitespace after the brace keeps the indentation of the json
+	space := rest[:len(rest)-len(strings.TrimLeft(rest, " \t\r\n"))]
+	property := fmt.Sprintf("%q: %q", "uid", uid)
+	if len(dashboard) > 0 {
+		property += ","
+	} else {
+		space = ""
+	}
+	return content[:start] + space + property + rest
+}
@@ -0,0 +1,37 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSetDashboardUID(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "no uid", content: `{"title":"a"}`, expected: `{"uid": "abc","title":"a"}`},
+		{name: "existing uid", content: `{"uid":"other","title":"a"}`, expected: `{"uid":"other","title":"a"}`},
+		{name: "null uid", content: `{"uid":null}`, expected: `{"uid":null}`},
+		{name: "empty object", content: `{}`, expected: `{"uid": "abc"}`},
+		{name: "empty object with whitespace", content: "{\n}", expected: "{\"uid\": \"abc\"\n}"},
+		{name: "null", content: `null`, expected: `null`},
+		{name: "array", content: `[{}]`, expected: `[{}]`},
+		{name: "invalid json", content: `{"title":`, expected: `{"title":`},
+		{name: "empty", content: ``, expected: ``},
+		{name: "indentation kept", content: "{\n  \"title\": \"a\"\n}", expected: "{\n  \"uid\": \"abc\",\n  \"title\": \"a\"\n}"},
+		{name: "tabs kept", content: "\n{\n\t\"title\": \"a\"\n}\n", expected: "\n{\n\t\"uid\": \"abc\",\n\t\"title\": \"a\"\n}\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := setDashboardUID(test.content, "abc")
+			if actual != test.expected {
+				t.Errorf("setDashboardUID(%q) = %q, expected %q", test.content, actual, test.expected)
+			}
+			if actual != test.content && !json.Valid([]byte(actual)) {
+				t.Errorf("setDashboardUID(%q) returned invalid json %q", test.content, actual)
+			}
+		})
+	}
+}
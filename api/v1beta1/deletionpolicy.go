@@ -0,0 +1,15 @@
+package v1beta1
+
+// DeletionPolicy decides what happens to the objects in Grafana when the cr importing them is deleted
+// +kubebuilder:validation:Enum=Delete;Orphan;Retain
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes the objects from Grafana
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan keeps the objects and removes the markers of the operator, orphaned dashboards lose
+	// the grafana-operator tag and are treated like dashboards created in the ui
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	// DeletionPolicyRetain keeps the objects unchanged, e.g. to import them with another cr during a migration
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
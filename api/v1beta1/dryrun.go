@@ -0,0 +1,23 @@
+package v1beta1
+
+// DryRunResult is the change a cr in dry run would make in a Grafana instance
+type DryRunResult struct {
+	// namespace and name of the Grafana instance
+	Instance string `json:"instance"`
+
+	// Created or Updated, empty if the object in Grafana matches the cr
+	// +optional
+	Action string `json:"action,omitempty"`
+
+	// changed fields as path: current -> desired, limited to the first changes
+	// +optional
+	Diff []string `json:"diff,omitempty"`
+
+	// number of changed fields, including those not listed in the diff
+	// +optional
+	Changes int `json:"changes,omitempty"`
+
+	// error of comparing the cr to the object in Grafana
+	// +optional
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,39 @@
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// GitRepositorySource is a file, or for dashboard folders the files matching a glob, of a git repository.
+// Repositories are fetched over http or https without history.
+type GitRepositorySource struct {
+	// url of the repository, e.g. https://github.com/org/dashboards.git
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// branch, tag or commit sha, defaults to the default branch of the repository
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// file in the repository. For dashboard folders a glob like dashboards/*.json matched against the json
+	// files of the repository, defaults to all of them.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// secret in the namespace of the cr with the username and password of the repository, tokens can be
+	// set as password without username
+	// +optional
+	SecretRef *v1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// ContentUrl returns the url of the file, e.g. git+https://github.com/org/dashboards.git#main:a.json
+func (in *GitRepositorySource) ContentUrl() string {
+	url := "git+" + in.URL
+	if in.Ref != "" {
+		url += "#" + in.Ref
+	}
+	if in.Path != "" {
+		url += ":" + in.Path
+	}
+	return url
+}
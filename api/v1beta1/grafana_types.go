@@ -17,10 +17,13 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type OperatorStageName string
@@ -30,12 +33,26 @@ type OperatorStageStatus string
 const (
 	OperatorStageGrafanaConfig  OperatorStageName = "config"
 	OperatorStageAdminUser      OperatorStageName = "admin user"
+	OperatorStageLdap           OperatorStageName = "ldap"
+	OperatorStageDatabase       OperatorStageName = "database"
+	OperatorStageHA             OperatorStageName = "high availability"
+	OperatorStageTLS            OperatorStageName = "tls"
+	OperatorStageServiceMonitor OperatorStageName = "service monitor"
 	OperatorStagePvc            OperatorStageName = "pvc"
 	OperatorStageServiceAccount OperatorStageName = "service account"
 	OperatorStageService        OperatorStageName = "service"
 	OperatorStageIngress        OperatorStageName = "ingress"
 	OperatorStagePlugins        OperatorStageName = "plugins"
+	OperatorStageImageRenderer  OperatorStageName = "image renderer"
+	OperatorStageExternal       OperatorStageName = "external"
+	OperatorStageCloudStack     OperatorStageName = "cloud stack"
 	OperatorStageDeployment     OperatorStageName = "deployment"
+	OperatorStagePDB            OperatorStageName = "pod disruption budget"
+	OperatorStageAutoscaling    OperatorStageName = "autoscaling"
+	OperatorStageSMTP           OperatorStageName = "smtp"
+	OperatorStageUpgrade        OperatorStageName = "upgrade"
+	OperatorStageExtraVolumes   OperatorStageName = "extra volumes"
+	OperatorStageDiscovery      OperatorStageName = "datasource discovery"
 )
 
 const (
@@ -51,6 +68,38 @@ type OperatorReconcileVars struct {
 
 	// env var value for installed plugins
 	Plugins string
+
+	// sorted ids of the plugins requested with signature level unsigned
+	UnsignedPlugins []string
+
+	// hash of the ldap.toml rendered for the instance, empty when ldap is not configured
+	LdapHash string
+
+	// value of auth.ldap allow_sign_up requested by the ldap config
+	LdapAllowSignUp *bool
+
+	// uids and resource versions of the database password and tls secrets, used to restart the Grafana
+	// container when they change
+	DatabaseSecretVersion string
+
+	// true when the database tls secret contains a client certificate
+	DatabaseClientCertificate bool
+
+	// true when more than one replica runs and unified alerting peers with the other pods
+	HighAvailability bool
+
+	// hash of the tls secret Grafana serves https with, used to restart the Grafana container on renewal
+	TLSHash string
+
+	// uid and resource version of the smtp password secret, used to restart the Grafana container when it
+	// changes
+	SMTPSecretVersion string
+
+	// hash of the ConfigMaps and Secrets mounted as extra volumes, set as pod annotation
+	ExtraVolumesHash string
+
+	// true when the deployment stage changed the installed plugins of existing pods, which restarts them
+	PluginsChanged bool
 }
 
 // GrafanaSpec defines the desired state of Grafana
@@ -65,6 +114,476 @@ type GrafanaSpec struct {
 	ServiceAccount        *ServiceAccountV1        `json:"serviceAccount,omitempty"`
 	Client                *GrafanaClient           `json:"client,omitempty"`
 	Jsonnet               *JsonnetConfig           `json:"jsonnet,omitempty"`
+	ImageRenderer         *GrafanaImageRenderer    `json:"imageRenderer,omitempty"`
+	External              *GrafanaExternal         `json:"external,omitempty"`
+	Cloud                 *GrafanaCloudStack       `json:"cloud,omitempty"`
+	Persistence           *GrafanaPersistence      `json:"persistence,omitempty"`
+	Database              *GrafanaDatabase         `json:"database,omitempty"`
+	// number of Grafana pods, more than one replica requires an external database and configures unified
+	// alerting to deduplicate notifications between the pods
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// +optional
+	TLS *GrafanaTLS `json:"tls,omitempty"`
+	// +optional
+	Metrics *GrafanaMetrics `json:"metrics,omitempty"`
+	// +optional
+	PodDisruptionBudget *GrafanaPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+	// scales the Grafana pods with a HorizontalPodAutoscaler, replicas are left to the autoscaler
+	// +optional
+	Autoscaling *GrafanaAutoscaling `json:"autoscaling,omitempty"`
+	// values of grafana.ini read from Secrets or ConfigMaps, the target path is the section followed by the
+	// key, e.g. auth.generic_oauth.client_secret. Referenced values replace the values of the config.
+	// +optional
+	ConfigValuesFrom []ValueFrom `json:"configValuesFrom,omitempty"`
+	// +optional
+	SMTP *GrafanaSMTP `json:"smtp,omitempty"`
+	// +optional
+	AdminCredentials *GrafanaAdminCredentials `json:"adminCredentials,omitempty"`
+	// feature toggles rendered into the feature_toggles section of grafana.ini, toggles unknown to the
+	// version of Grafana are reported in the FeatureToggles condition
+	// +optional
+	FeatureToggles map[string]bool `json:"featureToggles,omitempty"`
+	// version of Grafana, the tag of the docker.io/grafana/grafana image. An image set for the grafana
+	// container in the deployment overrides takes precedence.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Upgrade *GrafanaUpgrade `json:"upgrade,omitempty"`
+	// places the Grafana pods, deployment overrides take precedence
+	// +optional
+	Scheduling *GrafanaScheduling `json:"scheduling,omitempty"`
+	// volumes added to the Grafana pod, changes of referenced ConfigMaps and Secrets restart the pods
+	// +optional
+	ExtraVolumes []v1.Volume `json:"extraVolumes,omitempty"`
+	// mounts added to the grafana container
+	// +optional
+	ExtraVolumeMounts []v1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+	// +optional
+	Probes *GrafanaProbes `json:"probes,omitempty"`
+	// +optional
+	Plugins *GrafanaPlugins `json:"plugins,omitempty"`
+	// allows dashboards, datasources, folders and library panels of other namespaces to select the instance,
+	// when enabled for the operator and allowed by the resource
+	// +optional
+	AllowCrossNamespaceImport bool `json:"allowCrossNamespaceImport,omitempty"`
+	// creates GrafanaDatasources for the Prometheus, Thanos, Loki and Tempo services of the cluster
+	// +optional
+	DatasourceDiscovery *GrafanaDatasourceDiscovery `json:"datasourceDiscovery,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=env;api
+type PluginInstallMode string
+
+const (
+	// PluginInstallModeEnv installs plugins on startup through GF_INSTALL_PLUGINS, changes restart Grafana
+	PluginInstallModeEnv PluginInstallMode = "env"
+	// PluginInstallModeAPI installs plugins into the running instance through the plugin catalog api
+	PluginInstallModeAPI PluginInstallMode = "api"
+)
+
+// PluginVersionConflictPolicy decides which exact version of a plugin is installed when resources request
+// different versions
+// +kubebuilder:validation:Enum=highest;lowest;fail
+type PluginVersionConflictPolicy string
+
+const (
+	PluginVersionConflictHighest PluginVersionConflictPolicy = "highest"
+	PluginVersionConflictLowest  PluginVersionConflictPolicy = "lowest"
+	// PluginVersionConflictFail keeps the installed plugins and reports the conflicting resources
+	PluginVersionConflictFail PluginVersionConflictPolicy = "fail"
+)
+
+// DiscoveredDatasourceType is a kind of service found by the datasource discovery
+// +kubebuilder:validation:Enum=prometheus;thanos;loki;tempo
+type DiscoveredDatasourceType string
+
+const (
+	DiscoveredDatasourcePrometheus DiscoveredDatasourceType = "prometheus"
+	DiscoveredDatasourceThanos     DiscoveredDatasourceType = "thanos"
+	DiscoveredDatasourceLoki       DiscoveredDatasourceType = "loki"
+	DiscoveredDatasourceTempo      DiscoveredDatasourceType = "tempo"
+)
+
+// GrafanaDatasourceDiscovery finds services by their app.kubernetes.io/name label, the services of
+// prometheus-operator or the grafana.integreatly.org/datasource-type label. A GrafanaDatasource owned by the
+// instance and selecting the labels of the instance is created for every service found.
+type GrafanaDatasourceDiscovery struct {
+	// namespaces searched for services, defaults to the namespace of the instance
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// restricts the services found
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// kinds of services found, defaults to all
+	// +optional
+	Types []DiscoveredDatasourceType `json:"types,omitempty"`
+}
+
+// GrafanaPlugins configures how plugins are installed into the Grafana pods
+type GrafanaPlugins struct {
+	// plugins of external instances are always installed through the api, managed instances fall back to
+	// the env var when the plugin catalog is disabled
+	// +kubebuilder:default=env
+	// +optional
+	Mode PluginInstallMode `json:"mode,omitempty"`
+	// cron schedule on which plugins requested as latest or by range are resolved again, e.g. "0 3 * * 1".
+	// Without a schedule resolved versions are kept until they no longer satisfy the requested versions.
+	// +optional
+	AutoUpgradeSchedule string `json:"autoUpgradeSchedule,omitempty"`
+	// exact version installed when resources request different versions of a plugin, the version has to
+	// satisfy the ranges requested for the plugin
+	// +kubebuilder:default=highest
+	// +optional
+	ConflictPolicy PluginVersionConflictPolicy `json:"conflictPolicy,omitempty"`
+	// removes the plugins of dashboards and datasources that were deleted, no longer select the instance or
+	// no longer request plugins
+	// +optional
+	RemoveUnused bool `json:"removeUnused,omitempty"`
+	// plugin repository replacing https://grafana.com/api/plugins, e.g. an internal mirror. It is used to
+	// resolve versions and by grafana-cli through GF_PLUGIN_REPO.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	Repository string `json:"repository,omitempty"`
+	// downloads plugins from grafana.com directly instead of the plugin cache of the operator, instances
+	// with a repository never use the cache
+	// +optional
+	DisableCache bool `json:"disableCache,omitempty"`
+	// installs the plugins from zips of a bundle instead of downloading plugins from grafana.com, plugins
+	// requested by dashboards have to be part of the bundle
+	// +optional
+	OfflineBundle *GrafanaPluginBundle `json:"offlineBundle,omitempty"`
+}
+
+// GrafanaPluginBundle is an image or volume claim containing plugin zips, exactly one source must be set
+type GrafanaPluginBundle struct {
+	// image containing the zips, it is run as init container copying the zips and requires a shell
+	// +optional
+	Image string `json:"image,omitempty"`
+	// volume claim containing the zips
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+	// directory of the zips in the image or claim, defaults to /plugins in images and the root of claims
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// GrafanaProbes replaces the default probes of the grafana container, which check /api/health. Probes
+// without a handler keep checking the health api with the given timings.
+type GrafanaProbes struct {
+	// +optional
+	Readiness *v1.Probe `json:"readiness,omitempty"`
+	// +optional
+	Liveness *v1.Probe `json:"liveness,omitempty"`
+	// delays the liveness probe until Grafana started, raise the failure threshold for instances installing
+	// many plugins
+	// +optional
+	Startup *v1.Probe `json:"startup,omitempty"`
+}
+
+// GrafanaScheduling sets the scheduling fields of the Grafana pods
+type GrafanaScheduling struct {
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+	// constraints without a label selector select the pods of the instance
+	// +optional
+	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// GrafanaUpgrade configures how changes of the Grafana version are rolled out. Before an upgrade the
+// instance is backed up, the workload is only rolled once the backup is stored.
+type GrafanaUpgrade struct {
+	// allows rolling out versions older than the running version, or versions that can't be compared
+	// +optional
+	AllowDowngrade bool `json:"allowDowngrade,omitempty"`
+	// upgrades without taking a backup first
+	// +optional
+	SkipBackup bool `json:"skipBackup,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=RollingOut;Completed
+type GrafanaUpgradePhase string
+
+const (
+	// UpgradePhaseRollingOut is set while pods of the new version are started and the database is migrated
+	UpgradePhaseRollingOut GrafanaUpgradePhase = "RollingOut"
+	// UpgradePhaseCompleted is set once the new version reports a healthy database
+	UpgradePhaseCompleted GrafanaUpgradePhase = "Completed"
+)
+
+// GrafanaAdminCredentials configures the admin user the operator manages the instance with. Without an
+// existing secret the password is generated, changed passwords are applied to the running instance.
+type GrafanaAdminCredentials struct {
+	// secret holding the admin user and password, used instead of generated credentials
+	// +optional
+	ExistingSecret *GrafanaAdminSecretRef `json:"existingSecret,omitempty"`
+	// changing the value generates a new admin password, the grafana.integreatly.org/rotate-admin-password
+	// annotation has the same effect. Ignored with an existing secret.
+	// +optional
+	Rotate string `json:"rotate,omitempty"`
+}
+
+// GrafanaAdminSecretRef references the keys of a secret holding admin credentials
+type GrafanaAdminSecretRef struct {
+	Name string `json:"name"`
+	// +kubebuilder:default=admin-user
+	// +optional
+	UserKey string `json:"userKey,omitempty"`
+	// +kubebuilder:default=admin-password
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// GrafanaSMTP configures email delivery, the operator checks that the server is reachable and reports the
+// result in the SMTPReady condition
+type GrafanaSMTP struct {
+	// host and port of the smtp server, e.g. smtp.example.com:587
+	Host string `json:"host"`
+	// +optional
+	User string `json:"user,omitempty"`
+	// +optional
+	PasswordSecretRef *v1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	FromAddress       string                `json:"fromAddress"`
+	// +optional
+	FromName string `json:"fromName,omitempty"`
+	// +kubebuilder:validation:Enum=OpportunisticStartTLS;MandatoryStartTLS;NoStartTLS
+	// +kubebuilder:default=OpportunisticStartTLS
+	// +optional
+	StartTLS string `json:"startTLS,omitempty"`
+	// +optional
+	SkipVerify bool `json:"skipVerify,omitempty"`
+}
+
+// GrafanaAutoscaling configures the HorizontalPodAutoscaler of the Grafana deployment or statefulset.
+// Without any target the average cpu utilization is kept at 80%.
+type GrafanaAutoscaling struct {
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// +optional
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+	// additional metrics, e.g. custom pod or external metrics
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+// GrafanaPodDisruptionBudget creates a PodDisruptionBudget for the Grafana pods, only one of minAvailable
+// and maxUnavailable can be set. Defaults to minAvailable 1.
+type GrafanaPodDisruptionBudget struct {
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// GrafanaMetrics configures scraping of the Grafana metrics endpoint
+type GrafanaMetrics struct {
+	// creates a prometheus-operator ServiceMonitor for the instance, ignored when the ServiceMonitor crd is
+	// not installed
+	// +optional
+	ServiceMonitor *GrafanaServiceMonitor `json:"serviceMonitor,omitempty"`
+}
+
+// GrafanaServiceMonitor is a subset of the prometheus-operator ServiceMonitor endpoint
+type GrafanaServiceMonitor struct {
+	// labels of the ServiceMonitor, used by Prometheus to select it
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// scrape interval, defaults to the interval of Prometheus
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// +optional
+	ScrapeTimeout string `json:"scrapeTimeout,omitempty"`
+	// relabelings applied to the target before scraping
+	// +optional
+	Relabelings []RelabelConfig `json:"relabelings,omitempty"`
+	// relabelings applied to the scraped samples before ingestion
+	// +optional
+	MetricRelabelings []RelabelConfig `json:"metricRelabelings,omitempty"`
+}
+
+// RelabelConfig is a Prometheus relabel config
+type RelabelConfig struct {
+	// +optional
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+	// +optional
+	Separator string `json:"separator,omitempty"`
+	// +optional
+	TargetLabel string `json:"targetLabel,omitempty"`
+	// +optional
+	Regex string `json:"regex,omitempty"`
+	// +optional
+	Modulus uint64 `json:"modulus,omitempty"`
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+	// +kubebuilder:validation:Enum=replace;Replace;keep;Keep;drop;Drop;hashmod;HashMod;labelmap;LabelMap;labeldrop;LabelDrop;labelkeep;LabelKeep
+	// +optional
+	Action string `json:"action,omitempty"`
+}
+
+// GrafanaTLS serves Grafana over https, the certificate must be valid for the name of the Grafana service
+// as the operator verifies it
+type GrafanaTLS struct {
+	// secret of type kubernetes.io/tls, ca.crt is trusted by the operator when present. Defaults to
+	// <name>-tls when the certificate is requested from cert-manager.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// requests a certificate for the service and ingress host from cert-manager
+	// +optional
+	IssuerRef *CertManagerIssuerRef `json:"issuerRef,omitempty"`
+	// additional dns names of the requested certificate
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+}
+
+// CertManagerIssuerRef references the cert-manager issuer signing the certificate
+type CertManagerIssuerRef struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// +kubebuilder:default="cert-manager.io"
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// GrafanaDatabase connects Grafana to an external database instead of the embedded sqlite database, the
+// settings take precedence over the database section of the config
+type GrafanaDatabase struct {
+	// +kubebuilder:validation:Enum=mysql;postgres
+	Type string `json:"type"`
+	// host and port of the database, e.g. postgres:5432
+	Host string `json:"host"`
+	// name of the database
+	Name string `json:"name"`
+	User string `json:"user"`
+	// +optional
+	PasswordSecretRef *v1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	// +optional
+	TLS *GrafanaDatabaseTLS `json:"tls,omitempty"`
+}
+
+// GrafanaDatabaseTLS configures the tls connection to the database
+type GrafanaDatabaseTLS struct {
+	// ssl_mode of the connection, e.g. require or verify-full for postgres and true or skip-verify for mysql
+	Mode string `json:"mode"`
+	// secret containing the ca certificate in ca.crt and optionally a client certificate in tls.crt and tls.key
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// name of the database server certificate, required by mysql for verification
+	// +optional
+	ServerCertName string `json:"serverCertName,omitempty"`
+}
+
+// GrafanaPersistence runs Grafana as a statefulset with a persistent volume for the data directory, keeping
+// the embedded sqlite database and plugins across restarts. The volume claim can't be changed once created.
+type GrafanaPersistence struct {
+	// +kubebuilder:default="1Gi"
+	// +optional
+	Size resource.Quantity `json:"size,omitempty"`
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// defaults to ReadWriteOnce
+	// +optional
+	AccessModes []v1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+}
+
+// GrafanaCloudStack provisions a Grafana Cloud stack and manages the resources in it, the stack is created
+// if missing and not deleted together with the cr
+type GrafanaCloudStack struct {
+	// secret key containing a Grafana Cloud api key of the org with permissions to manage stacks
+	APIKey v1.SecretKeySelector `json:"apiKey"`
+	// slug of the stack, used as the subdomain of grafana.net
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9]*$`
+	Slug string `json:"slug"`
+	// name of the stack, defaults to the slug
+	// +optional
+	Name string `json:"name,omitempty"`
+	// region slug to create the stack in, e.g. us or eu
+	// +optional
+	Region string `json:"region,omitempty"`
+	// url of the Grafana Cloud api
+	// +kubebuilder:default="https://grafana.com"
+	// +optional
+	APIURL string `json:"apiUrl,omitempty"`
+}
+
+// GrafanaExternal connects the operator to an instance it does not deploy, e.g. a Grafana Cloud stack.
+// Only the resources in the instance are reconciled, no deployment is created.
+type GrafanaExternal struct {
+	// url of the instance
+	URL string `json:"url"`
+	// secret key containing the admin user for basic auth
+	// +optional
+	AdminUser *v1.SecretKeySelector `json:"adminUser,omitempty"`
+	// secret key containing the admin password for basic auth
+	// +optional
+	AdminPassword *v1.SecretKeySelector `json:"adminPassword,omitempty"`
+	// secret key containing an api key or service account token, used instead of basic auth
+	// +optional
+	APIKey *v1.SecretKeySelector `json:"apiKey,omitempty"`
+	// +optional
+	TLS *GrafanaExternalTLS `json:"tls,omitempty"`
+}
+
+// GrafanaExternalTLS configures the tls connection to an external instance
+type GrafanaExternalTLS struct {
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// secret key containing a pem encoded ca certificate to verify the instance with
+	// +optional
+	CertificateAuthority *v1.SecretKeySelector `json:"certificateAuthority,omitempty"`
+	// secret key containing a pem encoded client certificate
+	// +optional
+	ClientCertificate *v1.SecretKeySelector `json:"clientCertificate,omitempty"`
+	// secret key containing the pem encoded key of the client certificate
+	// +optional
+	ClientKey *v1.SecretKeySelector `json:"clientKey,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=sidecar;deployment
+type ImageRendererMode string
+
+const (
+	ImageRendererModeSidecar    ImageRendererMode = "sidecar"
+	ImageRendererModeDeployment ImageRendererMode = "deployment"
+)
+
+// GrafanaImageRenderer deploys the grafana-image-renderer and points Grafana at it
+type GrafanaImageRenderer struct {
+	// sidecar runs the renderer in the Grafana pod, deployment runs it as a separate deployment and service
+	// +kubebuilder:default=sidecar
+	// +optional
+	Mode ImageRendererMode `json:"mode,omitempty"`
+	// +optional
+	Image string `json:"image,omitempty"`
+	// replicas of the renderer deployment, ignored in sidecar mode
+	// +nullable
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// additional env vars of the renderer container
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
 }
 
 type JsonnetConfig struct {
@@ -97,13 +616,6 @@ type GrafanaDataStorage struct {
 	Class       string                          `json:"class,omitempty"`
 }
 
-type GrafanaServiceAccount struct {
-	Skip             *bool                     `json:"skip,omitempty"`
-	Annotations      map[string]string         `json:"annotations,omitempty"`
-	Labels           map[string]string         `json:"labels,omitempty"`
-	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
-}
-
 // GrafanaDeployment provides a means to configure the deployment
 type GrafanaDeployment struct {
 	Labels map[string]string `json:"labels,omitempty"`
@@ -493,6 +1005,8 @@ type GrafanaConfigSmtp struct {
 	FromAddress  string `json:"from_address,omitempty" ini:"from_address,omitempty"`
 	FromName     string `json:"from_name,omitempty" ini:"from_name,omitempty"`
 	EhloIdentity string `json:"ehlo_identity,omitempty" ini:"ehlo_identity,omitempty"`
+	// +kubebuilder:validation:Enum=OpportunisticStartTLS;MandatoryStartTLS;NoStartTLS
+	StartTLSPolicy string `json:"startTLS_policy,omitempty" ini:"startTLS_policy,omitempty"`
 }
 
 type GrafanaConfigLive struct {
@@ -603,6 +1117,10 @@ type GrafanaConfigUnifiedAlerting struct {
 	EvaluationTimeout string `json:"evaluation_timeout,omitempty" ini:"evaluation_timeout,omitempty"`
 	MaxAttempts       *int   `json:"max_attempts,omitempty" ini:"max_attempts,omitempty"`
 	MinInterval       string `json:"min_interval,omitempty" ini:"min_interval,omitempty"`
+	// set by the operator when running more than one replica
+	HaPeers            string `json:"ha_peers,omitempty" ini:"ha_peers,omitempty"`
+	HaListenAddress    string `json:"ha_listen_address,omitempty" ini:"ha_listen_address,omitempty"`
+	HaAdvertiseAddress string `json:"ha_advertise_address,omitempty" ini:"ha_advertise_address,omitempty"`
 }
 
 type GrafanaConfigPanels struct {
@@ -613,6 +1131,9 @@ type GrafanaConfigPanels struct {
 type GrafanaConfigPlugins struct {
 	// +nullable
 	EnableAlpha *bool `json:"enable_alpha,omitempty" ini:"enable_alpha"`
+	// comma separated ids of plugins loaded without signature, plugins requested with signatureLevel unsigned
+	// are added by the operator
+	AllowLoadingUnsignedPlugins string `json:"allow_loading_unsigned_plugins,omitempty" ini:"allow_loading_unsigned_plugins,omitempty"`
 }
 
 type GrafanaConfigRendering struct {
@@ -632,10 +1153,83 @@ type GrafanaStatus struct {
 	StageStatus OperatorStageStatus `json:"stageStatus,omitempty"`
 	LastMessage string              `json:"lastMessage,omitempty"`
 	AdminUrl    string              `json:"adminUrl,omitempty"`
+	// url the instance is reachable at through the Ingress or Route
+	ExternalUrl string `json:"externalUrl,omitempty"`
+	// last rotation of the admin password requested through spec.adminCredentials.rotate or annotation
+	AdminCredentialsRotation string `json:"adminCredentialsRotation,omitempty"`
+	// slug of the provisioned Grafana Cloud stack
+	StackSlug string `json:"stackSlug,omitempty"`
+	// url of the provisioned Grafana Cloud stack
+	StackUrl string `json:"stackUrl,omitempty"`
+	// version of Grafana the instance runs, or is rolled out to during an upgrade
+	Version string `json:"version,omitempty"`
+	// version of Grafana run before the last upgrade
+	PreviousVersion string `json:"previousVersion,omitempty"`
+	// +optional
+	UpgradePhase GrafanaUpgradePhase `json:"upgradePhase,omitempty"`
+	// secret holding the backup taken before the last upgrade
+	UpgradeBackup string `json:"upgradeBackup,omitempty"`
+	// plugins installed through the plugin api, they are uninstalled once no resource requests them
+	// +optional
+	InstalledPlugins []string `json:"installedPlugins,omitempty"`
+	// versions plugins requested as latest or by range are pinned to
+	// +optional
+	ResolvedPlugins []GrafanaResolvedPlugin `json:"resolvedPlugins,omitempty"`
+	// plugins reported by the plugin api of the instance, sorted by name
+	// +optional
+	Plugins PluginList `json:"plugins,omitempty"`
+	// last time the resolved plugins were upgraded on the auto upgrade schedule
+	// +optional
+	PluginsUpgradeTime *metav1.Time `json:"pluginsUpgradeTime,omitempty"`
+	// plugins consolidated from the requests of all resources, in the versions installed on the instance
+	// +optional
+	ConsolidatedPlugins PluginList `json:"consolidatedPlugins,omitempty"`
+	// hash of the grafana.ini last applied to the instance
+	ConfigHash string `json:"configHash,omitempty"`
+	// number of dashboards imported into the instance without error
+	// +optional
+	Dashboards int `json:"dashboards,omitempty"`
+	// number of datasources imported into the instance without error
+	// +optional
+	Datasources int `json:"datasources,omitempty"`
+	// number of folders synchronized into the instance
+	// +optional
+	Folders int `json:"folders,omitempty"`
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GrafanaResolvedPlugin is the catalog version a plugin requested as latest or by range is pinned to
+type GrafanaResolvedPlugin struct {
+	Name string `json:"name"`
+	// requested versions of all resources
+	Requested string `json:"requested"`
+	Version   string `json:"version"`
+	// time the version was resolved
+	Time metav1.Time `json:"time"`
+}
+
+// GetResolvedPlugin returns the pinned version of a plugin
+func (in *GrafanaStatus) GetResolvedPlugin(name string) *GrafanaResolvedPlugin {
+	for i := range in.ResolvedPlugins {
+		if in.ResolvedPlugins[i].Name == name {
+			return &in.ResolvedPlugins[i]
+		}
+	}
+	return nil
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
+//+kubebuilder:printcolumn:name="Stage",type=string,JSONPath=`.status.stage`
+//+kubebuilder:printcolumn:name="Stage Status",type=string,JSONPath=`.status.stageStatus`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Url",type=string,JSONPath=`.status.adminUrl`,priority=1
+//+kubebuilder:printcolumn:name="Dashboards",type=integer,JSONPath=`.status.dashboards`,priority=1
+//+kubebuilder:printcolumn:name="Datasources",type=integer,JSONPath=`.status.datasources`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
 
 // Grafana is the Schema for the grafanas API
 type Grafana struct {
@@ -659,6 +1253,70 @@ func init() {
 	SchemeBuilder.Register(&Grafana{}, &GrafanaList{})
 }
 
+// IsUpgrading returns true while a new version of Grafana is rolled out, resources are imported once the
+// database migrations finished
+func (r *Grafana) IsUpgrading() bool {
+	return r.Status.UpgradePhase == UpgradePhaseRollingOut
+}
+
+// GetPluginConflictPolicy returns the plugin version conflict policy, highest by default
+func (r *Grafana) GetPluginConflictPolicy() PluginVersionConflictPolicy {
+	if r.Spec.Plugins == nil || r.Spec.Plugins.ConflictPolicy == "" {
+		return PluginVersionConflictHighest
+	}
+	return r.Spec.Plugins.ConflictPolicy
+}
+
+// GetPluginRepository returns the plugin repository of the instance, empty for grafana.com
+func (r *Grafana) GetPluginRepository() string {
+	if r.Spec.Plugins == nil {
+		return ""
+	}
+	return r.Spec.Plugins.Repository
+}
+
+// InstallsPluginsThroughAPI returns true if plugins are installed into the running instance instead of
+// passing them to the pods
+func (r *Grafana) InstallsPluginsThroughAPI() bool {
+	return r.IsExternal() || (r.Spec.Plugins != nil && r.Spec.Plugins.Mode == PluginInstallModeAPI)
+}
+
+// IsExternal returns true if the instance is not deployed by the operator
+func (r *Grafana) IsExternal() bool {
+	return r.Spec.External != nil
+}
+
+// IsCloudStack returns true if the instance is a Grafana Cloud stack provisioned by the operator
+func (r *Grafana) IsCloudStack() bool {
+	return r.Spec.Cloud != nil
+}
+
+// GetReplicas returns the maximum number of Grafana pods, replicas set in the deployment overrides take
+// precedence over spec.replicas and are ignored when autoscaling
+func (r *Grafana) GetReplicas() int32 {
+	if r.Spec.Autoscaling != nil {
+		return r.Spec.Autoscaling.MaxReplicas
+	}
+	if r.Spec.Deployment != nil && r.Spec.Deployment.Spec.Replicas != nil {
+		return *r.Spec.Deployment.Spec.Replicas
+	}
+	if r.Spec.Replicas != nil {
+		return *r.Spec.Replicas
+	}
+	return 1
+}
+
+// TLSSecretName returns the name of the secret Grafana serves https with, empty when tls is disabled
+func (r *Grafana) TLSSecretName() string {
+	if r.Spec.TLS == nil {
+		return ""
+	}
+	if r.Spec.TLS.SecretName != "" {
+		return r.Spec.TLS.SecretName
+	}
+	return fmt.Sprintf("%s-tls", r.Name)
+}
+
 func (r *Grafana) PreferIngress() bool {
 	return r.Spec.Client != nil && r.Spec.Client.PreferIngress != nil && *r.Spec.Client.PreferIngress
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaAnnotationSpec defines the desired state of GrafanaAnnotation
+type GrafanaAnnotationSpec struct {
+	Text string `json:"text"`
+
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// time of the annotation, defaults to the creation time of the cr
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+
+	// end of the annotated time range
+	// +optional
+	TimeEnd *metav1.Time `json:"timeEnd,omitempty"`
+
+	// limits the annotation to a dashboard, annotations without a dashboard are shown on all dashboards
+	// querying them by tags
+	// +optional
+	DashboardUID string `json:"dashboardUid,omitempty"`
+
+	// limits the annotation to a panel of the dashboard
+	// +optional
+	PanelID *int64 `json:"panelId,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaAnnotationInstance is an annotation created in a Grafana instance
+type GrafanaAnnotationInstance struct {
+	// name of the Grafana instance
+	Instance string `json:"instance"`
+
+	// id of the annotation in Grafana
+	ID int64 `json:"id"`
+}
+
+// GrafanaAnnotationStatus defines the observed state of GrafanaAnnotation
+type GrafanaAnnotationStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	Annotations []GrafanaAnnotationInstance `json:"annotations,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaAnnotation is the Schema for the grafanaannotations API
+type GrafanaAnnotation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaAnnotationSpec   `json:"spec,omitempty"`
+	Status GrafanaAnnotationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaAnnotationList contains a list of GrafanaAnnotation
+type GrafanaAnnotationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaAnnotation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaAnnotation{}, &GrafanaAnnotationList{})
+}
+
+// AnnotationTime returns the time of the annotation
+func (in *GrafanaAnnotation) AnnotationTime() metav1.Time {
+	if in.Spec.Time != nil {
+		return *in.Spec.Time
+	}
+	return in.CreationTimestamp
+}
+
+// GetAnnotationID returns the id of the annotation in an instance, zero if it wasn't created yet
+func (in *GrafanaAnnotationStatus) GetAnnotationID(instance string) int64 {
+	for _, annotation := range in.Annotations {
+		if annotation.Instance == instance {
+			return annotation.ID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupPVCStorage stores backups in a persistent volume claim in the namespace of the cr
+type BackupPVCStorage struct {
+	ClaimName string `json:"claimName"`
+
+	// directory in the volume
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// BackupS3Storage uploads backups to a s3 compatible bucket
+type BackupS3Storage struct {
+	Bucket string `json:"bucket"`
+
+	// +kubebuilder:default=us-east-1
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// url of a s3 compatible service, defaults to aws
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// key prefix of the uploaded backups
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// secret in the namespace of the cr containing AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	CredentialsSecretRef v1.LocalObjectReference `json:"credentialsSecretRef"`
+}
+
+// BackupStorage defines where backups are stored, exactly one storage must be set
+type BackupStorage struct {
+	// +optional
+	PVC *BackupPVCStorage `json:"pvc,omitempty"`
+
+	// +optional
+	S3 *BackupS3Storage `json:"s3,omitempty"`
+}
+
+// GrafanaBackupSpec defines the desired state of GrafanaBackup
+type GrafanaBackupSpec struct {
+	// cron schedule of the backups, e.g. "0 2 * * *"
+	Schedule string `json:"schedule"`
+
+	Storage BackupStorage `json:"storage"`
+
+	// pauses scheduling of new backups
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// selects Grafanas to back up, folders, dashboards, datasources and alerting config of the main
+	// organization are included
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+}
+
+// GrafanaBackupInstance is the last backup of a Grafana instance
+type GrafanaBackupInstance struct {
+	// name of the Grafana instance
+	Instance string `json:"instance"`
+
+	Time metav1.Time `json:"time"`
+
+	// size of the tarball in bytes
+	Size int64 `json:"size"`
+
+	// pvc:// or s3:// url of the tarball
+	Location string `json:"location"`
+}
+
+// GrafanaBackupStatus defines the observed state of GrafanaBackup
+type GrafanaBackupStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// +optional
+	NextBackupTime *metav1.Time `json:"nextBackupTime,omitempty"`
+
+	// +optional
+	Backups []GrafanaBackupInstance `json:"backups,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaBackup is the Schema for the grafanabackups API
+type GrafanaBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaBackupSpec   `json:"spec,omitempty"`
+	Status GrafanaBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaBackupList contains a list of GrafanaBackup
+type GrafanaBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaBackup{}, &GrafanaBackupList{})
+}
+
+// SetBackup records the last backup of an instance
+func (in *GrafanaBackupStatus) SetBackup(backup GrafanaBackupInstance) {
+	for i := range in.Backups {
+		if in.Backups[i].Instance == backup.Instance {
+			in.Backups[i] = backup
+			return
+		}
+	}
+	in.Backups = append(in.Backups, backup)
+}
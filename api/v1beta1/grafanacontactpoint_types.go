@@ -0,0 +1,107 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaContactPointSpec defines the desired state of GrafanaContactPoint
+type GrafanaContactPointSpec struct {
+	// contact point name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// contact point uid, defaults to the uid of the cr
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// receiver type, e.g. slack, pagerduty, email or webhook
+	Type string `json:"type"`
+
+	// receiver settings, see the Grafana documentation of the receiver type
+	// +optional
+	Settings *apiextensionsv1.JSON `json:"settings,omitempty"`
+
+	// +optional
+	DisableResolveMessage bool `json:"disableResolveMessage,omitempty"`
+
+	// inject sensitive settings from secrets or config maps, target paths are relative to the contact point,
+	// e.g. settings.url
+	// +optional
+	ValuesFrom []ValueFrom `json:"valuesFrom,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaContactPointStatus defines the observed state of GrafanaContactPoint
+type GrafanaContactPointStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaContactPoint is the Schema for the grafanacontactpoints API
+type GrafanaContactPoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaContactPointSpec   `json:"spec,omitempty"`
+	Status GrafanaContactPointStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaContactPointList contains a list of GrafanaContactPoint
+type GrafanaContactPointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaContactPoint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaContactPoint{}, &GrafanaContactPointList{})
+}
+
+// ContactPointName returns the name of the contact point in Grafana
+func (in *GrafanaContactPoint) ContactPointName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
+
+// ContactPointUID returns the uid of the contact point in Grafana
+func (in *GrafanaContactPoint) ContactPointUID() string {
+	if in.Spec.UID != "" {
+		return in.Spec.UID
+	}
+	return string(in.UID)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CorrelationTransformation extracts variables from the source data
+type CorrelationTransformation struct {
+	// +kubebuilder:validation:Enum=regex;logfmt
+	Type string `json:"type"`
+
+	// regular expression, required for regex transformations
+	// +optional
+	Expression string `json:"expression,omitempty"`
+
+	// name of the variable, defaults to the field name
+	// +optional
+	Variable string `json:"variable,omitempty"`
+
+	// field the transformation is applied to
+	// +optional
+	Field string `json:"field,omitempty"`
+}
+
+// CorrelationConfig defines the query run against the target datasource
+type CorrelationConfig struct {
+	// +kubebuilder:default=query
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// field of the source data the link is attached to
+	Field string `json:"field"`
+
+	// query model of the target datasource, may reference variables like ${traceId}
+	Target *apiextensionsv1.JSON `json:"target"`
+
+	// +optional
+	Transformations []CorrelationTransformation `json:"transformations,omitempty"`
+}
+
+// GrafanaCorrelationSpec defines the desired state of GrafanaCorrelation
+type GrafanaCorrelationSpec struct {
+	Source DatasourceReference `json:"source"`
+
+	Target DatasourceReference `json:"target"`
+
+	// label of the link shown in explore, defaults to the name of the cr
+	// +optional
+	Label string `json:"label,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	Config CorrelationConfig `json:"config"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaCorrelationInstance is a correlation created in a Grafana instance
+type GrafanaCorrelationInstance struct {
+	// name of the Grafana instance
+	Instance string `json:"instance"`
+
+	// uid of the source datasource the correlation was created for
+	SourceUID string `json:"sourceUid"`
+
+	// uid of the correlation in Grafana
+	UID string `json:"uid"`
+}
+
+// GrafanaCorrelationStatus defines the observed state of GrafanaCorrelation
+type GrafanaCorrelationStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	Correlations []GrafanaCorrelationInstance `json:"correlations,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaCorrelation is the Schema for the grafanacorrelations API
+type GrafanaCorrelation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaCorrelationSpec   `json:"spec,omitempty"`
+	Status GrafanaCorrelationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaCorrelationList contains a list of GrafanaCorrelation
+type GrafanaCorrelationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaCorrelation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaCorrelation{}, &GrafanaCorrelationList{})
+}
+
+// CorrelationLabel returns the label of the correlation in Grafana
+func (in *GrafanaCorrelation) CorrelationLabel() string {
+	if in.Spec.Label != "" {
+		return in.Spec.Label
+	}
+	return in.Name
+}
+
+// GetCorrelation returns the correlation created in an instance
+func (in *GrafanaCorrelationStatus) GetCorrelation(instance string) *GrafanaCorrelationInstance {
+	for i := range in.Correlations {
+		if in.Correlations[i].Instance == instance {
+			return &in.Correlations[i]
+		}
+	}
+	return nil
+}
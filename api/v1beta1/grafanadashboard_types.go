@@ -17,6 +17,10 @@ limitations under the License.
 package v1beta1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -28,19 +32,283 @@ type GrafanaDashboardSpec struct {
 	// dashboard json
 	Json string `json:"json,omitempty"`
 
+	// gzipped dashboard json, base64 encoded in yaml. Generated dashboards too large to be stored inline
+	// usually fit once compressed. It is used when json is empty.
+	// +optional
+	GzipJson []byte `json:"gzipJson,omitempty"`
+
+	// key of a ConfigMap containing the dashboard json, e.g. a ConfigMap of a dashboard sidecar. It is used
+	// when json and gzipJson are empty. Gzipped binaryData is decompressed.
+	// +optional
+	ConfigMapRef *GrafanaDashboardConfigMapRef `json:"configMapRef,omitempty"`
+
+	// url the dashboard json is downloaded from, it is used when json and configMapRef are empty
+	// +optional
+	Url string `json:"url,omitempty"`
+
+	// dashboard published on grafana.com, it is used when json, configMapRef and url are empty
+	// +optional
+	GrafanaCom *GrafanaComDashboardReference `json:"grafanaCom,omitempty"`
+
+	// object of a s3, gcs or azure blob storage bucket the dashboard json is downloaded from, it is used when
+	// json, configMapRef, url and grafanaCom are empty
+	// +optional
+	ObjectStorage *GrafanaDashboardObjectStorage `json:"objectStorage,omitempty"`
+
+	// file of an OCI artifact the dashboard json is pulled from, it is used when json, configMapRef, url,
+	// grafanaCom and objectStorage are empty. The manifest is checked again after the content cache duration.
+	// +optional
+	OCI *OCIArtifactSource `json:"oci,omitempty"`
+
+	// file of a git repository the dashboard json is fetched from, it is used when no other source is set.
+	// The ref is resolved again after the content cache duration.
+	// +optional
+	GitRepository *GitRepositorySource `json:"gitRepository,omitempty"`
+
+	// headers sent with requests of the url, e.g. the authorization header of a private repository
+	// +optional
+	UrlHeaders []GrafanaDashboardUrlHeader `json:"urlHeaders,omitempty"`
+
+	// how long the content downloaded from the url, grafana.com, object storage, oci or git is used before it is requested again, defaults to 5m.
+	// Unchanged content is detected with ETag and Last-Modified.
+	// +optional
+	ContentCacheDuration *metav1.Duration `json:"contentCacheDuration,omitempty"`
+
+	// datasources replacing the ${DS_...} placeholders of the __inputs of a dashboard exported for sharing,
+	// e.g. from grafana.com. Constant inputs are replaced by their exported value.
+	// +optional
+	Datasources []GrafanaDashboardDatasource `json:"datasources,omitempty"`
+
+	// variables replacing ${NAME} placeholders in the strings of the dashboard json, so that one dashboard
+	// is parameterized per cluster or environment. Variables of envs take precedence over envFrom.
+	// +optional
+	Envs []GrafanaDashboardEnv `json:"envs,omitempty"`
+
+	// ConfigMaps and Secrets in the namespace of the dashboard whose keys are substituted like envs
+	// +optional
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
+
 	// selects Grafanas for import
 	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
 
+	// allows the import into selected Grafanas of other namespaces that allow cross namespace imports, when
+	// enabled for the operator
+	// +optional
+	AllowCrossNamespaceImport bool `json:"allowCrossNamespaceImport,omitempty"`
+
 	// plugins
 	Plugins PluginList `json:"plugins,omitempty"`
+
+	// adds the panel and datasource plugins used by the json that are not bundled with Grafana to the
+	// plugins in their latest version, the derived plugins are recorded in the status
+	// +optional
+	DerivePlugins bool `json:"derivePlugins,omitempty"`
+
+	// sets the dashboard as home dashboard of the organization once imported, the home dashboard of a
+	// GrafanaPreferences of the organization takes precedence
+	// +optional
+	IsHomeDashboard bool `json:"isHomeDashboard,omitempty"`
+
+	// treatment of a dashboard with the same uid that exists in Grafana before it is imported, defaults to overwrite
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// treatment of the dashboard in Grafana when the cr is deleted, defaults to Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	ResyncPolicy `json:",inline"`
+
+	// reports the changes the dashboard would make in the status instead of importing it, the dashboard in
+	// Grafana is neither changed nor deleted
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// name of a GrafanaFolder in the same namespace to import the dashboard into, the dashboard waits in each
+	// instance until the folder was imported into it like for referenced datasources and library panels
+	// +optional
+	FolderRef string `json:"folderRef,omitempty"`
+
+	// names of GrafanaLibraryPanels in the same namespace used by the dashboard, the panels are imported
+	// before the dashboard
+	// +optional
+	LibraryPanelRefs []string `json:"libraryPanelRefs,omitempty"`
+
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaDashboardConfigMapRef selects the key of a ConfigMap
+type GrafanaDashboardConfigMapRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	// namespace of the ConfigMap, defaults to the namespace of the dashboard. Other namespaces require
+	// allowCrossNamespaceImport on the dashboard and the operator.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// GrafanaComDashboardReference is a revision of a dashboard published on grafana.com
+type GrafanaComDashboardReference struct {
+	// id of the dashboard, e.g. 1860 for https://grafana.com/grafana/dashboards/1860
+	// +kubebuilder:validation:Minimum=1
+	Id int `json:"id"`
+	// revision to download, the latest revision if empty
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Revision *int `json:"revision,omitempty"`
+	// cron schedule on which the latest revision is checked, e.g. "0 3 * * *". Without a schedule the
+	// latest revision is only resolved once.
+	// +optional
+	UpdateSchedule string `json:"updateSchedule,omitempty"`
+}
+
+// ObjectStorageProvider is the service a dashboard object is downloaded from
+type ObjectStorageProvider string
+
+const (
+	ObjectStorageProviderS3    ObjectStorageProvider = "s3"
+	ObjectStorageProviderGCS   ObjectStorageProvider = "gcs"
+	ObjectStorageProviderAzure ObjectStorageProvider = "azure"
+)
+
+// GrafanaDashboardObjectStorage is an object in a bucket, e.g. a dashboard rendered and published by a pipeline.
+// Without a credentials secret the identity of the operator pod is used: IRSA for s3, workload identity or the
+// metadata server for gcs and workload identity for azure. Buckets are read anonymously if no identity is found.
+type GrafanaDashboardObjectStorage struct {
+	// +kubebuilder:validation:Enum=s3;gcs;azure
+	Provider ObjectStorageProvider `json:"provider"`
+
+	// name of the bucket, or the container of azure blob storage
+	Bucket string `json:"bucket"`
+
+	// key of the dashboard json in the bucket
+	Key string `json:"key"`
+
+	// region of a s3 bucket, defaults to us-east-1
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// url of a s3 compatible service, or of an azure storage account, e.g. https://<account>.blob.core.windows.net
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// storage account of azure blob storage, used unless endpoint is set
+	// +optional
+	Account string `json:"account,omitempty"`
+
+	// secret in the namespace of the dashboard containing AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY for s3,
+	// the same keys holding a HMAC key for gcs, or AZURE_STORAGE_SAS_TOKEN for azure
+	// +optional
+	CredentialsSecretRef *v1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// GrafanaDashboardDatasource maps a datasource input of a dashboard to a datasource, the placeholder is
+// replaced by the uid of the referenced datasource or by the datasource name
+type GrafanaDashboardDatasource struct {
+	// name of the input, e.g. DS_PROMETHEUS
+	InputName string `json:"inputName"`
+
+	// name of a datasource in Grafana, ignored when datasourceRef or datasourceUid is set
+	// +optional
+	DatasourceName string `json:"datasourceName,omitempty"`
+
+	DatasourceReference `json:",inline"`
+}
+
+// GrafanaDashboardEnv is a variable of the dashboard json, the value is read from a Secret or ConfigMap when
+// valueFrom is set
+type GrafanaDashboardEnv struct {
+	Name string `json:"name"`
+	// +optional
+	Value string `json:"value,omitempty"`
+	// +optional
+	ValueFrom *ValueFromSource `json:"valueFrom,omitempty"`
+}
+
+// GrafanaDashboardUrlHeader is a header of the requests of a dashboard url, the value is read from a Secret
+// or ConfigMap when valueFrom is set
+type GrafanaDashboardUrlHeader struct {
+	Name string `json:"name"`
+	// +optional
+	Value string `json:"value,omitempty"`
+	// +optional
+	ValueFrom *ValueFromSource `json:"valueFrom,omitempty"`
 }
 
 // GrafanaDashboardStatus defines the observed state of GrafanaDashboard
 type GrafanaDashboardStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	SyncRetryStatus `json:",inline"`
+
+	ReconcileRequestStatus `json:",inline"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// plugins derived from the json that are installed in addition to the plugins of the spec
+	// +optional
+	DerivedPlugins PluginList `json:"derivedPlugins,omitempty"`
+
+	// gzipped json last downloaded from the url
+	// +optional
+	ContentCache []byte `json:"contentCache,omitempty"`
+	// url the cached content was downloaded from, s3://, gs:// or azure:// urls for objects in a bucket and
+	// oci:// urls for artifacts
+	// +optional
+	ContentUrl string `json:"contentUrl,omitempty"`
+	// time the cached content was downloaded or revalidated
+	// +optional
+	ContentTimestamp *metav1.Time `json:"contentTimestamp,omitempty"`
+	// ETag of the cached content, or the digest of the manifest of an artifact
+	// +optional
+	ContentETag string `json:"contentETag,omitempty"`
+	// Last-Modified header of the cached content
+	// +optional
+	ContentLastModified string `json:"contentLastModified,omitempty"`
+	// uid the dashboard is imported with, a uid is claimed by the first dashboard importing it into an
+	// instance and organization
+	// +optional
+	UID string `json:"uid,omitempty"`
+	// sha256 of the normalized json last imported into the instances
+	// +optional
+	ContentHash string `json:"contentHash,omitempty"`
+	// changes the dashboard would make in the matching instances, set in dry run
+	// +optional
+	DryRun []DryRunResult `json:"dryRun,omitempty"`
+	// dashboards that existed in Grafana before they were adopted
+	// +optional
+	Adopted []AdoptedResource `json:"adopted,omitempty"`
+	// results of importing the dashboard into the matching instances
+	// +optional
+	Instances []InstanceStatus `json:"instances,omitempty"`
+	// number of instances matching the instance selector
+	// +optional
+	MatchedInstances int `json:"matchedInstances,omitempty"`
+	// time the dashboard was last imported into one of the instances
+	// +optional
+	LastSynced *metav1.Time `json:"lastSynced,omitempty"`
+	// revision of the grafana.com dashboard that is downloaded
+	// +optional
+	GrafanaComRevision int `json:"grafanaComRevision,omitempty"`
+	// time the latest revision of the grafana.com dashboard was checked
+	// +optional
+	GrafanaComRevisionTime *metav1.Time `json:"grafanaComRevisionTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Folder",type=string,JSONPath=`.spec.folderRef`
+//+kubebuilder:printcolumn:name="UID",type=string,JSONPath=`.status.uid`
+//+kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.status.matchedInstances`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSynced`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
 
 // GrafanaDashboard is the Schema for the grafanadashboards API
 type GrafanaDashboard struct {
@@ -63,3 +331,50 @@ type GrafanaDashboardList struct {
 func init() {
 	SchemeBuilder.Register(&GrafanaDashboard{}, &GrafanaDashboardList{})
 }
+
+// ReconcileRequested returns true if the reconcile annotation requests a resync that wasn't handled yet
+func (in *GrafanaDashboard) ReconcileRequested() bool {
+	return reconcileRequested(in, in.Status.ReconcileRequestStatus)
+}
+
+// ConfigMapKey returns the namespace and name of the referenced ConfigMap
+func (in *GrafanaDashboard) ConfigMapKey() (string, string) {
+	if in.Spec.ConfigMapRef == nil {
+		return "", ""
+	}
+	namespace := in.Spec.ConfigMapRef.Namespace
+	if namespace == "" {
+		namespace = in.Namespace
+	}
+	return namespace, in.Spec.ConfigMapRef.Name
+}
+
+// ConfigMapAllowed returns true if the ConfigMap of the json is in the namespace of the dashboard, ConfigMaps
+// of other namespaces require the dashboard and the operator to allow cross namespace imports
+func (in *GrafanaDashboard) ConfigMapAllowed(allowCrossNamespaceImport bool) bool {
+	namespace, _ := in.ConfigMapKey()
+	return namespace == in.Namespace || (allowCrossNamespaceImport && in.Spec.AllowCrossNamespaceImport)
+}
+
+// DashboardUID returns the uid set in the dashboard json, dashboards without uid are imported with the uid
+// generated from the cr
+func (in *GrafanaDashboard) DashboardUID() (string, error) {
+	var content struct {
+		UID string `json:"uid"`
+	}
+	err := json.Unmarshal([]byte(in.Spec.Json), &content)
+	if err != nil {
+		return "", err
+	}
+	if content.UID == "" {
+		return in.GeneratedUID(), nil
+	}
+	return content.UID, nil
+}
+
+// GeneratedUID returns a uid derived from the namespace and name of the cr, which is stable across instances
+// and fits the 40 characters Grafana allows
+func (in *GrafanaDashboard) GeneratedUID() string {
+	sum := sha256.Sum256([]byte(in.Namespace + "/" + in.Name))
+	return hex.EncodeToString(sum[:20])
+}
@@ -0,0 +1,306 @@
+package v1beta1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"regexp"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"strings"
+)
+
+// inline json is limited by the size of the resource, gzipped json is only decompressed up to this size
+const maxGzipJsonSize = 10 * 1024 * 1024
+
+// AllowCrossNamespaceConfigMaps is set to the allow-cross-namespace-import flag of the operator, the webhook
+// rejects ConfigMaps of other namespaces unless it is set
+var AllowCrossNamespaceConfigMaps bool
+
+// datasource variables are referenced as ${name}, ${name:format} or $name
+var datasourceVariable = regexp.MustCompile(`^\$(?:\{([^}:]+)(?::[^}]*)?\}|([A-Za-z0-9_]+))$`)
+
+func (in *GrafanaDashboard) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		WithDefaulter(&namespaceDefaulter{client: mgr.GetClient()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-grafana-integreatly-org-v1beta1-grafanadashboard,mutating=false,failurePolicy=fail,sideEffects=None,groups=grafana.integreatly.org,resources=grafanadashboards,verbs=create;update,versions=v1beta1,name=vgrafanadashboard.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &GrafanaDashboard{}
+
+// ValidateCreate rejects dashboards with invalid inline json or a ConfigMap of another namespace
+func (in *GrafanaDashboard) ValidateCreate() error {
+	err := in.validateConfigMapRef()
+	if err != nil {
+		return err
+	}
+	return in.validateJson()
+}
+
+// ValidateUpdate rejects changes to invalid inline json or a ConfigMap of another namespace, dashboards created
+// before the webhook keep their json and ConfigMap so that finalizers can still be added and removed
+func (in *GrafanaDashboard) ValidateUpdate(old runtime.Object) error {
+	if in.DeletionTimestamp != nil {
+		return nil
+	}
+	previous, ok := old.(*GrafanaDashboard)
+	if !ok || !equality.Semantic.DeepEqual(previous.Spec.ConfigMapRef, in.Spec.ConfigMapRef) || previous.Spec.AllowCrossNamespaceImport != in.Spec.AllowCrossNamespaceImport {
+		err := in.validateConfigMapRef()
+		if err != nil {
+			return err
+		}
+	}
+	if ok && previous.Spec.Json == in.Spec.Json && bytes.Equal(previous.Spec.GzipJson, in.Spec.GzipJson) {
+		return nil
+	}
+	return in.validateJson()
+}
+
+func (in *GrafanaDashboard) ValidateDelete() error {
+	return nil
+}
+
+// validateJson validates the json of the spec, json downloaded or loaded from a ConfigMap is validated when
+// the dashboard is reconciled
+func (in *GrafanaDashboard) validateJson() error {
+	var content []byte
+	var path *field.Path
+	switch {
+	case strings.TrimSpace(in.Spec.Json) != "":
+		content = []byte(in.Spec.Json)
+		path = field.NewPath("spec", "json")
+	case len(in.Spec.GzipJson) > 0:
+		path = field.NewPath("spec", "gzipJson")
+		var err error
+		content, err = gunzipJson(in.Spec.GzipJson)
+		if err != nil {
+			return in.invalid(field.ErrorList{field.Invalid(path, "", err.Error())})
+		}
+	default:
+		return nil
+	}
+
+	errs := validateDashboardJson(content, path, &in.Spec)
+	if len(errs) > 0 {
+		return in.invalid(errs)
+	}
+	return nil
+}
+
+// validateConfigMapRef rejects ConfigMaps of other namespaces unless the dashboard and the operator allow
+// cross namespace imports
+func (in *GrafanaDashboard) validateConfigMapRef() error {
+	if in.Spec.ConfigMapRef == nil || in.ConfigMapAllowed(AllowCrossNamespaceConfigMaps) {
+		return nil
+	}
+	path := field.NewPath("spec", "configMapRef", "namespace")
+	return in.invalid(field.ErrorList{field.Forbidden(path, "ConfigMaps of other namespaces require allowCrossNamespaceImport on the dashboard and the operator")})
+}
+
+func (in *GrafanaDashboard) invalid(errs field.ErrorList) error {
+	return apierrors.NewInvalid(GroupVersion.WithKind("GrafanaDashboard").GroupKind(), in.Name, errs)
+}
+
+func gunzipJson(content []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip: %w", err)
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxGzipJsonSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip: %w", err)
+	}
+	if len(decompressed) > maxGzipJsonSize {
+		return nil, fmt.Errorf("decompressed json is larger than %d bytes", maxGzipJsonSize)
+	}
+	return decompressed, nil
+}
+
+// dashboardVariables are the variables a datasource of a panel can reference
+type dashboardVariables struct {
+	// variables of the spec, inputs of type constant and template variables of the dashboard
+	known map[string]bool
+	// datasource inputs, they need to be mapped by the datasources of the spec
+	inputs map[string]bool
+	// envFrom sources can provide any variable
+	any bool
+}
+
+// validateDashboardJson requires an object with a panels array, unique panel ids and datasources that are
+// either names, variables the operator or Grafana substitutes, or objects of uid and type
+func validateDashboardJson(content []byte, path *field.Path, spec *GrafanaDashboardSpec) field.ErrorList {
+	var dashboard map[string]interface{}
+	err := json.Unmarshal(content, &dashboard)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, column := getPosition(content, syntaxErr.Offset)
+		return field.ErrorList{field.Invalid(path, "", fmt.Sprintf("invalid json at line %d, column %d: %s", line, column, syntaxErr.Error()))}
+	}
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, "", fmt.Sprintf("the json must be a dashboard object: %s", err.Error()))}
+	}
+
+	variables := getDashboardVariables(dashboard, spec)
+
+	panels, ok := dashboard["panels"]
+	if !ok {
+		// dashboards of the schema before Grafana 5 have rows instead of panels
+		if _, ok := dashboard["rows"]; ok {
+			return nil
+		}
+		return field.ErrorList{field.Required(path.Child("panels"), "dashboards require a panels array, use [] for an empty dashboard")}
+	}
+
+	ids := map[float64]string{}
+	return validatePanels(panels, path.Child("panels"), ids, variables)
+}
+
+func getDashboardVariables(dashboard map[string]interface{}, spec *GrafanaDashboardSpec) dashboardVariables {
+	variables := dashboardVariables{
+		known:  map[string]bool{},
+		inputs: map[string]bool{},
+		any:    len(spec.EnvFrom) > 0,
+	}
+	for _, datasource := range spec.Datasources {
+		variables.known[datasource.InputName] = true
+	}
+	for _, env := range spec.Envs {
+		variables.known[env.Name] = true
+	}
+
+	inputs, _ := dashboard["__inputs"].([]interface{})
+	for _, input := range inputs {
+		input, _ := input.(map[string]interface{})
+		name, _ := input["name"].(string)
+		if input["type"] == "datasource" {
+			variables.inputs[name] = true
+		} else {
+			variables.known[name] = true
+		}
+	}
+
+	templating, _ := dashboard["templating"].(map[string]interface{})
+	list, _ := templating["list"].([]interface{})
+	for _, variable := range list {
+		variable, _ := variable.(map[string]interface{})
+		if name, ok := variable["name"].(string); ok {
+			variables.known[name] = true
+		}
+	}
+	return variables
+}
+
+// validatePanels validates the panels of a dashboard and the panels of collapsed rows
+func validatePanels(panels interface{}, path *field.Path, ids map[float64]string, variables dashboardVariables) field.ErrorList {
+	list, ok := panels.([]interface{})
+	if !ok {
+		return field.ErrorList{field.Invalid(path, "", "panels must be an array")}
+	}
+
+	var errs field.ErrorList
+	for i, panel := range list {
+		panelPath := path.Index(i)
+		panel, ok := panel.(map[string]interface{})
+		if !ok {
+			errs = append(errs, field.Invalid(panelPath, "", "panels must be objects"))
+			continue
+		}
+
+		title, _ := panel["title"].(string)
+		if id, ok := panel["id"]; ok && id != nil {
+			number, ok := id.(float64)
+			if !ok {
+				errs = append(errs, field.Invalid(panelPath.Child("id"), id, "panel ids must be numbers"))
+			} else if other, ok := ids[number]; ok {
+				errs = append(errs, field.Invalid(panelPath.Child("id"), number, fmt.Sprintf("the id is already used by panel %q", other)))
+			} else {
+				ids[number] = title
+			}
+		}
+
+		if datasource, ok := panel["datasource"]; ok {
+			errs = append(errs, validateDatasourceRef(datasource, panelPath.Child("datasource"), variables)...)
+		}
+		targets, _ := panel["targets"].([]interface{})
+		for j, target := range targets {
+			target, _ := target.(map[string]interface{})
+			if datasource, ok := target["datasource"]; ok {
+				errs = append(errs, validateDatasourceRef(datasource, panelPath.Child("targets").Index(j).Child("datasource"), variables)...)
+			}
+		}
+
+		if nested, ok := panel["panels"]; ok {
+			errs = append(errs, validatePanels(nested, panelPath.Child("panels"), ids, variables)...)
+		}
+	}
+	return errs
+}
+
+// validateDatasourceRef accepts datasource names, variables and objects with a uid and type
+func validateDatasourceRef(datasource interface{}, path *field.Path, variables dashboardVariables) field.ErrorList {
+	switch ref := datasource.(type) {
+	case nil:
+		return nil
+	case string:
+		return validateDatasourceVariable(ref, path, variables)
+	case map[string]interface{}:
+		var errs field.ErrorList
+		for _, key := range []string{"uid", "type"} {
+			value, ok := ref[key]
+			if !ok || value == nil {
+				continue
+			}
+			text, ok := value.(string)
+			if !ok {
+				errs = append(errs, field.Invalid(path.Child(key), value, fmt.Sprintf("the %s of a datasource must be a string", key)))
+				continue
+			}
+			errs = append(errs, validateDatasourceVariable(text, path.Child(key), variables)...)
+		}
+		return errs
+	}
+	return field.ErrorList{field.Invalid(path, datasource, "datasources must be a name, a variable or an object with uid and type")}
+}
+
+// validateDatasourceVariable requires the variable a datasource references to be substituted
+func validateDatasourceVariable(value string, path *field.Path, variables dashboardVariables) field.ErrorList {
+	match := datasourceVariable.FindStringSubmatch(value)
+	if match == nil {
+		return nil
+	}
+	name := match[1] + match[2]
+	switch {
+	case variables.known[name], variables.any, strings.HasPrefix(name, "__"):
+		return nil
+	case variables.inputs[name]:
+		return field.ErrorList{field.Invalid(path, value, fmt.Sprintf("datasource input %s is not mapped, add it to spec.datasources", name))}
+	}
+	return field.ErrorList{field.Invalid(path, value, fmt.Sprintf("variable %s is neither a template variable, an input nor an env of the dashboard", name))}
+}
+
+// getPosition returns the line and column of the byte before an offset of the json, syntax errors are
+// reported after reading the invalid byte
+func getPosition(content []byte, offset int64) (int, int) {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	if offset > 0 {
+		offset--
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
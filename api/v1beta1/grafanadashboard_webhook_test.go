@@ -0,0 +1,128 @@
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestGetPosition(t *testing.T) {
+	content := []byte("{\n  \"a\": x\n}")
+	tests := []struct {
+		offset int64
+		line   int
+		column int
+	}{
+		{offset: 0, line: 1, column: 1},
+		{offset: 1, line: 1, column: 1},
+		{offset: 10, line: 2, column: 8},
+		{offset: 100, line: 3, column: 1},
+	}
+	for _, test := range tests {
+		if line, column := getPosition(content, test.offset); line != test.line || column != test.column {
+			t.Errorf("getPosition(%d) = %d:%d, expected %d:%d", test.offset, line, column, test.line, test.column)
+		}
+	}
+}
+
+func TestValidateDashboardJson(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		spec    GrafanaDashboardSpec
+		// substrings of the expected errors, in order
+		errors []string
+	}{
+		{name: "empty dashboard", content: `{"panels":[]}`},
+		{name: "rows instead of panels", content: `{"rows":[]}`},
+		{name: "missing panels", content: `{"title":"a"}`, errors: []string{"spec.json.panels: Required value"}},
+		{name: "null", content: `null`, errors: []string{"spec.json.panels: Required value"}},
+		{name: "array", content: `[]`, errors: []string{"the json must be a dashboard object"}},
+		{name: "syntax error", content: "{\n  \"panels\": [,]\n}", errors: []string{"invalid json at line 2, column 14"}},
+		{name: "panels not an array", content: `{"panels":{}}`, errors: []string{"panels must be an array"}},
+		{name: "panel not an object", content: `{"panels":[1]}`, errors: []string{"spec.json.panels[0]: Invalid value", "panels must be objects"}},
+		{name: "unique ids", content: `{"panels":[{"id":1},{"id":2},{"id":null},{}]}`},
+		{name: "duplicate ids", content: `{"panels":[{"id":1,"title":"a"},{"id":1}]}`, errors: []string{`spec.json.panels[1].id`, `the id is already used by panel "a"`}},
+		{name: "duplicate ids of collapsed rows", content: `{"panels":[{"id":1,"title":"a"},{"id":2,"panels":[{"id":1}]}]}`, errors: []string{`spec.json.panels[1].panels[0].id`}},
+		{name: "id not a number", content: `{"panels":[{"id":"1"}]}`, errors: []string{"panel ids must be numbers"}},
+		{name: "datasource name", content: `{"panels":[{"datasource":"Prometheus"}]}`},
+		{name: "datasource object", content: `{"panels":[{"datasource":{"uid":"abc","type":"prometheus"}}]}`},
+		{name: "datasource object with a number", content: `{"panels":[{"datasource":{"uid":1}}]}`, errors: []string{"spec.json.panels[0].datasource.uid", "must be a string"}},
+		{name: "datasource number", content: `{"panels":[{"datasource":1}]}`, errors: []string{"datasources must be a name, a variable or an object"}},
+		{name: "builtin variable", content: `{"panels":[{"datasource":"${__expr__}"}]}`},
+		{name: "template variable", content: `{"templating":{"list":[{"name":"ds"}]},"panels":[{"datasource":"$ds"}]}`},
+		{name: "constant input", content: `{"__inputs":[{"name":"DS","type":"constant"}],"panels":[{"datasource":{"uid":"${DS}"}}]}`},
+		{
+			name:    "unmapped datasource input",
+			content: `{"__inputs":[{"name":"DS_PROMETHEUS","type":"datasource"}],"panels":[{"targets":[{"datasource":"${DS_PROMETHEUS}"}]}]}`,
+			errors:  []string{"spec.json.panels[0].targets[0].datasource", "datasource input DS_PROMETHEUS is not mapped"},
+		},
+		{
+			name:    "mapped datasource input",
+			content: `{"__inputs":[{"name":"DS_PROMETHEUS","type":"datasource"}],"panels":[{"datasource":"${DS_PROMETHEUS}"}]}`,
+			spec:    GrafanaDashboardSpec{Datasources: []GrafanaDashboardDatasource{{InputName: "DS_PROMETHEUS", DatasourceName: "Prometheus"}}},
+		},
+		{name: "env", content: `{"panels":[{"datasource":"${DS:text}"}]}`, spec: GrafanaDashboardSpec{Envs: []GrafanaDashboardEnv{{Name: "DS", Value: "a"}}}},
+		{name: "envFrom", content: `{"panels":[{"datasource":"$DS"}]}`, spec: GrafanaDashboardSpec{EnvFrom: []v1.EnvFromSource{{}}}},
+		{name: "unknown variable", content: `{"panels":[{"datasource":"$DS"}]}`, errors: []string{"variable DS is neither a template variable, an input nor an env"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := test.spec
+			errs := validateDashboardJson([]byte(test.content), field.NewPath("spec", "json"), &spec)
+			if len(test.errors) == 0 {
+				if len(errs) > 0 {
+					t.Errorf("validateDashboardJson(%s) returned %v", test.content, errs)
+				}
+				return
+			}
+			if len(errs) == 0 {
+				t.Fatalf("validateDashboardJson(%s) accepted the dashboard, expected %v", test.content, test.errors)
+			}
+			message := errs.ToAggregate().Error()
+			for _, expected := range test.errors {
+				if !strings.Contains(message, expected) {
+					t.Errorf("validateDashboardJson(%s) returned %q, expected it to contain %q", test.content, message, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateConfigMapRef(t *testing.T) {
+	tests := []struct {
+		name           string
+		namespace      string
+		allowDashboard bool
+		allowOperator  bool
+		wantErr        bool
+	}{
+		{name: "same namespace"},
+		{name: "explicit same namespace", namespace: "grafana"},
+		{name: "other namespace", namespace: "shared", wantErr: true},
+		{name: "other namespace allowed by the dashboard", namespace: "shared", allowDashboard: true, wantErr: true},
+		{name: "other namespace allowed by the operator", namespace: "shared", allowOperator: true, wantErr: true},
+		{name: "other namespace allowed by both", namespace: "shared", allowDashboard: true, allowOperator: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dashboard := &GrafanaDashboard{
+				ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Namespace: "grafana"},
+				Spec: GrafanaDashboardSpec{
+					ConfigMapRef:              &GrafanaDashboardConfigMapRef{Name: "dashboards", Key: "a.json", Namespace: test.namespace},
+					AllowCrossNamespaceImport: test.allowDashboard,
+				},
+			}
+			AllowCrossNamespaceConfigMaps = test.allowOperator
+			defer func() { AllowCrossNamespaceConfigMaps = false }()
+
+			err := dashboard.ValidateCreate()
+			if (err != nil) != test.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
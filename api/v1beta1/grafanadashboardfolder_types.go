@@ -0,0 +1,139 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaDashboardFolderSpec defines the desired state of GrafanaDashboardFolder
+type GrafanaDashboardFolderSpec struct {
+	// ConfigMap in the same namespace, every key ending in .json is imported as a dashboard
+	// +optional
+	ConfigMapRef *v1.LocalObjectReference `json:"configMapRef,omitempty"`
+
+	// bucket whose objects ending in .json are imported as dashboards, the key is used as prefix of the
+	// objects, e.g. dashboards/
+	// +optional
+	ObjectStorage *GrafanaDashboardObjectStorage `json:"objectStorage,omitempty"`
+
+	// git repository whose json files matching the path are imported as dashboards, files removed from the
+	// repository are deleted
+	// +optional
+	GitRepository *GitRepositorySource `json:"gitRepository,omitempty"`
+
+	// how often the objects of the bucket or the ref of the repository are listed, defaults to 5m
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// name of a GrafanaFolder in the same namespace to import the dashboards into
+	// +optional
+	FolderRef string `json:"folderRef,omitempty"`
+
+	// datasources replacing the inputs of the dashboards
+	// +optional
+	Datasources []GrafanaDashboardDatasource `json:"datasources,omitempty"`
+
+	// variables substituted into the json of the dashboards
+	// +optional
+	Envs []GrafanaDashboardEnv `json:"envs,omitempty"`
+
+	// ConfigMaps and Secrets whose keys are substituted like envs
+	// +optional
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// derives the plugins of the dashboards from their panels and datasources
+	// +optional
+	DerivePlugins bool `json:"derivePlugins,omitempty"`
+
+	// how long the content of objects is used before it is downloaded again, defaults to 5m
+	// +optional
+	ContentCacheDuration *metav1.Duration `json:"contentCacheDuration,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// allows the import into selected Grafanas of other namespaces that allow cross namespace imports, when
+	// enabled for the operator
+	// +optional
+	AllowCrossNamespaceImport bool `json:"allowCrossNamespaceImport,omitempty"`
+
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaDashboardFolderItem is a dashboard of the folder
+type GrafanaDashboardFolderItem struct {
+	// key of the ConfigMap or bucket, or path in the repository
+	Key string `json:"key"`
+
+	// name of the GrafanaDashboard created for the key
+	Name string `json:"name"`
+
+	// last message of the dashboard, empty if it was imported into all matching instances
+	// +optional
+	LastMessage string `json:"lastMessage,omitempty"`
+}
+
+// GrafanaDashboardFolderStatus defines the observed state of GrafanaDashboardFolder
+type GrafanaDashboardFolderStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// time the keys of the source were last listed
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// +optional
+	Dashboards []GrafanaDashboardFolderItem `json:"dashboards,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Folder",type=string,JSONPath=`.spec.folderRef`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaDashboardFolder is the Schema for the grafanadashboardfolders API. Every key of the source is
+// imported through a GrafanaDashboard owned by the folder, dashboards of removed keys are deleted.
+type GrafanaDashboardFolder struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaDashboardFolderSpec   `json:"spec,omitempty"`
+	Status GrafanaDashboardFolderStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaDashboardFolderList contains a list of GrafanaDashboardFolder
+type GrafanaDashboardFolderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaDashboardFolder `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaDashboardFolder{}, &GrafanaDashboardFolderList{})
+}
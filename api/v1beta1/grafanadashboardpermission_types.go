@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaDashboardPermissionSpec defines the desired state of GrafanaDashboardPermission
+type GrafanaDashboardPermissionSpec struct {
+	// name of a GrafanaDashboard in the same namespace
+	// +optional
+	DashboardRef string `json:"dashboardRef,omitempty"`
+
+	// uid of a dashboard not managed by the operator, ignored when dashboardRef is set
+	// +optional
+	DashboardUID string `json:"dashboardUid,omitempty"`
+
+	// the complete set of permissions, permissions not listed are removed. The permissions are left untouched
+	// when the cr is deleted.
+	Permissions []GrafanaPermissionItem `json:"permissions"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaDashboardPermissionStatus defines the observed state of GrafanaDashboardPermission
+type GrafanaDashboardPermissionStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaDashboardPermission is the Schema for the grafanadashboardpermissions API
+type GrafanaDashboardPermission struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaDashboardPermissionSpec   `json:"spec,omitempty"`
+	Status GrafanaDashboardPermissionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaDashboardPermissionList contains a list of GrafanaDashboardPermission
+type GrafanaDashboardPermissionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaDashboardPermission `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaDashboardPermission{}, &GrafanaDashboardPermissionList{})
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaDatasourceInternal is the datasource as defined by the Grafana api
+type GrafanaDatasourceInternal struct {
+	// datasource uid, ignored when the uid of the spec is set
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// datasource name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	Type string `json:"type"`
+
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// +kubebuilder:validation:Enum=proxy;direct
+	// +optional
+	Access string `json:"access,omitempty"`
+
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// +optional
+	IsDefault *bool `json:"isDefault,omitempty"`
+
+	// +optional
+	BasicAuth *bool `json:"basicAuth,omitempty"`
+
+	// +optional
+	BasicAuthUser string `json:"basicAuthUser,omitempty"`
+
+	// +optional
+	Editable *bool `json:"editable,omitempty"`
+
+	// +optional
+	JSONData *apiextensionsv1.JSON `json:"jsonData,omitempty"`
+}
+
+// DatasourceReference references a datasource by cr name or uid
+type DatasourceReference struct {
+	// name of a GrafanaDatasource in the same namespace
+	// +optional
+	DatasourceRef string `json:"datasourceRef,omitempty"`
+
+	// uid of a datasource not managed by the operator, ignored when datasourceRef is set
+	// +optional
+	DatasourceUID string `json:"datasourceUid,omitempty"`
+}
+
+// GrafanaDatasourceHttpHeader is a header sent with the requests of a datasource, the value is read from a
+// Secret or ConfigMap when valueFrom is set
+type GrafanaDatasourceHttpHeader struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +optional
+	Value string `json:"value,omitempty"`
+	// +optional
+	ValueFrom *ValueFromSource `json:"valueFrom,omitempty"`
+}
+
+// GrafanaDatasourceSpec defines the desired state of GrafanaDatasource
+type GrafanaDatasourceSpec struct {
+	// uid of the datasource in all instances, defaults to the uid of the datasource or of the cr. Dashboards
+	// reference datasources by uid, a fixed uid survives recreating the cr.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]{1,40}$`
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	Datasource *GrafanaDatasourceInternal `json:"datasource"`
+
+	// inject values from secrets or config maps, target paths are relative to the datasource, e.g.
+	// secureJsonData.password or jsonData.tlsAuth. Datasources are updated when the values change.
+	// +optional
+	ValuesFrom []ValueFrom `json:"valuesFrom,omitempty"`
+
+	// headers sent with the requests of the datasource, e.g. X-Scope-OrgID for multi-tenant backends. Names
+	// are set as jsonData.httpHeaderName<n> and values as secureJsonData.httpHeaderValue<n>.
+	// +optional
+	HttpHeaders []GrafanaDatasourceHttpHeader `json:"httpHeaders,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// allows the import into selected Grafanas of other namespaces that allow cross namespace imports, when
+	// enabled for the operator
+	// +optional
+	AllowCrossNamespaceImport bool `json:"allowCrossNamespaceImport,omitempty"`
+
+	// plugins
+	Plugins PluginList `json:"plugins,omitempty"`
+
+	// version of the plugin of the datasource type, exact or a range. Types not bundled with Grafana are
+	// installed in the latest version unless the plugins list them.
+	// +optional
+	PluginVersion string `json:"pluginVersion,omitempty"`
+
+	// treatment of a datasource with the same name that exists in Grafana before it is imported, defaults to overwrite
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// treatment of the datasource in Grafana when the cr is deleted, defaults to Delete. Datasources carry no
+	// markers of the operator, Orphan and Retain both keep them unchanged.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	ResyncPolicy `json:",inline"`
+
+	// reports the changes the datasource would make in the status instead of importing it, the datasource
+	// in Grafana is neither changed nor deleted. Fields omitted from the cr and secure json data are not
+	// compared.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaDatasourceHealth is the result of the health check of a datasource in an instance
+type GrafanaDatasourceHealth struct {
+	// namespace and name of the Grafana instance
+	Instance string `json:"instance"`
+
+	// OK, ERROR or UNKNOWN for datasources without health check
+	Status string `json:"status"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// checks are repeated after the resync period
+	LastChecked metav1.Time `json:"lastChecked"`
+}
+
+// GrafanaDatasourceStatus defines the observed state of GrafanaDatasource
+type GrafanaDatasourceStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	SyncRetryStatus `json:",inline"`
+
+	ReconcileRequestStatus `json:",inline"`
+
+	// plugin of the datasource type that is installed in addition to the plugins of the spec
+	// +optional
+	DerivedPlugins PluginList `json:"derivedPlugins,omitempty"`
+
+	// changes the datasource would make in the matching instances, set in dry run
+	// +optional
+	DryRun []DryRunResult `json:"dryRun,omitempty"`
+
+	// health checks of the datasource in the matching instances
+	// +optional
+	Health []GrafanaDatasourceHealth `json:"health,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// generation of the cr last imported into all instances
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// uid the datasource is imported with, a uid is claimed by the first datasource importing it into an
+	// instance and organization. Datasources imported with a previous uid are updated to the new uid.
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// datasources that existed in Grafana before they were adopted
+	// +optional
+	Adopted []AdoptedResource `json:"adopted,omitempty"`
+
+	// results of importing the datasource into the matching instances
+	// +optional
+	Instances []InstanceStatus `json:"instances,omitempty"`
+
+	// number of instances matching the instance selector
+	// +optional
+	MatchedInstances int `json:"matchedInstances,omitempty"`
+
+	// time the datasource was last imported into one of the instances
+	// +optional
+	LastSynced *metav1.Time `json:"lastSynced,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="UID",type=string,JSONPath=`.status.uid`
+//+kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.status.matchedInstances`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSynced`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaDatasource is the Schema for the grafanadatasources API
+type GrafanaDatasource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaDatasourceSpec   `json:"spec,omitempty"`
+	Status GrafanaDatasourceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaDatasourceList contains a list of GrafanaDatasource
+type GrafanaDatasourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaDatasource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaDatasource{}, &GrafanaDatasourceList{})
+}
+
+// ReconcileRequested returns true if the reconcile annotation requests a resync that wasn't handled yet
+func (in *GrafanaDatasource) ReconcileRequested() bool {
+	return reconcileRequested(in, in.Status.ReconcileRequestStatus)
+}
+
+// DatasourceName returns the name of the datasource in Grafana
+func (in *GrafanaDatasource) DatasourceName() string {
+	if in.Spec.Datasource != nil && in.Spec.Datasource.Name != "" {
+		return in.Spec.Datasource.Name
+	}
+	return in.Name
+}
+
+// DatasourceUID returns the uid of the datasource in Grafana
+func (in *GrafanaDatasource) DatasourceUID() string {
+	if in.Spec.UID != "" {
+		return in.Spec.UID
+	}
+	if in.Spec.Datasource != nil && in.Spec.Datasource.UID != "" {
+		return in.Spec.Datasource.UID
+	}
+	return string(in.UID)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=Query;Edit
+type DatasourcePermission string
+
+const (
+	DatasourcePermissionQuery DatasourcePermission = "Query"
+	DatasourcePermissionEdit  DatasourcePermission = "Edit"
+)
+
+// GrafanaDatasourcePermissionItem grants access to a datasource to a team or a user
+type GrafanaDatasourcePermissionItem struct {
+	// team by name
+	// +optional
+	Team string `json:"team,omitempty"`
+
+	// user by login or email
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// +kubebuilder:default=Query
+	// +optional
+	Permission DatasourcePermission `json:"permission,omitempty"`
+}
+
+// GrafanaDatasourcePermissionSpec defines the desired state of GrafanaDatasourcePermission
+type GrafanaDatasourcePermissionSpec struct {
+	DatasourceReference `json:",inline"`
+
+	// the complete access list, entries not listed are removed. Once permissions are enabled only the
+	// listed teams and users can query the datasource. The permissions are left untouched when the cr is
+	// deleted.
+	Permissions []GrafanaDatasourcePermissionItem `json:"permissions"`
+
+	// selects Grafanas for import, requires Grafana Enterprise
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaDatasourcePermissionStatus defines the observed state of GrafanaDatasourcePermission
+type GrafanaDatasourcePermissionStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaDatasourcePermission is the Schema for the grafanadatasourcepermissions API
+type GrafanaDatasourcePermission struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaDatasourcePermissionSpec   `json:"spec,omitempty"`
+	Status GrafanaDatasourcePermissionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaDatasourcePermissionList contains a list of GrafanaDatasourcePermission
+type GrafanaDatasourcePermissionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaDatasourcePermission `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaDatasourcePermission{}, &GrafanaDatasourcePermissionList{})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=View;Edit;Admin
+type GrafanaPermission string
+
+const (
+	GrafanaPermissionView  GrafanaPermission = "View"
+	GrafanaPermissionEdit  GrafanaPermission = "Edit"
+	GrafanaPermissionAdmin GrafanaPermission = "Admin"
+)
+
+// GrafanaPermissionItem grants a permission to a role, a team or a user
+type GrafanaPermissionItem struct {
+	// +kubebuilder:validation:Enum=Viewer;Editor
+	// +optional
+	Role string `json:"role,omitempty"`
+	// +optional
+	TeamId *int64 `json:"teamId,omitempty"`
+	// +optional
+	UserId *int64 `json:"userId,omitempty"`
+
+	Permission GrafanaPermission `json:"permission"`
+}
+
+// GrafanaFolderSpec defines the desired state of GrafanaFolder
+type GrafanaFolderSpec struct {
+	// folder title, defaults to the name of the cr
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// folder uid, defaults to the uid of the cr
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// folder permissions, the permissions in grafana are left untouched when empty
+	// +optional
+	Permissions []GrafanaPermissionItem `json:"permissions,omitempty"`
+
+	// deletes the dashboards of the folder that are not imported by the operator, imported dashboards are
+	// tagged with grafana-operator
+	// +optional
+	Prune bool `json:"prune,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// allows the import into selected Grafanas of other namespaces that allow cross namespace imports, when
+	// enabled for the operator
+	// +optional
+	AllowCrossNamespaceImport bool `json:"allowCrossNamespaceImport,omitempty"`
+
+	// organization the folder is created in, dashboards and library panels referencing it have to be
+	// imported into the same organization
+	OrgReference `json:",inline"`
+
+	ResyncPolicy `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaFolderStatus defines the observed state of GrafanaFolder
+type GrafanaFolderStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	SyncRetryStatus `json:",inline"`
+
+	ReconcileRequestStatus `json:",inline"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// generation of the cr last imported into all instances
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// results of importing the folder into the matching instances
+	// +optional
+	Instances []InstanceStatus `json:"instances,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaFolder is the Schema for the grafanafolders API
+type GrafanaFolder struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaFolderSpec   `json:"spec,omitempty"`
+	Status GrafanaFolderStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaFolderList contains a list of GrafanaFolder
+type GrafanaFolderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaFolder `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaFolder{}, &GrafanaFolderList{})
+}
+
+// ReconcileRequested returns true if the reconcile annotation requests a resync that wasn't handled yet
+func (in *GrafanaFolder) ReconcileRequested() bool {
+	return reconcileRequested(in, in.Status.ReconcileRequestStatus)
+}
+
+// FolderTitle returns the title of the folder in Grafana
+func (in *GrafanaFolder) FolderTitle() string {
+	if in.Spec.Title != "" {
+		return in.Spec.Title
+	}
+	return in.Name
+}
+
+// FolderUID returns the uid of the folder in Grafana
+func (in *GrafanaFolder) FolderUID() string {
+	if in.Spec.UID != "" {
+		return in.Spec.UID
+	}
+	return string(in.UID)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaFolderPermissionSpec defines the desired state of GrafanaFolderPermission
+type GrafanaFolderPermissionSpec struct {
+	// name of a GrafanaFolder in the same namespace
+	// +optional
+	FolderRef string `json:"folderRef,omitempty"`
+
+	// uid of a folder not managed by the operator, ignored when folderRef is set
+	// +optional
+	FolderUID string `json:"folderUid,omitempty"`
+
+	// the complete set of permissions, permissions not listed are removed. The permissions are left untouched
+	// when the cr is deleted.
+	Permissions []GrafanaPermissionItem `json:"permissions"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// organization of the folder, has to match the organization of the GrafanaFolder referenced by folderRef
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaFolderPermissionStatus defines the observed state of GrafanaFolderPermission
+type GrafanaFolderPermissionStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Folder",type=string,JSONPath=`.spec.folderRef`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaFolderPermission is the Schema for the grafanafolderpermissions API
+type GrafanaFolderPermission struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaFolderPermissionSpec   `json:"spec,omitempty"`
+	Status GrafanaFolderPermissionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaFolderPermissionList contains a list of GrafanaFolderPermission
+type GrafanaFolderPermissionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaFolderPermission `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaFolderPermission{}, &GrafanaFolderPermissionList{})
+}
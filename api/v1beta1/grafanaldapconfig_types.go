@@ -0,0 +1,151 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LDAPAttributes maps ldap attributes to Grafana user properties
+type LDAPAttributes struct {
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Surname string `json:"surname,omitempty"`
+	// +optional
+	Username string `json:"username,omitempty"`
+	// +optional
+	MemberOf string `json:"memberOf,omitempty"`
+	// +optional
+	Email string `json:"email,omitempty"`
+}
+
+// LDAPGroupMapping assigns an organization role to the members of an ldap group
+type LDAPGroupMapping struct {
+	// distinguished name of the group, * matches all users
+	GroupDN string `json:"groupDn"`
+
+	OrgRole OrgRole `json:"orgRole"`
+
+	// +optional
+	GrafanaAdmin *bool `json:"grafanaAdmin,omitempty"`
+
+	// id of the organization, defaults to the main organization
+	// +optional
+	OrgID *int64 `json:"orgId,omitempty"`
+}
+
+// LDAPServer describes a single ldap server
+type LDAPServer struct {
+	Host string `json:"host"`
+
+	// +kubebuilder:default=389
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// +optional
+	UseSSL bool `json:"useSSL,omitempty"`
+
+	// +optional
+	StartTLS bool `json:"startTLS,omitempty"`
+
+	// +optional
+	SSLSkipVerify bool `json:"sslSkipVerify,omitempty"`
+
+	// path to a ca certificate in the Grafana container
+	// +optional
+	RootCACert string `json:"rootCACert,omitempty"`
+
+	// +optional
+	BindDN string `json:"bindDn,omitempty"`
+
+	// secret in the namespace of the cr containing the bind password
+	// +optional
+	BindPasswordSecretRef *v1.SecretKeySelector `json:"bindPasswordSecretRef,omitempty"`
+
+	// +kubebuilder:default="(cn=%s)"
+	// +optional
+	SearchFilter string `json:"searchFilter,omitempty"`
+
+	SearchBaseDNs []string `json:"searchBaseDns"`
+
+	// +optional
+	GroupSearchFilter string `json:"groupSearchFilter,omitempty"`
+
+	// +optional
+	GroupSearchBaseDNs []string `json:"groupSearchBaseDns,omitempty"`
+
+	// +optional
+	Attributes *LDAPAttributes `json:"attributes,omitempty"`
+
+	// +optional
+	GroupMappings []LDAPGroupMapping `json:"groupMappings,omitempty"`
+}
+
+// GrafanaLDAPConfigSpec defines the desired state of GrafanaLDAPConfig
+type GrafanaLDAPConfigSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Servers []LDAPServer `json:"servers"`
+
+	// create Grafana users on their first ldap login
+	// +optional
+	AllowSignUp *bool `json:"allowSignUp,omitempty"`
+
+	// selects Grafanas to configure
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaLDAPConfigStatus defines the observed state of GrafanaLDAPConfig
+type GrafanaLDAPConfigStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaLDAPConfig is the Schema for the grafanaldapconfigs API
+type GrafanaLDAPConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaLDAPConfigSpec   `json:"spec,omitempty"`
+	Status GrafanaLDAPConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaLDAPConfigList contains a list of GrafanaLDAPConfig
+type GrafanaLDAPConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaLDAPConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaLDAPConfig{}, &GrafanaLDAPConfigList{})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaLibraryPanelSpec defines the desired state of GrafanaLibraryPanel
+type GrafanaLibraryPanelSpec struct {
+	// library panel uid, defaults to the uid of the cr. Dashboards reference library panels by uid.
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// library panel name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// panel json
+	// +optional
+	Json string `json:"json,omitempty"`
+
+	// file of an OCI artifact the panel json is pulled from on each reconcile, it is used when json is empty
+	// +optional
+	OCI *OCIArtifactSource `json:"oci,omitempty"`
+
+	// name of a GrafanaFolder in the same namespace to store the library panel in
+	// +optional
+	FolderRef string `json:"folderRef,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// allows the import into selected Grafanas of other namespaces that allow cross namespace imports, when
+	// enabled for the operator
+	// +optional
+	AllowCrossNamespaceImport bool `json:"allowCrossNamespaceImport,omitempty"`
+
+	// organization the library panel is imported into, dashboards referencing it have to be imported into
+	// the same organization
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaLibraryPanelStatus defines the observed state of GrafanaLibraryPanel
+type GrafanaLibraryPanelStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// results of importing the library panel into the matching instances
+	// +optional
+	Instances []InstanceStatus `json:"instances,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Folder",type=string,JSONPath=`.spec.folderRef`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaLibraryPanel is the Schema for the grafanalibrarypanels API
+type GrafanaLibraryPanel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaLibraryPanelSpec   `json:"spec,omitempty"`
+	Status GrafanaLibraryPanelStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaLibraryPanelList contains a list of GrafanaLibraryPanel
+type GrafanaLibraryPanelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaLibraryPanel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaLibraryPanel{}, &GrafanaLibraryPanelList{})
+}
+
+// LibraryPanelName returns the name of the library panel in Grafana
+func (in *GrafanaLibraryPanel) LibraryPanelName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
+
+// LibraryPanelUID returns the uid of the library panel in Grafana
+func (in *GrafanaLibraryPanel) LibraryPanelUID() string {
+	if in.Spec.UID != "" {
+		return in.Spec.UID
+	}
+	return string(in.UID)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TimeRange is a range of time within a day
+type TimeRange struct {
+	// start time in 24h format, e.g. 08:00
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+	// end time in 24h format, e.g. 17:00, 24:00 marks the end of the day
+	// +kubebuilder:validation:Pattern=`^(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$`
+	EndTime string `json:"endTime"`
+}
+
+// TimeInterval describes when a mute timing is active, all fields are optional and empty fields match any time
+type TimeInterval struct {
+	// +optional
+	Times []TimeRange `json:"times,omitempty"`
+	// weekdays or ranges of weekdays, e.g. monday or monday:friday
+	// +optional
+	Weekdays []string `json:"weekdays,omitempty"`
+	// days or ranges of days of the month, e.g. 1, 1:5 or -1 for the last day of the month
+	// +optional
+	DaysOfMonth []string `json:"daysOfMonth,omitempty"`
+	// months or ranges of months, e.g. january, 1:3 or june:august
+	// +optional
+	Months []string `json:"months,omitempty"`
+	// years or ranges of years, e.g. 2022 or 2022:2024
+	// +optional
+	Years []string `json:"years,omitempty"`
+	// time zone of the interval, e.g. Europe/Stockholm, defaults to UTC
+	// +optional
+	Location string `json:"location,omitempty"`
+}
+
+// GrafanaMuteTimingSpec defines the desired state of GrafanaMuteTiming
+type GrafanaMuteTimingSpec struct {
+	// mute timing name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	TimeIntervals []TimeInterval `json:"timeIntervals"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaMuteTimingStatus defines the observed state of GrafanaMuteTiming
+type GrafanaMuteTimingStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaMuteTiming is the Schema for the grafanamutetimings API
+type GrafanaMuteTiming struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaMuteTimingSpec   `json:"spec,omitempty"`
+	Status GrafanaMuteTimingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaMuteTimingList contains a list of GrafanaMuteTiming
+type GrafanaMuteTimingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaMuteTiming `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaMuteTiming{}, &GrafanaMuteTimingList{})
+}
+
+// MuteTimingName returns the name of the mute timing in Grafana
+func (in *GrafanaMuteTiming) MuteTimingName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
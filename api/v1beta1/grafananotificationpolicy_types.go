@@ -0,0 +1,122 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum="=";"!=";"=~";"!~"
+type MatchType string
+
+// ObjectMatcher matches alert labels
+type ObjectMatcher struct {
+	Name  string    `json:"name"`
+	Type  MatchType `json:"type"`
+	Value string    `json:"value"`
+}
+
+// NotificationPolicySettings are the grouping and timing settings of a route
+type NotificationPolicySettings struct {
+	// +optional
+	Receiver string `json:"receiver,omitempty"`
+	// +optional
+	GroupBy []string `json:"groupBy,omitempty"`
+	// +optional
+	GroupWait string `json:"groupWait,omitempty"`
+	// +optional
+	GroupInterval string `json:"groupInterval,omitempty"`
+	// +optional
+	RepeatInterval string `json:"repeatInterval,omitempty"`
+}
+
+// NotificationPolicyRoute is a route in the notification policy tree
+type NotificationPolicyRoute struct {
+	NotificationPolicySettings `json:",inline"`
+
+	// +optional
+	ObjectMatchers []ObjectMatcher `json:"objectMatchers,omitempty"`
+	// +optional
+	MuteTimeIntervals []string `json:"muteTimeIntervals,omitempty"`
+	// +optional
+	Continue bool `json:"continue,omitempty"`
+
+	// nested routes, same structure as this route
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Routes []NotificationPolicyRoute `json:"routes,omitempty"`
+}
+
+// GrafanaNotificationPolicySpec defines the desired state of GrafanaNotificationPolicy
+type GrafanaNotificationPolicySpec struct {
+	// settings of the root policy, only one policy per instance may set them. The default root settings of
+	// Grafana are restored when no policy sets them.
+	// +optional
+	Root *NotificationPolicySettings `json:"root,omitempty"`
+
+	// routes added to the top level of the policy tree
+	// +optional
+	Routes []NotificationPolicyRoute `json:"routes,omitempty"`
+
+	// policies selecting the same instance are merged in ascending order of priority, then by namespace and name
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaNotificationPolicyStatus defines the observed state of GrafanaNotificationPolicy
+type GrafanaNotificationPolicyStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaNotificationPolicy is the Schema for the grafananotificationpolicies API
+type GrafanaNotificationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaNotificationPolicySpec   `json:"spec,omitempty"`
+	Status GrafanaNotificationPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaNotificationPolicyList contains a list of GrafanaNotificationPolicy
+type GrafanaNotificationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaNotificationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaNotificationPolicy{}, &GrafanaNotificationPolicyList{})
+}
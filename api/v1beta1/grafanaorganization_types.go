@@ -0,0 +1,91 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OrgReference places a resource in an organization, resources without a reference are provisioned to the
+// main organization
+type OrgReference struct {
+	// name of a GrafanaOrganization in the same namespace
+	// +optional
+	OrgRef string `json:"orgRef,omitempty"`
+
+	// id of an existing organization, ignored when orgRef is set
+	// +optional
+	OrgID *int64 `json:"orgId,omitempty"`
+}
+
+// GrafanaOrganizationSpec defines the desired state of GrafanaOrganization
+type GrafanaOrganizationSpec struct {
+	// organization name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaOrganizationStatus defines the observed state of GrafanaOrganization
+type GrafanaOrganizationStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaOrganization is the Schema for the grafanaorganizations API
+type GrafanaOrganization struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaOrganizationSpec   `json:"spec,omitempty"`
+	Status GrafanaOrganizationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaOrganizationList contains a list of GrafanaOrganization
+type GrafanaOrganizationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaOrganization `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaOrganization{}, &GrafanaOrganizationList{})
+}
+
+// OrgName returns the name of the organization in Grafana
+func (in *GrafanaOrganization) OrgName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
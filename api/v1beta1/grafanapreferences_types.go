@@ -0,0 +1,90 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaPreferencesSpec defines the desired state of GrafanaPreferences
+type GrafanaPreferencesSpec struct {
+	// name of a GrafanaDashboard in the same namespace used as home dashboard of the organization
+	// +optional
+	HomeDashboardRef string `json:"homeDashboardRef,omitempty"`
+
+	// uid of a home dashboard not managed by the operator, ignored when homeDashboardRef is set
+	// +optional
+	HomeDashboardUID string `json:"homeDashboardUid,omitempty"`
+
+	// +kubebuilder:validation:Enum=light;dark;system
+	// +optional
+	Theme string `json:"theme,omitempty"`
+
+	// utc, browser or a location like Europe/Berlin
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// +kubebuilder:validation:Enum=monday;saturday;sunday
+	// +optional
+	WeekStart string `json:"weekStart,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaPreferencesStatus defines the observed state of GrafanaPreferences
+type GrafanaPreferencesStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+//+kubebuilder:resource:path=grafanapreferences
+
+// GrafanaPreferences is the Schema for the grafanapreferences API
+type GrafanaPreferences struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaPreferencesSpec   `json:"spec,omitempty"`
+	Status GrafanaPreferencesStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaPreferencesList contains a list of GrafanaPreferences
+type GrafanaPreferencesList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaPreferences `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaPreferences{}, &GrafanaPreferencesList{})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaPublicDashboardSpec defines the desired state of GrafanaPublicDashboard
+type GrafanaPublicDashboardSpec struct {
+	// name of a GrafanaDashboard in the same namespace
+	// +optional
+	DashboardRef string `json:"dashboardRef,omitempty"`
+
+	// uid of a dashboard not managed by the operator, ignored when dashboardRef is set
+	// +optional
+	DashboardUID string `json:"dashboardUid,omitempty"`
+
+	// public sharing can be paused without losing the public url, defaults to true
+	// +optional
+	IsEnabled *bool `json:"isEnabled,omitempty"`
+
+	// allow viewers to change the time range
+	// +optional
+	TimeSelectionEnabled bool `json:"timeSelectionEnabled,omitempty"`
+
+	// show annotations on the public dashboard
+	// +optional
+	AnnotationsEnabled bool `json:"annotationsEnabled,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaPublicDashboardURL is the public url of the dashboard in a Grafana instance
+type GrafanaPublicDashboardURL struct {
+	// name of the Grafana instance
+	Instance string `json:"instance"`
+
+	URL string `json:"url"`
+}
+
+// GrafanaPublicDashboardStatus defines the observed state of GrafanaPublicDashboard
+type GrafanaPublicDashboardStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	URLs []GrafanaPublicDashboardURL `json:"urls,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaPublicDashboard is the Schema for the grafanapublicdashboards API
+type GrafanaPublicDashboard struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaPublicDashboardSpec   `json:"spec,omitempty"`
+	Status GrafanaPublicDashboardStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaPublicDashboardList contains a list of GrafanaPublicDashboard
+type GrafanaPublicDashboardList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaPublicDashboard `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaPublicDashboard{}, &GrafanaPublicDashboardList{})
+}
+
+// SharingEnabled returns true unless public sharing is paused
+func (in *GrafanaPublicDashboard) SharingEnabled() bool {
+	return in.Spec.IsEnabled == nil || *in.Spec.IsEnabled
+}
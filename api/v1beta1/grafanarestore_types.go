@@ -0,0 +1,134 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=merge;replace
+type RestoreMode string
+
+const (
+	// RestoreModeMerge creates and updates the resources of the backup and leaves all other resources untouched
+	RestoreModeMerge RestoreMode = "merge"
+	// RestoreModeReplace additionally deletes all resources missing from the backup
+	RestoreModeReplace RestoreMode = "replace"
+)
+
+// GrafanaRestoreSpec defines the desired state of GrafanaRestore
+type GrafanaRestoreSpec struct {
+	// name of a GrafanaBackup in the same namespace, its storage is used to read the tarball
+	BackupRef string `json:"backupRef"`
+
+	// pvc:// or s3:// url of the tarball, defaults to the last backup of the source instance
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// name of the Grafana instance whose last backup is restored, required if the backup contains more than
+	// one instance and no location is set
+	// +optional
+	SourceInstance string `json:"sourceInstance,omitempty"`
+
+	// +kubebuilder:default=merge
+	// +optional
+	Mode RestoreMode `json:"mode,omitempty"`
+
+	// only report the changes a restore would make
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// selects Grafanas to restore the backup into
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaRestoreInstance is the result of restoring into a Grafana instance
+type GrafanaRestoreInstance struct {
+	// name of the Grafana instance
+	Instance string `json:"instance"`
+
+	Time metav1.Time `json:"time"`
+
+	// changes made, or the changes that would be made in a dry run
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+}
+
+// GrafanaRestoreStatus defines the observed state of GrafanaRestore
+type GrafanaRestoreStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// generation of the cr the restore ran for, a restore runs once per generation
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// location of the restored tarball
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// true once the backup was restored into all selected instances
+	// +optional
+	Completed bool `json:"completed,omitempty"`
+
+	// +optional
+	Instances []GrafanaRestoreInstance `json:"instances,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaRestore is the Schema for the grafanarestores API
+type GrafanaRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaRestoreSpec   `json:"spec,omitempty"`
+	Status GrafanaRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaRestoreList contains a list of GrafanaRestore
+type GrafanaRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaRestore{}, &GrafanaRestoreList{})
+}
+
+// GetInstance returns the restore result of an instance
+func (in *GrafanaRestoreStatus) GetInstance(instance string) *GrafanaRestoreInstance {
+	for i := range in.Instances {
+		if in.Instances[i].Instance == instance {
+			return &in.Instances[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaRolePermission allows an action on a scope
+type GrafanaRolePermission struct {
+	// e.g. dashboards:read
+	Action string `json:"action"`
+
+	// e.g. dashboards:uid:abc, empty for actions without a scope
+	// +optional
+	Scope string `json:"scope,omitempty"`
+}
+
+// GrafanaRoleSpec defines the desired state of GrafanaRole
+type GrafanaRoleSpec struct {
+	// role uid, defaults to the uid of the cr
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// role name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// group the role is listed under in the role picker
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// permissions granted by the role, permissions not in the list are removed from the role
+	// +optional
+	Permissions []GrafanaRolePermission `json:"permissions,omitempty"`
+
+	// selects Grafanas for import, requires Grafana Enterprise
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaRoleStatus defines the observed state of GrafanaRole
+type GrafanaRoleStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaRole is the Schema for the grafanaroles API
+type GrafanaRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaRoleSpec   `json:"spec,omitempty"`
+	Status GrafanaRoleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaRoleList contains a list of GrafanaRole
+type GrafanaRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaRole{}, &GrafanaRoleList{})
+}
+
+// RoleName returns the name of the role in Grafana
+func (in *GrafanaRole) RoleName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
+
+// RoleUID returns the uid of the role in Grafana
+func (in *GrafanaRole) RoleUID() string {
+	if in.Spec.UID != "" {
+		return in.Spec.UID
+	}
+	return string(in.UID)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaRoleSubjects lists the users, teams and service accounts a role is assigned to
+type GrafanaRoleSubjects struct {
+	// users by login or email
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// teams by name
+	// +optional
+	Teams []string `json:"teams,omitempty"`
+
+	// service accounts by name
+	// +optional
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+}
+
+// GrafanaRoleBindingSpec defines the desired state of GrafanaRoleBinding
+type GrafanaRoleBindingSpec struct {
+	// name of a GrafanaRole in the same namespace
+	// +optional
+	RoleRef string `json:"roleRef,omitempty"`
+
+	// uid of a role not managed by the operator, e.g. a fixed role, ignored when roleRef is set
+	// +optional
+	RoleUID string `json:"roleUid,omitempty"`
+
+	// subjects the role is assigned to, subjects removed from the list lose the role
+	GrafanaRoleSubjects `json:",inline"`
+
+	// selects Grafanas for import, requires Grafana Enterprise
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	OrgReference `json:",inline"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaRoleBindingStatus defines the observed state of GrafanaRoleBinding
+type GrafanaRoleBindingStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// subjects the role was assigned to on the last successful reconcile
+	// +optional
+	Bound GrafanaRoleSubjects `json:"bound,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaRoleBinding is the Schema for the grafanarolebindings API
+type GrafanaRoleBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaRoleBindingSpec   `json:"spec,omitempty"`
+	Status GrafanaRoleBindingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaRoleBindingList contains a list of GrafanaRoleBinding
+type GrafanaRoleBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaRoleBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaRoleBinding{}, &GrafanaRoleBindingList{})
+}
+
+// RemovedSubjects returns the bound subjects that are no longer part of the spec
+func (in *GrafanaRoleBinding) RemovedSubjects() GrafanaRoleSubjects {
+	return GrafanaRoleSubjects{
+		Users:           subtractStrings(in.Status.Bound.Users, in.Spec.Users),
+		Teams:           subtractStrings(in.Status.Bound.Teams, in.Spec.Teams),
+		ServiceAccounts: subtractStrings(in.Status.Bound.ServiceAccounts, in.Spec.ServiceAccounts),
+	}
+}
+
+func subtractStrings(list []string, remove []string) []string {
+	var result []string
+	for _, item := range list {
+		found := false
+		for _, other := range remove {
+			if item == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, item)
+		}
+	}
+	return result
+}
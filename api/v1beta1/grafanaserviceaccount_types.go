@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TokenRotation defines when a new service account token is minted
+type TokenRotation struct {
+	// rotate the token once it is older than the interval
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// changing the trigger to any new value rotates the tokens on demand
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+}
+
+// GrafanaServiceAccountSpec defines the desired state of GrafanaServiceAccount
+type GrafanaServiceAccountSpec struct {
+	// service account name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// role of the service account in the organization
+	// +kubebuilder:default=Viewer
+	// +optional
+	Role OrgRole `json:"role,omitempty"`
+
+	// +optional
+	IsDisabled bool `json:"isDisabled,omitempty"`
+
+	// name of the secret the tokens are written to, the secret holds a key per Grafana instance
+	TokenSecretName string `json:"tokenSecretName"`
+
+	// +optional
+	TokenRotation *TokenRotation `json:"tokenRotation,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaServiceAccountToken is the token minted in a Grafana instance
+type GrafanaServiceAccountToken struct {
+	// name of the Grafana instance
+	Instance string `json:"instance"`
+
+	// id of the token in Grafana
+	TokenID int64 `json:"tokenId"`
+
+	Created metav1.Time `json:"created"`
+
+	// rotation trigger the token was minted for
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+}
+
+// GrafanaServiceAccountStatus defines the observed state of GrafanaServiceAccount
+type GrafanaServiceAccountStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	Tokens []GrafanaServiceAccountToken `json:"tokens,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaServiceAccount is the Schema for the grafanaserviceaccounts API
+type GrafanaServiceAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaServiceAccountSpec   `json:"spec,omitempty"`
+	Status GrafanaServiceAccountStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaServiceAccountList contains a list of GrafanaServiceAccount
+type GrafanaServiceAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaServiceAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaServiceAccount{}, &GrafanaServiceAccountList{})
+}
+
+// ServiceAccountName returns the name of the service account in Grafana
+func (in *GrafanaServiceAccount) ServiceAccountName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
+
+// GetToken returns the status of the token minted in an instance
+func (in *GrafanaServiceAccountStatus) GetToken(instance string) *GrafanaServiceAccountToken {
+	for i := range in.Tokens {
+		if in.Tokens[i].Instance == instance {
+			return &in.Tokens[i]
+		}
+	}
+	return nil
+}
+
+// SetToken records the token minted in an instance
+func (in *GrafanaServiceAccountStatus) SetToken(token GrafanaServiceAccountToken) {
+	if existing := in.GetToken(token.Instance); existing != nil {
+		*existing = token
+		return
+	}
+	in.Tokens = append(in.Tokens, token)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaSnapshotSpec defines the desired state of GrafanaSnapshot
+type GrafanaSnapshotSpec struct {
+	// name of a GrafanaDashboard in the same namespace, the snapshot is taken in the organization of the
+	// dashboard
+	// +optional
+	DashboardRef string `json:"dashboardRef,omitempty"`
+
+	// uid of a dashboard in the main organization not managed by the operator, ignored when dashboardRef is set
+	// +optional
+	DashboardUID string `json:"dashboardUid,omitempty"`
+
+	// snapshot name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// time after which Grafana deletes the snapshot, snapshots without expiry are kept until the cr is
+	// deleted
+	// +optional
+	Expires *metav1.Duration `json:"expires,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaSnapshotInstance is a snapshot taken in a Grafana instance
+type GrafanaSnapshotInstance struct {
+	// name of the Grafana instance
+	Instance string `json:"instance"`
+
+	// key of the snapshot in Grafana
+	Key string `json:"key"`
+
+	URL string `json:"url"`
+
+	// +optional
+	Expires *metav1.Time `json:"expires,omitempty"`
+}
+
+// GrafanaSnapshotStatus defines the observed state of GrafanaSnapshot
+type GrafanaSnapshotStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	Snapshots []GrafanaSnapshotInstance `json:"snapshots,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaSnapshot is the Schema for the grafanasnapshots API
+type GrafanaSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaSnapshotSpec   `json:"spec,omitempty"`
+	Status GrafanaSnapshotStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaSnapshotList contains a list of GrafanaSnapshot
+type GrafanaSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaSnapshot{}, &GrafanaSnapshotList{})
+}
+
+// SnapshotName returns the name of the snapshot in Grafana
+func (in *GrafanaSnapshot) SnapshotName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
+
+// GetSnapshot returns the snapshot taken in an instance, nil if it wasn't taken yet
+func (in *GrafanaSnapshotStatus) GetSnapshot(instance string) *GrafanaSnapshotInstance {
+	for i := range in.Snapshots {
+		if in.Snapshots[i].Instance == instance {
+			return &in.Snapshots[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaTeamSpec defines the desired state of GrafanaTeam
+type GrafanaTeamSpec struct {
+	// team name, defaults to the name of the cr
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// +optional
+	Email string `json:"email,omitempty"`
+
+	// team members by login or email, members not in the list are removed from the team
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// groups of the identity provider synced to the team, requires Grafana Enterprise
+	// +optional
+	ExternalGroups []string `json:"externalGroups,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaTeamStatus defines the observed state of GrafanaTeam
+type GrafanaTeamStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaTeam is the Schema for the grafanateams API
+type GrafanaTeam struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaTeamSpec   `json:"spec,omitempty"`
+	Status GrafanaTeamStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaTeamList contains a list of GrafanaTeam
+type GrafanaTeamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaTeam `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaTeam{}, &GrafanaTeamList{})
+}
+
+// TeamName returns the name of the team in Grafana
+func (in *GrafanaTeam) TeamName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=Viewer;Editor;Admin
+type OrgRole string
+
+const (
+	OrgRoleViewer OrgRole = "Viewer"
+	OrgRoleEditor OrgRole = "Editor"
+	OrgRoleAdmin  OrgRole = "Admin"
+)
+
+// GrafanaUserOrg is the membership of a user in an organization
+type GrafanaUserOrg struct {
+	// name of the organization
+	Name string  `json:"name"`
+	Role OrgRole `json:"role"`
+}
+
+// GrafanaUserSpec defines the desired state of GrafanaUser
+type GrafanaUserSpec struct {
+	// login of the user, defaults to the name of the cr
+	// +optional
+	Login string `json:"login,omitempty"`
+
+	// +optional
+	Email string `json:"email,omitempty"`
+
+	// display name of the user
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// secret key holding the password, the password is rotated in Grafana when the secret changes
+	PasswordSecretRef *v1.SecretKeySelector `json:"passwordSecretRef"`
+
+	// role in the main organization
+	// +kubebuilder:default=Viewer
+	// +optional
+	Role OrgRole `json:"role,omitempty"`
+
+	// memberships in other organizations, the user is removed from organizations not listed here
+	// +optional
+	Orgs []GrafanaUserOrg `json:"orgs,omitempty"`
+
+	// +optional
+	IsGrafanaAdmin bool `json:"isGrafanaAdmin,omitempty"`
+
+	// selects Grafanas for import
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+
+	// pauses the reconciliation of the cr, changes are applied once it is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GrafanaUserStatus defines the observed state of GrafanaUser
+type GrafanaUserStatus struct {
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// uid, resource version and key of the password secret applied to all matching instances
+	PasswordSecretVersion string `json:"passwordSecretVersion,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.lastMessage`,priority=1
+
+// GrafanaUser is the Schema for the grafanausers API
+type GrafanaUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaUserSpec   `json:"spec,omitempty"`
+	Status GrafanaUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaUserList contains a list of GrafanaUser
+type GrafanaUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaUser{}, &GrafanaUserList{})
+}
+
+// UserLogin returns the login of the user in Grafana
+func (in *GrafanaUser) UserLogin() string {
+	if in.Spec.Login != "" {
+		return in.Spec.Login
+	}
+	return in.Name
+}
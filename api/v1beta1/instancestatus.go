@@ -0,0 +1,41 @@
+package v1beta1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// InstanceStatus is the result of applying a cr to one of its matching Grafana instances
+type InstanceStatus struct {
+	// namespace and name of the Grafana instance
+	Instance string `json:"instance"`
+
+	// uid the object was last applied with
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// time the object was last applied, applies are repeated after the resync period
+	// +optional
+	LastApplied *metav1.Time `json:"lastApplied,omitempty"`
+
+	// error of the last apply, empty if it succeeded
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// SyncRetryStatus reports failed attempts to apply a cr to its instances, the operator retries with
+// exponential backoff until the cr is applied
+type SyncRetryStatus struct {
+	// error of the last failed attempt, cleared once the cr was applied to all instances
+	// +optional
+	LastSyncError string `json:"lastSyncError,omitempty"`
+
+	// time of the last failed attempt
+	// +optional
+	LastSyncAttempt *metav1.Time `json:"lastSyncAttempt,omitempty"`
+
+	// number of failed attempts since the cr was last applied
+	// +optional
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+
+	// time of the next attempt, changes of the spec are applied right away
+	// +optional
+	NextRetry *metav1.Time `json:"nextRetry,omitempty"`
+}
@@ -0,0 +1,49 @@
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// OCIArtifactSource is a file of an OCI artifact, e.g. pushed with oras. Layers are verified against the
+// digests of the manifest, pinning the digest of the manifest verifies the whole artifact.
+type OCIArtifactSource struct {
+	// repository of the artifact, e.g. ghcr.io/org/dashboards. Repositories without a registry are pulled
+	// from docker hub.
+	Repository string `json:"repository"`
+
+	// tag of the artifact, defaults to latest
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// sha256 digest of the manifest, e.g. sha256:2c26b4... The tag is ignored when a digest is set.
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// file in the artifact, the title of a layer or a path in a tar layer. Artifacts with a single layer
+	// don't need a path.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// secret of type kubernetes.io/dockerconfigjson in the namespace of the cr with the registry credentials
+	// +optional
+	PullSecretRef *v1.LocalObjectReference `json:"pullSecretRef,omitempty"`
+
+	// pull over plain http, e.g. from a registry in the cluster
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// ContentUrl returns the oci:// url of the file
+func (in *OCIArtifactSource) ContentUrl() string {
+	url := "oci://" + in.Repository
+	if in.Digest != "" {
+		url += "@" + in.Digest
+	} else if in.Tag != "" {
+		url += ":" + in.Tag
+	}
+	if in.Path != "" {
+		url += "/" + in.Path
+	}
+	return url
+}
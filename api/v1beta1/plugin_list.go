@@ -5,53 +5,309 @@ import (
 	"fmt"
 	"github.com/blang/semver"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+// PluginVersionLatest requests the newest version of a plugin, it is also used for plugins without version
+const PluginVersionLatest = "latest"
+
+// PluginSignatureLevel is the minimum signature of a plugin, levels are ordered from unsigned to grafana
+// +kubebuilder:validation:Enum=unsigned;private;community;commercial;grafana
+type PluginSignatureLevel string
+
+const (
+	PluginSignatureUnsigned   PluginSignatureLevel = "unsigned"
+	PluginSignaturePrivate    PluginSignatureLevel = "private"
+	PluginSignatureCommunity  PluginSignatureLevel = "community"
+	PluginSignatureCommercial PluginSignatureLevel = "commercial"
+	PluginSignatureGrafana    PluginSignatureLevel = "grafana"
+)
+
+var pluginSignatureLevels = []PluginSignatureLevel{
+	PluginSignatureUnsigned,
+	PluginSignaturePrivate,
+	PluginSignatureCommunity,
+	PluginSignatureCommercial,
+	PluginSignatureGrafana,
+}
+
+// Rank orders signature levels, unknown levels rank below unsigned
+func (l PluginSignatureLevel) Rank() int {
+	for i, level := range pluginSignatureLevels {
+		if level == l {
+			return i
+		}
+	}
+	return -1
+}
+
 type GrafanaPlugin struct {
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	Name string `json:"name"`
+	// exact version, semver range like >=1.2.0 <2.0.0 or 1.x, or latest. Ranges and latest are pinned to a
+	// version of the grafana.com catalog.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// sha256 checksum of the plugin archive, verified against the checksum published on grafana.com. Requires
+	// an exact version.
+	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
+	// +optional
+	Sha256 string `json:"sha256,omitempty"`
+	// minimum signature of the plugin version on grafana.com, unsigned allows Grafana to load the plugin
+	// without a signature
+	// +optional
+	SignatureLevel PluginSignatureLevel `json:"signatureLevel,omitempty"`
+	// url of the plugin archive, e.g. on an internal mirror, instead of the plugin repository. Requires an
+	// exact version, the plugin is installed through GF_INSTALL_PLUGINS.
+	// +kubebuilder:validation:Pattern=`^https?://[^,;]+$`
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// IsRange returns true if the version is a range or latest instead of an exact version
+func (p *GrafanaPlugin) IsRange() bool {
+	_, err := semver.Parse(p.Version)
+	return err != nil
+}
+
+// IsLatest returns true if the newest version of the plugin is requested
+func (p *GrafanaPlugin) IsLatest() bool {
+	return p.Version == "" || p.Version == PluginVersionLatest
+}
+
+// ParseVersionRange parses a semver range, x-ranges like 1.x or 1.2.x, partial versions like 1.2, caret and
+// tilde ranges and latest are accepted in addition to the comparisons of github.com/blang/semver
+func ParseVersionRange(version string) (semver.Range, error) {
+	if version == "" || version == PluginVersionLatest {
+		version = "*"
+	}
+	var expanded []string
+	for _, part := range strings.Fields(version) {
+		expanded = append(expanded, expandXRange(part))
+	}
+	return semver.ParseRange(strings.Join(expanded, " "))
+}
+
+// expandXRange rewrites an x-range, partial version, caret or tilde range into comparisons following the
+// rules of npm: ^ allows changes that keep the left-most non-zero component, ~ allows patch changes, or
+// minor changes if only the major version is given. Other parts are returned unchanged.
+func expandXRange(part string) string {
+	operator := part[0]
+	if operator == '^' || operator == '~' {
+		version, given, err := parsePartialVersion(part[1:])
+		if err != nil {
+			return part
+		}
+		if given == 0 {
+			return ">=0.0.0"
+		}
+		return fmt.Sprintf(">=%s <%s", version, getRangeUpperBound(operator, version, given))
+	}
+
+	if strings.ContainsAny(part[:1], "<>=!|") {
+		return part
+	}
+
+	// 1.x and 1 allow minor changes, 1.2.x and 1.2 patch changes
+	version, given, err := parsePartialVersion(part)
+	if err != nil || given == 3 {
+		return part
+	}
+	if given == 0 {
+		return ">=0.0.0"
+	}
+	return fmt.Sprintf(">=%s <%s", version, getRangeUpperBound('~', version, given))
+}
+
+// parsePartialVersion parses a version whose minor and patch version may be missing or x, and returns the
+// number of components given
+func parsePartialVersion(version string) (semver.Version, int, error) {
+	parsed, err := semver.Parse(version)
+	if err == nil {
+		return parsed, 3, nil
+	}
+
+	fields := strings.Split(version, ".")
+	if len(fields) > 3 {
+		return semver.Version{}, 0, err
+	}
+	var components [3]uint64
+	given := 0
+	for i, field := range fields {
+		if field == "x" || field == "X" || field == "*" {
+			break
+		}
+		components[i], err = strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return semver.Version{}, 0, err
+		}
+		given = i + 1
+	}
+	return semver.Version{Major: components[0], Minor: components[1], Patch: components[2]}, given, nil
+}
+
+// getRangeUpperBound returns the exclusive upper bound of a caret or tilde range of a version with the given
+// number of components
+func getRangeUpperBound(operator byte, version semver.Version, given int) semver.Version {
+	switch {
+	case given == 1:
+		return semver.Version{Major: version.Major + 1}
+	case operator == '~':
+		return semver.Version{Major: version.Major, Minor: version.Minor + 1}
+	case version.Major > 0:
+		return semver.Version{Major: version.Major + 1}
+	case version.Minor > 0 || given == 2:
+		return semver.Version{Minor: version.Minor + 1}
+	default:
+		return semver.Version{Patch: version.Patch + 1}
+	}
 }
 
 type PluginList []GrafanaPlugin
 
 type PluginMap map[string]PluginList
 
+// Hash returns the same hash for the same set of plugins regardless of their order, fields are delimited so
+// that e.g. plugin a version 1.0.0 and plugin a1 version .0.0 hash differently
 func (l PluginList) Hash() string {
-	sb := strings.Builder{}
+	// plugins are ordered by all hashed fields, so that plugins of the same name and version keep their order
+	lines := make([]string, 0, len(l))
 	for _, plugin := range l {
-		sb.WriteString(plugin.Name)
-		sb.WriteString(plugin.Version)
+		line := strings.Builder{}
+		for _, field := range []string{plugin.Name, plugin.Version, plugin.URL, plugin.Sha256, string(plugin.SignatureLevel)} {
+			line.WriteString(strconv.Quote(field))
+			line.WriteString(" ")
+		}
+		lines = append(lines, line.String())
+	}
+	sort.Strings(lines)
+
+	sb := strings.Builder{}
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\n")
 	}
 	hash := sha256.New()
 	io.WriteString(hash, sb.String()) // nolint
 	return fmt.Sprintf("%x", hash.Sum(nil))
 }
 
+// String renders the list in the format of GF_INSTALL_PLUGINS, plugins with a url as url;name
 func (l PluginList) String() string {
 	var plugins []string
 	for _, plugin := range l {
+		if plugin.URL != "" {
+			plugins = append(plugins, fmt.Sprintf("%s;%s", plugin.URL, plugin.Name))
+			continue
+		}
 		plugins = append(plugins, fmt.Sprintf("%s %s", plugin.Name, plugin.Version))
 	}
 	return strings.Join(plugins, ",")
 }
 
-// Update update plugin version
+// HasURLs returns true if any plugin of the list is downloaded from a url
+func (l PluginList) HasURLs() bool {
+	for _, plugin := range l {
+		if plugin.URL != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Update sets the version of a plugin in the list, plugins not in the list are ignored
 func (l PluginList) Update(plugin *GrafanaPlugin) {
-	for _, installedPlugin := range l {
-		if installedPlugin.Name == plugin.Name {
-			installedPlugin.Version = plugin.Version
+	for i := range l {
+		if l[i].Name == plugin.Name {
+			l[i].Version = plugin.Version
+			break
+		}
+	}
+}
+
+// PluginListDiff lists the changes applied by a merge
+type PluginListDiff struct {
+	// plugins not in the list before
+	Added PluginList
+	// plugins of the list replaced by a different version, url, checksum or signature level
+	Updated PluginList
+}
+
+// IsEmpty returns true if the merge didn't change the list
+func (d PluginListDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0
+}
+
+// Merge returns a copy of the list with the plugins added or replaced by the given plugins, and the applied
+// changes. The list itself is not modified.
+func (l PluginList) Merge(plugins PluginList) (PluginList, PluginListDiff) {
+	merged := make(PluginList, len(l))
+	copy(merged, l)
+
+	var diff PluginListDiff
+	for _, plugin := range plugins {
+		found := false
+		for i := range merged {
+			if merged[i].Name != plugin.Name {
+				continue
+			}
+			found = true
+			if merged[i] != plugin {
+				merged[i] = plugin
+				diff.Updated = append(diff.Updated, plugin)
+			}
 			break
 		}
+		if !found {
+			merged = append(merged, plugin)
+			diff.Added = append(diff.Added, plugin)
+		}
+	}
+	return merged, diff
+}
+
+// Validate returns an error for plugins without a name, with an unknown signature level, a checksum or url
+// of a range, an invalid url, or a version that is neither an exact version nor a range
+func (l PluginList) Validate() error {
+	for _, plugin := range l {
+		if plugin.Name == "" {
+			return fmt.Errorf("plugin with version %s has no name", plugin.Version)
+		}
+		if plugin.SignatureLevel != "" && plugin.SignatureLevel.Rank() < 0 {
+			return fmt.Errorf("unknown signature level %s of plugin %s", plugin.SignatureLevel, plugin.Name)
+		}
+		if plugin.Sha256 != "" && plugin.IsRange() {
+			return fmt.Errorf("checksum of plugin %s requires an exact version instead of %s", plugin.Name, plugin.Version)
+		}
+		if plugin.URL != "" {
+			if plugin.IsRange() {
+				return fmt.Errorf("url of plugin %s requires an exact version instead of %s", plugin.Name, plugin.Version)
+			}
+			if strings.ContainsAny(plugin.URL, ",;") || !(strings.HasPrefix(plugin.URL, "http://") || strings.HasPrefix(plugin.URL, "https://")) {
+				return fmt.Errorf("invalid url %s of plugin %s", plugin.URL, plugin.Name)
+			}
+			// plugins downloaded from a url are not published on grafana.com
+			if plugin.Sha256 != "" || (plugin.SignatureLevel != "" && plugin.SignatureLevel != PluginSignatureUnsigned) {
+				return fmt.Errorf("checksum and signature of plugin %s can't be verified for a url", plugin.Name)
+			}
+		}
+		if !plugin.IsRange() {
+			continue
+		}
+		_, err := ParseVersionRange(plugin.Version)
+		if err != nil {
+			return fmt.Errorf("invalid version %s of plugin %s: %w", plugin.Version, plugin.Name, err)
+		}
 	}
+	return nil
 }
 
-// Sanitize remove duplicates and enforce semver
+// Sanitize remove duplicates and invalid versions, lists are validated before
 func (l PluginList) Sanitize() PluginList {
 	var sanitized PluginList
 	for _, plugin := range l {
-		_, err := semver.Parse(plugin.Version)
-		if err != nil {
+		if (PluginList{plugin}).Validate() != nil {
 			continue
 		}
 		if !sanitized.HasSomeVersionOf(&plugin) {
@@ -0,0 +1,173 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver"
+)
+
+func TestExpandXRange(t *testing.T) {
+	tests := []struct {
+		part     string
+		expected string
+	}{
+		{part: "^1.2.3", expected: ">=1.2.3 <2.0.0"},
+		{part: "^0.2.3", expected: ">=0.2.3 <0.3.0"},
+		{part: "^0.0.3", expected: ">=0.0.3 <0.0.4"},
+		{part: "^1.2.3-beta.2", expected: ">=1.2.3-beta.2 <2.0.0"},
+		{part: "^1.2", expected: ">=1.2.0 <2.0.0"},
+		{part: "^0.2", expected: ">=0.2.0 <0.3.0"},
+		{part: "^0.0", expected: ">=0.0.0 <0.1.0"},
+		{part: "^1", expected: ">=1.0.0 <2.0.0"},
+		{part: "^0", expected: ">=0.0.0 <1.0.0"},
+		{part: "^1.x", expected: ">=1.0.0 <2.0.0"},
+		{part: "~1.2.3", expected: ">=1.2.3 <1.3.0"},
+		{part: "~0.2.3", expected: ">=0.2.3 <0.3.0"},
+		{part: "~1.2", expected: ">=1.2.0 <1.3.0"},
+		{part: "~1", expected: ">=1.0.0 <2.0.0"},
+		{part: "~0", expected: ">=0.0.0 <1.0.0"},
+		{part: "1.x", expected: ">=1.0.0 <2.0.0"},
+		{part: "1.2.x", expected: ">=1.2.0 <1.3.0"},
+		{part: "1.2.X", expected: ">=1.2.0 <1.3.0"},
+		{part: "1.2", expected: ">=1.2.0 <1.3.0"},
+		{part: "1", expected: ">=1.0.0 <2.0.0"},
+		{part: "*", expected: ">=0.0.0"},
+		{part: "x", expected: ">=0.0.0"},
+		{part: "1.2.3", expected: "1.2.3"},
+		{part: ">=1.2.0", expected: ">=1.2.0"},
+		{part: "||", expected: "||"},
+		{part: "^abc", expected: "^abc"},
+		{part: "1.2.3.4", expected: "1.2.3.4"},
+	}
+	for _, test := range tests {
+		t.Run(test.part, func(t *testing.T) {
+			if actual := expandXRange(test.part); actual != test.expected {
+				t.Errorf("expandXRange(%q) = %q, expected %q", test.part, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseVersionRange(t *testing.T) {
+	tests := []struct {
+		version  string
+		matching []string
+		other    []string
+	}{
+		{version: "", matching: []string{"0.0.1", "10.2.3"}},
+		{version: PluginVersionLatest, matching: []string{"0.0.1", "10.2.3"}},
+		{version: "^0.2.3", matching: []string{"0.2.3", "0.2.9"}, other: []string{"0.2.2", "0.3.0", "1.0.0"}},
+		{version: "^0.0.3", matching: []string{"0.0.3"}, other: []string{"0.0.4", "0.1.0"}},
+		{version: "^1.2", matching: []string{"1.2.0", "1.9.0"}, other: []string{"1.1.9", "2.0.0"}},
+		{version: "~1", matching: []string{"1.0.0", "1.9.9"}, other: []string{"0.9.9", "2.0.0"}},
+		{version: "~1.2.3", matching: []string{"1.2.3", "1.2.9"}, other: []string{"1.3.0"}},
+		{version: "1.x", matching: []string{"1.0.0", "1.5.2"}, other: []string{"2.0.0"}},
+		{version: ">=1.2.0 <2.0.0", matching: []string{"1.2.0", "1.9.9"}, other: []string{"1.1.0", "2.0.0"}},
+		{version: "1.x || ^3.1", matching: []string{"1.1.0", "3.2.0"}, other: []string{"2.0.0", "4.0.0"}},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			versionRange, err := ParseVersionRange(test.version)
+			if err != nil {
+				t.Fatalf("ParseVersionRange(%q) returned %v", test.version, err)
+			}
+			for _, version := range test.matching {
+				if !versionRange(semver.MustParse(version)) {
+					t.Errorf("%q doesn't match %s", test.version, version)
+				}
+			}
+			for _, version := range test.other {
+				if versionRange(semver.MustParse(version)) {
+					t.Errorf("%q matches %s", test.version, version)
+				}
+			}
+		})
+	}
+}
+
+func TestParseVersionRangeInvalid(t *testing.T) {
+	for _, version := range []string{"^abc", "1.2.3.4", ">=a"} {
+		if _, err := ParseVersionRange(version); err == nil {
+			t.Errorf("ParseVersionRange(%q) accepted an invalid range", version)
+		}
+	}
+}
+
+func TestPluginListHash(t *testing.T) {
+	a := GrafanaPlugin{Name: "a", Version: "1.0.0"}
+	a1 := GrafanaPlugin{Name: "a1", Version: ".0.0"}
+	b := GrafanaPlugin{Name: "b", Version: "2.0.0"}
+	mirrored := GrafanaPlugin{Name: "b", Version: "2.0.0", URL: "https://mirror.example.com/b.zip"}
+	signed := GrafanaPlugin{Name: "b", Version: "2.0.0", SignatureLevel: PluginSignatureGrafana}
+	checked := GrafanaPlugin{Name: "b", Version: "2.0.0", Sha256: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"}
+
+	tests := []struct {
+		name  string
+		list  PluginList
+		other PluginList
+		equal bool
+	}{
+		{name: "order of plugins", list: PluginList{a, b}, other: PluginList{b, a}, equal: true},
+		{name: "order of plugins differing in url", list: PluginList{b, mirrored}, other: PluginList{mirrored, b}, equal: true},
+		{name: "order of plugins differing in signature and checksum", list: PluginList{signed, checked, b}, other: PluginList{b, checked, signed}, equal: true},
+		{name: "empty lists", list: nil, other: PluginList{}, equal: true},
+		{name: "delimited fields", list: PluginList{{Name: "a", Version: "1.0.0"}}, other: PluginList{{Name: "a1", Version: ".0.0"}}},
+		{name: "fields moved between plugins", list: PluginList{a, a1}, other: PluginList{{Name: "a", Version: "1.0.0a1"}, {Name: "", Version: ".0.0"}}},
+		{name: "url", list: PluginList{b}, other: PluginList{mirrored}},
+		{name: "signature level", list: PluginList{b}, other: PluginList{signed}},
+		{name: "checksum", list: PluginList{b}, other: PluginList{checked}},
+		{name: "version", list: PluginList{a}, other: PluginList{{Name: "a", Version: "1.0.1"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if equal := test.list.Hash() == test.other.Hash(); equal != test.equal {
+				t.Errorf("hashes of %v and %v equal: %t, expected %t", test.list, test.other, equal, test.equal)
+			}
+		})
+	}
+}
+
+func TestPluginListMerge(t *testing.T) {
+	a := GrafanaPlugin{Name: "a", Version: "1.0.0"}
+	b := GrafanaPlugin{Name: "b", Version: "2.0.0"}
+	upgraded := GrafanaPlugin{Name: "b", Version: "2.1.0"}
+	mirrored := GrafanaPlugin{Name: "b", Version: "2.0.0", URL: "https://mirror.example.com/b.zip"}
+	c := GrafanaPlugin{Name: "c", Version: "3.0.0"}
+
+	tests := []struct {
+		name     string
+		list     PluginList
+		plugins  PluginList
+		expected PluginList
+		diff     PluginListDiff
+	}{
+		{name: "empty lists", list: nil, plugins: nil, expected: PluginList{}},
+		{name: "added to an empty list", list: nil, plugins: PluginList{a}, expected: PluginList{a}, diff: PluginListDiff{Added: PluginList{a}}},
+		{name: "added", list: PluginList{a}, plugins: PluginList{b, c}, expected: PluginList{a, b, c}, diff: PluginListDiff{Added: PluginList{b, c}}},
+		{name: "unchanged", list: PluginList{a, b}, plugins: PluginList{b}, expected: PluginList{a, b}},
+		{name: "version replaced in place", list: PluginList{b, a}, plugins: PluginList{upgraded}, expected: PluginList{upgraded, a}, diff: PluginListDiff{Updated: PluginList{upgraded}}},
+		{name: "url replaced", list: PluginList{b}, plugins: PluginList{mirrored}, expected: PluginList{mirrored}, diff: PluginListDiff{Updated: PluginList{mirrored}}},
+		{name: "added and updated", list: PluginList{a, b}, plugins: PluginList{c, upgraded}, expected: PluginList{a, upgraded, c}, diff: PluginListDiff{Added: PluginList{c}, Updated: PluginList{upgraded}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := make(PluginList, len(test.list))
+			copy(original, test.list)
+
+			merged, diff := test.list.Merge(test.plugins)
+			if !reflect.DeepEqual(merged, test.expected) {
+				t.Errorf("merged list %v, expected %v", merged, test.expected)
+			}
+			if !reflect.DeepEqual(diff, test.diff) {
+				t.Errorf("diff %+v, expected %+v", diff, test.diff)
+			}
+			if diff.IsEmpty() != (len(test.diff.Added) == 0 && len(test.diff.Updated) == 0) {
+				t.Errorf("IsEmpty of %+v returned %t", diff, diff.IsEmpty())
+			}
+			if len(test.list) > 0 && !reflect.DeepEqual(test.list, original) {
+				t.Errorf("merge modified the list to %v", test.list)
+			}
+		})
+	}
+}
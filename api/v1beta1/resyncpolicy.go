@@ -0,0 +1,36 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResyncPolicy decides how changes made in Grafana are treated, objects deleted in Grafana are always
+// restored on the next resync
+type ResyncPolicy struct {
+	// how often the object is compared to its state in Grafana and restored, defaults to the resync period
+	// of the operator
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// keeps changes made in the Grafana ui until the cr changes
+	// +optional
+	AllowUIUpdates bool `json:"allowUIUpdates,omitempty"`
+}
+
+// ReconcileRequestAnnotation requests an immediate resync of a dashboard, datasource or folder on every new
+// value, e.g. the current time. The cr is applied again even if its content is unchanged, changes made in the
+// ui are overwritten and downloaded content is revalidated.
+const ReconcileRequestAnnotation = "grafana.integreatly.org/reconcile"
+
+// ReconcileRequestStatus records the last reconcile request that was handled
+type ReconcileRequestStatus struct {
+	// value of the reconcile annotation last handled
+	// +optional
+	LastHandledReconcileAt string `json:"lastHandledReconcileAt,omitempty"`
+}
+
+// reconcileRequested returns true if the reconcile annotation of a cr has a value that wasn't handled yet
+func reconcileRequested(obj metav1.Object, status ReconcileRequestStatus) bool {
+	value := obj.GetAnnotations()[ReconcileRequestAnnotation]
+	return value != "" && value != status.LastHandledReconcileAt
+}
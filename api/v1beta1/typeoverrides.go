@@ -46,6 +46,7 @@ func (override *ObjectMeta) Merge(meta metav1.ObjectMeta) metav1.ObjectMeta {
 
 // +kubebuilder:object:generate=true
 
+// DeploymentV1 is a partial Deployment merged over the Deployment generated for a Grafana instance
 type DeploymentV1 struct {
 	ObjectMeta ObjectMeta       `json:"metadata,omitempty"`
 	Spec       DeploymentV1Spec `json:"spec,omitempty"`
@@ -57,6 +58,12 @@ type DeploymentV1Spec struct {
 
 	Selector *metav1.LabelSelector `json:"selector,omitempty" protobuf:"bytes,2,opt,name=selector"`
 
+	// Template is strategically merged over the generated pod template and may be partial, containers are
+	// merged by name and unknown containers are added as sidecars
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
 	Template *v14.PodTemplateSpec `json:"template,omitempty" protobuf:"bytes,3,opt,name=template"`
 
 	// +optional
@@ -77,16 +84,46 @@ type DeploymentV1Spec struct {
 
 // +kubebuilder:object:generate=true
 
+// IngressNetworkingV1 creates an Ingress for the instance, the host and path are used as root_url of
+// Grafana. Metadata and spec are merged over the generated Ingress.
 type IngressNetworkingV1 struct {
 	ObjectMeta ObjectMeta      `json:"metadata,omitempty"`
 	Spec       *v1.IngressSpec `json:"spec,omitempty"`
+
+	// public host name of the instance
+	// +optional
+	Host string `json:"host,omitempty"`
+	// secret containing the tls certificate for the host, the external url uses https when set
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// annotations added to the Ingress, e.g. for cert-manager or the ingress controller
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// path Grafana is served from, Grafana serves from the sub path when it isn't /
+	// +kubebuilder:default="/"
+	// +optional
+	Path string `json:"path,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
 
+// RouteOpenshiftV1 creates a Route for the instance on OpenShift, metadata and spec are merged over the
+// generated Route
 type RouteOpenshiftV1 struct {
 	ObjectMeta ObjectMeta            `json:"metadata,omitempty"`
 	Spec       *RouteOpenShiftV1Spec `json:"spec,omitempty"`
+
+	// tls termination of the Route, reencrypt requires Grafana to serve https
+	// +kubebuilder:validation:Enum=edge;reencrypt
+	// +kubebuilder:default=edge
+	// +optional
+	Termination string `json:"termination,omitempty"`
+	// pem encoded ca certificate the router verifies Grafana with in reencrypt mode, defaults to the
+	// OpenShift service ca
+	// +optional
+	DestinationCACertificate string `json:"destinationCACertificate,omitempty"`
 }
 
 type RouteOpenShiftV1Spec struct {
@@ -134,7 +171,20 @@ type PersistentVolumeClaimV1Spec struct {
 	DataSourceRef *v14.TypedLocalObjectReference `json:"dataSourceRef,omitempty" protobuf:"bytes,8,opt,name=dataSourceRef"`
 }
 
+// ServiceAccountV1 configures the service account of the Grafana pods, e.g. with annotations for IRSA or
+// Workload Identity used by the CloudWatch and BigQuery datasources
 type ServiceAccountV1 struct {
+	// create the service account, when false the named service account must exist
+	// +kubebuilder:default=true
+	// +optional
+	Create *bool `json:"create,omitempty"`
+	// name of the service account, defaults to <name>-sa
+	// +optional
+	Name string `json:"name,omitempty"`
+	// annotations added to the created service account
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
 	ObjectMeta ObjectMeta            `json:"metadata,omitempty"`
 	Secrets    []v14.ObjectReference `json:"secrets,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,2,rep,name=secrets"`
 	// +optional
@@ -143,6 +193,11 @@ type ServiceAccountV1 struct {
 	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty" protobuf:"varint,4,opt,name=automountServiceAccountToken"`
 }
 
+// IsCreated returns true if the operator manages the service account
+func (in *ServiceAccountV1) IsCreated() bool {
+	return in == nil || in.Create == nil || *in.Create
+}
+
 // Merge merges `overrides` into `base` using the SMP (structural merge patch) approach.
 // - It intentionally does not remove fields present in base but missing from overrides
 // - It merges slices only if the `patchStrategy:"merge"` tag is present and the `patchMergeKey` identifies the unique field
@@ -0,0 +1,20 @@
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// ValueFrom injects a value from a Secret or ConfigMap into the target path of a resource
+type ValueFrom struct {
+	// dot separated path of the field to set, e.g. settings.token
+	TargetPath string          `json:"targetPath"`
+	ValueFrom  ValueFromSource `json:"valueFrom"`
+}
+
+// ValueFromSource references a key of a Secret or ConfigMap in the namespace of the resource
+type ValueFromSource struct {
+	// +optional
+	ConfigMapKeyRef *v1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// +optional
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
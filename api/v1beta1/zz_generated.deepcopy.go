@@ -24,12 +24,156 @@ package v1beta1
 import (
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/autoscaling/v2"
 	"k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdoptedResource) DeepCopyInto(out *AdoptedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptedResource.
+func (in *AdoptedResource) DeepCopy() *AdoptedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(AdoptedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPVCStorage) DeepCopyInto(out *BackupPVCStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPVCStorage.
+func (in *BackupPVCStorage) DeepCopy() *BackupPVCStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPVCStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupS3Storage) DeepCopyInto(out *BackupS3Storage) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupS3Storage.
+func (in *BackupS3Storage) DeepCopy() *BackupS3Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupS3Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupStorage) DeepCopyInto(out *BackupStorage) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(BackupPVCStorage)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(BackupS3Storage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorage.
+func (in *BackupStorage) DeepCopy() *BackupStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorrelationConfig) DeepCopyInto(out *CorrelationConfig) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transformations != nil {
+		in, out := &in.Transformations, &out.Transformations
+		*out = make([]CorrelationTransformation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorrelationConfig.
+func (in *CorrelationConfig) DeepCopy() *CorrelationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CorrelationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorrelationTransformation) DeepCopyInto(out *CorrelationTransformation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorrelationTransformation.
+func (in *CorrelationTransformation) DeepCopy() *CorrelationTransformation {
+	if in == nil {
+		return nil
+	}
+	out := new(CorrelationTransformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatasourceReference) DeepCopyInto(out *DatasourceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasourceReference.
+func (in *DatasourceReference) DeepCopy() *DatasourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DatasourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentV1) DeepCopyInto(out *DeploymentV1) {
 	*out = *in
@@ -92,13 +236,53 @@ func (in *DeploymentV1Spec) DeepCopy() *DeploymentV1Spec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunResult) DeepCopyInto(out *DryRunResult) {
+	*out = *in
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunResult.
+func (in *DryRunResult) DeepCopy() *DryRunResult {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositorySource) DeepCopyInto(out *GitRepositorySource) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositorySource.
+func (in *GitRepositorySource) DeepCopy() *GitRepositorySource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositorySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Grafana) DeepCopyInto(out *Grafana) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Grafana.
@@ -120,1281 +304,5556 @@ func (in *Grafana) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaClient) DeepCopyInto(out *GrafanaClient) {
+func (in *GrafanaAdminCredentials) DeepCopyInto(out *GrafanaAdminCredentials) {
 	*out = *in
-	if in.TimeoutSeconds != nil {
-		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
-		*out = new(int)
+	if in.ExistingSecret != nil {
+		in, out := &in.ExistingSecret, &out.ExistingSecret
+		*out = new(GrafanaAdminSecretRef)
 		**out = **in
 	}
-	if in.PreferIngress != nil {
-		in, out := &in.PreferIngress, &out.PreferIngress
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAdminCredentials.
+func (in *GrafanaAdminCredentials) DeepCopy() *GrafanaAdminCredentials {
+	if in == nil {
+		return nil
 	}
+	out := new(GrafanaAdminCredentials)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaClient.
-func (in *GrafanaClient) DeepCopy() *GrafanaClient {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAdminSecretRef) DeepCopyInto(out *GrafanaAdminSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAdminSecretRef.
+func (in *GrafanaAdminSecretRef) DeepCopy() *GrafanaAdminSecretRef {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaClient)
+	out := new(GrafanaAdminSecretRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfig) DeepCopyInto(out *GrafanaConfig) {
+func (in *GrafanaAnnotation) DeepCopyInto(out *GrafanaAnnotation) {
 	*out = *in
-	if in.Paths != nil {
-		in, out := &in.Paths, &out.Paths
-		*out = new(GrafanaConfigPaths)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAnnotation.
+func (in *GrafanaAnnotation) DeepCopy() *GrafanaAnnotation {
+	if in == nil {
+		return nil
 	}
-	if in.Server != nil {
-		in, out := &in.Server, &out.Server
-		*out = new(GrafanaConfigServer)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaAnnotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaAnnotation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.Database != nil {
-		in, out := &in.Database, &out.Database
-		*out = new(GrafanaConfigDatabase)
-		(*in).DeepCopyInto(*out)
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAnnotationInstance) DeepCopyInto(out *GrafanaAnnotationInstance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAnnotationInstance.
+func (in *GrafanaAnnotationInstance) DeepCopy() *GrafanaAnnotationInstance {
+	if in == nil {
+		return nil
 	}
-	if in.RemoteCache != nil {
-		in, out := &in.RemoteCache, &out.RemoteCache
-		*out = new(GrafanaConfigRemoteCache)
-		**out = **in
+	out := new(GrafanaAnnotationInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAnnotationList) DeepCopyInto(out *GrafanaAnnotationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaAnnotation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Security != nil {
-		in, out := &in.Security, &out.Security
-		*out = new(GrafanaConfigSecurity)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAnnotationList.
+func (in *GrafanaAnnotationList) DeepCopy() *GrafanaAnnotationList {
+	if in == nil {
+		return nil
 	}
-	if in.Users != nil {
-		in, out := &in.Users, &out.Users
-		*out = new(GrafanaConfigUsers)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaAnnotationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaAnnotationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.Auth != nil {
-		in, out := &in.Auth, &out.Auth
-		*out = new(GrafanaConfigAuth)
-		(*in).DeepCopyInto(*out)
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAnnotationSpec) DeepCopyInto(out *GrafanaAnnotationSpec) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.AuthBasic != nil {
-		in, out := &in.AuthBasic, &out.AuthBasic
-		*out = new(GrafanaConfigAuthBasic)
-		(*in).DeepCopyInto(*out)
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
 	}
-	if in.AuthAnonymous != nil {
-		in, out := &in.AuthAnonymous, &out.AuthAnonymous
-		*out = new(GrafanaConfigAuthAnonymous)
-		(*in).DeepCopyInto(*out)
+	if in.TimeEnd != nil {
+		in, out := &in.TimeEnd, &out.TimeEnd
+		*out = (*in).DeepCopy()
 	}
-	if in.AuthAzureAD != nil {
-		in, out := &in.AuthAzureAD, &out.AuthAzureAD
-		*out = new(GrafanaConfigAuthAzureAD)
-		(*in).DeepCopyInto(*out)
+	if in.PanelID != nil {
+		in, out := &in.PanelID, &out.PanelID
+		*out = new(int64)
+		**out = **in
 	}
-	if in.AuthGoogle != nil {
-		in, out := &in.AuthGoogle, &out.AuthGoogle
-		*out = new(GrafanaConfigAuthGoogle)
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.AuthGithub != nil {
-		in, out := &in.AuthGithub, &out.AuthGithub
-		*out = new(GrafanaConfigAuthGithub)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAnnotationSpec.
+func (in *GrafanaAnnotationSpec) DeepCopy() *GrafanaAnnotationSpec {
+	if in == nil {
+		return nil
 	}
-	if in.AuthGitlab != nil {
-		in, out := &in.AuthGitlab, &out.AuthGitlab
-		*out = new(GrafanaConfigAuthGitlab)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaAnnotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAnnotationStatus) DeepCopyInto(out *GrafanaAnnotationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.AuthGenericOauth != nil {
-		in, out := &in.AuthGenericOauth, &out.AuthGenericOauth
-		*out = new(GrafanaConfigAuthGenericOauth)
-		(*in).DeepCopyInto(*out)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]GrafanaAnnotationInstance, len(*in))
+		copy(*out, *in)
 	}
-	if in.AuthOkta != nil {
-		in, out := &in.AuthOkta, &out.AuthOkta
-		*out = new(GrafanaConfigAuthOkta)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAnnotationStatus.
+func (in *GrafanaAnnotationStatus) DeepCopy() *GrafanaAnnotationStatus {
+	if in == nil {
+		return nil
 	}
-	if in.AuthLdap != nil {
-		in, out := &in.AuthLdap, &out.AuthLdap
-		*out = new(GrafanaConfigAuthLdap)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaAnnotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAutoscaling) DeepCopyInto(out *GrafanaAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
 	}
-	if in.AuthProxy != nil {
-		in, out := &in.AuthProxy, &out.AuthProxy
-		*out = new(GrafanaConfigAuthProxy)
-		(*in).DeepCopyInto(*out)
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
 	}
-	if in.AuthSaml != nil {
-		in, out := &in.AuthSaml, &out.AuthSaml
-		*out = new(GrafanaConfigAuthSaml)
-		(*in).DeepCopyInto(*out)
+	if in.TargetMemoryUtilizationPercentage != nil {
+		in, out := &in.TargetMemoryUtilizationPercentage, &out.TargetMemoryUtilizationPercentage
+		*out = new(int32)
+		**out = **in
 	}
-	if in.DataProxy != nil {
-		in, out := &in.DataProxy, &out.DataProxy
-		*out = new(GrafanaConfigDataProxy)
-		(*in).DeepCopyInto(*out)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Analytics != nil {
-		in, out := &in.Analytics, &out.Analytics
-		*out = new(GrafanaConfigAnalytics)
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(v2.HorizontalPodAutoscalerBehavior)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Dashboards != nil {
-		in, out := &in.Dashboards, &out.Dashboards
-		*out = new(GrafanaConfigDashboards)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAutoscaling.
+func (in *GrafanaAutoscaling) DeepCopy() *GrafanaAutoscaling {
+	if in == nil {
+		return nil
 	}
-	if in.Smtp != nil {
-		in, out := &in.Smtp, &out.Smtp
-		*out = new(GrafanaConfigSmtp)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaBackup) DeepCopyInto(out *GrafanaBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaBackup.
+func (in *GrafanaBackup) DeepCopy() *GrafanaBackup {
+	if in == nil {
+		return nil
 	}
-	if in.Live != nil {
-		in, out := &in.Live, &out.Live
-		*out = new(GrafanaConfigLive)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.Log != nil {
-		in, out := &in.Log, &out.Log
-		*out = new(GrafanaConfigLog)
-		**out = **in
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaBackupInstance) DeepCopyInto(out *GrafanaBackupInstance) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaBackupInstance.
+func (in *GrafanaBackupInstance) DeepCopy() *GrafanaBackupInstance {
+	if in == nil {
+		return nil
 	}
-	if in.LogConsole != nil {
-		in, out := &in.LogConsole, &out.LogConsole
-		*out = new(GrafanaConfigLogConsole)
-		**out = **in
+	out := new(GrafanaBackupInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaBackupList) DeepCopyInto(out *GrafanaBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.LogFrontend != nil {
-		in, out := &in.LogFrontend, &out.LogFrontend
-		*out = new(GrafanaConfigLogFrontend)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaBackupList.
+func (in *GrafanaBackupList) DeepCopy() *GrafanaBackupList {
+	if in == nil {
+		return nil
 	}
-	if in.Metrics != nil {
-		in, out := &in.Metrics, &out.Metrics
-		*out = new(GrafanaConfigMetrics)
+	out := new(GrafanaBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaBackupSpec) DeepCopyInto(out *GrafanaBackupSpec) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.MetricsGraphite != nil {
-		in, out := &in.MetricsGraphite, &out.MetricsGraphite
-		*out = new(GrafanaConfigMetricsGraphite)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaBackupSpec.
+func (in *GrafanaBackupSpec) DeepCopy() *GrafanaBackupSpec {
+	if in == nil {
+		return nil
 	}
-	if in.Snapshots != nil {
-		in, out := &in.Snapshots, &out.Snapshots
-		*out = new(GrafanaConfigSnapshots)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaBackupStatus) DeepCopyInto(out *GrafanaBackupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.ExternalImageStorage != nil {
-		in, out := &in.ExternalImageStorage, &out.ExternalImageStorage
-		*out = new(GrafanaConfigExternalImageStorage)
-		**out = **in
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
 	}
-	if in.ExternalImageStorageS3 != nil {
-		in, out := &in.ExternalImageStorageS3, &out.ExternalImageStorageS3
-		*out = new(GrafanaConfigExternalImageStorageS3)
-		**out = **in
+	if in.NextBackupTime != nil {
+		in, out := &in.NextBackupTime, &out.NextBackupTime
+		*out = (*in).DeepCopy()
 	}
-	if in.ExternalImageStorageWebdav != nil {
-		in, out := &in.ExternalImageStorageWebdav, &out.ExternalImageStorageWebdav
-		*out = new(GrafanaConfigExternalImageStorageWebdav)
-		**out = **in
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]GrafanaBackupInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.ExternalImageStorageGcs != nil {
-		in, out := &in.ExternalImageStorageGcs, &out.ExternalImageStorageGcs
-		*out = new(GrafanaConfigExternalImageStorageGcs)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaBackupStatus.
+func (in *GrafanaBackupStatus) DeepCopy() *GrafanaBackupStatus {
+	if in == nil {
+		return nil
 	}
-	if in.ExternalImageStorageAzureBlob != nil {
-		in, out := &in.ExternalImageStorageAzureBlob, &out.ExternalImageStorageAzureBlob
-		*out = new(GrafanaConfigExternalImageStorageAzureBlob)
+	out := new(GrafanaBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaClient) DeepCopyInto(out *GrafanaClient) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int)
 		**out = **in
 	}
-	if in.Alerting != nil {
-		in, out := &in.Alerting, &out.Alerting
-		*out = new(GrafanaConfigAlerting)
-		(*in).DeepCopyInto(*out)
+	if in.PreferIngress != nil {
+		in, out := &in.PreferIngress, &out.PreferIngress
+		*out = new(bool)
+		**out = **in
 	}
-	if in.UnifiedAlerting != nil {
-		in, out := &in.UnifiedAlerting, &out.UnifiedAlerting
-		*out = new(GrafanaConfigUnifiedAlerting)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaClient.
+func (in *GrafanaClient) DeepCopy() *GrafanaClient {
+	if in == nil {
+		return nil
 	}
-	if in.Panels != nil {
-		in, out := &in.Panels, &out.Panels
-		*out = new(GrafanaConfigPanels)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaCloudStack) DeepCopyInto(out *GrafanaCloudStack) {
+	*out = *in
+	in.APIKey.DeepCopyInto(&out.APIKey)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaCloudStack.
+func (in *GrafanaCloudStack) DeepCopy() *GrafanaCloudStack {
+	if in == nil {
+		return nil
 	}
-	if in.Plugins != nil {
-		in, out := &in.Plugins, &out.Plugins
-		*out = new(GrafanaConfigPlugins)
-		(*in).DeepCopyInto(*out)
+	out := new(GrafanaCloudStack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaComDashboardReference) DeepCopyInto(out *GrafanaComDashboardReference) {
+	*out = *in
+	if in.Revision != nil {
+		in, out := &in.Revision, &out.Revision
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaComDashboardReference.
+func (in *GrafanaComDashboardReference) DeepCopy() *GrafanaComDashboardReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaComDashboardReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfig) DeepCopyInto(out *GrafanaConfig) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = new(GrafanaConfigPaths)
+		**out = **in
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(GrafanaConfigServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(GrafanaConfigDatabase)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteCache != nil {
+		in, out := &in.RemoteCache, &out.RemoteCache
+		*out = new(GrafanaConfigRemoteCache)
+		**out = **in
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(GrafanaConfigSecurity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = new(GrafanaConfigUsers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(GrafanaConfigAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthBasic != nil {
+		in, out := &in.AuthBasic, &out.AuthBasic
+		*out = new(GrafanaConfigAuthBasic)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthAnonymous != nil {
+		in, out := &in.AuthAnonymous, &out.AuthAnonymous
+		*out = new(GrafanaConfigAuthAnonymous)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthAzureAD != nil {
+		in, out := &in.AuthAzureAD, &out.AuthAzureAD
+		*out = new(GrafanaConfigAuthAzureAD)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthGoogle != nil {
+		in, out := &in.AuthGoogle, &out.AuthGoogle
+		*out = new(GrafanaConfigAuthGoogle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthGithub != nil {
+		in, out := &in.AuthGithub, &out.AuthGithub
+		*out = new(GrafanaConfigAuthGithub)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthGitlab != nil {
+		in, out := &in.AuthGitlab, &out.AuthGitlab
+		*out = new(GrafanaConfigAuthGitlab)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthGenericOauth != nil {
+		in, out := &in.AuthGenericOauth, &out.AuthGenericOauth
+		*out = new(GrafanaConfigAuthGenericOauth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthOkta != nil {
+		in, out := &in.AuthOkta, &out.AuthOkta
+		*out = new(GrafanaConfigAuthOkta)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthLdap != nil {
+		in, out := &in.AuthLdap, &out.AuthLdap
+		*out = new(GrafanaConfigAuthLdap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthProxy != nil {
+		in, out := &in.AuthProxy, &out.AuthProxy
+		*out = new(GrafanaConfigAuthProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthSaml != nil {
+		in, out := &in.AuthSaml, &out.AuthSaml
+		*out = new(GrafanaConfigAuthSaml)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataProxy != nil {
+		in, out := &in.DataProxy, &out.DataProxy
+		*out = new(GrafanaConfigDataProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Analytics != nil {
+		in, out := &in.Analytics, &out.Analytics
+		*out = new(GrafanaConfigAnalytics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = new(GrafanaConfigDashboards)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Smtp != nil {
+		in, out := &in.Smtp, &out.Smtp
+		*out = new(GrafanaConfigSmtp)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Live != nil {
+		in, out := &in.Live, &out.Live
+		*out = new(GrafanaConfigLive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = new(GrafanaConfigLog)
+		**out = **in
+	}
+	if in.LogConsole != nil {
+		in, out := &in.LogConsole, &out.LogConsole
+		*out = new(GrafanaConfigLogConsole)
+		**out = **in
+	}
+	if in.LogFrontend != nil {
+		in, out := &in.LogFrontend, &out.LogFrontend
+		*out = new(GrafanaConfigLogFrontend)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(GrafanaConfigMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsGraphite != nil {
+		in, out := &in.MetricsGraphite, &out.MetricsGraphite
+		*out = new(GrafanaConfigMetricsGraphite)
+		**out = **in
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = new(GrafanaConfigSnapshots)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalImageStorage != nil {
+		in, out := &in.ExternalImageStorage, &out.ExternalImageStorage
+		*out = new(GrafanaConfigExternalImageStorage)
+		**out = **in
+	}
+	if in.ExternalImageStorageS3 != nil {
+		in, out := &in.ExternalImageStorageS3, &out.ExternalImageStorageS3
+		*out = new(GrafanaConfigExternalImageStorageS3)
+		**out = **in
+	}
+	if in.ExternalImageStorageWebdav != nil {
+		in, out := &in.ExternalImageStorageWebdav, &out.ExternalImageStorageWebdav
+		*out = new(GrafanaConfigExternalImageStorageWebdav)
+		**out = **in
+	}
+	if in.ExternalImageStorageGcs != nil {
+		in, out := &in.ExternalImageStorageGcs, &out.ExternalImageStorageGcs
+		*out = new(GrafanaConfigExternalImageStorageGcs)
+		**out = **in
+	}
+	if in.ExternalImageStorageAzureBlob != nil {
+		in, out := &in.ExternalImageStorageAzureBlob, &out.ExternalImageStorageAzureBlob
+		*out = new(GrafanaConfigExternalImageStorageAzureBlob)
+		**out = **in
+	}
+	if in.Alerting != nil {
+		in, out := &in.Alerting, &out.Alerting
+		*out = new(GrafanaConfigAlerting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnifiedAlerting != nil {
+		in, out := &in.UnifiedAlerting, &out.UnifiedAlerting
+		*out = new(GrafanaConfigUnifiedAlerting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Panels != nil {
+		in, out := &in.Panels, &out.Panels
+		*out = new(GrafanaConfigPanels)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(GrafanaConfigPlugins)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Rendering != nil {
 		in, out := &in.Rendering, &out.Rendering
 		*out = new(GrafanaConfigRendering)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.FeatureToggles != nil {
-		in, out := &in.FeatureToggles, &out.FeatureToggles
-		*out = new(GrafanaConfigFeatureToggles)
-		**out = **in
+	if in.FeatureToggles != nil {
+		in, out := &in.FeatureToggles, &out.FeatureToggles
+		*out = new(GrafanaConfigFeatureToggles)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfig.
+func (in *GrafanaConfig) DeepCopy() *GrafanaConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAlerting) DeepCopyInto(out *GrafanaConfigAlerting) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExecuteAlerts != nil {
+		in, out := &in.ExecuteAlerts, &out.ExecuteAlerts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConcurrentRenderLimit != nil {
+		in, out := &in.ConcurrentRenderLimit, &out.ConcurrentRenderLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.EvaluationTimeoutSeconds != nil {
+		in, out := &in.EvaluationTimeoutSeconds, &out.EvaluationTimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.NotificationTimeoutSeconds != nil {
+		in, out := &in.NotificationTimeoutSeconds, &out.NotificationTimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAlerting.
+func (in *GrafanaConfigAlerting) DeepCopy() *GrafanaConfigAlerting {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAlerting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAnalytics) DeepCopyInto(out *GrafanaConfigAnalytics) {
+	*out = *in
+	if in.ReportingEnabled != nil {
+		in, out := &in.ReportingEnabled, &out.ReportingEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CheckForUpdates != nil {
+		in, out := &in.CheckForUpdates, &out.CheckForUpdates
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAnalytics.
+func (in *GrafanaConfigAnalytics) DeepCopy() *GrafanaConfigAnalytics {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAnalytics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuth) DeepCopyInto(out *GrafanaConfigAuth) {
+	*out = *in
+	if in.LoginMaximumInactiveLifetimeDays != nil {
+		in, out := &in.LoginMaximumInactiveLifetimeDays, &out.LoginMaximumInactiveLifetimeDays
+		*out = new(int)
+		**out = **in
+	}
+	if in.LoginMaximumLifetimeDays != nil {
+		in, out := &in.LoginMaximumLifetimeDays, &out.LoginMaximumLifetimeDays
+		*out = new(int)
+		**out = **in
+	}
+	if in.TokenRotationIntervalMinutes != nil {
+		in, out := &in.TokenRotationIntervalMinutes, &out.TokenRotationIntervalMinutes
+		*out = new(int)
+		**out = **in
+	}
+	if in.DisableLoginForm != nil {
+		in, out := &in.DisableLoginForm, &out.DisableLoginForm
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableSignoutMenu != nil {
+		in, out := &in.DisableSignoutMenu, &out.DisableSignoutMenu
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SigV4AuthEnabled != nil {
+		in, out := &in.SigV4AuthEnabled, &out.SigV4AuthEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OauthAutoLogin != nil {
+		in, out := &in.OauthAutoLogin, &out.OauthAutoLogin
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuth.
+func (in *GrafanaConfigAuth) DeepCopy() *GrafanaConfigAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthAnonymous) DeepCopyInto(out *GrafanaConfigAuthAnonymous) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthAnonymous.
+func (in *GrafanaConfigAuthAnonymous) DeepCopy() *GrafanaConfigAuthAnonymous {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthAnonymous)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthAzureAD) DeepCopyInto(out *GrafanaConfigAuthAzureAD) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthAzureAD.
+func (in *GrafanaConfigAuthAzureAD) DeepCopy() *GrafanaConfigAuthAzureAD {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthAzureAD)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthBasic) DeepCopyInto(out *GrafanaConfigAuthBasic) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthBasic.
+func (in *GrafanaConfigAuthBasic) DeepCopy() *GrafanaConfigAuthBasic {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthBasic)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthGenericOauth) DeepCopyInto(out *GrafanaConfigAuthGenericOauth) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RoleAttributeStrict != nil {
+		in, out := &in.RoleAttributeStrict, &out.RoleAttributeStrict
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLSSkipVerifyInsecure != nil {
+		in, out := &in.TLSSkipVerifyInsecure, &out.TLSSkipVerifyInsecure
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthGenericOauth.
+func (in *GrafanaConfigAuthGenericOauth) DeepCopy() *GrafanaConfigAuthGenericOauth {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthGenericOauth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthGithub) DeepCopyInto(out *GrafanaConfigAuthGithub) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthGithub.
+func (in *GrafanaConfigAuthGithub) DeepCopy() *GrafanaConfigAuthGithub {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthGithub)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthGitlab) DeepCopyInto(out *GrafanaConfigAuthGitlab) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthGitlab.
+func (in *GrafanaConfigAuthGitlab) DeepCopy() *GrafanaConfigAuthGitlab {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthGitlab)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthGoogle) DeepCopyInto(out *GrafanaConfigAuthGoogle) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthGoogle.
+func (in *GrafanaConfigAuthGoogle) DeepCopy() *GrafanaConfigAuthGoogle {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthGoogle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthLdap) DeepCopyInto(out *GrafanaConfigAuthLdap) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthLdap.
+func (in *GrafanaConfigAuthLdap) DeepCopy() *GrafanaConfigAuthLdap {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthLdap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthOkta) DeepCopyInto(out *GrafanaConfigAuthOkta) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RoleAttributeStrict != nil {
+		in, out := &in.RoleAttributeStrict, &out.RoleAttributeStrict
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthOkta.
+func (in *GrafanaConfigAuthOkta) DeepCopy() *GrafanaConfigAuthOkta {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthOkta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthProxy) DeepCopyInto(out *GrafanaConfigAuthProxy) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoSignUp != nil {
+		in, out := &in.AutoSignUp, &out.AutoSignUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableLoginToken != nil {
+		in, out := &in.EnableLoginToken, &out.EnableLoginToken
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthProxy.
+func (in *GrafanaConfigAuthProxy) DeepCopy() *GrafanaConfigAuthProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigAuthSaml) DeepCopyInto(out *GrafanaConfigAuthSaml) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SingleLogout != nil {
+		in, out := &in.SingleLogout, &out.SingleLogout
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowIdpInitiated != nil {
+		in, out := &in.AllowIdpInitiated, &out.AllowIdpInitiated
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthSaml.
+func (in *GrafanaConfigAuthSaml) DeepCopy() *GrafanaConfigAuthSaml {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigAuthSaml)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigDashboards) DeepCopyInto(out *GrafanaConfigDashboards) {
+	*out = *in
+	if in.VersionsToKeep != nil {
+		in, out := &in.VersionsToKeep, &out.VersionsToKeep
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigDashboards.
+func (in *GrafanaConfigDashboards) DeepCopy() *GrafanaConfigDashboards {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigDashboards)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigDataProxy) DeepCopyInto(out *GrafanaConfigDataProxy) {
+	*out = *in
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(int)
+		**out = **in
+	}
+	if in.SendUserHeader != nil {
+		in, out := &in.SendUserHeader, &out.SendUserHeader
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigDataProxy.
+func (in *GrafanaConfigDataProxy) DeepCopy() *GrafanaConfigDataProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigDataProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigDatabase) DeepCopyInto(out *GrafanaConfigDatabase) {
+	*out = *in
+	if in.MaxIdleConn != nil {
+		in, out := &in.MaxIdleConn, &out.MaxIdleConn
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxOpenConn != nil {
+		in, out := &in.MaxOpenConn, &out.MaxOpenConn
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConnMaxLifetime != nil {
+		in, out := &in.ConnMaxLifetime, &out.ConnMaxLifetime
+		*out = new(int)
+		**out = **in
+	}
+	if in.LogQueries != nil {
+		in, out := &in.LogQueries, &out.LogQueries
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigDatabase.
+func (in *GrafanaConfigDatabase) DeepCopy() *GrafanaConfigDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigExternalImageStorage) DeepCopyInto(out *GrafanaConfigExternalImageStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorage.
+func (in *GrafanaConfigExternalImageStorage) DeepCopy() *GrafanaConfigExternalImageStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigExternalImageStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigExternalImageStorageAzureBlob) DeepCopyInto(out *GrafanaConfigExternalImageStorageAzureBlob) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorageAzureBlob.
+func (in *GrafanaConfigExternalImageStorageAzureBlob) DeepCopy() *GrafanaConfigExternalImageStorageAzureBlob {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigExternalImageStorageAzureBlob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigExternalImageStorageGcs) DeepCopyInto(out *GrafanaConfigExternalImageStorageGcs) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorageGcs.
+func (in *GrafanaConfigExternalImageStorageGcs) DeepCopy() *GrafanaConfigExternalImageStorageGcs {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigExternalImageStorageGcs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigExternalImageStorageS3) DeepCopyInto(out *GrafanaConfigExternalImageStorageS3) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorageS3.
+func (in *GrafanaConfigExternalImageStorageS3) DeepCopy() *GrafanaConfigExternalImageStorageS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigExternalImageStorageS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigExternalImageStorageWebdav) DeepCopyInto(out *GrafanaConfigExternalImageStorageWebdav) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorageWebdav.
+func (in *GrafanaConfigExternalImageStorageWebdav) DeepCopy() *GrafanaConfigExternalImageStorageWebdav {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigExternalImageStorageWebdav)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigFeatureToggles) DeepCopyInto(out *GrafanaConfigFeatureToggles) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigFeatureToggles.
+func (in *GrafanaConfigFeatureToggles) DeepCopy() *GrafanaConfigFeatureToggles {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigFeatureToggles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigLive) DeepCopyInto(out *GrafanaConfigLive) {
+	*out = *in
+	if in.MaxConnections != nil {
+		in, out := &in.MaxConnections, &out.MaxConnections
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigLive.
+func (in *GrafanaConfigLive) DeepCopy() *GrafanaConfigLive {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigLive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigLog) DeepCopyInto(out *GrafanaConfigLog) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigLog.
+func (in *GrafanaConfigLog) DeepCopy() *GrafanaConfigLog {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigLogConsole) DeepCopyInto(out *GrafanaConfigLogConsole) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigLogConsole.
+func (in *GrafanaConfigLogConsole) DeepCopy() *GrafanaConfigLogConsole {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigLogConsole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigLogFrontend) DeepCopyInto(out *GrafanaConfigLogFrontend) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LogEndpointRequestsPerSecondLimit != nil {
+		in, out := &in.LogEndpointRequestsPerSecondLimit, &out.LogEndpointRequestsPerSecondLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.LogEndpointBurstLimit != nil {
+		in, out := &in.LogEndpointBurstLimit, &out.LogEndpointBurstLimit
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigLogFrontend.
+func (in *GrafanaConfigLogFrontend) DeepCopy() *GrafanaConfigLogFrontend {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigLogFrontend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigMetrics) DeepCopyInto(out *GrafanaConfigMetrics) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigMetrics.
+func (in *GrafanaConfigMetrics) DeepCopy() *GrafanaConfigMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigMetricsGraphite) DeepCopyInto(out *GrafanaConfigMetricsGraphite) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigMetricsGraphite.
+func (in *GrafanaConfigMetricsGraphite) DeepCopy() *GrafanaConfigMetricsGraphite {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigMetricsGraphite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigPanels) DeepCopyInto(out *GrafanaConfigPanels) {
+	*out = *in
+	if in.DisableSanitizeHtml != nil {
+		in, out := &in.DisableSanitizeHtml, &out.DisableSanitizeHtml
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigPanels.
+func (in *GrafanaConfigPanels) DeepCopy() *GrafanaConfigPanels {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigPanels)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigPaths) DeepCopyInto(out *GrafanaConfigPaths) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigPaths.
+func (in *GrafanaConfigPaths) DeepCopy() *GrafanaConfigPaths {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigPaths)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigPlugins) DeepCopyInto(out *GrafanaConfigPlugins) {
+	*out = *in
+	if in.EnableAlpha != nil {
+		in, out := &in.EnableAlpha, &out.EnableAlpha
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigPlugins.
+func (in *GrafanaConfigPlugins) DeepCopy() *GrafanaConfigPlugins {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigPlugins)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigRemoteCache) DeepCopyInto(out *GrafanaConfigRemoteCache) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigRemoteCache.
+func (in *GrafanaConfigRemoteCache) DeepCopy() *GrafanaConfigRemoteCache {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigRemoteCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigRendering) DeepCopyInto(out *GrafanaConfigRendering) {
+	*out = *in
+	if in.ConcurrentRenderRequestLimit != nil {
+		in, out := &in.ConcurrentRenderRequestLimit, &out.ConcurrentRenderRequestLimit
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigRendering.
+func (in *GrafanaConfigRendering) DeepCopy() *GrafanaConfigRendering {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigRendering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigSecurity) DeepCopyInto(out *GrafanaConfigSecurity) {
+	*out = *in
+	if in.LoginRememberDays != nil {
+		in, out := &in.LoginRememberDays, &out.LoginRememberDays
+		*out = new(int)
+		**out = **in
+	}
+	if in.DisableGravatar != nil {
+		in, out := &in.DisableGravatar, &out.DisableGravatar
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CookieSecure != nil {
+		in, out := &in.CookieSecure, &out.CookieSecure
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowEmbedding != nil {
+		in, out := &in.AllowEmbedding, &out.AllowEmbedding
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StrictTransportSecurity != nil {
+		in, out := &in.StrictTransportSecurity, &out.StrictTransportSecurity
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StrictTransportSecurityMaxAgeSeconds != nil {
+		in, out := &in.StrictTransportSecurityMaxAgeSeconds, &out.StrictTransportSecurityMaxAgeSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.StrictTransportSecurityPreload != nil {
+		in, out := &in.StrictTransportSecurityPreload, &out.StrictTransportSecurityPreload
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StrictTransportSecuritySubdomains != nil {
+		in, out := &in.StrictTransportSecuritySubdomains, &out.StrictTransportSecuritySubdomains
+		*out = new(bool)
+		**out = **in
+	}
+	if in.XContentTypeOptions != nil {
+		in, out := &in.XContentTypeOptions, &out.XContentTypeOptions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.XXssProtection != nil {
+		in, out := &in.XXssProtection, &out.XXssProtection
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigSecurity.
+func (in *GrafanaConfigSecurity) DeepCopy() *GrafanaConfigSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigServer) DeepCopyInto(out *GrafanaConfigServer) {
+	*out = *in
+	if in.EnforceDomain != nil {
+		in, out := &in.EnforceDomain, &out.EnforceDomain
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServeFromSubPath != nil {
+		in, out := &in.ServeFromSubPath, &out.ServeFromSubPath
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableGzip != nil {
+		in, out := &in.EnableGzip, &out.EnableGzip
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RouterLogging != nil {
+		in, out := &in.RouterLogging, &out.RouterLogging
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigServer.
+func (in *GrafanaConfigServer) DeepCopy() *GrafanaConfigServer {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigSmtp) DeepCopyInto(out *GrafanaConfigSmtp) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipVerify != nil {
+		in, out := &in.SkipVerify, &out.SkipVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigSmtp.
+func (in *GrafanaConfigSmtp) DeepCopy() *GrafanaConfigSmtp {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigSmtp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigSnapshots) DeepCopyInto(out *GrafanaConfigSnapshots) {
+	*out = *in
+	if in.ExternalEnabled != nil {
+		in, out := &in.ExternalEnabled, &out.ExternalEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SnapshotRemoveExpired != nil {
+		in, out := &in.SnapshotRemoveExpired, &out.SnapshotRemoveExpired
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigSnapshots.
+func (in *GrafanaConfigSnapshots) DeepCopy() *GrafanaConfigSnapshots {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigSnapshots)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigUnifiedAlerting) DeepCopyInto(out *GrafanaConfigUnifiedAlerting) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExecuteAlerts != nil {
+		in, out := &in.ExecuteAlerts, &out.ExecuteAlerts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigUnifiedAlerting.
+func (in *GrafanaConfigUnifiedAlerting) DeepCopy() *GrafanaConfigUnifiedAlerting {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigUnifiedAlerting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfigUsers) DeepCopyInto(out *GrafanaConfigUsers) {
+	*out = *in
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowOrgCreate != nil {
+		in, out := &in.AllowOrgCreate, &out.AllowOrgCreate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoAssignOrg != nil {
+		in, out := &in.AutoAssignOrg, &out.AutoAssignOrg
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ViewersCanEdit != nil {
+		in, out := &in.ViewersCanEdit, &out.ViewersCanEdit
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EditorsCanAdmin != nil {
+		in, out := &in.EditorsCanAdmin, &out.EditorsCanAdmin
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigUsers.
+func (in *GrafanaConfigUsers) DeepCopy() *GrafanaConfigUsers {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfigUsers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaContactPoint) DeepCopyInto(out *GrafanaContactPoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaContactPoint.
+func (in *GrafanaContactPoint) DeepCopy() *GrafanaContactPoint {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaContactPoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaContactPoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaContactPointList) DeepCopyInto(out *GrafanaContactPointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaContactPoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaContactPointList.
+func (in *GrafanaContactPointList) DeepCopy() *GrafanaContactPointList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaContactPointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaContactPointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaContactPointSpec) DeepCopyInto(out *GrafanaContactPointSpec) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = make([]ValueFrom, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaContactPointSpec.
+func (in *GrafanaContactPointSpec) DeepCopy() *GrafanaContactPointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaContactPointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaContactPointStatus) DeepCopyInto(out *GrafanaContactPointStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaContactPointStatus.
+func (in *GrafanaContactPointStatus) DeepCopy() *GrafanaContactPointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaContactPointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaCorrelation) DeepCopyInto(out *GrafanaCorrelation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaCorrelation.
+func (in *GrafanaCorrelation) DeepCopy() *GrafanaCorrelation {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaCorrelation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaCorrelation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaCorrelationInstance) DeepCopyInto(out *GrafanaCorrelationInstance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaCorrelationInstance.
+func (in *GrafanaCorrelationInstance) DeepCopy() *GrafanaCorrelationInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaCorrelationInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaCorrelationList) DeepCopyInto(out *GrafanaCorrelationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaCorrelation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaCorrelationList.
+func (in *GrafanaCorrelationList) DeepCopy() *GrafanaCorrelationList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaCorrelationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaCorrelationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaCorrelationSpec) DeepCopyInto(out *GrafanaCorrelationSpec) {
+	*out = *in
+	out.Source = in.Source
+	out.Target = in.Target
+	in.Config.DeepCopyInto(&out.Config)
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaCorrelationSpec.
+func (in *GrafanaCorrelationSpec) DeepCopy() *GrafanaCorrelationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaCorrelationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaCorrelationStatus) DeepCopyInto(out *GrafanaCorrelationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Correlations != nil {
+		in, out := &in.Correlations, &out.Correlations
+		*out = make([]GrafanaCorrelationInstance, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaCorrelationStatus.
+func (in *GrafanaCorrelationStatus) DeepCopy() *GrafanaCorrelationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaCorrelationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboard) DeepCopyInto(out *GrafanaDashboard) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboard.
+func (in *GrafanaDashboard) DeepCopy() *GrafanaDashboard {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDashboard) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardConfigMapRef) DeepCopyInto(out *GrafanaDashboardConfigMapRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardConfigMapRef.
+func (in *GrafanaDashboardConfigMapRef) DeepCopy() *GrafanaDashboardConfigMapRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardConfigMapRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardDatasource) DeepCopyInto(out *GrafanaDashboardDatasource) {
+	*out = *in
+	out.DatasourceReference = in.DatasourceReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardDatasource.
+func (in *GrafanaDashboardDatasource) DeepCopy() *GrafanaDashboardDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardEnv) DeepCopyInto(out *GrafanaDashboardEnv) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ValueFromSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardEnv.
+func (in *GrafanaDashboardEnv) DeepCopy() *GrafanaDashboardEnv {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardEnv)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardFolder) DeepCopyInto(out *GrafanaDashboardFolder) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardFolder.
+func (in *GrafanaDashboardFolder) DeepCopy() *GrafanaDashboardFolder {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardFolder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDashboardFolder) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardFolderItem) DeepCopyInto(out *GrafanaDashboardFolderItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardFolderItem.
+func (in *GrafanaDashboardFolderItem) DeepCopy() *GrafanaDashboardFolderItem {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardFolderItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardFolderList) DeepCopyInto(out *GrafanaDashboardFolderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaDashboardFolder, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardFolderList.
+func (in *GrafanaDashboardFolderList) DeepCopy() *GrafanaDashboardFolderList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardFolderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDashboardFolderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardFolderSpec) DeepCopyInto(out *GrafanaDashboardFolderSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(GrafanaDashboardObjectStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitRepository != nil {
+		in, out := &in.GitRepository, &out.GitRepository
+		*out = new(GitRepositorySource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Datasources != nil {
+		in, out := &in.Datasources, &out.Datasources
+		*out = make([]GrafanaDashboardDatasource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make([]GrafanaDashboardEnv, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContentCacheDuration != nil {
+		in, out := &in.ContentCacheDuration, &out.ContentCacheDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardFolderSpec.
+func (in *GrafanaDashboardFolderSpec) DeepCopy() *GrafanaDashboardFolderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardFolderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardFolderStatus) DeepCopyInto(out *GrafanaDashboardFolderStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = make([]GrafanaDashboardFolderItem, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardFolderStatus.
+func (in *GrafanaDashboardFolderStatus) DeepCopy() *GrafanaDashboardFolderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardFolderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardList) DeepCopyInto(out *GrafanaDashboardList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaDashboard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardList.
+func (in *GrafanaDashboardList) DeepCopy() *GrafanaDashboardList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDashboardList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardObjectStorage) DeepCopyInto(out *GrafanaDashboardObjectStorage) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardObjectStorage.
+func (in *GrafanaDashboardObjectStorage) DeepCopy() *GrafanaDashboardObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardPermission) DeepCopyInto(out *GrafanaDashboardPermission) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardPermission.
+func (in *GrafanaDashboardPermission) DeepCopy() *GrafanaDashboardPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDashboardPermission) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardPermissionList) DeepCopyInto(out *GrafanaDashboardPermissionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaDashboardPermission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardPermissionList.
+func (in *GrafanaDashboardPermissionList) DeepCopy() *GrafanaDashboardPermissionList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardPermissionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDashboardPermissionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardPermissionSpec) DeepCopyInto(out *GrafanaDashboardPermissionSpec) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]GrafanaPermissionItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardPermissionSpec.
+func (in *GrafanaDashboardPermissionSpec) DeepCopy() *GrafanaDashboardPermissionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardPermissionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardPermissionStatus) DeepCopyInto(out *GrafanaDashboardPermissionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardPermissionStatus.
+func (in *GrafanaDashboardPermissionStatus) DeepCopy() *GrafanaDashboardPermissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardPermissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardSpec) DeepCopyInto(out *GrafanaDashboardSpec) {
+	*out = *in
+	if in.GzipJson != nil {
+		in, out := &in.GzipJson, &out.GzipJson
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(GrafanaDashboardConfigMapRef)
+		**out = **in
+	}
+	if in.GrafanaCom != nil {
+		in, out := &in.GrafanaCom, &out.GrafanaCom
+		*out = new(GrafanaComDashboardReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(GrafanaDashboardObjectStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIArtifactSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitRepository != nil {
+		in, out := &in.GitRepository, &out.GitRepository
+		*out = new(GitRepositorySource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UrlHeaders != nil {
+		in, out := &in.UrlHeaders, &out.UrlHeaders
+		*out = make([]GrafanaDashboardUrlHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContentCacheDuration != nil {
+		in, out := &in.ContentCacheDuration, &out.ContentCacheDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Datasources != nil {
+		in, out := &in.Datasources, &out.Datasources
+		*out = make([]GrafanaDashboardDatasource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make([]GrafanaDashboardEnv, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make(PluginList, len(*in))
+		copy(*out, *in)
+	}
+	in.ResyncPolicy.DeepCopyInto(&out.ResyncPolicy)
+	if in.LibraryPanelRefs != nil {
+		in, out := &in.LibraryPanelRefs, &out.LibraryPanelRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardSpec.
+func (in *GrafanaDashboardSpec) DeepCopy() *GrafanaDashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardStatus) DeepCopyInto(out *GrafanaDashboardStatus) {
+	*out = *in
+	in.SyncRetryStatus.DeepCopyInto(&out.SyncRetryStatus)
+	out.ReconcileRequestStatus = in.ReconcileRequestStatus
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DerivedPlugins != nil {
+		in, out := &in.DerivedPlugins, &out.DerivedPlugins
+		*out = make(PluginList, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContentCache != nil {
+		in, out := &in.ContentCache, &out.ContentCache
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContentTimestamp != nil {
+		in, out := &in.ContentTimestamp, &out.ContentTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = make([]DryRunResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Adopted != nil {
+		in, out := &in.Adopted, &out.Adopted
+		*out = make([]AdoptedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]InstanceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSynced != nil {
+		in, out := &in.LastSynced, &out.LastSynced
+		*out = (*in).DeepCopy()
+	}
+	if in.GrafanaComRevisionTime != nil {
+		in, out := &in.GrafanaComRevisionTime, &out.GrafanaComRevisionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardStatus.
+func (in *GrafanaDashboardStatus) DeepCopy() *GrafanaDashboardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardUrlHeader) DeepCopyInto(out *GrafanaDashboardUrlHeader) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ValueFromSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardUrlHeader.
+func (in *GrafanaDashboardUrlHeader) DeepCopy() *GrafanaDashboardUrlHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardUrlHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDataStorage) DeepCopyInto(out *GrafanaDataStorage) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDataStorage.
+func (in *GrafanaDataStorage) DeepCopy() *GrafanaDataStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDataStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatabase) DeepCopyInto(out *GrafanaDatabase) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(GrafanaDatabaseTLS)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatabase.
+func (in *GrafanaDatabase) DeepCopy() *GrafanaDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatabaseTLS) DeepCopyInto(out *GrafanaDatabaseTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatabaseTLS.
+func (in *GrafanaDatabaseTLS) DeepCopy() *GrafanaDatabaseTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatabaseTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasource) DeepCopyInto(out *GrafanaDatasource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasource.
+func (in *GrafanaDatasource) DeepCopy() *GrafanaDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDatasource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceDiscovery) DeepCopyInto(out *GrafanaDatasourceDiscovery) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]DiscoveredDatasourceType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceDiscovery.
+func (in *GrafanaDatasourceDiscovery) DeepCopy() *GrafanaDatasourceDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceHealth) DeepCopyInto(out *GrafanaDatasourceHealth) {
+	*out = *in
+	in.LastChecked.DeepCopyInto(&out.LastChecked)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceHealth.
+func (in *GrafanaDatasourceHealth) DeepCopy() *GrafanaDatasourceHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceHttpHeader) DeepCopyInto(out *GrafanaDatasourceHttpHeader) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ValueFromSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceHttpHeader.
+func (in *GrafanaDatasourceHttpHeader) DeepCopy() *GrafanaDatasourceHttpHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceHttpHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceInternal) DeepCopyInto(out *GrafanaDatasourceInternal) {
+	*out = *in
+	if in.IsDefault != nil {
+		in, out := &in.IsDefault, &out.IsDefault
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Editable != nil {
+		in, out := &in.Editable, &out.Editable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.JSONData != nil {
+		in, out := &in.JSONData, &out.JSONData
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceInternal.
+func (in *GrafanaDatasourceInternal) DeepCopy() *GrafanaDatasourceInternal {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceInternal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceList) DeepCopyInto(out *GrafanaDatasourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaDatasource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceList.
+func (in *GrafanaDatasourceList) DeepCopy() *GrafanaDatasourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDatasourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourcePermission) DeepCopyInto(out *GrafanaDatasourcePermission) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourcePermission.
+func (in *GrafanaDatasourcePermission) DeepCopy() *GrafanaDatasourcePermission {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourcePermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDatasourcePermission) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourcePermissionItem) DeepCopyInto(out *GrafanaDatasourcePermissionItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourcePermissionItem.
+func (in *GrafanaDatasourcePermissionItem) DeepCopy() *GrafanaDatasourcePermissionItem {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourcePermissionItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourcePermissionList) DeepCopyInto(out *GrafanaDatasourcePermissionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaDatasourcePermission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourcePermissionList.
+func (in *GrafanaDatasourcePermissionList) DeepCopy() *GrafanaDatasourcePermissionList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourcePermissionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaDatasourcePermissionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourcePermissionSpec) DeepCopyInto(out *GrafanaDatasourcePermissionSpec) {
+	*out = *in
+	out.DatasourceReference = in.DatasourceReference
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]GrafanaDatasourcePermissionItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourcePermissionSpec.
+func (in *GrafanaDatasourcePermissionSpec) DeepCopy() *GrafanaDatasourcePermissionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourcePermissionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourcePermissionStatus) DeepCopyInto(out *GrafanaDatasourcePermissionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourcePermissionStatus.
+func (in *GrafanaDatasourcePermissionStatus) DeepCopy() *GrafanaDatasourcePermissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourcePermissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceSpec) DeepCopyInto(out *GrafanaDatasourceSpec) {
+	*out = *in
+	if in.Datasource != nil {
+		in, out := &in.Datasource, &out.Datasource
+		*out = new(GrafanaDatasourceInternal)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = make([]ValueFrom, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HttpHeaders != nil {
+		in, out := &in.HttpHeaders, &out.HttpHeaders
+		*out = make([]GrafanaDatasourceHttpHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make(PluginList, len(*in))
+		copy(*out, *in)
+	}
+	in.ResyncPolicy.DeepCopyInto(&out.ResyncPolicy)
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceSpec.
+func (in *GrafanaDatasourceSpec) DeepCopy() *GrafanaDatasourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceStatus) DeepCopyInto(out *GrafanaDatasourceStatus) {
+	*out = *in
+	in.SyncRetryStatus.DeepCopyInto(&out.SyncRetryStatus)
+	out.ReconcileRequestStatus = in.ReconcileRequestStatus
+	if in.DerivedPlugins != nil {
+		in, out := &in.DerivedPlugins, &out.DerivedPlugins
+		*out = make(PluginList, len(*in))
+		copy(*out, *in)
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = make([]DryRunResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = make([]GrafanaDatasourceHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Adopted != nil {
+		in, out := &in.Adopted, &out.Adopted
+		*out = make([]AdoptedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]InstanceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSynced != nil {
+		in, out := &in.LastSynced, &out.LastSynced
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceStatus.
+func (in *GrafanaDatasourceStatus) DeepCopy() *GrafanaDatasourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDeployment) DeepCopyInto(out *GrafanaDeployment) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SkipCreateAdminAccount != nil {
+		in, out := &in.SkipCreateAdminAccount, &out.SkipCreateAdminAccount
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HostNetwork != nil {
+		in, out := &in.HostNetwork, &out.HostNetwork
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HttpProxy != nil {
+		in, out := &in.HttpProxy, &out.HttpProxy
+		*out = new(GrafanaHttpProxy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDeployment.
+func (in *GrafanaDeployment) DeepCopy() *GrafanaDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaExternal) DeepCopyInto(out *GrafanaExternal) {
+	*out = *in
+	if in.AdminUser != nil {
+		in, out := &in.AdminUser, &out.AdminUser
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminPassword != nil {
+		in, out := &in.AdminPassword, &out.AdminPassword
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(GrafanaExternalTLS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaExternal.
+func (in *GrafanaExternal) DeepCopy() *GrafanaExternal {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaExternal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaExternalTLS) DeepCopyInto(out *GrafanaExternalTLS) {
+	*out = *in
+	if in.CertificateAuthority != nil {
+		in, out := &in.CertificateAuthority, &out.CertificateAuthority
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientKey != nil {
+		in, out := &in.ClientKey, &out.ClientKey
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaExternalTLS.
+func (in *GrafanaExternalTLS) DeepCopy() *GrafanaExternalTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaExternalTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolder) DeepCopyInto(out *GrafanaFolder) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolder.
+func (in *GrafanaFolder) DeepCopy() *GrafanaFolder {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaFolder) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderList) DeepCopyInto(out *GrafanaFolderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaFolder, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderList.
+func (in *GrafanaFolderList) DeepCopy() *GrafanaFolderList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaFolderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderPermission) DeepCopyInto(out *GrafanaFolderPermission) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderPermission.
+func (in *GrafanaFolderPermission) DeepCopy() *GrafanaFolderPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaFolderPermission) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderPermissionList) DeepCopyInto(out *GrafanaFolderPermissionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaFolderPermission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderPermissionList.
+func (in *GrafanaFolderPermissionList) DeepCopy() *GrafanaFolderPermissionList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderPermissionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaFolderPermissionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderPermissionSpec) DeepCopyInto(out *GrafanaFolderPermissionSpec) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]GrafanaPermissionItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderPermissionSpec.
+func (in *GrafanaFolderPermissionSpec) DeepCopy() *GrafanaFolderPermissionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderPermissionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderPermissionStatus) DeepCopyInto(out *GrafanaFolderPermissionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderPermissionStatus.
+func (in *GrafanaFolderPermissionStatus) DeepCopy() *GrafanaFolderPermissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderPermissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderSpec) DeepCopyInto(out *GrafanaFolderSpec) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]GrafanaPermissionItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
+	in.ResyncPolicy.DeepCopyInto(&out.ResyncPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderSpec.
+func (in *GrafanaFolderSpec) DeepCopy() *GrafanaFolderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderStatus) DeepCopyInto(out *GrafanaFolderStatus) {
+	*out = *in
+	in.SyncRetryStatus.DeepCopyInto(&out.SyncRetryStatus)
+	out.ReconcileRequestStatus = in.ReconcileRequestStatus
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]InstanceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderStatus.
+func (in *GrafanaFolderStatus) DeepCopy() *GrafanaFolderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaHttpProxy) DeepCopyInto(out *GrafanaHttpProxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaHttpProxy.
+func (in *GrafanaHttpProxy) DeepCopy() *GrafanaHttpProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaHttpProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaImageRenderer) DeepCopyInto(out *GrafanaImageRenderer) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaImageRenderer.
+func (in *GrafanaImageRenderer) DeepCopy() *GrafanaImageRenderer {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaImageRenderer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLDAPConfig) DeepCopyInto(out *GrafanaLDAPConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLDAPConfig.
+func (in *GrafanaLDAPConfig) DeepCopy() *GrafanaLDAPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLDAPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaLDAPConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLDAPConfigList) DeepCopyInto(out *GrafanaLDAPConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaLDAPConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLDAPConfigList.
+func (in *GrafanaLDAPConfigList) DeepCopy() *GrafanaLDAPConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLDAPConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaLDAPConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLDAPConfigSpec) DeepCopyInto(out *GrafanaLDAPConfigSpec) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]LDAPServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllowSignUp != nil {
+		in, out := &in.AllowSignUp, &out.AllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLDAPConfigSpec.
+func (in *GrafanaLDAPConfigSpec) DeepCopy() *GrafanaLDAPConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLDAPConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLDAPConfigStatus) DeepCopyInto(out *GrafanaLDAPConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLDAPConfigStatus.
+func (in *GrafanaLDAPConfigStatus) DeepCopy() *GrafanaLDAPConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLDAPConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLibraryPanel) DeepCopyInto(out *GrafanaLibraryPanel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLibraryPanel.
+func (in *GrafanaLibraryPanel) DeepCopy() *GrafanaLibraryPanel {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLibraryPanel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaLibraryPanel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLibraryPanelList) DeepCopyInto(out *GrafanaLibraryPanelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaLibraryPanel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLibraryPanelList.
+func (in *GrafanaLibraryPanelList) DeepCopy() *GrafanaLibraryPanelList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLibraryPanelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaLibraryPanelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLibraryPanelSpec) DeepCopyInto(out *GrafanaLibraryPanelSpec) {
+	*out = *in
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIArtifactSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLibraryPanelSpec.
+func (in *GrafanaLibraryPanelSpec) DeepCopy() *GrafanaLibraryPanelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLibraryPanelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLibraryPanelStatus) DeepCopyInto(out *GrafanaLibraryPanelStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]InstanceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLibraryPanelStatus.
+func (in *GrafanaLibraryPanelStatus) DeepCopy() *GrafanaLibraryPanelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLibraryPanelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaList) DeepCopyInto(out *GrafanaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Grafana, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaList.
+func (in *GrafanaList) DeepCopy() *GrafanaList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaMetrics) DeepCopyInto(out *GrafanaMetrics) {
+	*out = *in
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(GrafanaServiceMonitor)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaMetrics.
+func (in *GrafanaMetrics) DeepCopy() *GrafanaMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaMuteTiming) DeepCopyInto(out *GrafanaMuteTiming) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaMuteTiming.
+func (in *GrafanaMuteTiming) DeepCopy() *GrafanaMuteTiming {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaMuteTiming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaMuteTiming) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaMuteTimingList) DeepCopyInto(out *GrafanaMuteTimingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaMuteTiming, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaMuteTimingList.
+func (in *GrafanaMuteTimingList) DeepCopy() *GrafanaMuteTimingList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaMuteTimingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaMuteTimingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaMuteTimingSpec) DeepCopyInto(out *GrafanaMuteTimingSpec) {
+	*out = *in
+	if in.TimeIntervals != nil {
+		in, out := &in.TimeIntervals, &out.TimeIntervals
+		*out = make([]TimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaMuteTimingSpec.
+func (in *GrafanaMuteTimingSpec) DeepCopy() *GrafanaMuteTimingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaMuteTimingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaMuteTimingStatus) DeepCopyInto(out *GrafanaMuteTimingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaMuteTimingStatus.
+func (in *GrafanaMuteTimingStatus) DeepCopy() *GrafanaMuteTimingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaMuteTimingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaNotificationPolicy) DeepCopyInto(out *GrafanaNotificationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaNotificationPolicy.
+func (in *GrafanaNotificationPolicy) DeepCopy() *GrafanaNotificationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaNotificationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaNotificationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaNotificationPolicyList) DeepCopyInto(out *GrafanaNotificationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaNotificationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaNotificationPolicyList.
+func (in *GrafanaNotificationPolicyList) DeepCopy() *GrafanaNotificationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaNotificationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaNotificationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaNotificationPolicySpec) DeepCopyInto(out *GrafanaNotificationPolicySpec) {
+	*out = *in
+	if in.Root != nil {
+		in, out := &in.Root, &out.Root
+		*out = new(NotificationPolicySettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]NotificationPolicyRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaNotificationPolicySpec.
+func (in *GrafanaNotificationPolicySpec) DeepCopy() *GrafanaNotificationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaNotificationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaNotificationPolicyStatus) DeepCopyInto(out *GrafanaNotificationPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaNotificationPolicyStatus.
+func (in *GrafanaNotificationPolicyStatus) DeepCopy() *GrafanaNotificationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaNotificationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaOrganization) DeepCopyInto(out *GrafanaOrganization) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaOrganization.
+func (in *GrafanaOrganization) DeepCopy() *GrafanaOrganization {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaOrganization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaOrganization) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaOrganizationList) DeepCopyInto(out *GrafanaOrganizationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaOrganization, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaOrganizationList.
+func (in *GrafanaOrganizationList) DeepCopy() *GrafanaOrganizationList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaOrganizationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaOrganizationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaOrganizationSpec) DeepCopyInto(out *GrafanaOrganizationSpec) {
+	*out = *in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaOrganizationSpec.
+func (in *GrafanaOrganizationSpec) DeepCopy() *GrafanaOrganizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaOrganizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaOrganizationStatus) DeepCopyInto(out *GrafanaOrganizationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaOrganizationStatus.
+func (in *GrafanaOrganizationStatus) DeepCopy() *GrafanaOrganizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaOrganizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPermissionItem) DeepCopyInto(out *GrafanaPermissionItem) {
+	*out = *in
+	if in.TeamId != nil {
+		in, out := &in.TeamId, &out.TeamId
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UserId != nil {
+		in, out := &in.UserId, &out.UserId
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPermissionItem.
+func (in *GrafanaPermissionItem) DeepCopy() *GrafanaPermissionItem {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPermissionItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPersistence) DeepCopyInto(out *GrafanaPersistence) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPersistence.
+func (in *GrafanaPersistence) DeepCopy() *GrafanaPersistence {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPersistence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPlugin) DeepCopyInto(out *GrafanaPlugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPlugin.
+func (in *GrafanaPlugin) DeepCopy() *GrafanaPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPluginBundle) DeepCopyInto(out *GrafanaPluginBundle) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPluginBundle.
+func (in *GrafanaPluginBundle) DeepCopy() *GrafanaPluginBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPluginBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPlugins) DeepCopyInto(out *GrafanaPlugins) {
+	*out = *in
+	if in.OfflineBundle != nil {
+		in, out := &in.OfflineBundle, &out.OfflineBundle
+		*out = new(GrafanaPluginBundle)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPlugins.
+func (in *GrafanaPlugins) DeepCopy() *GrafanaPlugins {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPlugins)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPodDisruptionBudget) DeepCopyInto(out *GrafanaPodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPodDisruptionBudget.
+func (in *GrafanaPodDisruptionBudget) DeepCopy() *GrafanaPodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPreferences) DeepCopyInto(out *GrafanaPreferences) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPreferences.
+func (in *GrafanaPreferences) DeepCopy() *GrafanaPreferences {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPreferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaPreferences) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPreferencesList) DeepCopyInto(out *GrafanaPreferencesList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaPreferences, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPreferencesList.
+func (in *GrafanaPreferencesList) DeepCopy() *GrafanaPreferencesList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPreferencesList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaPreferencesList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPreferencesSpec) DeepCopyInto(out *GrafanaPreferencesSpec) {
+	*out = *in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPreferencesSpec.
+func (in *GrafanaPreferencesSpec) DeepCopy() *GrafanaPreferencesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPreferencesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPreferencesStatus) DeepCopyInto(out *GrafanaPreferencesStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPreferencesStatus.
+func (in *GrafanaPreferencesStatus) DeepCopy() *GrafanaPreferencesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPreferencesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaProbes) DeepCopyInto(out *GrafanaProbes) {
+	*out = *in
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaProbes.
+func (in *GrafanaProbes) DeepCopy() *GrafanaProbes {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaProbes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPublicDashboard) DeepCopyInto(out *GrafanaPublicDashboard) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPublicDashboard.
+func (in *GrafanaPublicDashboard) DeepCopy() *GrafanaPublicDashboard {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPublicDashboard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaPublicDashboard) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPublicDashboardList) DeepCopyInto(out *GrafanaPublicDashboardList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaPublicDashboard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfig.
-func (in *GrafanaConfig) DeepCopy() *GrafanaConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPublicDashboardList.
+func (in *GrafanaPublicDashboardList) DeepCopy() *GrafanaPublicDashboardList {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfig)
+	out := new(GrafanaPublicDashboardList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaPublicDashboardList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAlerting) DeepCopyInto(out *GrafanaConfigAlerting) {
+func (in *GrafanaPublicDashboardSpec) DeepCopyInto(out *GrafanaPublicDashboardSpec) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ExecuteAlerts != nil {
-		in, out := &in.ExecuteAlerts, &out.ExecuteAlerts
+	if in.IsEnabled != nil {
+		in, out := &in.IsEnabled, &out.IsEnabled
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ConcurrentRenderLimit != nil {
-		in, out := &in.ConcurrentRenderLimit, &out.ConcurrentRenderLimit
-		*out = new(int)
-		**out = **in
-	}
-	if in.EvaluationTimeoutSeconds != nil {
-		in, out := &in.EvaluationTimeoutSeconds, &out.EvaluationTimeoutSeconds
-		*out = new(int)
-		**out = **in
-	}
-	if in.NotificationTimeoutSeconds != nil {
-		in, out := &in.NotificationTimeoutSeconds, &out.NotificationTimeoutSeconds
-		*out = new(int)
-		**out = **in
-	}
-	if in.MaxAttempts != nil {
-		in, out := &in.MaxAttempts, &out.MaxAttempts
-		*out = new(int)
-		**out = **in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAlerting.
-func (in *GrafanaConfigAlerting) DeepCopy() *GrafanaConfigAlerting {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPublicDashboardSpec.
+func (in *GrafanaPublicDashboardSpec) DeepCopy() *GrafanaPublicDashboardSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAlerting)
+	out := new(GrafanaPublicDashboardSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAnalytics) DeepCopyInto(out *GrafanaConfigAnalytics) {
+func (in *GrafanaPublicDashboardStatus) DeepCopyInto(out *GrafanaPublicDashboardStatus) {
 	*out = *in
-	if in.ReportingEnabled != nil {
-		in, out := &in.ReportingEnabled, &out.ReportingEnabled
-		*out = new(bool)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.CheckForUpdates != nil {
-		in, out := &in.CheckForUpdates, &out.CheckForUpdates
-		*out = new(bool)
-		**out = **in
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]GrafanaPublicDashboardURL, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAnalytics.
-func (in *GrafanaConfigAnalytics) DeepCopy() *GrafanaConfigAnalytics {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPublicDashboardStatus.
+func (in *GrafanaPublicDashboardStatus) DeepCopy() *GrafanaPublicDashboardStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAnalytics)
+	out := new(GrafanaPublicDashboardStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuth) DeepCopyInto(out *GrafanaConfigAuth) {
+func (in *GrafanaPublicDashboardURL) DeepCopyInto(out *GrafanaPublicDashboardURL) {
 	*out = *in
-	if in.LoginMaximumInactiveLifetimeDays != nil {
-		in, out := &in.LoginMaximumInactiveLifetimeDays, &out.LoginMaximumInactiveLifetimeDays
-		*out = new(int)
-		**out = **in
-	}
-	if in.LoginMaximumLifetimeDays != nil {
-		in, out := &in.LoginMaximumLifetimeDays, &out.LoginMaximumLifetimeDays
-		*out = new(int)
-		**out = **in
-	}
-	if in.TokenRotationIntervalMinutes != nil {
-		in, out := &in.TokenRotationIntervalMinutes, &out.TokenRotationIntervalMinutes
-		*out = new(int)
-		**out = **in
-	}
-	if in.DisableLoginForm != nil {
-		in, out := &in.DisableLoginForm, &out.DisableLoginForm
-		*out = new(bool)
-		**out = **in
-	}
-	if in.DisableSignoutMenu != nil {
-		in, out := &in.DisableSignoutMenu, &out.DisableSignoutMenu
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SigV4AuthEnabled != nil {
-		in, out := &in.SigV4AuthEnabled, &out.SigV4AuthEnabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.OauthAutoLogin != nil {
-		in, out := &in.OauthAutoLogin, &out.OauthAutoLogin
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuth.
-func (in *GrafanaConfigAuth) DeepCopy() *GrafanaConfigAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPublicDashboardURL.
+func (in *GrafanaPublicDashboardURL) DeepCopy() *GrafanaPublicDashboardURL {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuth)
+	out := new(GrafanaPublicDashboardURL)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthAnonymous) DeepCopyInto(out *GrafanaConfigAuthAnonymous) {
+func (in *GrafanaResolvedPlugin) DeepCopyInto(out *GrafanaResolvedPlugin) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthAnonymous.
-func (in *GrafanaConfigAuthAnonymous) DeepCopy() *GrafanaConfigAuthAnonymous {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaResolvedPlugin.
+func (in *GrafanaResolvedPlugin) DeepCopy() *GrafanaResolvedPlugin {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthAnonymous)
+	out := new(GrafanaResolvedPlugin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthAzureAD) DeepCopyInto(out *GrafanaConfigAuthAzureAD) {
+func (in *GrafanaRestore) DeepCopyInto(out *GrafanaRestore) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AllowSignUp != nil {
-		in, out := &in.AllowSignUp, &out.AllowSignUp
-		*out = new(bool)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthAzureAD.
-func (in *GrafanaConfigAuthAzureAD) DeepCopy() *GrafanaConfigAuthAzureAD {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRestore.
+func (in *GrafanaRestore) DeepCopy() *GrafanaRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthAzureAD)
+	out := new(GrafanaRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthBasic) DeepCopyInto(out *GrafanaConfigAuthBasic) {
+func (in *GrafanaRestoreInstance) DeepCopyInto(out *GrafanaRestoreInstance) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthBasic.
-func (in *GrafanaConfigAuthBasic) DeepCopy() *GrafanaConfigAuthBasic {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRestoreInstance.
+func (in *GrafanaRestoreInstance) DeepCopy() *GrafanaRestoreInstance {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthBasic)
+	out := new(GrafanaRestoreInstance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthGenericOauth) DeepCopyInto(out *GrafanaConfigAuthGenericOauth) {
+func (in *GrafanaRestoreList) DeepCopyInto(out *GrafanaRestoreList) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.AllowSignUp != nil {
-		in, out := &in.AllowSignUp, &out.AllowSignUp
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRestoreList.
+func (in *GrafanaRestoreList) DeepCopy() *GrafanaRestoreList {
+	if in == nil {
+		return nil
 	}
-	if in.RoleAttributeStrict != nil {
-		in, out := &in.RoleAttributeStrict, &out.RoleAttributeStrict
-		*out = new(bool)
-		**out = **in
+	out := new(GrafanaRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.TLSSkipVerifyInsecure != nil {
-		in, out := &in.TLSSkipVerifyInsecure, &out.TLSSkipVerifyInsecure
-		*out = new(bool)
-		**out = **in
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaRestoreSpec) DeepCopyInto(out *GrafanaRestoreSpec) {
+	*out = *in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthGenericOauth.
-func (in *GrafanaConfigAuthGenericOauth) DeepCopy() *GrafanaConfigAuthGenericOauth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRestoreSpec.
+func (in *GrafanaRestoreSpec) DeepCopy() *GrafanaRestoreSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthGenericOauth)
+	out := new(GrafanaRestoreSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthGithub) DeepCopyInto(out *GrafanaConfigAuthGithub) {
+func (in *GrafanaRestoreStatus) DeepCopyInto(out *GrafanaRestoreStatus) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.AllowSignUp != nil {
-		in, out := &in.AllowSignUp, &out.AllowSignUp
-		*out = new(bool)
-		**out = **in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]GrafanaRestoreInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthGithub.
-func (in *GrafanaConfigAuthGithub) DeepCopy() *GrafanaConfigAuthGithub {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRestoreStatus.
+func (in *GrafanaRestoreStatus) DeepCopy() *GrafanaRestoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthGithub)
+	out := new(GrafanaRestoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthGitlab) DeepCopyInto(out *GrafanaConfigAuthGitlab) {
+func (in *GrafanaRole) DeepCopyInto(out *GrafanaRole) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRole.
+func (in *GrafanaRole) DeepCopy() *GrafanaRole {
+	if in == nil {
+		return nil
 	}
-	if in.AllowSignUp != nil {
-		in, out := &in.AllowSignUp, &out.AllowSignUp
-		*out = new(bool)
-		**out = **in
+	out := new(GrafanaRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthGitlab.
-func (in *GrafanaConfigAuthGitlab) DeepCopy() *GrafanaConfigAuthGitlab {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaRoleBinding) DeepCopyInto(out *GrafanaRoleBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleBinding.
+func (in *GrafanaRoleBinding) DeepCopy() *GrafanaRoleBinding {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthGitlab)
+	out := new(GrafanaRoleBinding)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaRoleBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthGoogle) DeepCopyInto(out *GrafanaConfigAuthGoogle) {
+func (in *GrafanaRoleBindingList) DeepCopyInto(out *GrafanaRoleBindingList) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AllowSignUp != nil {
-		in, out := &in.AllowSignUp, &out.AllowSignUp
-		*out = new(bool)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthGoogle.
-func (in *GrafanaConfigAuthGoogle) DeepCopy() *GrafanaConfigAuthGoogle {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleBindingList.
+func (in *GrafanaRoleBindingList) DeepCopy() *GrafanaRoleBindingList {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthGoogle)
+	out := new(GrafanaRoleBindingList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaRoleBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthLdap) DeepCopyInto(out *GrafanaConfigAuthLdap) {
+func (in *GrafanaRoleBindingSpec) DeepCopyInto(out *GrafanaRoleBindingSpec) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AllowSignUp != nil {
-		in, out := &in.AllowSignUp, &out.AllowSignUp
-		*out = new(bool)
-		**out = **in
+	in.GrafanaRoleSubjects.DeepCopyInto(&out.GrafanaRoleSubjects)
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthLdap.
-func (in *GrafanaConfigAuthLdap) DeepCopy() *GrafanaConfigAuthLdap {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleBindingSpec.
+func (in *GrafanaRoleBindingSpec) DeepCopy() *GrafanaRoleBindingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthLdap)
+	out := new(GrafanaRoleBindingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthOkta) DeepCopyInto(out *GrafanaConfigAuthOkta) {
+func (in *GrafanaRoleBindingStatus) DeepCopyInto(out *GrafanaRoleBindingStatus) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AllowSignUp != nil {
-		in, out := &in.AllowSignUp, &out.AllowSignUp
-		*out = new(bool)
-		**out = **in
-	}
-	if in.RoleAttributeStrict != nil {
-		in, out := &in.RoleAttributeStrict, &out.RoleAttributeStrict
-		*out = new(bool)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
+	in.Bound.DeepCopyInto(&out.Bound)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthOkta.
-func (in *GrafanaConfigAuthOkta) DeepCopy() *GrafanaConfigAuthOkta {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleBindingStatus.
+func (in *GrafanaRoleBindingStatus) DeepCopy() *GrafanaRoleBindingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthOkta)
+	out := new(GrafanaRoleBindingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthProxy) DeepCopyInto(out *GrafanaConfigAuthProxy) {
+func (in *GrafanaRoleList) DeepCopyInto(out *GrafanaRoleList) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AutoSignUp != nil {
-		in, out := &in.AutoSignUp, &out.AutoSignUp
-		*out = new(bool)
-		**out = **in
-	}
-	if in.EnableLoginToken != nil {
-		in, out := &in.EnableLoginToken, &out.EnableLoginToken
-		*out = new(bool)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthProxy.
-func (in *GrafanaConfigAuthProxy) DeepCopy() *GrafanaConfigAuthProxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleList.
+func (in *GrafanaRoleList) DeepCopy() *GrafanaRoleList {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthProxy)
+	out := new(GrafanaRoleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigAuthSaml) DeepCopyInto(out *GrafanaConfigAuthSaml) {
+func (in *GrafanaRolePermission) DeepCopyInto(out *GrafanaRolePermission) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SingleLogout != nil {
-		in, out := &in.SingleLogout, &out.SingleLogout
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AllowIdpInitiated != nil {
-		in, out := &in.AllowIdpInitiated, &out.AllowIdpInitiated
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigAuthSaml.
-func (in *GrafanaConfigAuthSaml) DeepCopy() *GrafanaConfigAuthSaml {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRolePermission.
+func (in *GrafanaRolePermission) DeepCopy() *GrafanaRolePermission {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigAuthSaml)
+	out := new(GrafanaRolePermission)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigDashboards) DeepCopyInto(out *GrafanaConfigDashboards) {
+func (in *GrafanaRoleSpec) DeepCopyInto(out *GrafanaRoleSpec) {
 	*out = *in
-	if in.VersionsToKeep != nil {
-		in, out := &in.VersionsToKeep, &out.VersionsToKeep
-		*out = new(int)
-		**out = **in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]GrafanaRolePermission, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
+	in.OrgReference.DeepCopyInto(&out.OrgReference)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigDashboards.
-func (in *GrafanaConfigDashboards) DeepCopy() *GrafanaConfigDashboards {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleSpec.
+func (in *GrafanaRoleSpec) DeepCopy() *GrafanaRoleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigDashboards)
+	out := new(GrafanaRoleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigDataProxy) DeepCopyInto(out *GrafanaConfigDataProxy) {
+func (in *GrafanaRoleStatus) DeepCopyInto(out *GrafanaRoleStatus) {
 	*out = *in
-	if in.Logging != nil {
-		in, out := &in.Logging, &out.Logging
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Timeout != nil {
-		in, out := &in.Timeout, &out.Timeout
-		*out = new(int)
-		**out = **in
-	}
-	if in.SendUserHeader != nil {
-		in, out := &in.SendUserHeader, &out.SendUserHeader
-		*out = new(bool)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigDataProxy.
-func (in *GrafanaConfigDataProxy) DeepCopy() *GrafanaConfigDataProxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleStatus.
+func (in *GrafanaRoleStatus) DeepCopy() *GrafanaRoleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigDataProxy)
+	out := new(GrafanaRoleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigDatabase) DeepCopyInto(out *GrafanaConfigDatabase) {
+func (in *GrafanaRoleSubjects) DeepCopyInto(out *GrafanaRoleSubjects) {
 	*out = *in
-	if in.MaxIdleConn != nil {
-		in, out := &in.MaxIdleConn, &out.MaxIdleConn
-		*out = new(int)
-		**out = **in
-	}
-	if in.MaxOpenConn != nil {
-		in, out := &in.MaxOpenConn, &out.MaxOpenConn
-		*out = new(int)
-		**out = **in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ConnMaxLifetime != nil {
-		in, out := &in.ConnMaxLifetime, &out.ConnMaxLifetime
-		*out = new(int)
-		**out = **in
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.LogQueries != nil {
-		in, out := &in.LogQueries, &out.LogQueries
-		*out = new(bool)
-		**out = **in
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigDatabase.
-func (in *GrafanaConfigDatabase) DeepCopy() *GrafanaConfigDatabase {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleSubjects.
+func (in *GrafanaRoleSubjects) DeepCopy() *GrafanaRoleSubjects {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigDatabase)
+	out := new(GrafanaRoleSubjects)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigExternalImageStorage) DeepCopyInto(out *GrafanaConfigExternalImageStorage) {
+func (in *GrafanaSMTP) DeepCopyInto(out *GrafanaSMTP) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorage.
-func (in *GrafanaConfigExternalImageStorage) DeepCopy() *GrafanaConfigExternalImageStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSMTP.
+func (in *GrafanaSMTP) DeepCopy() *GrafanaSMTP {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigExternalImageStorage)
+	out := new(GrafanaSMTP)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigExternalImageStorageAzureBlob) DeepCopyInto(out *GrafanaConfigExternalImageStorageAzureBlob) {
+func (in *GrafanaScheduling) DeepCopyInto(out *GrafanaScheduling) {
 	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorageAzureBlob.
-func (in *GrafanaConfigExternalImageStorageAzureBlob) DeepCopy() *GrafanaConfigExternalImageStorageAzureBlob {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaScheduling.
+func (in *GrafanaScheduling) DeepCopy() *GrafanaScheduling {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigExternalImageStorageAzureBlob)
+	out := new(GrafanaScheduling)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigExternalImageStorageGcs) DeepCopyInto(out *GrafanaConfigExternalImageStorageGcs) {
+func (in *GrafanaService) DeepCopyInto(out *GrafanaService) {
 	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]v1.ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorageGcs.
-func (in *GrafanaConfigExternalImageStorageGcs) DeepCopy() *GrafanaConfigExternalImageStorageGcs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaService.
+func (in *GrafanaService) DeepCopy() *GrafanaService {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigExternalImageStorageGcs)
+	out := new(GrafanaService)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigExternalImageStorageS3) DeepCopyInto(out *GrafanaConfigExternalImageStorageS3) {
+func (in *GrafanaServiceAccount) DeepCopyInto(out *GrafanaServiceAccount) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorageS3.
-func (in *GrafanaConfigExternalImageStorageS3) DeepCopy() *GrafanaConfigExternalImageStorageS3 {
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceAccount.
+func (in *GrafanaServiceAccount) DeepCopy() *GrafanaServiceAccount {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigExternalImageStorageS3)
+	out := new(GrafanaServiceAccount)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaServiceAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigExternalImageStorageWebdav) DeepCopyInto(out *GrafanaConfigExternalImageStorageWebdav) {
+func (in *GrafanaServiceAccountList) DeepCopyInto(out *GrafanaServiceAccountList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaServiceAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigExternalImageStorageWebdav.
-func (in *GrafanaConfigExternalImageStorageWebdav) DeepCopy() *GrafanaConfigExternalImageStorageWebdav {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceAccountList.
+func (in *GrafanaServiceAccountList) DeepCopy() *GrafanaServiceAccountList {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigExternalImageStorageWebdav)
+	out := new(GrafanaServiceAccountList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaServiceAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigFeatureToggles) DeepCopyInto(out *GrafanaConfigFeatureToggles) {
+func (in *GrafanaServiceAccountSpec) DeepCopyInto(out *GrafanaServiceAccountSpec) {
 	*out = *in
+	if in.TokenRotation != nil {
+		in, out := &in.TokenRotation, &out.TokenRotation
+		*out = new(TokenRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigFeatureToggles.
-func (in *GrafanaConfigFeatureToggles) DeepCopy() *GrafanaConfigFeatureToggles {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceAccountSpec.
+func (in *GrafanaServiceAccountSpec) DeepCopy() *GrafanaServiceAccountSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigFeatureToggles)
+	out := new(GrafanaServiceAccountSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigLive) DeepCopyInto(out *GrafanaConfigLive) {
+func (in *GrafanaServiceAccountStatus) DeepCopyInto(out *GrafanaServiceAccountStatus) {
 	*out = *in
-	if in.MaxConnections != nil {
-		in, out := &in.MaxConnections, &out.MaxConnections
-		*out = new(int)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]GrafanaServiceAccountToken, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigLive.
-func (in *GrafanaConfigLive) DeepCopy() *GrafanaConfigLive {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceAccountStatus.
+func (in *GrafanaServiceAccountStatus) DeepCopy() *GrafanaServiceAccountStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigLive)
+	out := new(GrafanaServiceAccountStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigLog) DeepCopyInto(out *GrafanaConfigLog) {
+func (in *GrafanaServiceAccountToken) DeepCopyInto(out *GrafanaServiceAccountToken) {
 	*out = *in
+	in.Created.DeepCopyInto(&out.Created)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigLog.
-func (in *GrafanaConfigLog) DeepCopy() *GrafanaConfigLog {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceAccountToken.
+func (in *GrafanaServiceAccountToken) DeepCopy() *GrafanaServiceAccountToken {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigLog)
+	out := new(GrafanaServiceAccountToken)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigLogConsole) DeepCopyInto(out *GrafanaConfigLogConsole) {
+func (in *GrafanaServiceMonitor) DeepCopyInto(out *GrafanaServiceMonitor) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Relabelings != nil {
+		in, out := &in.Relabelings, &out.Relabelings
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricRelabelings != nil {
+		in, out := &in.MetricRelabelings, &out.MetricRelabelings
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigLogConsole.
-func (in *GrafanaConfigLogConsole) DeepCopy() *GrafanaConfigLogConsole {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceMonitor.
+func (in *GrafanaServiceMonitor) DeepCopy() *GrafanaServiceMonitor {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigLogConsole)
+	out := new(GrafanaServiceMonitor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigLogFrontend) DeepCopyInto(out *GrafanaConfigLogFrontend) {
+func (in *GrafanaSnapshot) DeepCopyInto(out *GrafanaSnapshot) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.LogEndpointRequestsPerSecondLimit != nil {
-		in, out := &in.LogEndpointRequestsPerSecondLimit, &out.LogEndpointRequestsPerSecondLimit
-		*out = new(int)
-		**out = **in
-	}
-	if in.LogEndpointBurstLimit != nil {
-		in, out := &in.LogEndpointBurstLimit, &out.LogEndpointBurstLimit
-		*out = new(int)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigLogFrontend.
-func (in *GrafanaConfigLogFrontend) DeepCopy() *GrafanaConfigLogFrontend {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSnapshot.
+func (in *GrafanaSnapshot) DeepCopy() *GrafanaSnapshot {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigLogFrontend)
+	out := new(GrafanaSnapshot)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigMetrics) DeepCopyInto(out *GrafanaConfigMetrics) {
+func (in *GrafanaSnapshotInstance) DeepCopyInto(out *GrafanaSnapshotInstance) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.IntervalSeconds != nil {
-		in, out := &in.IntervalSeconds, &out.IntervalSeconds
-		*out = new(int)
-		**out = **in
+	if in.Expires != nil {
+		in, out := &in.Expires, &out.Expires
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigMetrics.
-func (in *GrafanaConfigMetrics) DeepCopy() *GrafanaConfigMetrics {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSnapshotInstance.
+func (in *GrafanaSnapshotInstance) DeepCopy() *GrafanaSnapshotInstance {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigMetrics)
+	out := new(GrafanaSnapshotInstance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigMetricsGraphite) DeepCopyInto(out *GrafanaConfigMetricsGraphite) {
+func (in *GrafanaSnapshotList) DeepCopyInto(out *GrafanaSnapshotList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigMetricsGraphite.
-func (in *GrafanaConfigMetricsGraphite) DeepCopy() *GrafanaConfigMetricsGraphite {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSnapshotList.
+func (in *GrafanaSnapshotList) DeepCopy() *GrafanaSnapshotList {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigMetricsGraphite)
+	out := new(GrafanaSnapshotList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigPanels) DeepCopyInto(out *GrafanaConfigPanels) {
+func (in *GrafanaSnapshotSpec) DeepCopyInto(out *GrafanaSnapshotSpec) {
 	*out = *in
-	if in.DisableSanitizeHtml != nil {
-		in, out := &in.DisableSanitizeHtml, &out.DisableSanitizeHtml
-		*out = new(bool)
+	if in.Expires != nil {
+		in, out := &in.Expires, &out.Expires
+		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigPanels.
-func (in *GrafanaConfigPanels) DeepCopy() *GrafanaConfigPanels {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSnapshotSpec.
+func (in *GrafanaSnapshotSpec) DeepCopy() *GrafanaSnapshotSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigPanels)
+	out := new(GrafanaSnapshotSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigPaths) DeepCopyInto(out *GrafanaConfigPaths) {
+func (in *GrafanaSnapshotStatus) DeepCopyInto(out *GrafanaSnapshotStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]GrafanaSnapshotInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigPaths.
-func (in *GrafanaConfigPaths) DeepCopy() *GrafanaConfigPaths {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSnapshotStatus.
+func (in *GrafanaSnapshotStatus) DeepCopy() *GrafanaSnapshotStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigPaths)
+	out := new(GrafanaSnapshotStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigPlugins) DeepCopyInto(out *GrafanaConfigPlugins) {
+func (in *GrafanaSpec) DeepCopyInto(out *GrafanaSpec) {
 	*out = *in
-	if in.EnableAlpha != nil {
-		in, out := &in.EnableAlpha, &out.EnableAlpha
-		*out = new(bool)
+	in.Config.DeepCopyInto(&out.Config)
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressNetworkingV1)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(RouteOpenshiftV1)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceV1)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(DeploymentV1)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PersistentVolumeClaimV1)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(ServiceAccountV1)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Client != nil {
+		in, out := &in.Client, &out.Client
+		*out = new(GrafanaClient)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Jsonnet != nil {
+		in, out := &in.Jsonnet, &out.Jsonnet
+		*out = new(JsonnetConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageRenderer != nil {
+		in, out := &in.ImageRenderer, &out.ImageRenderer
+		*out = new(GrafanaImageRenderer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(GrafanaExternal)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cloud != nil {
+		in, out := &in.Cloud, &out.Cloud
+		*out = new(GrafanaCloudStack)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(GrafanaPersistence)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(GrafanaDatabase)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(GrafanaTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(GrafanaMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(GrafanaPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(GrafanaAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigValuesFrom != nil {
+		in, out := &in.ConfigValuesFrom, &out.ConfigValuesFrom
+		*out = make([]ValueFrom, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SMTP != nil {
+		in, out := &in.SMTP, &out.SMTP
+		*out = new(GrafanaSMTP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminCredentials != nil {
+		in, out := &in.AdminCredentials, &out.AdminCredentials
+		*out = new(GrafanaAdminCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FeatureToggles != nil {
+		in, out := &in.FeatureToggles, &out.FeatureToggles
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(GrafanaUpgrade)
 		**out = **in
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigPlugins.
-func (in *GrafanaConfigPlugins) DeepCopy() *GrafanaConfigPlugins {
-	if in == nil {
-		return nil
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(GrafanaScheduling)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(GrafanaConfigPlugins)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigRemoteCache) DeepCopyInto(out *GrafanaConfigRemoteCache) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigRemoteCache.
-func (in *GrafanaConfigRemoteCache) DeepCopy() *GrafanaConfigRemoteCache {
-	if in == nil {
-		return nil
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	out := new(GrafanaConfigRemoteCache)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigRendering) DeepCopyInto(out *GrafanaConfigRendering) {
-	*out = *in
-	if in.ConcurrentRenderRequestLimit != nil {
-		in, out := &in.ConcurrentRenderRequestLimit, &out.ConcurrentRenderRequestLimit
-		*out = new(int)
-		**out = **in
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(GrafanaProbes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(GrafanaPlugins)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatasourceDiscovery != nil {
+		in, out := &in.DatasourceDiscovery, &out.DatasourceDiscovery
+		*out = new(GrafanaDatasourceDiscovery)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigRendering.
-func (in *GrafanaConfigRendering) DeepCopy() *GrafanaConfigRendering {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSpec.
+func (in *GrafanaSpec) DeepCopy() *GrafanaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigRendering)
+	out := new(GrafanaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigSecurity) DeepCopyInto(out *GrafanaConfigSecurity) {
+func (in *GrafanaStatus) DeepCopyInto(out *GrafanaStatus) {
 	*out = *in
-	if in.LoginRememberDays != nil {
-		in, out := &in.LoginRememberDays, &out.LoginRememberDays
-		*out = new(int)
-		**out = **in
-	}
-	if in.DisableGravatar != nil {
-		in, out := &in.DisableGravatar, &out.DisableGravatar
-		*out = new(bool)
-		**out = **in
-	}
-	if in.CookieSecure != nil {
-		in, out := &in.CookieSecure, &out.CookieSecure
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AllowEmbedding != nil {
-		in, out := &in.AllowEmbedding, &out.AllowEmbedding
-		*out = new(bool)
-		**out = **in
-	}
-	if in.StrictTransportSecurity != nil {
-		in, out := &in.StrictTransportSecurity, &out.StrictTransportSecurity
-		*out = new(bool)
-		**out = **in
+	if in.InstalledPlugins != nil {
+		in, out := &in.InstalledPlugins, &out.InstalledPlugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.StrictTransportSecurityMaxAgeSeconds != nil {
-		in, out := &in.StrictTransportSecurityMaxAgeSeconds, &out.StrictTransportSecurityMaxAgeSeconds
-		*out = new(int)
-		**out = **in
+	if in.ResolvedPlugins != nil {
+		in, out := &in.ResolvedPlugins, &out.ResolvedPlugins
+		*out = make([]GrafanaResolvedPlugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.StrictTransportSecurityPreload != nil {
-		in, out := &in.StrictTransportSecurityPreload, &out.StrictTransportSecurityPreload
-		*out = new(bool)
-		**out = **in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make(PluginList, len(*in))
+		copy(*out, *in)
 	}
-	if in.StrictTransportSecuritySubdomains != nil {
-		in, out := &in.StrictTransportSecuritySubdomains, &out.StrictTransportSecuritySubdomains
-		*out = new(bool)
-		**out = **in
+	if in.PluginsUpgradeTime != nil {
+		in, out := &in.PluginsUpgradeTime, &out.PluginsUpgradeTime
+		*out = (*in).DeepCopy()
 	}
-	if in.XContentTypeOptions != nil {
-		in, out := &in.XContentTypeOptions, &out.XContentTypeOptions
-		*out = new(bool)
-		**out = **in
+	if in.ConsolidatedPlugins != nil {
+		in, out := &in.ConsolidatedPlugins, &out.ConsolidatedPlugins
+		*out = make(PluginList, len(*in))
+		copy(*out, *in)
 	}
-	if in.XXssProtection != nil {
-		in, out := &in.XXssProtection, &out.XXssProtection
-		*out = new(bool)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigSecurity.
-func (in *GrafanaConfigSecurity) DeepCopy() *GrafanaConfigSecurity {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaStatus.
+func (in *GrafanaStatus) DeepCopy() *GrafanaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigSecurity)
+	out := new(GrafanaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigServer) DeepCopyInto(out *GrafanaConfigServer) {
+func (in *GrafanaTLS) DeepCopyInto(out *GrafanaTLS) {
 	*out = *in
-	if in.EnforceDomain != nil {
-		in, out := &in.EnforceDomain, &out.EnforceDomain
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ServeFromSubPath != nil {
-		in, out := &in.ServeFromSubPath, &out.ServeFromSubPath
-		*out = new(bool)
-		**out = **in
-	}
-	if in.EnableGzip != nil {
-		in, out := &in.EnableGzip, &out.EnableGzip
-		*out = new(bool)
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerRef)
 		**out = **in
 	}
-	if in.RouterLogging != nil {
-		in, out := &in.RouterLogging, &out.RouterLogging
-		*out = new(bool)
-		**out = **in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigServer.
-func (in *GrafanaConfigServer) DeepCopy() *GrafanaConfigServer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTLS.
+func (in *GrafanaTLS) DeepCopy() *GrafanaTLS {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigServer)
+	out := new(GrafanaTLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigSmtp) DeepCopyInto(out *GrafanaConfigSmtp) {
+func (in *GrafanaTeam) DeepCopyInto(out *GrafanaTeam) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SkipVerify != nil {
-		in, out := &in.SkipVerify, &out.SkipVerify
-		*out = new(bool)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigSmtp.
-func (in *GrafanaConfigSmtp) DeepCopy() *GrafanaConfigSmtp {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeam.
+func (in *GrafanaTeam) DeepCopy() *GrafanaTeam {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigSmtp)
+	out := new(GrafanaTeam)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaTeam) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigSnapshots) DeepCopyInto(out *GrafanaConfigSnapshots) {
+func (in *GrafanaTeamList) DeepCopyInto(out *GrafanaTeamList) {
 	*out = *in
-	if in.ExternalEnabled != nil {
-		in, out := &in.ExternalEnabled, &out.ExternalEnabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SnapshotRemoveExpired != nil {
-		in, out := &in.SnapshotRemoveExpired, &out.SnapshotRemoveExpired
-		*out = new(bool)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaTeam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigSnapshots.
-func (in *GrafanaConfigSnapshots) DeepCopy() *GrafanaConfigSnapshots {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeamList.
+func (in *GrafanaTeamList) DeepCopy() *GrafanaTeamList {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigSnapshots)
+	out := new(GrafanaTeamList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaTeamList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigUnifiedAlerting) DeepCopyInto(out *GrafanaConfigUnifiedAlerting) {
+func (in *GrafanaTeamSpec) DeepCopyInto(out *GrafanaTeamSpec) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ExecuteAlerts != nil {
-		in, out := &in.ExecuteAlerts, &out.ExecuteAlerts
-		*out = new(bool)
-		**out = **in
+	if in.ExternalGroups != nil {
+		in, out := &in.ExternalGroups, &out.ExternalGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MaxAttempts != nil {
-		in, out := &in.MaxAttempts, &out.MaxAttempts
-		*out = new(int)
-		**out = **in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigUnifiedAlerting.
-func (in *GrafanaConfigUnifiedAlerting) DeepCopy() *GrafanaConfigUnifiedAlerting {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeamSpec.
+func (in *GrafanaTeamSpec) DeepCopy() *GrafanaTeamSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigUnifiedAlerting)
+	out := new(GrafanaTeamSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfigUsers) DeepCopyInto(out *GrafanaConfigUsers) {
+func (in *GrafanaTeamStatus) DeepCopyInto(out *GrafanaTeamStatus) {
 	*out = *in
-	if in.AllowSignUp != nil {
-		in, out := &in.AllowSignUp, &out.AllowSignUp
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AllowOrgCreate != nil {
-		in, out := &in.AllowOrgCreate, &out.AllowOrgCreate
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AutoAssignOrg != nil {
-		in, out := &in.AutoAssignOrg, &out.AutoAssignOrg
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ViewersCanEdit != nil {
-		in, out := &in.ViewersCanEdit, &out.ViewersCanEdit
-		*out = new(bool)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.EditorsCanAdmin != nil {
-		in, out := &in.EditorsCanAdmin, &out.EditorsCanAdmin
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeamStatus.
+func (in *GrafanaTeamStatus) DeepCopy() *GrafanaTeamStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(GrafanaTeamStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfigUsers.
-func (in *GrafanaConfigUsers) DeepCopy() *GrafanaConfigUsers {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaUpgrade) DeepCopyInto(out *GrafanaUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUpgrade.
+func (in *GrafanaUpgrade) DeepCopy() *GrafanaUpgrade {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfigUsers)
+	out := new(GrafanaUpgrade)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaDashboard) DeepCopyInto(out *GrafanaDashboard) {
+func (in *GrafanaUser) DeepCopyInto(out *GrafanaUser) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboard.
-func (in *GrafanaDashboard) DeepCopy() *GrafanaDashboard {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUser.
+func (in *GrafanaUser) DeepCopy() *GrafanaUser {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaDashboard)
+	out := new(GrafanaUser)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GrafanaDashboard) DeepCopyObject() runtime.Object {
+func (in *GrafanaUser) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1402,31 +5861,31 @@ func (in *GrafanaDashboard) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaDashboardList) DeepCopyInto(out *GrafanaDashboardList) {
+func (in *GrafanaUserList) DeepCopyInto(out *GrafanaUserList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GrafanaDashboard, len(*in))
+		*out = make([]GrafanaUser, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardList.
-func (in *GrafanaDashboardList) DeepCopy() *GrafanaDashboardList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUserList.
+func (in *GrafanaUserList) DeepCopy() *GrafanaUserList {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaDashboardList)
+	out := new(GrafanaUserList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GrafanaDashboardList) DeepCopyObject() runtime.Object {
+func (in *GrafanaUserList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1434,441 +5893,310 @@ func (in *GrafanaDashboardList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaDashboardSpec) DeepCopyInto(out *GrafanaDashboardSpec) {
+func (in *GrafanaUserOrg) DeepCopyInto(out *GrafanaUserOrg) {
 	*out = *in
-	if in.InstanceSelector != nil {
-		in, out := &in.InstanceSelector, &out.InstanceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Plugins != nil {
-		in, out := &in.Plugins, &out.Plugins
-		*out = make(PluginList, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardSpec.
-func (in *GrafanaDashboardSpec) DeepCopy() *GrafanaDashboardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUserOrg.
+func (in *GrafanaUserOrg) DeepCopy() *GrafanaUserOrg {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaDashboardSpec)
+	out := new(GrafanaUserOrg)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaDashboardStatus) DeepCopyInto(out *GrafanaDashboardStatus) {
+func (in *GrafanaUserSpec) DeepCopyInto(out *GrafanaUserSpec) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Orgs != nil {
+		in, out := &in.Orgs, &out.Orgs
+		*out = make([]GrafanaUserOrg, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardStatus.
-func (in *GrafanaDashboardStatus) DeepCopy() *GrafanaDashboardStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUserSpec.
+func (in *GrafanaUserSpec) DeepCopy() *GrafanaUserSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaDashboardStatus)
+	out := new(GrafanaUserSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaDataStorage) DeepCopyInto(out *GrafanaDataStorage) {
+func (in *GrafanaUserStatus) DeepCopyInto(out *GrafanaUserStatus) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.AccessModes != nil {
-		in, out := &in.AccessModes, &out.AccessModes
-		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
-		copy(*out, *in)
-	}
-	out.Size = in.Size.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDataStorage.
-func (in *GrafanaDataStorage) DeepCopy() *GrafanaDataStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUserStatus.
+func (in *GrafanaUserStatus) DeepCopy() *GrafanaUserStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaDataStorage)
+	out := new(GrafanaUserStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaDeployment) DeepCopyInto(out *GrafanaDeployment) {
+func (in *IngressNetworkingV1) DeepCopyInto(out *IngressNetworkingV1) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(networkingv1.IngressSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
 		**out = **in
 	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SecurityContext != nil {
-		in, out := &in.SecurityContext, &out.SecurityContext
-		*out = new(v1.PodSecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ContainerSecurityContext != nil {
-		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
-		*out = new(v1.SecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TerminationGracePeriodSeconds != nil {
-		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
-		*out = new(int64)
-		**out = **in
-	}
-	if in.EnvFrom != nil {
-		in, out := &in.EnvFrom, &out.EnvFrom
-		*out = make([]v1.EnvFromSource, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.SkipCreateAdminAccount != nil {
-		in, out := &in.SkipCreateAdminAccount, &out.SkipCreateAdminAccount
-		*out = new(bool)
-		**out = **in
-	}
-	if in.HostNetwork != nil {
-		in, out := &in.HostNetwork, &out.HostNetwork
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ExtraVolumes != nil {
-		in, out := &in.ExtraVolumes, &out.ExtraVolumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumeMounts != nil {
-		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Strategy != nil {
-		in, out := &in.Strategy, &out.Strategy
-		*out = new(appsv1.DeploymentStrategy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HttpProxy != nil {
-		in, out := &in.HttpProxy, &out.HttpProxy
-		*out = new(GrafanaHttpProxy)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDeployment.
-func (in *GrafanaDeployment) DeepCopy() *GrafanaDeployment {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressNetworkingV1.
+func (in *IngressNetworkingV1) DeepCopy() *IngressNetworkingV1 {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaDeployment)
+	out := new(IngressNetworkingV1)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaHttpProxy) DeepCopyInto(out *GrafanaHttpProxy) {
+func (in *InstanceStatus) DeepCopyInto(out *InstanceStatus) {
 	*out = *in
+	if in.LastApplied != nil {
+		in, out := &in.LastApplied, &out.LastApplied
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaHttpProxy.
-func (in *GrafanaHttpProxy) DeepCopy() *GrafanaHttpProxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceStatus.
+func (in *InstanceStatus) DeepCopy() *InstanceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaHttpProxy)
+	out := new(InstanceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaList) DeepCopyInto(out *GrafanaList) {
+func (in *JsonnetConfig) DeepCopyInto(out *JsonnetConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Grafana, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LibraryLabelSelector != nil {
+		in, out := &in.LibraryLabelSelector, &out.LibraryLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaList.
-func (in *GrafanaList) DeepCopy() *GrafanaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonnetConfig.
+func (in *JsonnetConfig) DeepCopy() *JsonnetConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaList)
+	out := new(JsonnetConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GrafanaList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaPlugin) DeepCopyInto(out *GrafanaPlugin) {
+func (in *LDAPAttributes) DeepCopyInto(out *LDAPAttributes) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPlugin.
-func (in *GrafanaPlugin) DeepCopy() *GrafanaPlugin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPAttributes.
+func (in *LDAPAttributes) DeepCopy() *LDAPAttributes {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaPlugin)
+	out := new(LDAPAttributes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaService) DeepCopyInto(out *GrafanaService) {
+func (in *LDAPGroupMapping) DeepCopyInto(out *LDAPGroupMapping) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.GrafanaAdmin != nil {
+		in, out := &in.GrafanaAdmin, &out.GrafanaAdmin
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]v1.ServicePort, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.OrgID != nil {
+		in, out := &in.OrgID, &out.OrgID
+		*out = new(int64)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaService.
-func (in *GrafanaService) DeepCopy() *GrafanaService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPGroupMapping.
+func (in *LDAPGroupMapping) DeepCopy() *LDAPGroupMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaService)
+	out := new(LDAPGroupMapping)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaServiceAccount) DeepCopyInto(out *GrafanaServiceAccount) {
+func (in *LDAPServer) DeepCopyInto(out *LDAPServer) {
 	*out = *in
-	if in.Skip != nil {
-		in, out := &in.Skip, &out.Skip
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.BindPasswordSecretRef != nil {
+		in, out := &in.BindPasswordSecretRef, &out.BindPasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.SearchBaseDNs != nil {
+		in, out := &in.SearchBaseDNs, &out.SearchBaseDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
+	if in.GroupSearchBaseDNs != nil {
+		in, out := &in.GroupSearchBaseDNs, &out.GroupSearchBaseDNs
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = new(LDAPAttributes)
+		**out = **in
+	}
+	if in.GroupMappings != nil {
+		in, out := &in.GroupMappings, &out.GroupMappings
+		*out = make([]LDAPGroupMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceAccount.
-func (in *GrafanaServiceAccount) DeepCopy() *GrafanaServiceAccount {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPServer.
+func (in *LDAPServer) DeepCopy() *LDAPServer {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaServiceAccount)
+	out := new(LDAPServer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaSpec) DeepCopyInto(out *GrafanaSpec) {
+func (in *NotificationPolicyRoute) DeepCopyInto(out *NotificationPolicyRoute) {
 	*out = *in
-	in.Config.DeepCopyInto(&out.Config)
-	if in.Containers != nil {
-		in, out := &in.Containers, &out.Containers
-		*out = make([]v1.Container, len(*in))
+	in.NotificationPolicySettings.DeepCopyInto(&out.NotificationPolicySettings)
+	if in.ObjectMatchers != nil {
+		in, out := &in.ObjectMatchers, &out.ObjectMatchers
+		*out = make([]ObjectMatcher, len(*in))
+		copy(*out, *in)
+	}
+	if in.MuteTimeIntervals != nil {
+		in, out := &in.MuteTimeIntervals, &out.MuteTimeIntervals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]NotificationPolicyRoute, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Ingress != nil {
-		in, out := &in.Ingress, &out.Ingress
-		*out = new(IngressNetworkingV1)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Route != nil {
-		in, out := &in.Route, &out.Route
-		*out = new(RouteOpenshiftV1)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceV1)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Deployment != nil {
-		in, out := &in.Deployment, &out.Deployment
-		*out = new(DeploymentV1)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PersistentVolumeClaim != nil {
-		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
-		*out = new(PersistentVolumeClaimV1)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceAccount != nil {
-		in, out := &in.ServiceAccount, &out.ServiceAccount
-		*out = new(ServiceAccountV1)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Client != nil {
-		in, out := &in.Client, &out.Client
-		*out = new(GrafanaClient)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Jsonnet != nil {
-		in, out := &in.Jsonnet, &out.Jsonnet
-		*out = new(JsonnetConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSpec.
-func (in *GrafanaSpec) DeepCopy() *GrafanaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationPolicyRoute.
+func (in *NotificationPolicyRoute) DeepCopy() *NotificationPolicyRoute {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaSpec)
+	out := new(NotificationPolicyRoute)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaStatus) DeepCopyInto(out *GrafanaStatus) {
+func (in *NotificationPolicySettings) DeepCopyInto(out *NotificationPolicySettings) {
 	*out = *in
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaStatus.
-func (in *GrafanaStatus) DeepCopy() *GrafanaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationPolicySettings.
+func (in *NotificationPolicySettings) DeepCopy() *NotificationPolicySettings {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaStatus)
+	out := new(NotificationPolicySettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressNetworkingV1) DeepCopyInto(out *IngressNetworkingV1) {
+func (in *OCIArtifactSource) DeepCopyInto(out *OCIArtifactSource) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.Spec != nil {
-		in, out := &in.Spec, &out.Spec
-		*out = new(networkingv1.IngressSpec)
-		(*in).DeepCopyInto(*out)
+	if in.PullSecretRef != nil {
+		in, out := &in.PullSecretRef, &out.PullSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressNetworkingV1.
-func (in *IngressNetworkingV1) DeepCopy() *IngressNetworkingV1 {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIArtifactSource.
+func (in *OCIArtifactSource) DeepCopy() *OCIArtifactSource {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressNetworkingV1)
+	out := new(OCIArtifactSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JsonnetConfig) DeepCopyInto(out *JsonnetConfig) {
+func (in *ObjectMatcher) DeepCopyInto(out *ObjectMatcher) {
 	*out = *in
-	if in.LibraryLabelSelector != nil {
-		in, out := &in.LibraryLabelSelector, &out.LibraryLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonnetConfig.
-func (in *JsonnetConfig) DeepCopy() *JsonnetConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectMatcher.
+func (in *ObjectMatcher) DeepCopy() *ObjectMatcher {
 	if in == nil {
 		return nil
 	}
-	out := new(JsonnetConfig)
+	out := new(ObjectMatcher)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1905,6 +6233,16 @@ func (in *ObjectMeta) DeepCopy() *ObjectMeta {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorReconcileVars) DeepCopyInto(out *OperatorReconcileVars) {
 	*out = *in
+	if in.UnsignedPlugins != nil {
+		in, out := &in.UnsignedPlugins, &out.UnsignedPlugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LdapAllowSignUp != nil {
+		in, out := &in.LdapAllowSignUp, &out.LdapAllowSignUp
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorReconcileVars.
@@ -1917,6 +6255,26 @@ func (in *OperatorReconcileVars) DeepCopy() *OperatorReconcileVars {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrgReference) DeepCopyInto(out *OrgReference) {
+	*out = *in
+	if in.OrgID != nil {
+		in, out := &in.OrgID, &out.OrgID
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrgReference.
+func (in *OrgReference) DeepCopy() *OrgReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OrgReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersistentVolumeClaimV1) DeepCopyInto(out *PersistentVolumeClaimV1) {
 	*out = *in
@@ -2007,6 +6365,31 @@ func (in PluginList) DeepCopy() PluginList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginListDiff) DeepCopyInto(out *PluginListDiff) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make(PluginList, len(*in))
+		copy(*out, *in)
+	}
+	if in.Updated != nil {
+		in, out := &in.Updated, &out.Updated
+		*out = make(PluginList, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginListDiff.
+func (in *PluginListDiff) DeepCopy() *PluginListDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginListDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in PluginMap) DeepCopyInto(out *PluginMap) {
 	{
@@ -2036,6 +6419,61 @@ func (in PluginMap) DeepCopy() PluginMap {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileRequestStatus) DeepCopyInto(out *ReconcileRequestStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileRequestStatus.
+func (in *ReconcileRequestStatus) DeepCopy() *ReconcileRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelabelConfig.
+func (in *RelabelConfig) DeepCopy() *RelabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RelabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResyncPolicy) DeepCopyInto(out *ResyncPolicy) {
+	*out = *in
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResyncPolicy.
+func (in *ResyncPolicy) DeepCopy() *ResyncPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ResyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteOpenShiftV1Spec) DeepCopyInto(out *RouteOpenShiftV1Spec) {
 	*out = *in
@@ -2097,6 +6535,18 @@ func (in *RouteOpenshiftV1) DeepCopy() *RouteOpenshiftV1 {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountV1) DeepCopyInto(out *ServiceAccountV1) {
 	*out = *in
+	if in.Create != nil {
+		in, out := &in.Create, &out.Create
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	if in.Secrets != nil {
 		in, out := &in.Secrets, &out.Secrets
@@ -2145,3 +6595,142 @@ func (in *ServiceV1) DeepCopy() *ServiceV1 {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncRetryStatus) DeepCopyInto(out *SyncRetryStatus) {
+	*out = *in
+	if in.LastSyncAttempt != nil {
+		in, out := &in.LastSyncAttempt, &out.LastSyncAttempt
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRetry != nil {
+		in, out := &in.NextRetry, &out.NextRetry
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncRetryStatus.
+func (in *SyncRetryStatus) DeepCopy() *SyncRetryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncRetryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeInterval) DeepCopyInto(out *TimeInterval) {
+	*out = *in
+	if in.Times != nil {
+		in, out := &in.Times, &out.Times
+		*out = make([]TimeRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Weekdays != nil {
+		in, out := &in.Weekdays, &out.Weekdays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DaysOfMonth != nil {
+		in, out := &in.DaysOfMonth, &out.DaysOfMonth
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Months != nil {
+		in, out := &in.Months, &out.Months
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Years != nil {
+		in, out := &in.Years, &out.Years
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeInterval.
+func (in *TimeInterval) DeepCopy() *TimeInterval {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeInterval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeRange) DeepCopyInto(out *TimeRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeRange.
+func (in *TimeRange) DeepCopy() *TimeRange {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenRotation) DeepCopyInto(out *TokenRotation) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRotation.
+func (in *TokenRotation) DeepCopy() *TokenRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValueFrom) DeepCopyInto(out *ValueFrom) {
+	*out = *in
+	in.ValueFrom.DeepCopyInto(&out.ValueFrom)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueFrom.
+func (in *ValueFrom) DeepCopy() *ValueFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ValueFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValueFromSource) DeepCopyInto(out *ValueFromSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueFromSource.
+func (in *ValueFromSource) DeepCopy() *ValueFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ValueFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
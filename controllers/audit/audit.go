@@ -0,0 +1,105 @@
+// Package audit records the requests changing a Grafana instance as json lines, so that every change the
+// operator applies can be traced back to the resource it reconciled
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sync"
+	"time"
+)
+
+var (
+	lock   sync.Mutex
+	output io.Writer
+)
+
+// Entry is a request sent to the api of a Grafana instance or Grafana Cloud, request bodies are never
+// recorded as they contain credentials
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Instance  string    `json:"instance"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// SetOutput enables the audit log, entries are written to w. A nil writer disables the audit log.
+func SetOutput(w io.Writer) {
+	lock.Lock()
+	defer lock.Unlock()
+	output = w
+}
+
+type resourceKey struct{}
+
+type resource struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// NewReconciler passes the reconciled resource to the entries recorded during a reconcile
+func NewReconciler(kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &auditReconciler{
+		kind:       kind,
+		reconciler: r,
+	}
+}
+
+type auditReconciler struct {
+	kind       string
+	reconciler reconcile.Reconciler
+}
+
+func (r *auditReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx = context.WithValue(ctx, resourceKey{}, resource{
+		kind:      r.kind,
+		namespace: req.Namespace,
+		name:      req.Name,
+	})
+	return r.reconciler.Reconcile(ctx, req)
+}
+
+// Record writes an entry for a request changing an instance, reads are not recorded. The status is 0 if
+// the instance was not reachable.
+func Record(ctx context.Context, instance string, method string, path string, status int, err error) {
+	if method == http.MethodGet || method == http.MethodHead {
+		return
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if output == nil {
+		return
+	}
+
+	entry := Entry{
+		Time:     time.Now().UTC(),
+		Instance: instance,
+		Method:   method,
+		Path:     path,
+		Status:   status,
+	}
+	if cr, ok := ctx.Value(resourceKey{}).(resource); ok {
+		entry.Kind = cr.kind
+		entry.Namespace = cr.namespace
+		entry.Name = cr.name
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	output.Write(append(line, '\n')) // nolint
+}
@@ -0,0 +1,102 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"strings"
+)
+
+const writerContainerName = "writer"
+
+// WriterPodSpec returns the spec of the helper pod that mounts the backup volume, the operator streams
+// the tarballs into it
+func WriterPodSpec(image string, claimName string, mountPath string) v1.PodSpec {
+	return v1.PodSpec{
+		RestartPolicy: v1.RestartPolicyNever,
+		Containers: []v1.Container{
+			{
+				Name:    writerContainerName,
+				Image:   image,
+				Command: []string{"sleep", "3600"},
+				VolumeMounts: []v1.VolumeMount{
+					{
+						Name:      "backup",
+						MountPath: mountPath,
+					},
+				},
+			},
+		},
+		Volumes: []v1.Volume{
+			{
+				Name: "backup",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+						ClaimName: claimName,
+					},
+				},
+			},
+		},
+	}
+}
+
+// execInWriter runs a command in the writer pod
+func execInWriter(config *rest.Config, pod *v1.Pod, command []string, stdin io.Reader, stdout io.Writer) error {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: writerContainerName,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	err = executor.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// WriteFile writes content to a file in the writer pod, missing directories are created
+func WriteFile(config *rest.Config, pod *v1.Pod, path string, content []byte) error {
+	err := execInWriter(config, pod, []string{"sh", "-c", `mkdir -p "$(dirname "$0")" && cat > "$0"`, path}, bytes.NewReader(content), io.Discard)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadFile reads a file from the writer pod
+func ReadFile(config *rest.Config, pod *v1.Pod, path string) ([]byte, error) {
+	var stdout bytes.Buffer
+	err := execInWriter(config, pod, []string{"cat", path}, nil, &stdout)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return stdout.Bytes(), nil
+}
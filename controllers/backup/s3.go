@@ -0,0 +1,222 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Target is a bucket of a s3 compatible service
+type S3Target struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// session token of temporary credentials
+	SessionToken string
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data)) // nolint
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// objectURL returns the path style url of an object, which works for aws as well as most s3 compatible services
+func (t *S3Target) objectURL(key string) (*url.URL, error) {
+	endpoint := t.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", t.Region)
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	// the bucket itself is requested for listings
+	if key == "" {
+		u.RawPath = u.Path + "/" + awsEscape(t.Bucket)
+		u.Path = u.Path + "/" + t.Bucket
+		return u, nil
+	}
+
+	// the segments are escaped as in the canonical uri of the signature, url.PathEscape keeps characters
+	// like = and @ that aws escapes
+	segments := []string{awsEscape(t.Bucket)}
+	for _, segment := range strings.Split(key, "/") {
+		segments = append(segments, awsEscape(segment))
+	}
+	u.RawPath = u.Path + "/" + strings.Join(segments, "/")
+	u.Path = u.Path + "/" + t.Bucket + "/" + key
+	return u, nil
+}
+
+// canonicalQuery encodes a query sorted and escaped as required by signature version 4
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape escapes all bytes but the unreserved characters
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sign adds an aws signature version 4 to a request
+func (t *S3Target) sign(req *http.Request, payloadHash string) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, amzDate)
+	if t.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", t.SessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += fmt.Sprintf("x-amz-security-token:%s\n", t.SessionToken)
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, t.Region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+t.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, t.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// NewGetObjectRequest returns a signed request downloading an object, targets without credentials read
+// public buckets anonymously. Headers added to the request later are not signed.
+func (t *S3Target) NewGetObjectRequest(ctx context.Context, key string) (*http.Request, error) {
+	return t.newGetRequest(ctx, key, nil)
+}
+
+// NewListObjectsRequest returns a signed request listing a page of the objects below a prefix, starting at
+// the continuation token of the previous page
+func (t *S3Target) NewListObjectsRequest(ctx context.Context, prefix string, continuationToken string) (*http.Request, error) {
+	query := url.Values{
+		"list-type": {"2"},
+		"prefix":    {prefix},
+	}
+	if continuationToken != "" {
+		query.Set("continuation-token", continuationToken)
+	}
+	return t.newGetRequest(ctx, "", query)
+}
+
+func (t *S3Target) newGetRequest(ctx context.Context, key string, query url.Values) (*http.Request, error) {
+	u, err := t.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if t.AccessKeyID != "" {
+		t.sign(req, sha256Hex(nil))
+	}
+	return req, nil
+}
+
+// do sends a signed request for an object and returns the response body
+func (t *S3Target) do(ctx context.Context, httpClient *http.Client, method string, key string, content []byte) ([]byte, error) {
+	u, err := t.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if content != nil {
+		req.Header.Set("Content-Type", "application/gzip")
+	}
+	t.sign(req, sha256Hex(content))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("s3 %s %s returned status %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// PutObject uploads an object
+func (t *S3Target) PutObject(ctx context.Context, httpClient *http.Client, key string, content []byte) error {
+	_, err := t.do(ctx, httpClient, http.MethodPut, key, content)
+	return err
+}
+
+// GetObject downloads an object
+func (t *S3Target) GetObject(ctx context.Context, httpClient *http.Client, key string) ([]byte, error) {
+	return t.do(ctx, httpClient, http.MethodGet, key, nil)
+}
@@ -0,0 +1,34 @@
+package backup
+
+import (
+	"testing"
+)
+
+func TestObjectURL(t *testing.T) {
+	target := &S3Target{
+		Endpoint: "https://s3.example.com/",
+		Bucket:   "backups",
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "", want: "/backups"},
+		{key: "grafana/backup.tar.gz", want: "/backups/grafana/backup.tar.gz"},
+		{key: "a b/c+d.json", want: "/backups/a%20b/c%2Bd.json"},
+		{key: "x=y@z!.json", want: "/backups/x%3Dy%40z%21.json"},
+		{key: "~user/file_1-2.json", want: "/backups/~user/file_1-2.json"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			u, err := target.objectURL(test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := u.EscapedPath(); got != test.want {
+				t.Errorf("objectURL() path = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package backup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression with the fields minute, hour, day of month, month and day of week
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// day of month and day of week are combined with or when both are restricted
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type scheduleField struct {
+	min int
+	max int
+}
+
+var (
+	minuteField     = scheduleField{min: 0, max: 59}
+	hourField       = scheduleField{min: 0, max: 23}
+	dayOfMonthField = scheduleField{min: 1, max: 31}
+	monthField      = scheduleField{min: 1, max: 12}
+	dayOfWeekField  = scheduleField{min: 0, max: 6}
+)
+
+var scheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a standard five field cron expression or one of the @daily style macros
+func ParseSchedule(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if macro, ok := scheduleMacros[spec]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	schedule := &Schedule{
+		anyDayOfMonth: fields[2] == "*" || fields[2] == "?",
+		anyDayOfWeek:  fields[4] == "*" || fields[4] == "?",
+	}
+
+	var err error
+	targets := []struct {
+		bits  *uint64
+		field scheduleField
+	}{
+		{&schedule.minute, minuteField},
+		{&schedule.hour, hourField},
+		{&schedule.dayOfMonth, dayOfMonthField},
+		{&schedule.month, monthField},
+		{&schedule.dayOfWeek, dayOfWeekField},
+	}
+	for i, target := range targets {
+		*target.bits, err = parseScheduleField(fields[i], target.field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+	}
+	return schedule, nil
+}
+
+// parseScheduleField parses a comma separated list of values, ranges and steps into a bit set
+func parseScheduleField(value string, field scheduleField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			part = part[:idx]
+		}
+
+		start, end := field.min, field.max
+		if part != "*" && part != "?" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			start, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			end = start
+			if len(bounds) == 2 {
+				end, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				end = field.max
+			}
+		}
+
+		// sunday can be written as 7
+		if field == dayOfWeekField {
+			if start == 7 && end == 7 {
+				end = 0
+			}
+			if start == 7 {
+				start = 0
+			}
+			if end == 7 {
+				end = 6
+				bits |= 1
+			}
+		}
+
+		if start < field.min || end > field.max || start > end {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, field.min, field.max)
+		}
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// Next returns the first time after t matching the schedule, or the zero time if there is none within
+// five years
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,112 @@
+package backup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleField(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		field   scheduleField
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name:  "list",
+			value: "1,5,10",
+			field: minuteField,
+			want:  1<<1 | 1<<5 | 1<<10,
+		},
+		{
+			name:  "range with step",
+			value: "0-10/5",
+			field: minuteField,
+			want:  1<<0 | 1<<5 | 1<<10,
+		},
+		{
+			name:  "value with step",
+			value: "20/20",
+			field: minuteField,
+			want:  1<<20 | 1<<40,
+		},
+		{
+			name:  "sunday as 7",
+			value: "7",
+			field: dayOfWeekField,
+			want:  1 << 0,
+		},
+		{
+			name:  "range ending on sunday as 7",
+			value: "5-7",
+			field: dayOfWeekField,
+			want:  1<<0 | 1<<5 | 1<<6,
+		},
+		{
+			name:  "range starting on sunday as 7",
+			value: "7-2",
+			field: dayOfWeekField,
+			want:  1<<0 | 1<<1 | 1<<2,
+		},
+		{
+			name:    "out of range",
+			value:   "8",
+			field:   dayOfWeekField,
+			wantErr: true,
+		},
+		{
+			name:    "reversed range",
+			value:   "10-5",
+			field:   minuteField,
+			wantErr: true,
+		},
+		{
+			name:    "invalid step",
+			value:   "*/0",
+			field:   minuteField,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseScheduleField(test.value, test.field)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseScheduleField() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("parseScheduleField() = %b, want %b", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// a wednesday
+	now := time.Date(2022, 6, 1, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "@hourly", want: time.Date(2022, 6, 1, 11, 0, 0, 0, time.UTC)},
+		{spec: "@daily", want: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2022, 6, 1, 10, 45, 0, 0, time.UTC)},
+		{spec: "0 3 * * 7", want: time.Date(2022, 6, 5, 3, 0, 0, 0, time.UTC)},
+		{spec: "0 3 * * 0", want: time.Date(2022, 6, 5, 3, 0, 0, 0, time.UTC)},
+		{spec: "0 3 15 * 5", want: time.Date(2022, 6, 3, 3, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1 1 *", want: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := schedule.Next(now); !got.Equal(test.want) {
+				t.Errorf("Next() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
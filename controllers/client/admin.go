@@ -0,0 +1,41 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+type grafanaPasswordChange struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+	ConfirmNew  string `json:"confirmNew"`
+}
+
+// IsUnauthorized returns true if the error is a 401 returned by the Grafana api
+func IsUnauthorized(err error) bool {
+	var apiErr *GrafanaApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// ChangeAdminPassword changes the password of the user the client authenticates as
+func (r *GrafanaClientImpl) ChangeAdminPassword(newPassword string) error {
+	return r.do(http.MethodPut, "/api/user/password", &grafanaPasswordChange{
+		OldPassword: r.password,
+		NewPassword: newPassword,
+		ConfirmNew:  newPassword,
+	}, nil)
+}
+
+// VerifyCredentials returns true if the instance accepts the given basic auth credentials
+func (r *GrafanaClientImpl) VerifyCredentials(username string, password string) (bool, error) {
+	verifier := *r
+	verifier.apiKey = ""
+	verifier.username = username
+	verifier.password = password
+
+	err := verifier.do(http.MethodGet, "/api/user", nil, nil)
+	if IsUnauthorized(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+)
+
+type grafanaAnnotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      *int64   `json:"panelId,omitempty"`
+	Time         int64    `json:"time"`
+	TimeEnd      int64    `json:"timeEnd,omitempty"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+type grafanaAnnotationCreated struct {
+	ID int64 `json:"id"`
+}
+
+// CreateAnnotation creates the annotation and returns its id
+func (r *GrafanaClientImpl) CreateAnnotation(annotation *v1beta1.GrafanaAnnotation) (int64, error) {
+	created := &grafanaAnnotationCreated{}
+	err := r.do(http.MethodPost, "/api/annotations", toGrafanaAnnotation(annotation), created)
+	return created.ID, err
+}
+
+func (r *GrafanaClientImpl) UpdateAnnotation(id int64, annotation *v1beta1.GrafanaAnnotation) error {
+	return r.do(http.MethodPut, fmt.Sprintf("/api/annotations/%d", id), toGrafanaAnnotation(annotation), nil)
+}
+
+func (r *GrafanaClientImpl) DeleteAnnotation(id int64) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/annotations/%d", id), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func toGrafanaAnnotation(annotation *v1beta1.GrafanaAnnotation) *grafanaAnnotation {
+	result := &grafanaAnnotation{
+		DashboardUID: annotation.Spec.DashboardUID,
+		PanelID:      annotation.Spec.PanelID,
+		Time:         annotation.AnnotationTime().UnixMilli(),
+		Tags:         annotation.Spec.Tags,
+		Text:         annotation.Spec.Text,
+	}
+	if result.Tags == nil {
+		result.Tags = []string{}
+	}
+	if annotation.Spec.TimeEnd != nil {
+		result.TimeEnd = annotation.Spec.TimeEnd.UnixMilli()
+	}
+	return result
+}
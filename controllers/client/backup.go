@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type grafanaSearchHit struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid,omitempty"`
+}
+
+// ExportBackup returns the folders, dashboards, datasources and alerting configuration of the organization,
+// keyed by their path in a backup archive
+func (r *GrafanaClientImpl) ExportBackup() (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	exports := []struct {
+		name string
+		path string
+		// the alerting apis don't exist when unified alerting is disabled
+		optional bool
+	}{
+		{name: "folders.json", path: "/api/folders"},
+		{name: "datasources.json", path: "/api/datasources"},
+		{name: "alerting/contact-points.json", path: "/api/v1/provisioning/contact-points", optional: true},
+		{name: "alerting/policies.json", path: "/api/v1/provisioning/policies", optional: true},
+		{name: "alerting/mute-timings.json", path: "/api/v1/provisioning/mute-timings", optional: true},
+		{name: "alerting/rules.json", path: "/api/ruler/grafana/api/v1/rules", optional: true},
+	}
+
+	for _, export := range exports {
+		var content json.RawMessage
+		err := r.do(http.MethodGet, export.path, nil, &content)
+		if export.optional && IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files[export.name] = content
+	}
+
+	var dashboards []grafanaSearchHit
+	err := r.do(http.MethodGet, "/api/search?type=dash-db&limit=5000", nil, &dashboards)
+	if err != nil {
+		return nil, err
+	}
+
+	// the dashboard models don't contain the folder they are placed in
+	index, err := json.Marshal(dashboards)
+	if err != nil {
+		return nil, err
+	}
+	files["dashboards.json"] = index
+
+	for _, dashboard := range dashboards {
+		content, err := r.GetDashboardJson(dashboard.UID)
+		if err != nil {
+			return nil, err
+		}
+		files[fmt.Sprintf("dashboards/%s.json", dashboard.UID)] = content
+	}
+
+	return files, nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CatalogCacheTTL limits how often the versions of a plugin are requested from grafana.com
+var CatalogCacheTTL = 10 * time.Minute
+
+// CatalogPluginVersion is a version of a plugin published on grafana.com
+type CatalogPluginVersion struct {
+	Version string `json:"version"`
+	// range of Grafana versions the plugin version supports
+	GrafanaDependency string `json:"grafanaDependency"`
+	// grafana, commercial, community or private, empty for unsigned versions
+	SignatureType string `json:"signatureType"`
+	// archives of the version by platform
+	Packages map[string]CatalogPluginPackage `json:"packages"`
+}
+
+// CatalogPluginPackage is the archive of a plugin version for a platform, or for any platform
+type CatalogPluginPackage struct {
+	Sha256 string `json:"sha256"`
+}
+
+// HasChecksum returns true if one of the archives of the version has the sha256 checksum
+func (v *CatalogPluginVersion) HasChecksum(sha256 string) bool {
+	for _, pkg := range v.Packages {
+		if pkg.Sha256 != "" && strings.EqualFold(pkg.Sha256, sha256) {
+			return true
+		}
+	}
+	return false
+}
+
+type catalogVersions struct {
+	versions []CatalogPluginVersion
+	fetched  time.Time
+}
+
+var (
+	catalogCache      = map[string]catalogVersions{}
+	catalogCacheMutex sync.Mutex
+)
+
+// GetCatalogPluginVersions returns the versions of a plugin published in a plugin repository, grafana.com
+// if the repository is empty. Responses are cached for all instances.
+func GetCatalogPluginVersions(ctx context.Context, repository string, name string) ([]CatalogPluginVersion, error) {
+	if repository == "" {
+		repository = config.GrafanaPluginRepository
+	}
+	key := repository + "/" + name
+
+	catalogCacheMutex.Lock()
+	cached, ok := catalogCache[key]
+	catalogCacheMutex.Unlock()
+	if ok && time.Since(cached.fetched) < CatalogCacheTTL {
+		return cached.versions, nil
+	}
+
+	catalog := &GrafanaCloudClientImpl{
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+		url: repository,
+		ctx: ctx,
+	}
+
+	var response struct {
+		Items []CatalogPluginVersion `json:"items"`
+	}
+	err := catalog.do(http.MethodGet, fmt.Sprintf("/%s/versions", url.PathEscape(name)), nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	versions := response.Items
+
+	catalogCacheMutex.Lock()
+	catalogCache[key] = catalogVersions{
+		versions: versions,
+		fetched:  time.Now(),
+	}
+	catalogCacheMutex.Unlock()
+	return versions, nil
+}
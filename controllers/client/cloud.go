@@ -0,0 +1,250 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/audit"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"io"
+	"net/http"
+	"net/url"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"time"
+)
+
+// GrafanaCloudStack is the Grafana Cloud api representation of a stack
+type GrafanaCloudStack struct {
+	ID         int64  `json:"id"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Status     string `json:"status"`
+	RegionSlug string `json:"regionSlug"`
+}
+
+type grafanaCloudStackCreate struct {
+	Name   string `json:"name"`
+	Slug   string `json:"slug"`
+	Region string `json:"region,omitempty"`
+}
+
+type grafanaCloudAPIKeyCreate struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type grafanaCloudAPIKey struct {
+	Key string `json:"key"`
+}
+
+type grafanaCloudPlugin struct {
+	PluginSlug string `json:"pluginSlug"`
+	Version    string `json:"version"`
+}
+
+type grafanaCloudPluginInstall struct {
+	Plugin  string `json:"plugin,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type GrafanaCloudClient interface {
+	GetStack(slug string) (*GrafanaCloudStack, error)
+	CreateStack(stack *v1beta1.GrafanaCloudStack) (*GrafanaCloudStack, error)
+	CreateStackAPIKey(slug string, name string) (string, error)
+	GetStackPlugins(slug string) (map[string]string, error)
+	InstallStackPlugin(slug string, plugin v1beta1.GrafanaPlugin, installedVersion string) error
+}
+
+type GrafanaCloudClientImpl struct {
+	httpClient *http.Client
+	apiKey     string
+	url        string
+	ctx        context.Context
+
+	// namespace and name of the instance, used in the audit log
+	instance string
+}
+
+// NewGrafanaCloudClient returns a client for the Grafana Cloud api using the org api key of the stack spec
+func NewGrafanaCloudClient(ctx context.Context, c client.Client, grafana *v1beta1.Grafana) (GrafanaCloudClient, error) {
+	cloud := grafana.Spec.Cloud
+
+	apiKey, err := getSecretKey(ctx, c, grafana.Namespace, &cloud.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := cloud.APIURL
+	if apiURL == "" {
+		apiURL = config.GrafanaCloudAPIURL
+	}
+
+	return &GrafanaCloudClientImpl{
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+		apiKey:   apiKey,
+		url:      apiURL,
+		ctx:      ctx,
+		instance: instanceName(grafana),
+	}, nil
+}
+
+// newCloudStackGrafanaClient returns a client for a provisioned stack, using the api key created for the
+// operator when the stack was provisioned
+func newCloudStackGrafanaClient(ctx context.Context, c client.Client, grafana *v1beta1.Grafana, timeout time.Duration) (GrafanaClient, error) {
+	secret := model.GetGrafanaCloudStackSecret(grafana, nil)
+	err := c.Get(ctx, client.ObjectKey{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+	}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, ok := secret.Data[config.GrafanaCloudStackAPIKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s does not contain key %s", secret.Namespace, secret.Name, config.GrafanaCloudStackAPIKeyKey)
+	}
+
+	return &GrafanaClientImpl{
+		url:        grafana.Status.AdminUrl,
+		instance:   instanceName(grafana),
+		apiKey:     string(apiKey),
+		kubeClient: c,
+		ctx:        ctx,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}, nil
+}
+
+// do sends a request to the Grafana Cloud api, body and response are encoded as json
+func (r *GrafanaCloudClientImpl) do(method string, path string, body interface{}, response interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, method, strings.TrimSuffix(r.url, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		audit.Record(r.ctx, r.instance, method, path, 0, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		audit.Record(r.ctx, r.instance, method, path, resp.StatusCode, err)
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &GrafanaApiError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(data)),
+		}
+		var msg GrafanaResponse
+		if json.Unmarshal(data, &msg) == nil && msg.Message != nil {
+			apiErr.Message = *msg.Message
+		}
+		audit.Record(r.ctx, r.instance, method, path, resp.StatusCode, apiErr)
+		return apiErr
+	}
+	audit.Record(r.ctx, r.instance, method, path, resp.StatusCode, nil)
+
+	if response == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, response)
+}
+
+func (r *GrafanaCloudClientImpl) GetStack(slug string) (*GrafanaCloudStack, error) {
+	stack := &GrafanaCloudStack{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/instances/%s", url.PathEscape(slug)), nil, stack)
+	if err != nil {
+		return nil, err
+	}
+	return stack, nil
+}
+
+func (r *GrafanaCloudClientImpl) CreateStack(stack *v1beta1.GrafanaCloudStack) (*GrafanaCloudStack, error) {
+	name := stack.Name
+	if name == "" {
+		name = stack.Slug
+	}
+
+	created := &GrafanaCloudStack{}
+	err := r.do(http.MethodPost, "/api/instances", &grafanaCloudStackCreate{
+		Name:   name,
+		Slug:   stack.Slug,
+		Region: stack.Region,
+	}, created)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CreateStackAPIKey creates an admin api key in the stack through the instance proxy of the cloud api
+func (r *GrafanaCloudClientImpl) CreateStackAPIKey(slug string, name string) (string, error) {
+	key := &grafanaCloudAPIKey{}
+	err := r.do(http.MethodPost, fmt.Sprintf("/api/instances/%s/api/auth/keys", url.PathEscape(slug)), &grafanaCloudAPIKeyCreate{
+		Name: name,
+		Role: "Admin",
+	}, key)
+	if err != nil {
+		return "", err
+	}
+	return key.Key, nil
+}
+
+// GetStackPlugins returns the versions of all plugins installed in the stack, keyed by plugin id
+func (r *GrafanaCloudClientImpl) GetStackPlugins(slug string) (map[string]string, error) {
+	var plugins struct {
+		Items []grafanaCloudPlugin `json:"items"`
+	}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/instances/%s/plugins", url.PathEscape(slug)), nil, &plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string]string{}
+	for _, plugin := range plugins.Items {
+		versions[plugin.PluginSlug] = plugin.Version
+	}
+	return versions, nil
+}
+
+// InstallStackPlugin installs a plugin in the stack, or changes the version of an installed plugin
+func (r *GrafanaCloudClientImpl) InstallStackPlugin(slug string, plugin v1beta1.GrafanaPlugin, installedVersion string) error {
+	if installedVersion != "" {
+		return r.do(http.MethodPost, fmt.Sprintf("/api/instances/%s/plugins/%s", url.PathEscape(slug), url.PathEscape(plugin.Name)), &grafanaCloudPluginInstall{
+			Version: plugin.Version,
+		}, nil)
+	}
+
+	return r.do(http.MethodPost, fmt.Sprintf("/api/instances/%s/plugins", url.PathEscape(slug)), &grafanaCloudPluginInstall{
+		Plugin:  plugin.Name,
+		Version: plugin.Version,
+	}, nil)
+}
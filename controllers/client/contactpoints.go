@@ -0,0 +1,46 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GrafanaContactPoint is the provisioning api representation of a contact point
+type GrafanaContactPoint struct {
+	UID                   string          `json:"uid"`
+	Name                  string          `json:"name"`
+	Type                  string          `json:"type"`
+	Settings              json.RawMessage `json:"settings"`
+	DisableResolveMessage bool            `json:"disableResolveMessage"`
+}
+
+func (r *GrafanaClientImpl) GetContactPoints() ([]GrafanaContactPoint, error) {
+	var contactPoints []GrafanaContactPoint
+	err := r.do(http.MethodGet, "/api/v1/provisioning/contact-points", nil, &contactPoints)
+	return contactPoints, err
+}
+
+func (r *GrafanaClientImpl) CreateOrUpdateContactPoint(contactPoint *GrafanaContactPoint) error {
+	existing, err := r.GetContactPoints()
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range existing {
+		if candidate.UID == contactPoint.UID {
+			return r.do(http.MethodPut, fmt.Sprintf("/api/v1/provisioning/contact-points/%s", url.PathEscape(contactPoint.UID)), contactPoint, nil)
+		}
+	}
+
+	return r.do(http.MethodPost, "/api/v1/provisioning/contact-points", contactPoint, nil)
+}
+
+func (r *GrafanaClientImpl) DeleteContactPoint(uid string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/v1/provisioning/contact-points/%s", url.PathEscape(uid)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
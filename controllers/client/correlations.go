@@ -0,0 +1,90 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+type grafanaCorrelationTransformation struct {
+	Type       string `json:"type"`
+	Expression string `json:"expression,omitempty"`
+	Variable   string `json:"variable,omitempty"`
+	Field      string `json:"field,omitempty"`
+}
+
+type grafanaCorrelationConfig struct {
+	Type            string                             `json:"type"`
+	Field           string                             `json:"field"`
+	Target          json.RawMessage                    `json:"target"`
+	Transformations []grafanaCorrelationTransformation `json:"transformations,omitempty"`
+}
+
+type grafanaCorrelation struct {
+	UID         string                   `json:"uid,omitempty"`
+	TargetUID   string                   `json:"targetUID"`
+	Label       string                   `json:"label"`
+	Description string                   `json:"description"`
+	Config      grafanaCorrelationConfig `json:"config"`
+}
+
+type grafanaCorrelationResponse struct {
+	Result grafanaCorrelation `json:"result"`
+}
+
+// CreateOrUpdateCorrelation creates the correlation if uid is empty or unknown, otherwise it is updated. Returns
+// the uid of the correlation.
+func (r *GrafanaClientImpl) CreateOrUpdateCorrelation(sourceUID string, targetUID string, uid string, correlation *v1beta1.GrafanaCorrelation) (string, error) {
+	body := toGrafanaCorrelation(targetUID, correlation)
+	path := fmt.Sprintf("/api/datasources/uid/%s/correlations", url.PathEscape(sourceUID))
+
+	if uid != "" {
+		err := r.do(http.MethodPatch, fmt.Sprintf("%s/%s", path, url.PathEscape(uid)), body, nil)
+		if !IsNotFound(err) {
+			return uid, err
+		}
+	}
+
+	created := &grafanaCorrelationResponse{}
+	err := r.do(http.MethodPost, path, body, created)
+	return created.Result.UID, err
+}
+
+func (r *GrafanaClientImpl) DeleteCorrelation(sourceUID string, uid string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", url.PathEscape(sourceUID), url.PathEscape(uid)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func toGrafanaCorrelation(targetUID string, correlation *v1beta1.GrafanaCorrelation) *grafanaCorrelation {
+	config := grafanaCorrelationConfig{
+		Type:   correlation.Spec.Config.Type,
+		Field:  correlation.Spec.Config.Field,
+		Target: json.RawMessage("{}"),
+	}
+	if config.Type == "" {
+		config.Type = "query"
+	}
+	if correlation.Spec.Config.Target != nil && len(correlation.Spec.Config.Target.Raw) > 0 {
+		config.Target = correlation.Spec.Config.Target.Raw
+	}
+	for _, transformation := range correlation.Spec.Config.Transformations {
+		config.Transformations = append(config.Transformations, grafanaCorrelationTransformation{
+			Type:       transformation.Type,
+			Expression: transformation.Expression,
+			Variable:   transformation.Variable,
+			Field:      transformation.Field,
+		})
+	}
+
+	return &grafanaCorrelation{
+		TargetUID:   targetUID,
+		Label:       correlation.CorrelationLabel(),
+		Description: correlation.Spec.Description,
+		Config:      config,
+	}
+}
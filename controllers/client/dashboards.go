@@ -0,0 +1,160 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"net/http"
+	"net/url"
+)
+
+// fields Grafana changes on every save, they are not part of the logical content of a dashboard
+var volatileDashboardFields = []string{"id", "version", "iteration"}
+
+type grafanaDashboardHit struct {
+	UID       string   `json:"uid"`
+	FolderUID string   `json:"folderUid,omitempty"`
+	Tags      []string `json:"tags"`
+}
+
+// NormalizeDashboard returns the json of a dashboard without volatile fields and with sorted keys, dashboards
+// without uid get the uid generated from the cr. The json is tagged as managed by the operator.
+func NormalizeDashboard(dashboard *v1beta1.GrafanaDashboard) ([]byte, error) {
+	uid, err := dashboard.DashboardUID()
+	if err != nil {
+		return nil, err
+	}
+	return normalizeDashboardJson([]byte(dashboard.Spec.Json), uid)
+}
+
+// DashboardContentHash returns the sha256 of the normalized json of a dashboard
+func DashboardContentHash(dashboard *v1beta1.GrafanaDashboard) (string, error) {
+	raw, err := NormalizeDashboard(dashboard)
+	if err != nil {
+		return "", err
+	}
+	return contentHash(raw), nil
+}
+
+func contentHash(raw []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(raw))
+}
+
+func normalizeDashboardJson(raw []byte, uid string) ([]byte, error) {
+	var content map[string]interface{}
+	err := json.Unmarshal(raw, &content)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, errors.New("the json must be a dashboard object")
+	}
+
+	// the internal id differs between instances, dashboards are matched by uid instead
+	for _, field := range volatileDashboardFields {
+		delete(content, field)
+	}
+	content["uid"] = uid
+
+	tags, _ := content["tags"].([]interface{})
+	if !hasManagedTag(tags) {
+		content["tags"] = append(tags, config.ManagedDashboardTag)
+	}
+
+	// maps are marshalled with sorted keys
+	return json.Marshal(content)
+}
+
+func hasManagedTag(tags []interface{}) bool {
+	for _, tag := range tags {
+		if tag == config.ManagedDashboardTag {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneFolderDashboards deletes the dashboards of a folder that are not tagged as managed by the operator and
+// returns their uids
+func (r *GrafanaClientImpl) PruneFolderDashboards(folderUID string) ([]string, error) {
+	var hits []grafanaDashboardHit
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/search?type=dash-db&limit=5000&folderUIDs=%s", url.QueryEscape(folderUID)), nil, &hits)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+	for _, hit := range hits {
+		// versions of Grafana that don't filter by folder uid return all dashboards
+		if hit.FolderUID != folderUID || isManaged(hit.Tags) {
+			continue
+		}
+		err = r.do(http.MethodDelete, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(hit.UID)), nil, nil)
+		if err != nil && !IsNotFound(err) {
+			return pruned, err
+		}
+		pruned = append(pruned, hit.UID)
+	}
+	return pruned, nil
+}
+
+func (r *GrafanaClientImpl) DeleteDashboard(uid string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(uid)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// OrphanDashboard removes the grafana-operator tag from a dashboard so that it is treated like a dashboard
+// created in the ui
+func (r *GrafanaClientImpl) OrphanDashboard(uid string) error {
+	existing := &grafanaDashboardWithMeta{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(uid)), nil, existing)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	content := map[string]interface{}{}
+	err = json.Unmarshal(existing.Dashboard, &content)
+	if err != nil {
+		return err
+	}
+	tags, _ := content["tags"].([]interface{})
+	if !hasManagedTag(tags) {
+		return nil
+	}
+
+	remaining := []interface{}{}
+	for _, tag := range tags {
+		if tag != config.ManagedDashboardTag {
+			remaining = append(remaining, tag)
+		}
+	}
+	content["tags"] = remaining
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	return r.do(http.MethodPost, "/api/dashboards/db", GrafanaRequest{
+		Dashboard: raw,
+		FolderUID: existing.Meta.FolderUID,
+		Overwrite: true,
+	}, nil)
+}
+
+func isManaged(tags []string) bool {
+	for _, tag := range tags {
+		if tag == config.ManagedDashboardTag {
+			return true
+		}
+	}
+	return false
+}
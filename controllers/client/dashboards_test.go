@@ -0,0 +1,55 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestNormalizeDashboardJson(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		uid      string
+		expected string
+	}{
+		{name: "empty dashboard", raw: `{}`, uid: "abc", expected: `{"tags":["grafana-operator"],"uid":"abc"}`},
+		{name: "volatile fields removed", raw: `{"id":12,"version":3,"iteration":1650000000000,"title":"a"}`, uid: "abc", expected: `{"tags":["grafana-operator"],"title":"a","uid":"abc"}`},
+		{name: "uid replaced", raw: `{"uid":"other"}`, uid: "abc", expected: `{"tags":["grafana-operator"],"uid":"abc"}`},
+		{name: "keys sorted", raw: `{"title":"a","panels":[{"type":"graph","id":1}],"editable":true}`, uid: "abc", expected: `{"editable":true,"panels":[{"id":1,"type":"graph"}],"tags":["grafana-operator"],"title":"a","uid":"abc"}`},
+		{name: "managed tag appended", raw: `{"tags":["team"]}`, uid: "abc", expected: `{"tags":["team","grafana-operator"],"uid":"abc"}`},
+		{name: "managed tag kept", raw: `{"tags":["grafana-operator","team"]}`, uid: "abc", expected: `{"tags":["grafana-operator","team"],"uid":"abc"}`},
+		{name: "invalid tags replaced", raw: `{"tags":"team"}`, uid: "abc", expected: `{"tags":["grafana-operator"],"uid":"abc"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := normalizeDashboardJson([]byte(test.raw), test.uid)
+			if err != nil {
+				t.Fatalf("normalizeDashboardJson(%s) returned %v", test.raw, err)
+			}
+			if string(actual) != test.expected {
+				t.Errorf("normalizeDashboardJson(%s) = %s, expected %s", test.raw, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeDashboardJsonInvalid(t *testing.T) {
+	for _, raw := range []string{``, `{`, `[]`, `"dashboard"`, `null`} {
+		if _, err := normalizeDashboardJson([]byte(raw), "abc"); err == nil {
+			t.Errorf("normalizeDashboardJson(%s) accepted invalid json", raw)
+		}
+	}
+}
+
+func TestNormalizeDashboardJsonStable(t *testing.T) {
+	a, err := normalizeDashboardJson([]byte(`{"id":1,"version":2,"title":"a","tags":["team"]}`), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := normalizeDashboardJson([]byte(`{"tags":["team","grafana-operator"],"title":"a","id":7,"version":9}`), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentHash(a) != contentHash(b) {
+		t.Errorf("hashes of %s and %s differ", a, b)
+	}
+}
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+const (
+	DatasourceHealthOK      = "OK"
+	DatasourceHealthError   = "ERROR"
+	DatasourceHealthUnknown = "UNKNOWN"
+)
+
+type GrafanaDatasource struct {
+	ID      int64  `json:"id,omitempty"`
+	UID     string `json:"uid"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Version int64  `json:"version,omitempty"`
+}
+
+func (r *GrafanaClientImpl) GetDatasource(uid string) (*GrafanaDatasource, error) {
+	datasource := &GrafanaDatasource{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/datasources/uid/%s", url.PathEscape(uid)), nil, datasource)
+	if err != nil {
+		return nil, err
+	}
+	return datasource, nil
+}
+
+// CreateOrUpdateDatasource imports the api representation of a datasource, a datasource with the same name but another uid existed before
+// and is replaced according to the adoption policy. It returns the replaced datasource if it was adopted.
+func (r *GrafanaClientImpl) CreateOrUpdateDatasource(datasource *v1beta1.GrafanaDatasource, body map[string]interface{}) (ApplyResult, *v1beta1.AdoptedResource, error) {
+	existing, err := r.findDatasource(datasource)
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+
+	var adopted *v1beta1.AdoptedResource
+	if existing == nil {
+		existing, err = r.getDatasourceByName(datasource.DatasourceName())
+		if err != nil && !IsNotFound(err) {
+			return ApplyUnchanged, nil, err
+		}
+		if existing == nil {
+			err = r.do(http.MethodPost, "/api/datasources", body, nil)
+			if err != nil {
+				return ApplyUnchanged, nil, err
+			}
+			return ApplyCreated, nil, nil
+		}
+
+		switch datasource.Spec.AdoptionPolicy {
+		case v1beta1.AdoptionPolicyFail:
+			return ApplyUnchanged, nil, fmt.Errorf("datasource %s already exists with uid %s", existing.Name, existing.UID)
+		case v1beta1.AdoptionPolicyAdopt:
+			adopted = &v1beta1.AdoptedResource{
+				UID:     existing.UID,
+				Version: existing.Version,
+			}
+		}
+	} else if datasource.Spec.AllowUIUpdates && datasource.Status.ObservedGeneration == datasource.Generation && !datasource.ReconcileRequested() {
+		// changes made in the ui are kept until the cr changes
+		return ApplyUnchanged, nil, nil
+	}
+
+	body["id"] = existing.ID
+	err = r.do(http.MethodPut, fmt.Sprintf("/api/datasources/%d", existing.ID), body, nil)
+	if err != nil {
+		return ApplyUnchanged, adopted, err
+	}
+	return ApplyUpdated, adopted, nil
+}
+
+// DiffDatasource returns the change importing the api representation of a datasource would make and the
+// changed fields, without changing the datasource in Grafana. Only the fields of the body are compared,
+// secure json data can't be read from Grafana.
+func (r *GrafanaClientImpl) DiffDatasource(datasource *v1beta1.GrafanaDatasource, body map[string]interface{}) (ApplyResult, []string, error) {
+	existing, err := r.findDatasource(datasource)
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+	if existing == nil {
+		existing, err = r.getDatasourceByName(datasource.DatasourceName())
+		if IsNotFound(err) {
+			return ApplyCreated, nil, nil
+		}
+		if err != nil {
+			return ApplyUnchanged, nil, err
+		}
+		if datasource.Spec.AdoptionPolicy == v1beta1.AdoptionPolicyFail {
+			return ApplyUnchanged, nil, fmt.Errorf("datasource %s already exists with uid %s", existing.Name, existing.UID)
+		}
+	} else if datasource.Spec.AllowUIUpdates && datasource.Status.ObservedGeneration == datasource.Generation && !datasource.ReconcileRequested() {
+		return ApplyUnchanged, nil, nil
+	}
+
+	current := map[string]interface{}{}
+	err = r.do(http.MethodGet, fmt.Sprintf("/api/datasources/uid/%s", url.PathEscape(existing.UID)), nil, &current)
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+
+	// the body is compared as it is sent, numbers are decoded the same way on both sides
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+	desired := map[string]interface{}{}
+	err = json.Unmarshal(raw, &desired)
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+	delete(desired, "secureJsonData")
+
+	compared := map[string]interface{}{}
+	for key := range desired {
+		compared[key] = current[key]
+	}
+	changes := diffJson("", compared, desired)
+	if len(changes) == 0 {
+		return ApplyUnchanged, nil, nil
+	}
+	return ApplyUpdated, changes, nil
+}
+
+// findDatasource returns the datasource with the uid of the cr, or with the uid it was imported with before,
+// nil if neither exists
+func (r *GrafanaClientImpl) findDatasource(datasource *v1beta1.GrafanaDatasource) (*GrafanaDatasource, error) {
+	existing, err := r.GetDatasource(datasource.DatasourceUID())
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+
+	// datasources imported with a previous uid are updated instead of left behind
+	if previous := r.getPreviousUID(datasource); existing == nil && previous != "" && previous != datasource.DatasourceUID() {
+		existing, err = r.GetDatasource(previous)
+		if err != nil && !IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return existing, nil
+}
+
+// getPreviousUID returns the uid a datasource was imported into the instance with, datasources without a
+// record of the instance fall back to the uid of the cr
+func (r *GrafanaClientImpl) getPreviousUID(datasource *v1beta1.GrafanaDatasource) string {
+	for _, status := range datasource.Status.Instances {
+		if status.Instance == r.instance && status.UID != "" {
+			return status.UID
+		}
+	}
+	return datasource.Status.UID
+}
+
+// GrafanaDatasourceHealth is the result of the health check of a datasource plugin
+type GrafanaDatasourceHealth struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// CheckDatasourceHealth runs the health check of a datasource, failed checks are returned with status ERROR
+// and datasources of plugins without health check with status UNKNOWN
+func (r *GrafanaClientImpl) CheckDatasourceHealth(uid string) (*GrafanaDatasourceHealth, error) {
+	health := &GrafanaDatasourceHealth{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/datasources/uid/%s/health", url.PathEscape(uid)), nil, health)
+
+	var apiErr *GrafanaApiError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusNotFound, http.StatusNotImplemented:
+			return &GrafanaDatasourceHealth{Status: DatasourceHealthUnknown, Message: apiErr.Message}, nil
+		case http.StatusBadRequest, http.StatusInternalServerError:
+			return &GrafanaDatasourceHealth{Status: DatasourceHealthError, Message: apiErr.Message}, nil
+		}
+	}
+	return health, err
+}
+
+func (r *GrafanaClientImpl) getDatasourceByName(name string) (*GrafanaDatasource, error) {
+	datasource := &GrafanaDatasource{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/datasources/name/%s", url.PathEscape(name)), nil, datasource)
+	if err != nil {
+		return nil, err
+	}
+	return datasource, nil
+}
+
+func (r *GrafanaClientImpl) DeleteDatasource(uid string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/datasources/uid/%s", url.PathEscape(uid)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// ToGrafanaDatasource returns the api representation of the datasource with uid and name defaults applied
+func ToGrafanaDatasource(datasource *v1beta1.GrafanaDatasource) (map[string]interface{}, error) {
+	content := map[string]interface{}{}
+	if datasource.Spec.Datasource != nil {
+		raw, err := json.Marshal(datasource.Spec.Datasource)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(raw, &content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content["uid"] = datasource.DatasourceUID()
+	content["name"] = datasource.DatasourceName()
+	return content, nil
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// values in a diff are shortened to this length, so that large panels don't fill the status
+const maxDiffValueLength = 80
+
+// diffJson returns the paths of the differences between two decoded json values as
+// "path: current -> desired", values missing on one side are shown as <none>
+func diffJson(path string, current, desired interface{}) []string {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		currentValue, ok := current.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := map[string]bool{}
+		for key := range currentValue {
+			keys[key] = true
+		}
+		for key := range desiredValue {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		var changes []string
+		for _, key := range sorted {
+			changes = append(changes, diffJson(joinDiffPath(path, key), currentValue[key], desiredValue[key])...)
+		}
+		return changes
+	case []interface{}:
+		currentValue, ok := current.([]interface{})
+		if !ok {
+			break
+		}
+		length := len(desiredValue)
+		if len(currentValue) > length {
+			length = len(currentValue)
+		}
+
+		var changes []string
+		for i := 0; i < length; i++ {
+			var currentItem, desiredItem interface{}
+			if i < len(currentValue) {
+				currentItem = currentValue[i]
+			}
+			if i < len(desiredValue) {
+				desiredItem = desiredValue[i]
+			}
+			changes = append(changes, diffJson(fmt.Sprintf("%s[%d]", path, i), currentItem, desiredItem)...)
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(current, desired) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: %s -> %s", path, formatDiffValue(current), formatDiffValue(desired))}
+}
+
+func joinDiffPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func formatDiffValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	if len(raw) > maxDiffValueLength {
+		return string(raw[:maxDiffValueLength]) + "..."
+	}
+	return string(raw)
+}
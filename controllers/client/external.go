@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+// newExternalGrafanaClient returns a client for an instance not deployed by the operator, using the url,
+// tls settings and credentials of the external spec
+func newExternalGrafanaClient(ctx context.Context, c client.Client, grafana *v1beta1.Grafana, timeout time.Duration) (GrafanaClient, error) {
+	external := grafana.Spec.External
+
+	tlsConfig, err := getExternalTLSConfig(ctx, c, grafana.Namespace, external.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	impl := &GrafanaClientImpl{
+		url:        external.URL,
+		instance:   instanceName(grafana),
+		kubeClient: c,
+		ctx:        ctx,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+			Timeout: timeout,
+		},
+	}
+
+	if external.APIKey != nil {
+		impl.apiKey, err = getSecretKey(ctx, c, grafana.Namespace, external.APIKey)
+		return impl, err
+	}
+
+	if external.AdminUser == nil || external.AdminPassword == nil {
+		return nil, errors.New("external grafana requires either an api key or an admin user and password")
+	}
+
+	impl.username, err = getSecretKey(ctx, c, grafana.Namespace, external.AdminUser)
+	if err != nil {
+		return nil, err
+	}
+	impl.password, err = getSecretKey(ctx, c, grafana.Namespace, external.AdminPassword)
+	if err != nil {
+		return nil, err
+	}
+	return impl, nil
+}
+
+func getExternalTLSConfig(ctx context.Context, c client.Client, namespace string, settings *v1beta1.GrafanaExternalTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if settings == nil {
+		return tlsConfig, nil
+	}
+
+	// #nosec G402 explicitly requested for the instance
+	tlsConfig.InsecureSkipVerify = settings.InsecureSkipVerify
+
+	if settings.CertificateAuthority != nil {
+		ca, err := getSecretKey(ctx, c, namespace, settings.CertificateAuthority)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ca)) {
+			return nil, fmt.Errorf("secret %s/%s contains no valid ca certificate", namespace, settings.CertificateAuthority.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (settings.ClientCertificate == nil) != (settings.ClientKey == nil) {
+		return nil, errors.New("clientCertificate and clientKey must be set together")
+	}
+
+	if settings.ClientCertificate != nil {
+		cert, err := getSecretKey(ctx, c, namespace, settings.ClientCertificate)
+		if err != nil {
+			return nil, err
+		}
+		key, err := getSecretKey(ctx, c, namespace, settings.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	return tlsConfig, nil
+}
+
+func getSecretKey(ctx context.Context, c client.Client, namespace string, selector *v1.SecretKeySelector) (string, error) {
+	secret := &v1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      selector.Name,
+	}, secret)
+	if err != nil {
+		return "", err
+	}
+
+	if val, ok := secret.Data[selector.Key]; ok {
+		return string(val), nil
+	}
+	return "", fmt.Errorf("secret %s/%s does not contain key %s", namespace, selector.Name, selector.Key)
+}
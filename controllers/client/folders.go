@@ -0,0 +1,74 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+type GrafanaFolder struct {
+	ID      int64  `json:"id,omitempty"`
+	UID     string `json:"uid"`
+	Title   string `json:"title"`
+	Version int    `json:"version,omitempty"`
+}
+
+type grafanaFolderUpdate struct {
+	Title     string `json:"title"`
+	Version   int    `json:"version,omitempty"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+func (r *GrafanaClientImpl) GetFolder(uid string) (*GrafanaFolder, error) {
+	folder := &GrafanaFolder{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/folders/%s", url.PathEscape(uid)), nil, folder)
+	if err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (r *GrafanaClientImpl) CreateOrUpdateFolder(folder *v1beta1.GrafanaFolder) error {
+	uid := folder.FolderUID()
+
+	existing, err := r.GetFolder(uid)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	// changes made in the ui are kept until the cr changes
+	if existing != nil && folder.Spec.AllowUIUpdates && folder.Status.ObservedGeneration == folder.Generation && !folder.ReconcileRequested() {
+		return nil
+	}
+
+	if existing == nil {
+		err = r.do(http.MethodPost, "/api/folders", &GrafanaFolder{
+			UID:   uid,
+			Title: folder.FolderTitle(),
+		}, nil)
+	} else if existing.Title != folder.FolderTitle() {
+		err = r.do(http.MethodPut, fmt.Sprintf("/api/folders/%s", url.PathEscape(uid)), &grafanaFolderUpdate{
+			Title:     folder.FolderTitle(),
+			Version:   existing.Version,
+			Overwrite: true,
+		}, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(folder.Spec.Permissions) == 0 {
+		return nil
+	}
+
+	return r.SyncFolderPermissions(uid, folder.Spec.Permissions)
+}
+
+func (r *GrafanaClientImpl) DeleteFolder(uid string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/folders/%s", url.PathEscape(uid)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
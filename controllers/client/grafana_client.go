@@ -1,23 +1,33 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/audit"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/metrics"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/tracing"
+	"io"
+	v1 "k8s.io/api/core/v1"
 	"net/http"
+	"net/url"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type GrafanaRequest struct {
-	Dashboard  json.RawMessage `json:"dashboard"`
-	FolderId   int64           `json:"folderId"`
-	FolderName string          `json:"folderName"`
-	Overwrite  bool            `json:"overwrite"`
+	Dashboard json.RawMessage `json:"dashboard"`
+	FolderUID string          `json:"folderUid,omitempty"`
+	Overwrite bool            `json:"overwrite"`
 }
 
 type GrafanaResponse struct {
@@ -33,8 +43,119 @@ type GrafanaResponse struct {
 	FolderName string  `json:"folderName"`
 }
 
+// GrafanaApiError is returned for any non 2xx response of the Grafana api
+type GrafanaApiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *GrafanaApiError) Error() string {
+	return fmt.Sprintf("grafana api returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is a 404 returned by the Grafana api
+func IsNotFound(err error) bool {
+	var apiErr *GrafanaApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// ApplyResult is the change made in Grafana by importing an object, the values are used as event reasons
+type ApplyResult string
+
+const (
+	ApplyUnchanged ApplyResult = ""
+	ApplyCreated   ApplyResult = "Created"
+	ApplyUpdated   ApplyResult = "Updated"
+)
+
 type GrafanaClient interface {
-	CreateOrUpdateDashboard(dashboard *v1beta1.GrafanaDashboard) error
+	CreateOrUpdateDashboard(dashboard *v1beta1.GrafanaDashboard, folderUID string) (ApplyResult, *v1beta1.AdoptedResource, error)
+	PruneFolderDashboards(folderUID string) ([]string, error)
+	DeleteDashboard(uid string) error
+	OrphanDashboard(uid string) error
+	DiffDashboard(dashboard *v1beta1.GrafanaDashboard, folderUID string) (ApplyResult, []string, error)
+
+	GetFolder(uid string) (*GrafanaFolder, error)
+	CreateOrUpdateFolder(folder *v1beta1.GrafanaFolder) error
+	DeleteFolder(uid string) error
+
+	SyncDashboardPermissions(uid string, items []v1beta1.GrafanaPermissionItem) error
+	SyncFolderPermissions(uid string, items []v1beta1.GrafanaPermissionItem) error
+	SyncDatasourcePermissions(uid string, items []v1beta1.GrafanaDatasourcePermissionItem) ([]string, error)
+
+	GetContactPoints() ([]GrafanaContactPoint, error)
+	CreateOrUpdateContactPoint(contactPoint *GrafanaContactPoint) error
+	DeleteContactPoint(uid string) error
+
+	GetNotificationPolicy() (*GrafanaNotificationRoute, error)
+	SetNotificationPolicy(route *GrafanaNotificationRoute) error
+
+	CreateOrUpdateMuteTiming(muteTiming *v1beta1.GrafanaMuteTiming) error
+	DeleteMuteTiming(name string) error
+
+	GetTeamByName(name string) (*GrafanaTeam, error)
+	LookupUser(loginOrEmail string) (*GrafanaUserLookup, error)
+	CreateOrUpdateTeam(team *v1beta1.GrafanaTeam) ([]string, error)
+	DeleteTeam(name string) error
+
+	GetOrgByName(name string) (*GrafanaOrg, error)
+	CreateOrUpdateUser(user *v1beta1.GrafanaUser, password string, updatePassword bool) error
+	DeleteUser(login string) error
+
+	CreateOrg(name string) error
+	DeleteOrg(name string) error
+	InOrg(orgID int64) GrafanaClient
+
+	GetDatasource(uid string) (*GrafanaDatasource, error)
+	CreateOrUpdateDatasource(datasource *v1beta1.GrafanaDatasource, body map[string]interface{}) (ApplyResult, *v1beta1.AdoptedResource, error)
+	DeleteDatasource(uid string) error
+	CheckDatasourceHealth(uid string) (*GrafanaDatasourceHealth, error)
+	DiffDatasource(datasource *v1beta1.GrafanaDatasource, body map[string]interface{}) (ApplyResult, []string, error)
+
+	CreateOrUpdateServiceAccount(serviceAccount *v1beta1.GrafanaServiceAccount) (int64, error)
+	CreateServiceAccountToken(serviceAccountID int64, name string) (*GrafanaServiceAccountToken, error)
+	DeleteServiceAccountToken(serviceAccountID int64, tokenID int64) error
+	DeleteServiceAccount(name string) error
+
+	CreateOrUpdateLibraryPanel(panel *v1beta1.GrafanaLibraryPanel, folderUID string) error
+	DeleteLibraryPanel(uid string) error
+
+	CreateAnnotation(annotation *v1beta1.GrafanaAnnotation) (int64, error)
+	UpdateAnnotation(id int64, annotation *v1beta1.GrafanaAnnotation) error
+	DeleteAnnotation(id int64) error
+
+	CreateOrUpdatePublicDashboard(dashboardUID string, publicDashboard *v1beta1.GrafanaPublicDashboard) (string, error)
+	DeletePublicDashboard(dashboardUID string) error
+
+	CreateOrUpdateCorrelation(sourceUID string, targetUID string, uid string, correlation *v1beta1.GrafanaCorrelation) (string, error)
+	DeleteCorrelation(sourceUID string, uid string) error
+
+	IsEnterprise() (bool, error)
+	CreateOrUpdateRole(role *v1beta1.GrafanaRole) error
+	DeleteRole(uid string) error
+	AssignRole(roleUID string, subjects v1beta1.GrafanaRoleSubjects) ([]string, error)
+	UnassignRole(roleUID string, subjects v1beta1.GrafanaRoleSubjects) error
+
+	GetDashboardJson(uid string) (json.RawMessage, error)
+	CreateSnapshot(dashboard json.RawMessage, name string, expiresSeconds int64) (*GrafanaSnapshot, error)
+	DeleteSnapshot(key string) error
+
+	GetOrgPreferences() (*GrafanaPreferences, error)
+	SetOrgPreferences(preferences *GrafanaPreferences) error
+	SetOrgHomeDashboard(uid string) error
+
+	ExportBackup() (map[string][]byte, error)
+	RestoreBackup(files map[string][]byte, replace bool, dryRun bool) ([]string, error)
+
+	GetInstalledPlugins() (map[string]string, error)
+	InstallPlugin(plugin v1beta1.GrafanaPlugin, installedVersion string) error
+	UninstallPlugin(name string) error
+	IsPluginCatalogEnabled() (bool, error)
+
+	ChangeAdminPassword(newPassword string) error
+	VerifyCredentials(username string, password string) (bool, error)
+
+	GetHealth() (*GrafanaHealth, error)
 }
 
 type GrafanaClientImpl struct {
@@ -42,17 +163,54 @@ type GrafanaClientImpl struct {
 	httpClient *http.Client
 	username   string
 	password   string
+	apiKey     string
 	url        string
+	orgID      int64
 	ctx        context.Context
+
+	// namespace and name of the instance, used as metrics label
+	instance string
 }
 
-func NewGrafanaClient(ctx context.Context, c client.Client, grafana *v1beta1.Grafana) (GrafanaClient, error) {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
+// getInstanceTLSConfig verifies instances serving https with the ca of their tls secret, or the system
+// roots when the secret has no ca.crt
+func getInstanceTLSConfig(ctx context.Context, c client.Client, grafana *v1beta1.Grafana) (*tls.Config, error) {
+	if grafana.Spec.TLS == nil {
+		// #nosec G402 the instance serves plain http
+		return &tls.Config{
 			InsecureSkipVerify: true,
-		},
+		}, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	secret := &v1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{
+		Namespace: grafana.Namespace,
+		Name:      grafana.TLSSecretName(),
+	}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if ca, ok := secret.Data[v1.ServiceAccountRootCAKey]; ok && len(ca) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("secret %s/%s contains no valid ca certificate", secret.Namespace, secret.Name)
+		}
+		tlsConfig.RootCAs = pool
 	}
 
+	return tlsConfig, nil
+}
+
+func instanceName(grafana *v1beta1.Grafana) string {
+	return fmt.Sprintf("%s/%s", grafana.Namespace, grafana.Name)
+}
+
+func NewGrafanaClient(ctx context.Context, c client.Client, grafana *v1beta1.Grafana) (GrafanaClient, error) {
 	var timeoutSeconds time.Duration
 	if grafana.Spec.Client != nil && grafana.Spec.Client.TimeoutSeconds != nil {
 		timeoutSeconds = time.Duration(*grafana.Spec.Client.TimeoutSeconds)
@@ -63,13 +221,29 @@ func NewGrafanaClient(ctx context.Context, c client.Client, grafana *v1beta1.Gra
 		timeoutSeconds = 10
 	}
 
+	if grafana.IsCloudStack() {
+		return newCloudStackGrafanaClient(ctx, c, grafana, time.Second*timeoutSeconds)
+	}
+
+	if grafana.IsExternal() {
+		return newExternalGrafanaClient(ctx, c, grafana, time.Second*timeoutSeconds)
+	}
+
+	tlsConfig, err := getInstanceTLSConfig(ctx, c, grafana)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+
 	credentialSecret := model.GetGrafanaAdminSecret(grafana, nil)
 	selector := client.ObjectKey{
 		Namespace: credentialSecret.Namespace,
 		Name:      credentialSecret.Name,
 	}
 
-	err := c.Get(ctx, selector, credentialSecret)
+	err = c.Get(ctx, selector, credentialSecret)
 	if err != nil {
 		return nil, err
 	}
@@ -90,9 +264,11 @@ func NewGrafanaClient(ctx context.Context, c client.Client, grafana *v1beta1.Gra
 
 	return &GrafanaClientImpl{
 		url:        grafana.Status.AdminUrl,
+		instance:   instanceName(grafana),
 		username:   username,
 		password:   password,
 		kubeClient: c,
+		ctx:        ctx,
 		httpClient: &http.Client{
 			Transport: transport,
 			Timeout:   time.Second * timeoutSeconds,
@@ -100,6 +276,203 @@ func NewGrafanaClient(ctx context.Context, c client.Client, grafana *v1beta1.Gra
 	}, nil
 }
 
-func (r *GrafanaClientImpl) CreateOrUpdateDashboard(dashboard *v1beta1.GrafanaDashboard) error {
-	return nil
+// do sends a request to the Grafana api, body and response are encoded as json
+func (r *GrafanaClientImpl) do(method string, path string, body interface{}, response interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	ctx, span := tracing.StartClient(r.ctx, "grafana api "+method, "http.method", method, "url.path", path, "grafana.instance", r.instance)
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(r.url, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if traceparent := tracing.Traceparent(ctx); traceparent != "" {
+		req.Header.Set("traceparent", traceparent)
+	}
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	} else {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	if r.orgID > 0 {
+		req.Header.Set("X-Grafana-Org-Id", strconv.FormatInt(r.orgID, 10))
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := r.httpClient.Do(req)
+	metrics.GrafanaAPIRequestDuration.WithLabelValues(r.instance, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.GrafanaAPIRequests.WithLabelValues(r.instance, method, "error").Inc()
+		span.SetError(err)
+		audit.Record(ctx, r.instance, method, path, 0, err)
+		return err
+	}
+	defer resp.Body.Close()
+	metrics.GrafanaAPIRequests.WithLabelValues(r.instance, method, strconv.Itoa(resp.StatusCode)).Inc()
+	span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode))
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		audit.Record(ctx, r.instance, method, path, resp.StatusCode, err)
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &GrafanaApiError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(data)),
+		}
+		var msg GrafanaResponse
+		if json.Unmarshal(data, &msg) == nil && msg.Message != nil {
+			apiErr.Message = *msg.Message
+		}
+		audit.Record(ctx, r.instance, method, path, resp.StatusCode, apiErr)
+		return apiErr
+	}
+	audit.Record(ctx, r.instance, method, path, resp.StatusCode, nil)
+
+	if response == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, response)
+}
+
+// CreateOrUpdateDashboard imports a dashboard, dashboards that already have the normalized content in the
+// folder are left untouched so that no new version is added to their history. It returns the dashboard
+// that existed without the grafana-operator tag if it was adopted.
+func (r *GrafanaClientImpl) CreateOrUpdateDashboard(dashboard *v1beta1.GrafanaDashboard, folderUID string) (ApplyResult, *v1beta1.AdoptedResource, error) {
+	raw, err := NormalizeDashboard(dashboard)
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+
+	uid, err := dashboard.DashboardUID()
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+	existing := &grafanaDashboardWithMeta{}
+	err = r.do(http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(uid)), nil, existing)
+	if err != nil && !IsNotFound(err) {
+		return ApplyUnchanged, nil, err
+	}
+
+	result := ApplyCreated
+	var adopted *v1beta1.AdoptedResource
+	if err == nil {
+		result = ApplyUpdated
+		var model struct {
+			Version int64    `json:"version"`
+			Tags    []string `json:"tags"`
+		}
+		err = json.Unmarshal(existing.Dashboard, &model)
+		if err != nil {
+			return ApplyUnchanged, nil, err
+		}
+
+		if !isManaged(model.Tags) {
+			switch dashboard.Spec.AdoptionPolicy {
+			case v1beta1.AdoptionPolicyFail:
+				return ApplyUnchanged, nil, fmt.Errorf("dashboard %s already exists and is not managed by the operator", uid)
+			case v1beta1.AdoptionPolicyAdopt:
+				adopted = &v1beta1.AdoptedResource{
+					UID:     uid,
+					Version: model.Version,
+				}
+			}
+		} else if dashboard.ReconcileRequested() {
+			// requested resyncs import the dashboard even if it is unchanged
+		} else if dashboard.Spec.AllowUIUpdates && dashboard.Status.ContentHash == contentHash(raw) {
+			// changes made in the ui are kept until the content of the cr changes
+			return ApplyUnchanged, nil, nil
+		} else if existing.Meta.FolderUID == folderUID {
+			current, err := normalizeDashboardJson(existing.Dashboard, uid)
+			if err == nil && bytes.Equal(current, raw) {
+				return ApplyUnchanged, nil, nil
+			}
+		}
+	}
+
+	request := GrafanaRequest{
+		Dashboard: raw,
+		FolderUID: folderUID,
+		Overwrite: true,
+	}
+
+	err = r.do(http.MethodPost, "/api/dashboards/db", request, nil)
+	if err != nil {
+		return ApplyUnchanged, adopted, err
+	}
+	return result, adopted, nil
+}
+
+// DiffDashboard returns the change importing a dashboard would make and the changed fields of the normalized
+// json, without changing the dashboard in Grafana
+func (r *GrafanaClientImpl) DiffDashboard(dashboard *v1beta1.GrafanaDashboard, folderUID string) (ApplyResult, []string, error) {
+	raw, err := NormalizeDashboard(dashboard)
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+
+	uid, err := dashboard.DashboardUID()
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+	existing := &grafanaDashboardWithMeta{}
+	err = r.do(http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(uid)), nil, existing)
+	if IsNotFound(err) {
+		return ApplyCreated, nil, nil
+	}
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+
+	var model struct {
+		Tags []string `json:"tags"`
+	}
+	err = json.Unmarshal(existing.Dashboard, &model)
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+	if !isManaged(model.Tags) && dashboard.Spec.AdoptionPolicy == v1beta1.AdoptionPolicyFail {
+		return ApplyUnchanged, nil, fmt.Errorf("dashboard %s already exists and is not managed by the operator", uid)
+	}
+	if isManaged(model.Tags) && dashboard.Spec.AllowUIUpdates && dashboard.Status.ContentHash == contentHash(raw) && !dashboard.ReconcileRequested() {
+		return ApplyUnchanged, nil, nil
+	}
+
+	// the tag of the operator is not added to the current json, so that dashboards to adopt show it as change
+	var current map[string]interface{}
+	var desired interface{}
+	err = json.Unmarshal(existing.Dashboard, &current)
+	if err == nil {
+		err = json.Unmarshal(raw, &desired)
+	}
+	if err != nil {
+		return ApplyUnchanged, nil, err
+	}
+	for _, field := range volatileDashboardFields {
+		delete(current, field)
+	}
+
+	var changes []string
+	if existing.Meta.FolderUID != folderUID {
+		changes = append(changes, fmt.Sprintf("folderUid: %s -> %s", formatDiffValue(existing.Meta.FolderUID), formatDiffValue(folderUID)))
+	}
+	changes = append(changes, diffJson("", current, desired)...)
+	if len(changes) == 0 {
+		return ApplyUnchanged, nil, nil
+	}
+	return ApplyUpdated, changes, nil
 }
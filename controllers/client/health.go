@@ -0,0 +1,23 @@
+package client
+
+import (
+	"net/http"
+)
+
+// GrafanaHealth is the response of the health api
+type GrafanaHealth struct {
+	Commit   string `json:"commit"`
+	Database string `json:"database"`
+	Version  string `json:"version"`
+}
+
+// GetHealth returns the health of the instance, the api responds with an error while the database is not
+// reachable or migrations are running
+func (r *GrafanaClientImpl) GetHealth() (*GrafanaHealth, error) {
+	health := &GrafanaHealth{}
+	err := r.do(http.MethodGet, "/api/health", nil, health)
+	if err != nil {
+		return nil, err
+	}
+	return health, nil
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// libraryElementKindPanel is the kind of library elements holding panels
+	libraryElementKindPanel = 1
+)
+
+type grafanaLibraryElement struct {
+	UID       string          `json:"uid,omitempty"`
+	FolderUID string          `json:"folderUid,omitempty"`
+	Name      string          `json:"name"`
+	Model     json.RawMessage `json:"model"`
+	Kind      int             `json:"kind"`
+	Version   int64           `json:"version,omitempty"`
+}
+
+type grafanaLibraryElementResponse struct {
+	Result grafanaLibraryElement `json:"result"`
+}
+
+func (r *GrafanaClientImpl) CreateOrUpdateLibraryPanel(panel *v1beta1.GrafanaLibraryPanel, folderUID string) error {
+	var model map[string]interface{}
+	err := json.Unmarshal([]byte(panel.Spec.Json), &model)
+	if err != nil {
+		return err
+	}
+
+	// grafana keeps the identity of the panel outside of the model
+	delete(model, "id")
+	delete(model, "libraryPanel")
+
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+
+	uid := panel.LibraryPanelUID()
+	element := &grafanaLibraryElement{
+		UID:       uid,
+		FolderUID: folderUID,
+		Name:      panel.LibraryPanelName(),
+		Model:     raw,
+		Kind:      libraryElementKindPanel,
+	}
+
+	existing := &grafanaLibraryElementResponse{}
+	err = r.do(http.MethodGet, fmt.Sprintf("/api/library-elements/%s", url.PathEscape(uid)), nil, existing)
+	if IsNotFound(err) {
+		return r.do(http.MethodPost, "/api/library-elements", element, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	element.Version = existing.Result.Version
+	return r.do(http.MethodPatch, fmt.Sprintf("/api/library-elements/%s", url.PathEscape(uid)), element, nil)
+}
+
+func (r *GrafanaClientImpl) DeleteLibraryPanel(uid string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/library-elements/%s", url.PathEscape(uid)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+type grafanaTimeRange struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type grafanaTimeInterval struct {
+	Times       []grafanaTimeRange `json:"times,omitempty"`
+	Weekdays    []string           `json:"weekdays,omitempty"`
+	DaysOfMonth []string           `json:"days_of_month,omitempty"`
+	Months      []string           `json:"months,omitempty"`
+	Years       []string           `json:"years,omitempty"`
+	Location    string             `json:"location,omitempty"`
+}
+
+type grafanaMuteTiming struct {
+	Name          string                `json:"name"`
+	TimeIntervals []grafanaTimeInterval `json:"time_intervals"`
+}
+
+func (r *GrafanaClientImpl) CreateOrUpdateMuteTiming(muteTiming *v1beta1.GrafanaMuteTiming) error {
+	name := muteTiming.MuteTimingName()
+	path := fmt.Sprintf("/api/v1/provisioning/mute-timings/%s", url.PathEscape(name))
+
+	err := r.do(http.MethodGet, path, nil, &grafanaMuteTiming{})
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	body := toGrafanaMuteTiming(name, muteTiming.Spec.TimeIntervals)
+	if IsNotFound(err) {
+		return r.do(http.MethodPost, "/api/v1/provisioning/mute-timings", body, nil)
+	}
+	return r.do(http.MethodPut, path, body, nil)
+}
+
+func (r *GrafanaClientImpl) DeleteMuteTiming(name string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/v1/provisioning/mute-timings/%s", url.PathEscape(name)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func toGrafanaMuteTiming(name string, intervals []v1beta1.TimeInterval) *grafanaMuteTiming {
+	muteTiming := &grafanaMuteTiming{
+		Name:          name,
+		TimeIntervals: []grafanaTimeInterval{},
+	}
+	for _, interval := range intervals {
+		item := grafanaTimeInterval{
+			Weekdays:    interval.Weekdays,
+			DaysOfMonth: interval.DaysOfMonth,
+			Months:      interval.Months,
+			Years:       interval.Years,
+			Location:    interval.Location,
+		}
+		for _, times := range interval.Times {
+			item.Times = append(item.Times, grafanaTimeRange{
+				StartTime: times.StartTime,
+				EndTime:   times.EndTime,
+			})
+		}
+		muteTiming.TimeIntervals = append(muteTiming.TimeIntervals, item)
+	}
+	return muteTiming
+}
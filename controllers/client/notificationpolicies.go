@@ -0,0 +1,69 @@
+package client
+
+import (
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+)
+
+// GrafanaNotificationRoute is the provisioning api representation of a notification policy route
+type GrafanaNotificationRoute struct {
+	Receiver          string                      `json:"receiver,omitempty"`
+	GroupBy           []string                    `json:"group_by,omitempty"`
+	ObjectMatchers    [][3]string                 `json:"object_matchers,omitempty"`
+	MuteTimeIntervals []string                    `json:"mute_time_intervals,omitempty"`
+	Continue          bool                        `json:"continue,omitempty"`
+	GroupWait         string                      `json:"group_wait,omitempty"`
+	GroupInterval     string                      `json:"group_interval,omitempty"`
+	RepeatInterval    string                      `json:"repeat_interval,omitempty"`
+	Routes            []*GrafanaNotificationRoute `json:"routes,omitempty"`
+}
+
+func (r *GrafanaClientImpl) GetNotificationPolicy() (*GrafanaNotificationRoute, error) {
+	route := &GrafanaNotificationRoute{}
+	err := r.do(http.MethodGet, "/api/v1/provisioning/policies", nil, route)
+	if err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+func (r *GrafanaClientImpl) SetNotificationPolicy(route *GrafanaNotificationRoute) error {
+	return r.do(http.MethodPut, "/api/v1/provisioning/policies", route, nil)
+}
+
+// ApplySettings overwrites the grouping and timing settings of the route with all non empty settings
+func (in *GrafanaNotificationRoute) ApplySettings(settings v1beta1.NotificationPolicySettings) {
+	if settings.Receiver != "" {
+		in.Receiver = settings.Receiver
+	}
+	if len(settings.GroupBy) > 0 {
+		in.GroupBy = settings.GroupBy
+	}
+	if settings.GroupWait != "" {
+		in.GroupWait = settings.GroupWait
+	}
+	if settings.GroupInterval != "" {
+		in.GroupInterval = settings.GroupInterval
+	}
+	if settings.RepeatInterval != "" {
+		in.RepeatInterval = settings.RepeatInterval
+	}
+}
+
+// ToGrafanaRoute converts a route of a GrafanaNotificationPolicy to its api representation
+func ToGrafanaRoute(route v1beta1.NotificationPolicyRoute) *GrafanaNotificationRoute {
+	result := &GrafanaNotificationRoute{
+		MuteTimeIntervals: route.MuteTimeIntervals,
+		Continue:          route.Continue,
+	}
+	result.ApplySettings(route.NotificationPolicySettings)
+
+	for _, matcher := range route.ObjectMatchers {
+		result.ObjectMatchers = append(result.ObjectMatchers, [3]string{matcher.Name, string(matcher.Type), matcher.Value})
+	}
+
+	for _, child := range route.Routes {
+		result.Routes = append(result.Routes, ToGrafanaRoute(child))
+	}
+	return result
+}
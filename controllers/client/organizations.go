@@ -0,0 +1,42 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// CreateOrg creates an organization unless one with the same name already exists
+func (r *GrafanaClientImpl) CreateOrg(name string) error {
+	_, err := r.GetOrgByName(name)
+	if !IsNotFound(err) {
+		return err
+	}
+	return r.do(http.MethodPost, "/api/orgs", &GrafanaOrg{Name: name}, nil)
+}
+
+func (r *GrafanaClientImpl) DeleteOrg(name string) error {
+	org, err := r.GetOrgByName(name)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if org.ID == mainOrgID {
+		return fmt.Errorf("the main organization can not be deleted")
+	}
+
+	err = r.do(http.MethodDelete, fmt.Sprintf("/api/orgs/%d", org.ID), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// InOrg returns a client that sends all requests in the context of the given organization
+func (r *GrafanaClientImpl) InOrg(orgID int64) GrafanaClient {
+	orgClient := *r
+	orgClient.orgID = orgID
+	return &orgClient
+}
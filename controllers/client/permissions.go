@@ -0,0 +1,210 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+type grafanaPermissionItem struct {
+	Role       string `json:"role,omitempty"`
+	TeamId     *int64 `json:"teamId,omitempty"`
+	UserId     *int64 `json:"userId,omitempty"`
+	Permission int    `json:"permission"`
+	Inherited  bool   `json:"inherited,omitempty"`
+}
+
+type grafanaPermissions struct {
+	Items []grafanaPermissionItem `json:"items"`
+}
+
+// SyncDashboardPermissions replaces the permissions of a dashboard if they differ from the desired permissions
+func (r *GrafanaClientImpl) SyncDashboardPermissions(uid string, items []v1beta1.GrafanaPermissionItem) error {
+	return r.syncPermissions(fmt.Sprintf("/api/dashboards/uid/%s/permissions", url.PathEscape(uid)), items)
+}
+
+// SyncFolderPermissions replaces the permissions of a folder if they differ from the desired permissions
+func (r *GrafanaClientImpl) SyncFolderPermissions(uid string, items []v1beta1.GrafanaPermissionItem) error {
+	return r.syncPermissions(fmt.Sprintf("/api/folders/%s/permissions", url.PathEscape(uid)), items)
+}
+
+// syncPermissions compares the permissions set directly on a resource with the desired permissions. The api
+// replaces all permissions at once, so a partially applied permission set is never visible.
+func (r *GrafanaClientImpl) syncPermissions(path string, items []v1beta1.GrafanaPermissionItem) error {
+	var current []grafanaPermissionItem
+	err := r.do(http.MethodGet, path, nil, &current)
+	if err != nil {
+		return err
+	}
+
+	desired := toGrafanaPermissions(items)
+
+	var direct []grafanaPermissionItem
+	for _, item := range current {
+		if !item.Inherited {
+			direct = append(direct, item)
+		}
+	}
+
+	if permissionsEqual(direct, desired.Items) {
+		return nil
+	}
+	return r.do(http.MethodPost, path, desired, nil)
+}
+
+func toGrafanaPermissions(items []v1beta1.GrafanaPermissionItem) *grafanaPermissions {
+	permissions := &grafanaPermissions{
+		Items: []grafanaPermissionItem{},
+	}
+	for _, item := range items {
+		permissions.Items = append(permissions.Items, grafanaPermissionItem{
+			Role:       item.Role,
+			TeamId:     item.TeamId,
+			UserId:     item.UserId,
+			Permission: permissionLevel(item.Permission),
+		})
+	}
+	return permissions
+}
+
+// permissionLevel maps a permission to the numeric level used by the Grafana api
+func permissionLevel(permission v1beta1.GrafanaPermission) int {
+	switch permission {
+	case v1beta1.GrafanaPermissionAdmin:
+		return 4
+	case v1beta1.GrafanaPermissionEdit:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func permissionsEqual(a []grafanaPermissionItem, b []grafanaPermissionItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	keysA := permissionKeys(a)
+	keysB := permissionKeys(b)
+	for i := range keysA {
+		if keysA[i] != keysB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// permissionKeys returns a sorted representation of the permissions, the api returns 0 for unset ids
+func permissionKeys(items []grafanaPermissionItem) []string {
+	var keys []string
+	for _, item := range items {
+		var teamId, userId int64
+		if item.TeamId != nil {
+			teamId = *item.TeamId
+		}
+		if item.UserId != nil {
+			userId = *item.UserId
+		}
+		keys = append(keys, fmt.Sprintf("%s/%d/%d/%d", item.Role, teamId, userId, item.Permission))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type grafanaDatasourcePermission struct {
+	ID         int64 `json:"id,omitempty"`
+	TeamId     int64 `json:"teamId,omitempty"`
+	UserId     int64 `json:"userId,omitempty"`
+	Permission int   `json:"permission"`
+}
+
+type grafanaDatasourcePermissions struct {
+	Enabled     bool                          `json:"enabled"`
+	Permissions []grafanaDatasourcePermission `json:"permissions"`
+}
+
+// datasourcePermissionLevel maps a datasource permission to the numeric level used by the Grafana api
+func datasourcePermissionLevel(permission v1beta1.DatasourcePermission) int {
+	if permission == v1beta1.DatasourcePermissionEdit {
+		return 2
+	}
+	return 1
+}
+
+// SyncDatasourcePermissions enables permissions on a datasource and converges its access list, entries not
+// in the desired list are removed. Teams and users that don't exist in Grafana are returned as unresolved.
+func (r *GrafanaClientImpl) SyncDatasourcePermissions(uid string, items []v1beta1.GrafanaDatasourcePermissionItem) ([]string, error) {
+	datasource, err := r.GetDatasource(uid)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/datasources/%d/permissions", datasource.ID)
+
+	desired := map[grafanaDatasourcePermission]bool{}
+	var unresolved []string
+	for _, item := range items {
+		permission := grafanaDatasourcePermission{Permission: datasourcePermissionLevel(item.Permission)}
+		if item.Team != "" {
+			team, err := r.GetTeamByName(item.Team)
+			if err != nil {
+				return nil, err
+			}
+			if team == nil {
+				unresolved = append(unresolved, fmt.Sprintf("team %s", item.Team))
+				continue
+			}
+			permission.TeamId = team.ID
+		} else if item.User != "" {
+			user, err := r.LookupUser(item.User)
+			if IsNotFound(err) {
+				unresolved = append(unresolved, fmt.Sprintf("user %s", item.User))
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			permission.UserId = user.ID
+		} else {
+			return nil, fmt.Errorf("datasource permissions need either a team or a user")
+		}
+		desired[permission] = true
+	}
+
+	current := &grafanaDatasourcePermissions{}
+	err = r.do(http.MethodGet, path, nil, current)
+	if err != nil {
+		return unresolved, err
+	}
+
+	if !current.Enabled {
+		err = r.do(http.MethodPost, fmt.Sprintf("/api/datasources/%d/enable-permissions", datasource.ID), nil, nil)
+		if err != nil {
+			return unresolved, err
+		}
+	}
+
+	for _, permission := range current.Permissions {
+		key := grafanaDatasourcePermission{
+			TeamId:     permission.TeamId,
+			UserId:     permission.UserId,
+			Permission: permission.Permission,
+		}
+		if desired[key] {
+			delete(desired, key)
+			continue
+		}
+		err = r.do(http.MethodDelete, fmt.Sprintf("%s/%d", path, permission.ID), nil, nil)
+		if err != nil && !IsNotFound(err) {
+			return unresolved, err
+		}
+	}
+
+	for permission := range desired {
+		err = r.do(http.MethodPost, path, permission, nil)
+		if err != nil {
+			return unresolved, err
+		}
+	}
+	return unresolved, nil
+}
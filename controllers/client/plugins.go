@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+type grafanaPluginInfo struct {
+	ID   string `json:"id"`
+	Info struct {
+		Version string `json:"version"`
+	} `json:"info"`
+}
+
+type grafanaPluginInstall struct {
+	Version string `json:"version,omitempty"`
+}
+
+// GetInstalledPlugins returns the versions of all plugins installed in the instance, keyed by plugin id
+func (r *GrafanaClientImpl) GetInstalledPlugins() (map[string]string, error) {
+	var plugins []grafanaPluginInfo
+	err := r.do(http.MethodGet, "/api/plugins?embedded=0&core=0", nil, &plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string]string{}
+	for _, plugin := range plugins {
+		versions[plugin.ID] = plugin.Info.Version
+	}
+	return versions, nil
+}
+
+// InstallPlugin installs a plugin through the plugin catalog api, a different version of the plugin is
+// uninstalled first
+func (r *GrafanaClientImpl) InstallPlugin(plugin v1beta1.GrafanaPlugin, installedVersion string) error {
+	path := fmt.Sprintf("/api/plugins/%s", url.PathEscape(plugin.Name))
+
+	if installedVersion != "" {
+		err := r.do(http.MethodPost, path+"/uninstall", nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.do(http.MethodPost, path+"/install", &grafanaPluginInstall{
+		Version: plugin.Version,
+	}, nil)
+}
+
+// UninstallPlugin removes a plugin installed through the plugin catalog api
+func (r *GrafanaClientImpl) UninstallPlugin(name string) error {
+	return r.do(http.MethodPost, fmt.Sprintf("/api/plugins/%s/uninstall", url.PathEscape(name)), nil, nil)
+}
+
+// IsPluginCatalogEnabled returns true if plugins can be installed through the api, which requires
+// plugin_admin_enabled
+func (r *GrafanaClientImpl) IsPluginCatalogEnabled() (bool, error) {
+	settings := &grafanaFrontendSettings{}
+	err := r.do(http.MethodGet, "/api/frontend/settings", nil, settings)
+	if err != nil {
+		return false, err
+	}
+	return settings.PluginAdminEnabled, nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"net/http"
+)
+
+// GrafanaPreferences are the preferences of an organization
+type GrafanaPreferences struct {
+	HomeDashboardUID string `json:"homeDashboardUID,omitempty"`
+	Theme            string `json:"theme"`
+	Timezone         string `json:"timezone"`
+	WeekStart        string `json:"weekStart"`
+}
+
+func (r *GrafanaClientImpl) GetOrgPreferences() (*GrafanaPreferences, error) {
+	preferences := &GrafanaPreferences{}
+	err := r.do(http.MethodGet, "/api/org/preferences", nil, preferences)
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// SetOrgPreferences replaces the preferences of the organization, empty values reset a preference to its
+// default
+func (r *GrafanaClientImpl) SetOrgPreferences(preferences *GrafanaPreferences) error {
+	existing, err := r.GetOrgPreferences()
+	if err != nil {
+		return err
+	}
+
+	if *existing == *preferences {
+		return nil
+	}
+	return r.do(http.MethodPut, "/api/org/preferences", preferences, nil)
+}
+
+// SetOrgHomeDashboard sets the home dashboard of the organization and keeps the other preferences
+func (r *GrafanaClientImpl) SetOrgHomeDashboard(uid string) error {
+	existing, err := r.GetOrgPreferences()
+	if err != nil {
+		return err
+	}
+
+	if existing.HomeDashboardUID == uid {
+		return nil
+	}
+	existing.HomeDashboardUID = uid
+	return r.do(http.MethodPut, "/api/org/preferences", existing, nil)
+}
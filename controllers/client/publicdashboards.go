@@ -0,0 +1,77 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type grafanaPublicDashboard struct {
+	UID                  string `json:"uid,omitempty"`
+	AccessToken          string `json:"accessToken,omitempty"`
+	IsEnabled            bool   `json:"isEnabled"`
+	TimeSelectionEnabled bool   `json:"timeSelectionEnabled"`
+	AnnotationsEnabled   bool   `json:"annotationsEnabled"`
+	Share                string `json:"share,omitempty"`
+}
+
+func (r *GrafanaClientImpl) getPublicDashboard(dashboardUID string) (*grafanaPublicDashboard, error) {
+	existing := &grafanaPublicDashboard{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards", url.PathEscape(dashboardUID)), nil, existing)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// CreateOrUpdatePublicDashboard configures public sharing of a dashboard and returns its public url
+func (r *GrafanaClientImpl) CreateOrUpdatePublicDashboard(dashboardUID string, publicDashboard *v1beta1.GrafanaPublicDashboard) (string, error) {
+	existing, err := r.getPublicDashboard(dashboardUID)
+	if err != nil {
+		return "", err
+	}
+
+	desired := &grafanaPublicDashboard{
+		IsEnabled:            publicDashboard.SharingEnabled(),
+		TimeSelectionEnabled: publicDashboard.Spec.TimeSelectionEnabled,
+		AnnotationsEnabled:   publicDashboard.Spec.AnnotationsEnabled,
+		Share:                "public",
+	}
+
+	result := &grafanaPublicDashboard{}
+	if existing == nil || existing.UID == "" {
+		err = r.do(http.MethodPost, fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards", url.PathEscape(dashboardUID)), desired, result)
+	} else if existing.IsEnabled != desired.IsEnabled ||
+		existing.TimeSelectionEnabled != desired.TimeSelectionEnabled ||
+		existing.AnnotationsEnabled != desired.AnnotationsEnabled {
+		err = r.do(http.MethodPatch, fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards/%s", url.PathEscape(dashboardUID), url.PathEscape(existing.UID)), desired, result)
+	} else {
+		result = existing
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if result.AccessToken == "" {
+		result.AccessToken = existing.AccessToken
+	}
+	return fmt.Sprintf("%s/public-dashboards/%s", strings.TrimSuffix(r.url, "/"), result.AccessToken), nil
+}
+
+func (r *GrafanaClientImpl) DeletePublicDashboard(dashboardUID string) error {
+	existing, err := r.getPublicDashboard(dashboardUID)
+	if err != nil || existing == nil || existing.UID == "" {
+		return err
+	}
+
+	err = r.do(http.MethodDelete, fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards/%s", url.PathEscape(dashboardUID), url.PathEscape(existing.UID)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,550 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// restorePlan collects the changes of a restore and applies them unless running dry
+type restorePlan struct {
+	client  *GrafanaClientImpl
+	dryRun  bool
+	changes []string
+}
+
+func (p *restorePlan) apply(change string, method string, path string, body interface{}) error {
+	p.changes = append(p.changes, change)
+	if p.dryRun {
+		return nil
+	}
+	err := p.client.do(method, path, body, nil)
+	if err != nil {
+		return fmt.Errorf("error during %s: %w", change, err)
+	}
+	return nil
+}
+
+// RestoreBackup replays the files of a backup archive into the organization and returns the changes made.
+// Resources of the backup are created or updated, in replace mode resources missing from the backup are
+// deleted as well. Datasource secrets are not part of a backup and have to be set again after a restore.
+func (r *GrafanaClientImpl) RestoreBackup(files map[string][]byte, replace bool, dryRun bool) ([]string, error) {
+	plan := &restorePlan{
+		client: r,
+		dryRun: dryRun,
+	}
+
+	steps := []func(*restorePlan, map[string][]byte, bool) error{
+		restoreFolders,
+		restoreDatasources,
+		restoreDashboards,
+		restoreMuteTimings,
+		restoreContactPoints,
+		restorePolicies,
+		restoreRules,
+	}
+
+	for _, step := range steps {
+		err := step(plan, files, replace)
+		if err != nil {
+			return plan.changes, err
+		}
+	}
+
+	if !replace {
+		return plan.changes, nil
+	}
+
+	// the policies reference contact points and mute timings, they can only be removed once the policies
+	// are restored. folders are removed last, deleting a folder also deletes the dashboards and rules it
+	// contains
+	for _, step := range []func(*restorePlan, map[string][]byte) error{deleteContactPoints, deleteMuteTimings, deleteFolders} {
+		err := step(plan, files)
+		if err != nil {
+			return plan.changes, err
+		}
+	}
+
+	return plan.changes, nil
+}
+
+// readBackupFile unmarshals a file of the backup, returning false if the backup doesn't contain it
+func readBackupFile(files map[string][]byte, name string, v interface{}) (bool, error) {
+	content, ok := files[name]
+	if !ok {
+		return false, nil
+	}
+	err := json.Unmarshal(content, v)
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// jsonEqual compares the json representation of two values
+func jsonEqual(a interface{}, b interface{}) bool {
+	var normalized [2]interface{}
+	for i, v := range []interface{}{a, b} {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return false
+		}
+		if json.Unmarshal(raw, &normalized[i]) != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(normalized[0], normalized[1])
+}
+
+// withoutKeys returns a copy of an object without the given keys
+func withoutKeys(object map[string]interface{}, keys ...string) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range object {
+		result[key] = value
+	}
+	for _, key := range keys {
+		delete(result, key)
+	}
+	return result
+}
+
+func restoreFolders(plan *restorePlan, files map[string][]byte, replace bool) error {
+	var folders []GrafanaFolder
+	ok, err := readBackupFile(files, "folders.json", &folders)
+	if err != nil || !ok {
+		return err
+	}
+
+	var existing []GrafanaFolder
+	err = plan.client.do(http.MethodGet, "/api/folders", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	titles := map[string]string{}
+	for _, folder := range existing {
+		titles[folder.UID] = folder.Title
+	}
+
+	for _, folder := range folders {
+		title, found := titles[folder.UID]
+		if !found {
+			err = plan.apply(fmt.Sprintf("create folder %s", folder.UID), http.MethodPost, "/api/folders", &GrafanaFolder{
+				UID:   folder.UID,
+				Title: folder.Title,
+			})
+		} else if title != folder.Title {
+			err = plan.apply(fmt.Sprintf("update folder %s", folder.UID), http.MethodPut, fmt.Sprintf("/api/folders/%s", url.PathEscape(folder.UID)), &grafanaFolderUpdate{
+				Title:     folder.Title,
+				Overwrite: true,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteFolders(plan *restorePlan, files map[string][]byte) error {
+	var folders []GrafanaFolder
+	ok, err := readBackupFile(files, "folders.json", &folders)
+	if err != nil || !ok {
+		return err
+	}
+
+	var existing []GrafanaFolder
+	err = plan.client.do(http.MethodGet, "/api/folders", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	keep := map[string]bool{}
+	for _, folder := range folders {
+		keep[folder.UID] = true
+	}
+
+	for _, folder := range existing {
+		if keep[folder.UID] {
+			continue
+		}
+		err = plan.apply(fmt.Sprintf("delete folder %s", folder.UID), http.MethodDelete, fmt.Sprintf("/api/folders/%s", url.PathEscape(folder.UID)), nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreDatasources(plan *restorePlan, files map[string][]byte, replace bool) error {
+	var datasources []map[string]interface{}
+	ok, err := readBackupFile(files, "datasources.json", &datasources)
+	if err != nil || !ok {
+		return err
+	}
+
+	var existing []map[string]interface{}
+	err = plan.client.do(http.MethodGet, "/api/datasources", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	// ids and versions are local to an instance and not compared
+	ignored := []string{"id", "orgId", "version", "readOnly", "typeLogoUrl"}
+
+	current := map[string]map[string]interface{}{}
+	for _, datasource := range existing {
+		uid, _ := datasource["uid"].(string)
+		current[uid] = datasource
+	}
+
+	keep := map[string]bool{}
+	for _, datasource := range datasources {
+		uid, _ := datasource["uid"].(string)
+		keep[uid] = true
+		body := withoutKeys(datasource, ignored...)
+
+		found, ok := current[uid]
+		if !ok {
+			err = plan.apply(fmt.Sprintf("create datasource %s", uid), http.MethodPost, "/api/datasources", body)
+		} else if !jsonEqual(body, withoutKeys(found, ignored...)) {
+			body["id"] = found["id"]
+			err = plan.apply(fmt.Sprintf("update datasource %s", uid), http.MethodPut, fmt.Sprintf("/api/datasources/%v", found["id"]), body)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !replace {
+		return nil
+	}
+
+	for _, datasource := range existing {
+		uid, _ := datasource["uid"].(string)
+		if keep[uid] {
+			continue
+		}
+		err = plan.apply(fmt.Sprintf("delete datasource %s", uid), http.MethodDelete, fmt.Sprintf("/api/datasources/uid/%s", url.PathEscape(uid)), nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreDashboards(plan *restorePlan, files map[string][]byte, replace bool) error {
+	var dashboards []grafanaSearchHit
+	ok, err := readBackupFile(files, "dashboards.json", &dashboards)
+	if err != nil {
+		return err
+	}
+
+	// backups without an index place all dashboards in the general folder
+	if !ok {
+		for name := range files {
+			if strings.HasPrefix(name, "dashboards/") && strings.HasSuffix(name, ".json") {
+				dashboards = append(dashboards, grafanaSearchHit{
+					UID: strings.TrimSuffix(strings.TrimPrefix(name, "dashboards/"), ".json"),
+				})
+			}
+		}
+		sort.Slice(dashboards, func(i, j int) bool {
+			return dashboards[i].UID < dashboards[j].UID
+		})
+	}
+
+	var existing []grafanaSearchHit
+	err = plan.client.do(http.MethodGet, "/api/search?type=dash-db&limit=5000", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	folders := map[string]string{}
+	for _, dashboard := range existing {
+		folders[dashboard.UID] = dashboard.FolderUID
+	}
+
+	keep := map[string]bool{}
+	for _, dashboard := range dashboards {
+		keep[dashboard.UID] = true
+
+		var model map[string]interface{}
+		ok, err = readBackupFile(files, fmt.Sprintf("dashboards/%s.json", dashboard.UID), &model)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		model = withoutKeys(model, "id", "version")
+
+		change := fmt.Sprintf("create dashboard %s", dashboard.UID)
+		if folderUID, found := folders[dashboard.UID]; found {
+			current, err := plan.client.GetDashboardJson(dashboard.UID)
+			if err != nil {
+				return err
+			}
+			var currentModel map[string]interface{}
+			err = json.Unmarshal(current, &currentModel)
+			if err != nil {
+				return err
+			}
+			if folderUID == dashboard.FolderUID && jsonEqual(model, withoutKeys(currentModel, "id", "version")) {
+				continue
+			}
+			change = fmt.Sprintf("update dashboard %s", dashboard.UID)
+		}
+
+		raw, err := json.Marshal(model)
+		if err != nil {
+			return err
+		}
+		err = plan.apply(change, http.MethodPost, "/api/dashboards/db", &GrafanaRequest{
+			Dashboard: raw,
+			FolderUID: dashboard.FolderUID,
+			Overwrite: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !replace {
+		return nil
+	}
+
+	for _, dashboard := range existing {
+		if keep[dashboard.UID] {
+			continue
+		}
+		err = plan.apply(fmt.Sprintf("delete dashboard %s", dashboard.UID), http.MethodDelete, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(dashboard.UID)), nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreMuteTimings(plan *restorePlan, files map[string][]byte, replace bool) error {
+	var muteTimings []grafanaMuteTiming
+	ok, err := readBackupFile(files, "alerting/mute-timings.json", &muteTimings)
+	if err != nil || !ok {
+		return err
+	}
+
+	var existing []grafanaMuteTiming
+	err = plan.client.do(http.MethodGet, "/api/v1/provisioning/mute-timings", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	current := map[string]grafanaMuteTiming{}
+	for _, muteTiming := range existing {
+		current[muteTiming.Name] = muteTiming
+	}
+
+	for i := range muteTimings {
+		muteTiming := &muteTimings[i]
+
+		found, ok := current[muteTiming.Name]
+		if !ok {
+			err = plan.apply(fmt.Sprintf("create mute timing %s", muteTiming.Name), http.MethodPost, "/api/v1/provisioning/mute-timings", muteTiming)
+		} else if !jsonEqual(muteTiming, found) {
+			err = plan.apply(fmt.Sprintf("update mute timing %s", muteTiming.Name), http.MethodPut, fmt.Sprintf("/api/v1/provisioning/mute-timings/%s", url.PathEscape(muteTiming.Name)), muteTiming)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteMuteTimings(plan *restorePlan, files map[string][]byte) error {
+	var muteTimings []grafanaMuteTiming
+	ok, err := readBackupFile(files, "alerting/mute-timings.json", &muteTimings)
+	if err != nil || !ok {
+		return err
+	}
+
+	var existing []grafanaMuteTiming
+	err = plan.client.do(http.MethodGet, "/api/v1/provisioning/mute-timings", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	keep := map[string]bool{}
+	for _, muteTiming := range muteTimings {
+		keep[muteTiming.Name] = true
+	}
+
+	for _, muteTiming := range existing {
+		if keep[muteTiming.Name] {
+			continue
+		}
+		err = plan.apply(fmt.Sprintf("delete mute timing %s", muteTiming.Name), http.MethodDelete, fmt.Sprintf("/api/v1/provisioning/mute-timings/%s", url.PathEscape(muteTiming.Name)), nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreContactPoints(plan *restorePlan, files map[string][]byte, replace bool) error {
+	var contactPoints []GrafanaContactPoint
+	ok, err := readBackupFile(files, "alerting/contact-points.json", &contactPoints)
+	if err != nil || !ok {
+		return err
+	}
+
+	existing, err := plan.client.GetContactPoints()
+	if err != nil {
+		return err
+	}
+
+	current := map[string]GrafanaContactPoint{}
+	for _, contactPoint := range existing {
+		current[contactPoint.UID] = contactPoint
+	}
+
+	for i := range contactPoints {
+		contactPoint := &contactPoints[i]
+
+		found, ok := current[contactPoint.UID]
+		if !ok {
+			err = plan.apply(fmt.Sprintf("create contact point %s", contactPoint.UID), http.MethodPost, "/api/v1/provisioning/contact-points", contactPoint)
+		} else if !jsonEqual(contactPoint, found) {
+			err = plan.apply(fmt.Sprintf("update contact point %s", contactPoint.UID), http.MethodPut, fmt.Sprintf("/api/v1/provisioning/contact-points/%s", url.PathEscape(contactPoint.UID)), contactPoint)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteContactPoints(plan *restorePlan, files map[string][]byte) error {
+	var contactPoints []GrafanaContactPoint
+	ok, err := readBackupFile(files, "alerting/contact-points.json", &contactPoints)
+	if err != nil || !ok {
+		return err
+	}
+
+	existing, err := plan.client.GetContactPoints()
+	if err != nil {
+		return err
+	}
+
+	keep := map[string]bool{}
+	for _, contactPoint := range contactPoints {
+		keep[contactPoint.UID] = true
+	}
+
+	for _, contactPoint := range existing {
+		if keep[contactPoint.UID] {
+			continue
+		}
+		err = plan.apply(fmt.Sprintf("delete contact point %s", contactPoint.UID), http.MethodDelete, fmt.Sprintf("/api/v1/provisioning/contact-points/%s", url.PathEscape(contactPoint.UID)), nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restorePolicies(plan *restorePlan, files map[string][]byte, replace bool) error {
+	var policies json.RawMessage
+	ok, err := readBackupFile(files, "alerting/policies.json", &policies)
+	if err != nil || !ok {
+		return err
+	}
+
+	var existing json.RawMessage
+	err = plan.client.do(http.MethodGet, "/api/v1/provisioning/policies", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	if jsonEqual(policies, existing) {
+		return nil
+	}
+	return plan.apply("update notification policies", http.MethodPut, "/api/v1/provisioning/policies", policies)
+}
+
+// restoreRules restores the rule groups of the ruler api, which are keyed by the title of their folder
+func restoreRules(plan *restorePlan, files map[string][]byte, replace bool) error {
+	var rules map[string][]map[string]interface{}
+	ok, err := readBackupFile(files, "alerting/rules.json", &rules)
+	if err != nil || !ok {
+		return err
+	}
+
+	var existing map[string][]map[string]interface{}
+	err = plan.client.do(http.MethodGet, "/api/ruler/grafana/api/v1/rules", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	current := map[string]map[string]interface{}{}
+	for namespace, groups := range existing {
+		for _, group := range groups {
+			current[fmt.Sprintf("%s/%v", namespace, group["name"])] = group
+		}
+	}
+
+	keep := map[string]bool{}
+	for _, namespace := range sortedKeys(rules) {
+		for _, group := range rules[namespace] {
+			key := fmt.Sprintf("%s/%v", namespace, group["name"])
+			keep[key] = true
+
+			found, ok := current[key]
+			change := fmt.Sprintf("create rule group %s", key)
+			if ok {
+				if jsonEqual(group, found) {
+					continue
+				}
+				change = fmt.Sprintf("update rule group %s", key)
+			}
+
+			err = plan.apply(change, http.MethodPost, fmt.Sprintf("/api/ruler/grafana/api/v1/rules/%s", url.PathEscape(namespace)), group)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !replace {
+		return nil
+	}
+
+	for _, namespace := range sortedKeys(existing) {
+		for _, group := range existing[namespace] {
+			name := fmt.Sprintf("%v", group["name"])
+			key := fmt.Sprintf("%s/%s", namespace, name)
+			if keep[key] {
+				continue
+			}
+			err = plan.apply(fmt.Sprintf("delete rule group %s", key), http.MethodDelete, fmt.Sprintf("/api/ruler/grafana/api/v1/rules/%s/%s", url.PathEscape(namespace), url.PathEscape(name)), nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string][]map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
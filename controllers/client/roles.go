@@ -0,0 +1,216 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+const grafanaEditionEnterprise = "Enterprise"
+
+type grafanaFrontendSettings struct {
+	BuildInfo struct {
+		Edition string `json:"edition"`
+	} `json:"buildInfo"`
+	PluginAdminEnabled bool `json:"pluginAdminEnabled"`
+}
+
+type grafanaRolePermission struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope,omitempty"`
+}
+
+type grafanaRole struct {
+	UID         string                  `json:"uid"`
+	Name        string                  `json:"name"`
+	DisplayName string                  `json:"displayName,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Group       string                  `json:"group,omitempty"`
+	Version     int64                   `json:"version"`
+	Permissions []grafanaRolePermission `json:"permissions"`
+}
+
+type grafanaRoleAssignment struct {
+	RoleUID string `json:"roleUid"`
+}
+
+// IsEnterprise returns true if the instance runs Grafana Enterprise, which is required for fine-grained
+// access control
+func (r *GrafanaClientImpl) IsEnterprise() (bool, error) {
+	settings := &grafanaFrontendSettings{}
+	err := r.do(http.MethodGet, "/api/frontend/settings", nil, settings)
+	if err != nil {
+		return false, err
+	}
+	return settings.BuildInfo.Edition == grafanaEditionEnterprise, nil
+}
+
+func sortRolePermissions(permissions []grafanaRolePermission) {
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].Action != permissions[j].Action {
+			return permissions[i].Action < permissions[j].Action
+		}
+		return permissions[i].Scope < permissions[j].Scope
+	})
+}
+
+func rolePermissionsEqual(a []grafanaRolePermission, b []grafanaRolePermission) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortRolePermissions(a)
+	sortRolePermissions(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *GrafanaClientImpl) CreateOrUpdateRole(role *v1beta1.GrafanaRole) error {
+	uid := role.RoleUID()
+
+	desired := &grafanaRole{
+		UID:         uid,
+		Name:        role.RoleName(),
+		DisplayName: role.Spec.DisplayName,
+		Description: role.Spec.Description,
+		Group:       role.Spec.Group,
+		Version:     1,
+		Permissions: []grafanaRolePermission{},
+	}
+	for _, permission := range role.Spec.Permissions {
+		desired.Permissions = append(desired.Permissions, grafanaRolePermission{
+			Action: permission.Action,
+			Scope:  permission.Scope,
+		})
+	}
+
+	existing := &grafanaRole{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/access-control/roles/%s", url.PathEscape(uid)), nil, existing)
+	if IsNotFound(err) {
+		return r.do(http.MethodPost, "/api/access-control/roles", desired, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Name == desired.Name &&
+		existing.DisplayName == desired.DisplayName &&
+		existing.Description == desired.Description &&
+		existing.Group == desired.Group &&
+		rolePermissionsEqual(existing.Permissions, desired.Permissions) {
+		return nil
+	}
+
+	// grafana only accepts updates with an increased version
+	desired.Version = existing.Version + 1
+	return r.do(http.MethodPut, fmt.Sprintf("/api/access-control/roles/%s", url.PathEscape(uid)), desired, nil)
+}
+
+func (r *GrafanaClientImpl) DeleteRole(uid string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/access-control/roles/%s?force=true", url.PathEscape(uid)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// roleAssignmentPaths resolves the subjects to the api paths listing their role assignments, subjects
+// that don't exist in Grafana are returned as unresolved
+func (r *GrafanaClientImpl) roleAssignmentPaths(subjects v1beta1.GrafanaRoleSubjects) ([]string, []string, error) {
+	var paths []string
+	var unresolved []string
+
+	for _, login := range subjects.Users {
+		user, err := r.LookupUser(login)
+		if IsNotFound(err) {
+			unresolved = append(unresolved, fmt.Sprintf("user %s", login))
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		paths = append(paths, fmt.Sprintf("/api/access-control/users/%d/roles", user.ID))
+	}
+
+	for _, name := range subjects.Teams {
+		team, err := r.GetTeamByName(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if team == nil {
+			unresolved = append(unresolved, fmt.Sprintf("team %s", name))
+			continue
+		}
+		paths = append(paths, fmt.Sprintf("/api/access-control/teams/%d/roles", team.ID))
+	}
+
+	// service accounts are users as far as access control is concerned
+	for _, name := range subjects.ServiceAccounts {
+		serviceAccount, err := r.getServiceAccountByName(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if serviceAccount == nil {
+			unresolved = append(unresolved, fmt.Sprintf("service account %s", name))
+			continue
+		}
+		paths = append(paths, fmt.Sprintf("/api/access-control/users/%d/roles", serviceAccount.ID))
+	}
+
+	return paths, unresolved, nil
+}
+
+// AssignRole assigns the role to all subjects that don't have it yet and returns the subjects that could
+// not be found
+func (r *GrafanaClientImpl) AssignRole(roleUID string, subjects v1beta1.GrafanaRoleSubjects) ([]string, error) {
+	paths, unresolved, err := r.roleAssignmentPaths(subjects)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		var assigned []grafanaRole
+		err = r.do(http.MethodGet, path, nil, &assigned)
+		if err != nil {
+			return unresolved, err
+		}
+
+		found := false
+		for _, role := range assigned {
+			if role.UID == roleUID {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		err = r.do(http.MethodPost, path, &grafanaRoleAssignment{RoleUID: roleUID}, nil)
+		if err != nil {
+			return unresolved, err
+		}
+	}
+	return unresolved, nil
+}
+
+// UnassignRole removes the role from the subjects, subjects that don't exist anymore are ignored
+func (r *GrafanaClientImpl) UnassignRole(roleUID string, subjects v1beta1.GrafanaRoleSubjects) error {
+	paths, _, err := r.roleAssignmentPaths(subjects)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		err = r.do(http.MethodDelete, fmt.Sprintf("%s/%s", path, url.PathEscape(roleUID)), nil, nil)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
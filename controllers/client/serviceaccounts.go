@@ -0,0 +1,101 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+type grafanaServiceAccount struct {
+	ID         int64  `json:"id,omitempty"`
+	Name       string `json:"name"`
+	Role       string `json:"role,omitempty"`
+	IsDisabled bool   `json:"isDisabled"`
+}
+
+type grafanaServiceAccountSearch struct {
+	ServiceAccounts []grafanaServiceAccount `json:"serviceAccounts"`
+}
+
+type grafanaTokenCreate struct {
+	Name string `json:"name"`
+}
+
+// GrafanaServiceAccountToken is a newly minted token, the key is only returned on creation
+type GrafanaServiceAccountToken struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+func (r *GrafanaClientImpl) getServiceAccountByName(name string) (*grafanaServiceAccount, error) {
+	search := &grafanaServiceAccountSearch{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/serviceaccounts/search?query=%s", url.QueryEscape(name)), nil, search)
+	if err != nil {
+		return nil, err
+	}
+	for _, serviceAccount := range search.ServiceAccounts {
+		if serviceAccount.Name == name {
+			return &serviceAccount, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateOrUpdateServiceAccount creates or updates the service account and returns its id
+func (r *GrafanaClientImpl) CreateOrUpdateServiceAccount(serviceAccount *v1beta1.GrafanaServiceAccount) (int64, error) {
+	existing, err := r.getServiceAccountByName(serviceAccount.ServiceAccountName())
+	if err != nil {
+		return 0, err
+	}
+
+	desired := &grafanaServiceAccount{
+		Name:       serviceAccount.ServiceAccountName(),
+		Role:       string(serviceAccount.Spec.Role),
+		IsDisabled: serviceAccount.Spec.IsDisabled,
+	}
+
+	if existing == nil {
+		created := &grafanaServiceAccount{}
+		err = r.do(http.MethodPost, "/api/serviceaccounts", desired, created)
+		return created.ID, err
+	}
+
+	if existing.Role != desired.Role || existing.IsDisabled != desired.IsDisabled {
+		err = r.do(http.MethodPatch, fmt.Sprintf("/api/serviceaccounts/%d", existing.ID), desired, nil)
+	}
+	return existing.ID, err
+}
+
+func (r *GrafanaClientImpl) CreateServiceAccountToken(serviceAccountID int64, name string) (*GrafanaServiceAccountToken, error) {
+	token := &GrafanaServiceAccountToken{}
+	err := r.do(http.MethodPost, fmt.Sprintf("/api/serviceaccounts/%d/tokens", serviceAccountID), &grafanaTokenCreate{
+		Name: name,
+	}, token)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *GrafanaClientImpl) DeleteServiceAccountToken(serviceAccountID int64, tokenID int64) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/serviceaccounts/%d/tokens/%d", serviceAccountID, tokenID), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *GrafanaClientImpl) DeleteServiceAccount(name string) error {
+	existing, err := r.getServiceAccountByName(name)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	err = r.do(http.MethodDelete, fmt.Sprintf("/api/serviceaccounts/%d", existing.ID), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type grafanaDashboardWithMeta struct {
+	Dashboard json.RawMessage `json:"dashboard"`
+	Meta      struct {
+		FolderUID string `json:"folderUid"`
+	} `json:"meta"`
+}
+
+type grafanaSnapshotCreate struct {
+	Dashboard json.RawMessage `json:"dashboard"`
+	Name      string          `json:"name"`
+	Expires   int64           `json:"expires,omitempty"`
+}
+
+// GrafanaSnapshot is a newly created snapshot
+type GrafanaSnapshot struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// GetDashboardJson returns the json model of a dashboard as stored in Grafana
+func (r *GrafanaClientImpl) GetDashboardJson(uid string) (json.RawMessage, error) {
+	dashboard := &grafanaDashboardWithMeta{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(uid)), nil, dashboard)
+	if err != nil {
+		return nil, err
+	}
+	return dashboard.Dashboard, nil
+}
+
+// CreateSnapshot takes a snapshot of a dashboard model, snapshots with an expiry of zero never expire
+func (r *GrafanaClientImpl) CreateSnapshot(dashboard json.RawMessage, name string, expiresSeconds int64) (*GrafanaSnapshot, error) {
+	snapshot := &GrafanaSnapshot{}
+	err := r.do(http.MethodPost, "/api/snapshots", &grafanaSnapshotCreate{
+		Dashboard: dashboard,
+		Name:      name,
+		Expires:   expiresSeconds,
+	}, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func (r *GrafanaClientImpl) DeleteSnapshot(key string) error {
+	err := r.do(http.MethodDelete, fmt.Sprintf("/api/snapshots/%s", url.PathEscape(key)), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+type GrafanaTeam struct {
+	ID    int64  `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type grafanaTeamSearch struct {
+	Teams []GrafanaTeam `json:"teams"`
+}
+
+type grafanaTeamCreated struct {
+	TeamID int64 `json:"teamId"`
+}
+
+type grafanaTeamMember struct {
+	UserID int64  `json:"userId"`
+	Login  string `json:"login,omitempty"`
+	Email  string `json:"email,omitempty"`
+}
+
+type grafanaTeamGroup struct {
+	GroupID string `json:"groupId"`
+}
+
+type GrafanaUserLookup struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+// GetTeamByName returns the team with the given name or nil if it doesn't exist
+func (r *GrafanaClientImpl) GetTeamByName(name string) (*GrafanaTeam, error) {
+	search := &grafanaTeamSearch{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/teams/search?name=%s", url.QueryEscape(name)), nil, search)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range search.Teams {
+		if team.Name == name {
+			return &team, nil
+		}
+	}
+	return nil, nil
+}
+
+// LookupUser finds a user by login or email
+func (r *GrafanaClientImpl) LookupUser(loginOrEmail string) (*GrafanaUserLookup, error) {
+	user := &GrafanaUserLookup{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/users/lookup?loginOrEmail=%s", url.QueryEscape(loginOrEmail)), nil, user)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateOrUpdateTeam creates the team and converges its members and external groups. Members that don't
+// exist in Grafana are skipped and returned as unresolved.
+func (r *GrafanaClientImpl) CreateOrUpdateTeam(team *v1beta1.GrafanaTeam) ([]string, error) {
+	existing, err := r.GetTeamByName(team.TeamName())
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	if existing == nil {
+		created := &grafanaTeamCreated{}
+		err = r.do(http.MethodPost, "/api/teams", &GrafanaTeam{
+			Name:  team.TeamName(),
+			Email: team.Spec.Email,
+		}, created)
+		id = created.TeamID
+	} else {
+		id = existing.ID
+		if existing.Email != team.Spec.Email {
+			err = r.do(http.MethodPut, fmt.Sprintf("/api/teams/%d", id), &GrafanaTeam{
+				Name:  team.TeamName(),
+				Email: team.Spec.Email,
+			}, nil)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved, err := r.syncTeamMembers(id, team.Spec.Members)
+	if err != nil {
+		return unresolved, err
+	}
+
+	if len(team.Spec.ExternalGroups) > 0 {
+		err = r.syncTeamGroups(id, team.Spec.ExternalGroups)
+	}
+	return unresolved, err
+}
+
+func (r *GrafanaClientImpl) syncTeamMembers(id int64, members []string) ([]string, error) {
+	var current []grafanaTeamMember
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/teams/%d/members", id), nil, &current)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := map[int64]bool{}
+	var unresolved []string
+	for _, member := range members {
+		user, err := r.LookupUser(member)
+		if IsNotFound(err) {
+			unresolved = append(unresolved, member)
+			continue
+		}
+		if err != nil {
+			return unresolved, err
+		}
+		desired[user.ID] = true
+	}
+
+	for _, member := range current {
+		if desired[member.UserID] {
+			delete(desired, member.UserID)
+			continue
+		}
+		err = r.do(http.MethodDelete, fmt.Sprintf("/api/teams/%d/members/%d", id, member.UserID), nil, nil)
+		if err != nil {
+			return unresolved, err
+		}
+	}
+
+	for userID := range desired {
+		err = r.do(http.MethodPost, fmt.Sprintf("/api/teams/%d/members", id), &grafanaTeamMember{UserID: userID}, nil)
+		if err != nil {
+			return unresolved, err
+		}
+	}
+
+	return unresolved, nil
+}
+
+func (r *GrafanaClientImpl) syncTeamGroups(id int64, groups []string) error {
+	var current []grafanaTeamGroup
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/teams/%d/groups", id), nil, &current)
+	if err != nil {
+		return err
+	}
+
+	desired := map[string]bool{}
+	for _, group := range groups {
+		desired[group] = true
+	}
+
+	for _, group := range current {
+		if desired[group.GroupID] {
+			delete(desired, group.GroupID)
+			continue
+		}
+		err = r.do(http.MethodDelete, fmt.Sprintf("/api/teams/%d/groups?groupId=%s", id, url.QueryEscape(group.GroupID)), nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	for group := range desired {
+		err = r.do(http.MethodPost, fmt.Sprintf("/api/teams/%d/groups", id), &grafanaTeamGroup{GroupID: group}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GrafanaClientImpl) DeleteTeam(name string) error {
+	existing, err := r.GetTeamByName(name)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	err = r.do(http.MethodDelete, fmt.Sprintf("/api/teams/%d", existing.ID), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
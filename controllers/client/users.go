@@ -0,0 +1,193 @@
+package client
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// mainOrgID is the id of the organization created with every Grafana instance
+	mainOrgID = 1
+)
+
+type grafanaUserCreate struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+type grafanaUserUpdate struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Login string `json:"login"`
+}
+
+type grafanaUserCreated struct {
+	ID int64 `json:"id"`
+}
+
+type grafanaUserPassword struct {
+	Password string `json:"password"`
+}
+
+type grafanaUserPermissions struct {
+	IsGrafanaAdmin bool `json:"isGrafanaAdmin"`
+}
+
+type grafanaUserOrg struct {
+	OrgID int64  `json:"orgId"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+}
+
+type grafanaOrgUserAdd struct {
+	LoginOrEmail string `json:"loginOrEmail"`
+	Role         string `json:"role"`
+}
+
+type grafanaOrgUserRole struct {
+	Role string `json:"role"`
+}
+
+type GrafanaOrg struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type grafanaUser struct {
+	ID             int64  `json:"id"`
+	Login          string `json:"login"`
+	Email          string `json:"email"`
+	Name           string `json:"name"`
+	IsGrafanaAdmin bool   `json:"isGrafanaAdmin"`
+}
+
+func (r *GrafanaClientImpl) GetOrgByName(name string) (*GrafanaOrg, error) {
+	org := &GrafanaOrg{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/orgs/name/%s", url.PathEscape(name)), nil, org)
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// CreateOrUpdateUser creates the user and converges its profile, permissions and org memberships. The password
+// of an existing user is only set when updatePassword is true.
+func (r *GrafanaClientImpl) CreateOrUpdateUser(user *v1beta1.GrafanaUser, password string, updatePassword bool) error {
+	existing := &grafanaUser{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/users/lookup?loginOrEmail=%s", url.QueryEscape(user.UserLogin())), nil, existing)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	var id int64
+	if IsNotFound(err) {
+		created := &grafanaUserCreated{}
+		err = r.do(http.MethodPost, "/api/admin/users", &grafanaUserCreate{
+			Name:     user.Spec.Name,
+			Email:    user.Spec.Email,
+			Login:    user.UserLogin(),
+			Password: password,
+		}, created)
+		if err != nil {
+			return err
+		}
+		id = created.ID
+	} else {
+		id = existing.ID
+		if existing.Email != user.Spec.Email || existing.Name != user.Spec.Name {
+			err = r.do(http.MethodPut, fmt.Sprintf("/api/users/%d", id), &grafanaUserUpdate{
+				Name:  user.Spec.Name,
+				Email: user.Spec.Email,
+				Login: user.UserLogin(),
+			}, nil)
+			if err != nil {
+				return err
+			}
+		}
+		if updatePassword {
+			err = r.do(http.MethodPut, fmt.Sprintf("/api/admin/users/%d/password", id), &grafanaUserPassword{
+				Password: password,
+			}, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if existing.IsGrafanaAdmin != user.Spec.IsGrafanaAdmin {
+		err = r.do(http.MethodPut, fmt.Sprintf("/api/admin/users/%d/permissions", id), &grafanaUserPermissions{
+			IsGrafanaAdmin: user.Spec.IsGrafanaAdmin,
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.syncUserOrgs(id, user)
+}
+
+func (r *GrafanaClientImpl) syncUserOrgs(id int64, user *v1beta1.GrafanaUser) error {
+	desired := map[int64]string{}
+	if user.Spec.Role != "" {
+		desired[mainOrgID] = string(user.Spec.Role)
+	}
+	for _, membership := range user.Spec.Orgs {
+		org, err := r.GetOrgByName(membership.Name)
+		if err != nil {
+			return fmt.Errorf("organization %s: %w", membership.Name, err)
+		}
+		desired[org.ID] = string(membership.Role)
+	}
+
+	var current []grafanaUserOrg
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/users/%d/orgs", id), nil, &current)
+	if err != nil {
+		return err
+	}
+
+	for _, membership := range current {
+		role, ok := desired[membership.OrgID]
+		delete(desired, membership.OrgID)
+		if !ok {
+			// the main org membership is left untouched when no role is defined for it
+			if membership.OrgID == mainOrgID {
+				continue
+			}
+			err = r.do(http.MethodDelete, fmt.Sprintf("/api/orgs/%d/users/%d", membership.OrgID, id), nil, nil)
+		} else if membership.Role != role {
+			err = r.do(http.MethodPatch, fmt.Sprintf("/api/orgs/%d/users/%d", membership.OrgID, id), &grafanaOrgUserRole{
+				Role: role,
+			}, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for orgID, role := range desired {
+		err = r.do(http.MethodPost, fmt.Sprintf("/api/orgs/%d/users", orgID), &grafanaOrgUserAdd{
+			LoginOrEmail: user.UserLogin(),
+			Role:         role,
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GrafanaClientImpl) DeleteUser(login string) error {
+	existing := &grafanaUser{}
+	err := r.do(http.MethodGet, fmt.Sprintf("/api/users/lookup?loginOrEmail=%s", url.QueryEscape(login)), nil, existing)
+	if err == nil {
+		err = r.do(http.MethodDelete, fmt.Sprintf("/api/admin/users/%d", existing.ID), nil, nil)
+	}
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
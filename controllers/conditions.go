@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/url"
+)
+
+// conditions and reasons set on every cr, Ready mirrors Synchronized so that kubectl wait and health checks
+// can rely on a single condition
+const (
+	conditionReady        = "Ready"
+	conditionSynchronized = "Synchronized"
+
+	reasonSynchronized        = "Synchronized"
+	reasonNoMatchingInstances = "NoMatchingInstances"
+	reasonInvalidSpec         = "InvalidSpec"
+	reasonGrafanaAPIError     = "GrafanaAPIError"
+	reasonInProgress          = "InProgress"
+	reasonStageFailed         = "StageFailed"
+	reasonDryRun              = "DryRun"
+
+	reasonWaitingForDependencies = "WaitingForDependencies"
+)
+
+// setSyncConditions sets the Synchronized and Ready conditions of a cr imported into its matching instances.
+// err is the last error of the reconcile, complete is false while instances are not ready.
+func setSyncConditions(conditions *[]v1.Condition, generation int64, instances int, complete bool, err error) {
+	condition := v1.Condition{
+		Type:               conditionSynchronized,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             reasonSynchronized,
+	}
+	switch {
+	case err != nil:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = getErrorReason(err)
+		condition.Message = err.Error()
+	case instances == 0:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = reasonNoMatchingInstances
+		condition.Message = "no Grafana instance matches the instance selector"
+	case !complete:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = reasonInProgress
+		condition.Message = "not all matching instances are ready"
+	}
+	setReadyConditions(conditions, condition)
+}
+
+// setDryRunConditions sets the conditions of a cr in dry run, which is never synchronized. Errors of
+// comparing the cr are reported like errors of importing it.
+func setDryRunConditions(conditions *[]v1.Condition, generation int64, results []grafanav1beta1.DryRunResult, complete bool, err error) {
+	setSyncConditions(conditions, generation, len(results), complete, err)
+	if !meta.IsStatusConditionTrue(*conditions, conditionSynchronized) {
+		return
+	}
+
+	changed := 0
+	for _, result := range results {
+		if result.Action != "" {
+			changed++
+		}
+	}
+	setReadyConditions(conditions, v1.Condition{
+		Status:             v1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             reasonDryRun,
+		Message:            fmt.Sprintf("dry run, the cr would change %d of %d instances, see status.dryRun", changed, len(results)),
+	})
+}
+
+// setReadyConditions sets a condition as both Synchronized and Ready
+func setReadyConditions(conditions *[]v1.Condition, condition v1.Condition) {
+	condition.Type = conditionSynchronized
+	meta.SetStatusCondition(conditions, condition)
+	condition.Type = conditionReady
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// getErrorReason returns GrafanaAPIError for errors returned by or reaching an instance, all other errors
+// come from resolving the spec like missing references or invalid json
+func getErrorReason(err error) string {
+	var apiErr *client2.GrafanaApiError
+	var urlErr *url.Error
+	if errors.As(err, &apiErr) || errors.As(err, &urlErr) {
+		return reasonGrafanaAPIError
+	}
+	return reasonInvalidSpec
+}
+
+// getLastMessage returns the message of the last error of a reconcile, empty if it succeeded
+func getLastMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// GrafanaFeatureToggles lists the feature toggles known to each minor version of Grafana, toggles of older
+// versions that are still accepted are repeated
+var GrafanaFeatureToggles = map[string][]string{
+	"8.5": {
+		"accesscontrol", "alertingNotificationsPoliciesMatchingInstances", "annotationComments",
+		"autoMigrateGraphPanels", "azureMonitorResourcePickerForMetrics", "commandPalette", "correlations",
+		"dashboardComments", "dashboardPreviews", "datasourceQueryMultiStatus", "explore2Dashboard",
+		"exploreMixedDatasource", "fullRangeLogsVolume", "live-config", "live-pipeline",
+		"live-service-web-worker", "newNavigation", "panelTitleSearch", "prometheusAzureOverrideAudience",
+		"prometheusStreamingJSONParser", "prometheusStreamingJSONParserTest", "prometheus_azure_auth",
+		"publicDashboards", "queryOverLive", "recordedQueries", "saveDashboardDrawer", "scenes",
+		"serviceAccounts", "storage", "swaggerUi", "tempoApmTable", "tempoSearch", "tempoServiceGraph",
+		"trimDefaults", "validatedQueries", "envelopeEncryption", "export",
+	},
+	"9.0": {
+		"accesscontrol", "alertingBigTransactions", "annotationComments", "autoMigrateGraphPanels",
+		"azureMonitorResourcePickerForMetrics", "commandPalette", "correlations", "dashboardComments",
+		"dashboardPreviews", "dashboardPreviewsAdmin", "dashboardsFromStorage", "datasourceQueryMultiStatus",
+		"envelopeEncryption", "explore2Dashboard", "exploreMixedDatasource", "export", "fullRangeLogsVolume",
+		"live-config", "live-pipeline", "live-service-web-worker", "newNavigation", "panelTitleSearch",
+		"persistNotifications", "prometheusAzureOverrideAudience", "prometheusStreamingJSONParser",
+		"prometheus_azure_auth", "publicDashboards", "queryLibrary", "queryOverLive", "recordedQueries",
+		"redshiftAsyncQueryDataSupport", "saveDashboardDrawer", "savedItems", "scenes",
+		"service-accounts", "serviceAccounts", "showFeatureFlagsInUI", "storage", "storageLocalUpload",
+		"swaggerUi", "tempoApmTable", "tempoSearch", "tempoServiceGraph", "tracing", "trimDefaults",
+		"validatedQueries",
+	},
+}
+
+// UnknownFeatureToggles returns the toggles not known to a version of Grafana, sorted by name. The second
+// return value is false when no toggles are known for the version, in that case every toggle is accepted.
+func UnknownFeatureToggles(version string, toggles map[string]bool) ([]string, bool) {
+	known, ok := GrafanaFeatureToggles[minorVersion(version)]
+	if !ok {
+		return nil, false
+	}
+
+	var unknown []string
+	for name := range toggles {
+		found := false
+		for _, toggle := range known {
+			if toggle == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, true
+}
+
+// minorVersion returns the major and minor part of a version, e.g. 9.0 for 9.0.2 or v9.0.2-ubuntu
+func minorVersion(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
@@ -11,15 +11,43 @@ import (
 )
 
 type GrafanaIni struct {
-	cfg *v1beta1.GrafanaConfig
+	cfg    *v1beta1.GrafanaConfig
+	values map[string]map[string]string
 }
 
 func NewGrafanaIni(cfg *v1beta1.GrafanaConfig) *GrafanaIni {
 	return &GrafanaIni{
-		cfg: cfg,
+		cfg:    cfg,
+		values: map[string]map[string]string{},
 	}
 }
 
+// SetValue sets a key of a section, replacing the value rendered from the typed config
+func (i *GrafanaIni) SetValue(section string, key string, value string) {
+	if i.values[section] == nil {
+		i.values[section] = map[string]string{}
+	}
+	i.values[section][key] = value
+}
+
+// applyValues replaces the keys set with SetValue in the parsed config
+func (i *GrafanaIni) applyValues(config map[string][]string) map[string][]string {
+	for section, values := range i.values {
+		var items []string
+		for _, item := range config[section] {
+			key := strings.TrimSpace(strings.SplitN(item, "=", 2)[0])
+			if _, ok := values[key]; !ok {
+				items = append(items, item)
+			}
+		}
+		for key, value := range values {
+			items = append(items, fmt.Sprintf("%v = %v", key, value))
+		}
+		config[section] = items
+	}
+	return config
+}
+
 func appendStr(list []string, key, value string) []string {
 	if value != "" {
 		return append(list, fmt.Sprintf("%v = %v", key, value))
@@ -55,6 +83,7 @@ func appendBool(list []string, key string, value *bool) []string {
 func (i *GrafanaIni) Write() (string, string) {
 	config := map[string][]string{}
 	config = i.parseConfig(config)
+	config = i.applyValues(config)
 
 	sb := strings.Builder{}
 
@@ -573,6 +602,7 @@ func (i *GrafanaIni) cfgSmtp(config map[string][]string) map[string][]string {
 	items = appendStr(items, "from_address", i.cfg.Smtp.FromAddress)
 	items = appendStr(items, "from_name", i.cfg.Smtp.FromName)
 	items = appendStr(items, "ehlo_identity", i.cfg.Smtp.EhloIdentity)
+	items = appendStr(items, "startTLS_policy", i.cfg.Smtp.StartTLSPolicy)
 	config["smtp"] = items
 
 	return config
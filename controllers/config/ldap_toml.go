@@ -0,0 +1,97 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type LdapToml struct {
+	cfg *v1beta1.GrafanaLDAPConfigSpec
+
+	// resolved bind passwords, indexed like the servers of the config
+	bindPasswords []string
+}
+
+func NewLdapToml(cfg *v1beta1.GrafanaLDAPConfigSpec, bindPasswords []string) *LdapToml {
+	return &LdapToml{
+		cfg:           cfg,
+		bindPasswords: bindPasswords,
+	}
+}
+
+func writeTomlStr(sb *strings.Builder, key, value string) {
+	if value != "" {
+		sb.WriteString(fmt.Sprintf("%s = %s\n", key, strconv.Quote(value)))
+	}
+}
+
+func writeTomlStrList(sb *strings.Builder, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	sb.WriteString(fmt.Sprintf("%s = [%s]\n", key, strings.Join(quoted, ", ")))
+}
+
+// Write renders ldap.toml and returns it together with its hash
+func (t *LdapToml) Write() (string, string) {
+	sb := strings.Builder{}
+
+	for i, server := range t.cfg.Servers {
+		sb.WriteString("[[servers]]\n")
+		writeTomlStr(&sb, "host", server.Host)
+		port := server.Port
+		if port == 0 {
+			port = 389
+		}
+		sb.WriteString(fmt.Sprintf("port = %d\n", port))
+		sb.WriteString(fmt.Sprintf("use_ssl = %v\n", server.UseSSL))
+		sb.WriteString(fmt.Sprintf("start_tls = %v\n", server.StartTLS))
+		sb.WriteString(fmt.Sprintf("ssl_skip_verify = %v\n", server.SSLSkipVerify))
+		writeTomlStr(&sb, "root_ca_cert", server.RootCACert)
+		writeTomlStr(&sb, "bind_dn", server.BindDN)
+		if i < len(t.bindPasswords) {
+			writeTomlStr(&sb, "bind_password", t.bindPasswords[i])
+		}
+		writeTomlStr(&sb, "search_filter", server.SearchFilter)
+		writeTomlStrList(&sb, "search_base_dns", server.SearchBaseDNs)
+		writeTomlStr(&sb, "group_search_filter", server.GroupSearchFilter)
+		writeTomlStrList(&sb, "group_search_base_dns", server.GroupSearchBaseDNs)
+		sb.WriteByte('\n')
+
+		if server.Attributes != nil {
+			sb.WriteString("[servers.attributes]\n")
+			writeTomlStr(&sb, "name", server.Attributes.Name)
+			writeTomlStr(&sb, "surname", server.Attributes.Surname)
+			writeTomlStr(&sb, "username", server.Attributes.Username)
+			writeTomlStr(&sb, "member_of", server.Attributes.MemberOf)
+			writeTomlStr(&sb, "email", server.Attributes.Email)
+			sb.WriteByte('\n')
+		}
+
+		for _, mapping := range server.GroupMappings {
+			sb.WriteString("[[servers.group_mappings]]\n")
+			writeTomlStr(&sb, "group_dn", mapping.GroupDN)
+			writeTomlStr(&sb, "org_role", string(mapping.OrgRole))
+			if mapping.GrafanaAdmin != nil {
+				sb.WriteString(fmt.Sprintf("grafana_admin = %v\n", *mapping.GrafanaAdmin))
+			}
+			if mapping.OrgID != nil {
+				sb.WriteString(fmt.Sprintf("org_id = %d\n", *mapping.OrgID))
+			}
+			sb.WriteByte('\n')
+		}
+	}
+
+	hash := sha256.New()
+	io.WriteString(hash, sb.String()) // nolint
+
+	return sb.String(), fmt.Sprintf("%x", hash.Sum(nil))
+}
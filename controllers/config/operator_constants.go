@@ -5,22 +5,87 @@ const (
 	GrafanaImage   = "docker.io/grafana/grafana"
 	GrafanaVersion = "9.0.0"
 
+	// Image renderer
+	GrafanaImageRendererImage        = "docker.io/grafana/grafana-image-renderer"
+	GrafanaImageRendererVersion      = "3.4.2"
+	GrafanaImageRendererPort     int = 8081
+	GrafanaImageRendererPortName     = "renderer"
+
 	// Paths
 	GrafanaDataPath         = "/var/lib/grafana"
 	GrafanaLogsPath         = "/var/log/grafana"
 	GrafanaPluginsPath      = "/var/lib/grafana/plugins"
 	GrafanaProvisioningPath = "/etc/grafana/provisioning/"
+	GrafanaLdapConfigPath   = "/etc/grafana-ldap/ldap.toml"
+	GrafanaDatabaseTLSPath  = "/etc/grafana-database-tls"
+	GrafanaTLSPath          = "/etc/grafana-tls"
 
 	// Grafana env vars and admin user
-	DefaultAdminUser           = "admin"
-	GrafanaAdminUserEnvVar     = "GF_SECURITY_ADMIN_USER"
-	GrafanaAdminPasswordEnvVar = "GF_SECURITY_ADMIN_PASSWORD" // #nosec G101
-	GrafanaPluginsEnvVar       = "GF_INSTALL_PLUGINS"
+	DefaultAdminUser               = "admin"
+	GrafanaAdminUserEnvVar         = "GF_SECURITY_ADMIN_USER"
+	GrafanaAdminPasswordEnvVar     = "GF_SECURITY_ADMIN_PASSWORD"         // #nosec G101
+	GrafanaAdminPendingPasswordKey = "GF_SECURITY_ADMIN_PASSWORD_PENDING" // #nosec G101
+	GrafanaAdminRotateAnnotation   = "grafana.integreatly.org/rotate-admin-password"
+	GrafanaPluginsEnvVar           = "GF_INSTALL_PLUGINS"
+	GrafanaUnsignedPluginsEnvVar   = "GF_PLUGINS_ALLOW_LOADING_UNSIGNED_PLUGINS"
+	GrafanaPluginRepoEnvVar        = "GF_PLUGIN_REPO"
+	GrafanaDatabasePasswordEnvVar  = "GF_DATABASE_PASSWORD" // #nosec G101
+	GrafanaSMTPPasswordEnvVar      = "GF_SMTP_PASSWORD"     // #nosec G101
+	GrafanaExtraVolumesAnnotation  = "grafana.integreatly.org/extra-volumes-hash"
+
+	// LDAP
+	GrafanaLdapConfigKey        = "ldap.toml"
+	GrafanaLdapAllowSignUpKey   = "allow_sign_up"
+	GrafanaLdapConfigAnnotation = "grafana.integreatly.org/ldap-config"
+
+	// Grafana Cloud
+	GrafanaCloudAPIURL         = "https://grafana.com"
+	GrafanaPluginRepository    = "https://grafana.com/api/plugins"
+	GrafanaCloudStackAPIKeyKey = "GF_CLOUD_STACK_API_KEY" // #nosec G101
+
+	// Backups
+	BackupWriterImage        = "docker.io/library/busybox:1.35"
+	BackupMountPath          = "/backup"
+	BackupAccessKeyIDKey     = "AWS_ACCESS_KEY_ID"
+	BackupSecretAccessKeyKey = "AWS_SECRET_ACCESS_KEY" // #nosec G101
+	BackupDefaultS3Region    = "us-east-1"
+
+	// Dashboards in object storage
+	ObjectStorageSessionTokenKey = "AWS_SESSION_TOKEN"
+	ObjectStorageSASTokenKey     = "AZURE_STORAGE_SAS_TOKEN" // #nosec G101
+	ObjectStorageGCSEndpoint     = "https://storage.googleapis.com"
+	ObjectStorageGCSRegion       = "auto"
+
+	// Notification policies without root settings route to the contact point of a new instance
+	GrafanaDefaultContactPoint = "grafana-default-email"
+
+	// Dashboards imported by the operator are tagged to tell them apart from dashboards created in the ui
+	ManagedDashboardTag = "grafana-operator"
+
+	// Datasource discovery
+	DatasourceTypeLabel       = "grafana.integreatly.org/datasource-type"
+	DatasourceDiscoveredLabel = "grafana.integreatly.org/discovered-by"
+
+	// Offline plugin bundles
+	PluginBundleInstallerImage = "docker.io/library/busybox:1.35"
+	PluginBundleMountPath      = "/plugin-bundle"
+	PluginBundleImagePath      = "/plugins"
+
+	// Upgrades
+	UpgradeBackupKey        = "backup.tar.gz"
+	UpgradeBackupVersionKey = "version"
+	// the api server rejects larger secrets
+	UpgradeBackupMaxSize = 1024 * 1024
 
 	// Networking
 	GrafanaHttpPort     int = 3000
 	GrafanaHttpPortName     = "grafana"
 
+	// Unified alerting peers of replicas
+	GrafanaAlertingHAPort        int = 9094
+	GrafanaAlertingHAPortNameTCP     = "alerting-tcp"
+	GrafanaAlertingHAPortNameUDP     = "alerting-udp"
+
 	// Data storage
 	GrafanaProvisionPluginVolumeName    = "grafana-provision-plugins"
 	GrafanaPluginsVolumeName            = "grafana-plugins"
@@ -28,6 +93,11 @@ const (
 	GrafanaProvisionNotifierVolumeName  = "grafana-provision-notifiers"
 	GrafanaLogsVolumeName               = "grafana-logs"
 	GrafanaDataVolumeName               = "grafana-data"
+	GrafanaLdapVolumeName               = "grafana-ldap"
+	GrafanaDatabaseTLSVolumeName        = "grafana-database-tls"
+	GrafanaTLSVolumeName                = "grafana-tls"
+	GrafanaPluginBundleVolumeName       = "grafana-plugin-bundle"
+	GrafanaDefaultPersistenceSize       = "1Gi"
 	SecretsMountDir                     = "/etc/grafana-secrets/" // #nosec G101
 	ConfigMapsMountDir                  = "/etc/grafana-configmaps/"
 )
@@ -0,0 +1,520 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/audit"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/tracing"
+	v12 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// grafanaFinalizer is added to resources that need to be removed from Grafana before deletion
+	grafanaFinalizer = "grafana.integreatly.org/finalizer"
+
+	// conditionUnsupported is set on resources that require a feature some selected instances don't provide
+	conditionUnsupported = "Unsupported"
+)
+
+// results recorded for instances a cr is not applied to yet
+var (
+	errInstanceNotReady  = errors.New("grafana instance not ready")
+	errInstanceUpgrading = errors.New("grafana instance is upgrading")
+)
+
+// AllowCrossNamespaceImport enables the import of resources into instances of other namespaces, set by the
+// operator flag
+var AllowCrossNamespaceImport bool
+
+// DefaultResyncPeriod is the interval in which resources without a resync period are compared to their state
+// in Grafana, set by the operator flag
+var DefaultResyncPeriod = RequeueDelayDrift
+
+// getResyncPeriod returns the interval in which a resource is compared to its state in Grafana
+func getResyncPeriod(policy grafanav1beta1.ResyncPolicy) time.Duration {
+	if policy.ResyncPeriod == nil || policy.ResyncPeriod.Duration <= 0 {
+		return DefaultResyncPeriod
+	}
+	return policy.ResyncPeriod.Duration
+}
+
+// setSyncRetry records a failed attempt to apply a cr and returns the delay until the next attempt, the delay
+// doubles with every failure up to the drift interval. An attempt without error resets the failures.
+func setSyncRetry(status *grafanav1beta1.SyncRetryStatus, err error) time.Duration {
+	if err == nil {
+		*status = grafanav1beta1.SyncRetryStatus{}
+		return RequeueDelayError
+	}
+
+	status.ConsecutiveFailures++
+	delay := RequeueDelayError
+	for i := 1; i < status.ConsecutiveFailures && delay < RequeueDelayDrift; i++ {
+		delay *= 2
+	}
+	if delay > RequeueDelayDrift {
+		delay = RequeueDelayDrift
+	}
+
+	now := time.Now()
+	status.LastSyncError = err.Error()
+	status.LastSyncAttempt = &v1.Time{Time: now}
+	status.NextRetry = &v1.Time{Time: now.Add(delay)}
+	return delay
+}
+
+// getRetryDelay returns the time until the next attempt of a failed cr, or 0 if the attempt is due. Changed
+// specs are applied right away, the reconciles triggered by writing the status wait for the backoff.
+func getRetryDelay(status grafanav1beta1.SyncRetryStatus, conditions []v1.Condition, generation int64) time.Duration {
+	if status.NextRetry == nil {
+		return 0
+	}
+	condition := meta.FindStatusCondition(conditions, conditionSynchronized)
+	if condition == nil || condition.ObservedGeneration != generation {
+		return 0
+	}
+	delay := time.Until(status.NextRetry.Time)
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// appendInstanceStatus records the result of applying a cr to an instance. Failed applies keep the uid and
+// time of the last successful apply, unchanged results keep their time until the resync period passed so that
+// the status is not written on every reconcile.
+func appendInstanceStatus(statuses []grafanav1beta1.InstanceStatus, previous []grafanav1beta1.InstanceStatus, grafana *grafanav1beta1.Grafana, uid string, err error, resyncPeriod time.Duration) []grafanav1beta1.InstanceStatus {
+	status := grafanav1beta1.InstanceStatus{
+		Instance: fmt.Sprintf("%s/%s", grafana.Namespace, grafana.Name),
+	}
+	for _, p := range previous {
+		if p.Instance == status.Instance {
+			status = p
+			break
+		}
+	}
+
+	if err != nil {
+		status.Error = err.Error()
+		return append(statuses, status)
+	}
+	if status.Error == "" && status.UID == uid && status.LastApplied != nil && time.Since(status.LastApplied.Time) < resyncPeriod {
+		return append(statuses, status)
+	}
+
+	now := v1.Now()
+	status.UID = uid
+	status.Error = ""
+	status.LastApplied = &now
+	return append(statuses, status)
+}
+
+// getInstanceUID returns the uid a cr was last applied with to an instance, empty if it wasn't applied yet
+func getInstanceUID(statuses []grafanav1beta1.InstanceStatus, grafana *grafanav1beta1.Grafana) string {
+	instance := fmt.Sprintf("%s/%s", grafana.Namespace, grafana.Name)
+	for _, status := range statuses {
+		if status.Instance == instance {
+			return status.UID
+		}
+	}
+	return ""
+}
+
+// isAppliedWith returns true if a cr was applied with the uid to any instance
+func isAppliedWith(statuses []grafanav1beta1.InstanceStatus, uid string) bool {
+	for _, status := range statuses {
+		if status.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// getRecordedInstance returns the instance of an instance status, nil if the instance was deleted
+func getRecordedInstance(ctx context.Context, k8sClient client.Client, status grafanav1beta1.InstanceStatus) (*grafanav1beta1.Grafana, error) {
+	parts := strings.SplitN(status.Instance, "/", 2)
+	if len(parts) != 2 {
+		return nil, nil
+	}
+	grafana := &grafanav1beta1.Grafana{}
+	err := k8sClient.Get(ctx, client.ObjectKey{Namespace: parts[0], Name: parts[1]}, grafana)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return grafana, nil
+}
+
+// appendAdopted records an object adopted in an instance, the first adoption of an instance is kept since
+// it is the version to restore
+func appendAdopted(adopted []grafanav1beta1.AdoptedResource, resource grafanav1beta1.AdoptedResource) []grafanav1beta1.AdoptedResource {
+	for _, a := range adopted {
+		if a.Instance == resource.Instance {
+			return adopted
+		}
+	}
+	return append(adopted, resource)
+}
+
+// maxDryRunChanges limits the changes listed in the dry run results of a cr
+const maxDryRunChanges = 20
+
+// newDryRunResult returns the dry run result of a cr for an instance
+func newDryRunResult(grafana *grafanav1beta1.Grafana, action client2.ApplyResult, changes []string, err error) grafanav1beta1.DryRunResult {
+	result := grafanav1beta1.DryRunResult{
+		Instance: fmt.Sprintf("%s/%s", grafana.Namespace, grafana.Name),
+		Action:   string(action),
+		Changes:  len(changes),
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if len(changes) > maxDryRunChanges {
+		changes = changes[:maxDryRunChanges]
+	}
+	result.Diff = changes
+	return result
+}
+
+// getLastSynced returns the latest time a cr was applied to one of its instances
+func getLastSynced(statuses []grafanav1beta1.InstanceStatus) *v1.Time {
+	var lastSynced *v1.Time
+	for _, status := range statuses {
+		if status.LastApplied != nil && (lastSynced == nil || lastSynced.Before(status.LastApplied)) {
+			lastSynced = status.LastApplied
+		}
+	}
+	if lastSynced == nil {
+		return nil
+	}
+	return lastSynced.DeepCopy()
+}
+
+// newReconciler traces the reconciles of a controller and records the reconciled resource in the audit log,
+// reconciles of suspended v1beta1 crs are skipped
+func newReconciler(mgr ctrl.Manager, kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	if gvk := grafanav1beta1.GroupVersion.WithKind(kind); mgr.GetScheme().Recognizes(gvk) && !selfSuspendingKinds[kind] {
+		r = &suspendReconciler{
+			client:     mgr.GetClient(),
+			kind:       gvk,
+			reconciler: r,
+		}
+	}
+	return tracing.NewReconciler(kind, audit.NewReconciler(kind, r))
+}
+
+// GetMatchingInstances returns the Grafana instances selected by the label selector of a cr
+func GetMatchingInstances(ctx context.Context, k8sClient client.Client, cr client.Object, labelSelector *v1.LabelSelector) (grafanav1beta1.GrafanaList, error) {
+	ctx, span := tracing.Start(ctx, "resolve instances")
+	defer span.End()
+
+	var list grafanav1beta1.GrafanaList
+	selector, err := v1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		span.SetError(err)
+		return list, fmt.Errorf("invalid instance selector: %w", err)
+	}
+
+	err = k8sClient.List(ctx, &list, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		span.SetError(err)
+		return list, err
+	}
+
+	items := list.Items[:0]
+	for _, grafana := range list.Items {
+		if importAllowed(&grafana, cr) {
+			items = append(items, grafana)
+		}
+	}
+	list.Items = items
+	span.SetAttribute("grafana.instances", strconv.Itoa(len(items)))
+	return list, nil
+}
+
+// instanceSelected returns true if the labels of a Grafana instance satisfy the label selector of a cr, using
+// the same semantics as GetMatchingInstances
+func instanceSelected(grafana *grafanav1beta1.Grafana, cr client.Object, labelSelector *v1.LabelSelector) bool {
+	if labelSelector == nil || !importAllowed(grafana, cr) {
+		return false
+	}
+	selector, err := v1.LabelSelectorAsSelector(labelSelector)
+	return err == nil && selector.Matches(labels.Set(grafana.Labels))
+}
+
+// importAllowed returns true if a cr can be imported into an instance, crs of other namespaces require the
+// operator flag and both the instance and the cr to allow cross namespace imports
+func importAllowed(grafana *grafanav1beta1.Grafana, cr client.Object) bool {
+	if grafana.Namespace == cr.GetNamespace() {
+		return true
+	}
+	return AllowCrossNamespaceImport && grafana.Spec.AllowCrossNamespaceImport && allowsCrossNamespaceImport(cr)
+}
+
+// allowsCrossNamespaceImport returns true for the crs shared between tenants that opt into cross namespace
+// imports, all other crs are only imported into instances of their namespace
+func allowsCrossNamespaceImport(cr client.Object) bool {
+	switch cr := cr.(type) {
+	case *grafanav1beta1.GrafanaDashboard:
+		return cr.Spec.AllowCrossNamespaceImport
+	case *grafanav1beta1.GrafanaDatasource:
+		return cr.Spec.AllowCrossNamespaceImport
+	case *grafanav1beta1.GrafanaFolder:
+		return cr.Spec.AllowCrossNamespaceImport
+	case *grafanav1beta1.GrafanaLibraryPanel:
+		return cr.Spec.AllowCrossNamespaceImport
+	case *grafanav1beta1.GrafanaDashboardFolder:
+		return cr.Spec.AllowCrossNamespaceImport
+	}
+	return false
+}
+
+// getFolderUID resolves a reference to a GrafanaFolder of the organization of the referencing cr, an empty uid
+// refers to the general folder
+func getFolderUID(ctx context.Context, k8sClient client.Client, namespace string, folderRef string, org grafanav1beta1.OrgReference) (string, error) {
+	if folderRef == "" {
+		return "", nil
+	}
+
+	folder := &grafanav1beta1.GrafanaFolder{}
+	err := k8sClient.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      folderRef,
+	}, folder)
+	if err != nil {
+		return "", err
+	}
+	if getOrgKey(folder.Namespace, folder.Spec.OrgReference) != getOrgKey(namespace, org) {
+		return "", fmt.Errorf("folder %s is created in another organization", folderRef)
+	}
+
+	return folder.FolderUID(), nil
+}
+
+// loadDashboardJson reads the json of a dashboard from its gzipped json, the referenced ConfigMap or the
+// content downloaded from its url into the spec. Dashboards with inline json are not changed.
+func loadDashboardJson(ctx context.Context, k8sClient client.Client, dashboard *grafanav1beta1.GrafanaDashboard) error {
+	if dashboard.Spec.Json == "" && len(dashboard.Spec.GzipJson) > 0 {
+		if !isGzipped(dashboard.Spec.GzipJson) {
+			return fmt.Errorf("gzipJson of dashboard %s is not gzipped", dashboard.Name)
+		}
+		content, err := gunzipContent(dashboard.Spec.GzipJson)
+		if err != nil {
+			return fmt.Errorf("gzipJson of dashboard %s: %w", dashboard.Name, err)
+		}
+		dashboard.Spec.Json = string(content)
+		return nil
+	}
+	if isDashboardUrlSource(dashboard) {
+		content, err := getCachedContent(dashboard)
+		if err != nil {
+			return err
+		}
+		dashboard.Spec.Json = content
+		return nil
+	}
+	if dashboard.Spec.Json != "" || dashboard.Spec.ConfigMapRef == nil {
+		return nil
+	}
+
+	namespace, name := dashboard.ConfigMapKey()
+	if !dashboard.ConfigMapAllowed(AllowCrossNamespaceImport) {
+		return fmt.Errorf("dashboard configmap %s/%s is in another namespace, this requires allowCrossNamespaceImport on the dashboard and the operator", namespace, name)
+	}
+	configMap := &v12.ConfigMap{}
+	err := k8sClient.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}, configMap)
+	if err != nil {
+		return fmt.Errorf("dashboard configmap %s/%s: %w", namespace, name, err)
+	}
+
+	key := dashboard.Spec.ConfigMapRef.Key
+	if value, ok := configMap.Data[key]; ok {
+		dashboard.Spec.Json = value
+		return nil
+	}
+	if value, ok := configMap.BinaryData[key]; ok {
+		content, err := gunzipContent(value)
+		if err != nil {
+			return fmt.Errorf("dashboard configmap %s/%s key %s: %w", namespace, name, key, err)
+		}
+		dashboard.Spec.Json = string(content)
+		return nil
+	}
+	return fmt.Errorf("dashboard configmap %s/%s has no key %s", namespace, name, key)
+}
+
+// getDashboardUID resolves a reference to a GrafanaDashboard, falling back to the uid of a dashboard not
+// managed by the operator
+func getDashboardUID(ctx context.Context, k8sClient client.Client, namespace string, dashboardRef string, dashboardUID string) (string, error) {
+	if dashboardRef != "" {
+		dashboard := &grafanav1beta1.GrafanaDashboard{}
+		err := k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      dashboardRef,
+		}, dashboard)
+		if err != nil {
+			return "", err
+		}
+		err = loadDashboardJson(ctx, k8sClient, dashboard)
+		if err != nil {
+			return "", err
+		}
+		return dashboard.DashboardUID()
+	}
+
+	if dashboardUID == "" {
+		return "", fmt.Errorf("either dashboardRef or dashboardUid must be set")
+	}
+	return dashboardUID, nil
+}
+
+// getDatasourceUID resolves a reference to a datasource
+func getDatasourceUID(ctx context.Context, k8sClient client.Client, namespace string, reference grafanav1beta1.DatasourceReference) (string, error) {
+	if reference.DatasourceRef != "" {
+		datasource := &grafanav1beta1.GrafanaDatasource{}
+		err := k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      reference.DatasourceRef,
+		}, datasource)
+		if err != nil {
+			return "", err
+		}
+		return datasource.DatasourceUID(), nil
+	}
+
+	if reference.DatasourceUID == "" {
+		return "", fmt.Errorf("either datasourceRef or datasourceUid must be set")
+	}
+	return reference.DatasourceUID, nil
+}
+
+// getOrgClient returns a client for the organization a resource is placed in
+func getOrgClient(ctx context.Context, k8sClient client.Client, grafanaClient client2.GrafanaClient, namespace string, reference grafanav1beta1.OrgReference) (client2.GrafanaClient, error) {
+	if reference.OrgRef != "" {
+		org := &grafanav1beta1.GrafanaOrganization{}
+		err := k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      reference.OrgRef,
+		}, org)
+		if err != nil {
+			return nil, err
+		}
+
+		grafanaOrg, err := grafanaClient.GetOrgByName(org.OrgName())
+		if err != nil {
+			return nil, err
+		}
+		return grafanaClient.InOrg(grafanaOrg.ID), nil
+	}
+
+	if reference.OrgID != nil {
+		return grafanaClient.InOrg(*reference.OrgID), nil
+	}
+
+	return grafanaClient, nil
+}
+
+// getRoleUID resolves a reference to a GrafanaRole, falling back to the uid of a role not managed by the
+// operator
+func getRoleUID(ctx context.Context, k8sClient client.Client, namespace string, roleRef string, roleUID string) (string, error) {
+	if roleRef != "" {
+		role := &grafanav1beta1.GrafanaRole{}
+		err := k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      roleRef,
+		}, role)
+		if err != nil {
+			return "", err
+		}
+		return role.RoleUID(), nil
+	}
+
+	if roleUID == "" {
+		return "", fmt.Errorf("either roleRef or roleUid must be set")
+	}
+	return roleUID, nil
+}
+
+// setUnsupportedCondition reports the selected instances that are not running Grafana Enterprise
+func setUnsupportedCondition(conditions *[]v1.Condition, generation int64, unsupported []string) {
+	condition := v1.Condition{
+		Type:               conditionUnsupported,
+		Status:             v1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "Enterprise",
+	}
+	if len(unsupported) > 0 {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = "NotEnterprise"
+		condition.Message = fmt.Sprintf("instances not running Grafana Enterprise: %s", strings.Join(unsupported, ", "))
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// ReconcilePlugins stores the plugins requested by a resource in the plugins configmap of an instance, from
+// where the grafana reconciler will pick them up. The entries are keyed by the kind, namespace and name of
+// the resource, see model.GetPluginsKey.
+func ReconcilePlugins(ctx context.Context, k8sClient client.Client, scheme *runtime.Scheme, grafana *grafanav1beta1.Grafana, plugins grafanav1beta1.PluginList, kind string, cr client.Object) error {
+	if plugins == nil || len(plugins) == 0 {
+		return nil
+	}
+
+	err := plugins.Validate()
+	if err != nil {
+		return err
+	}
+
+	pluginsConfigMap := model.GetPluginsConfigMap(grafana, scheme)
+	selector := client.ObjectKey{
+		Namespace: pluginsConfigMap.Namespace,
+		Name:      pluginsConfigMap.Name,
+	}
+
+	err = k8sClient.Get(ctx, selector, pluginsConfigMap)
+	if err != nil {
+		return err
+	}
+
+	val, err := json.Marshal(plugins.Sanitize())
+	if err != nil {
+		return err
+	}
+
+	if pluginsConfigMap.BinaryData == nil {
+		pluginsConfigMap.BinaryData = make(map[string][]byte)
+	}
+
+	// entries of previous versions of the operator were keyed by name only
+	key := model.GetPluginsKey(kind, cr.GetNamespace(), cr.GetName())
+	legacyKey := model.GetLegacyPluginsKey(kind, cr.GetName())
+	_, legacy := pluginsConfigMap.BinaryData[legacyKey]
+	if legacy || bytes.Compare(val, pluginsConfigMap.BinaryData[key]) != 0 {
+		delete(pluginsConfigMap.BinaryData, legacyKey)
+		pluginsConfigMap.BinaryData[key] = val
+		return k8sClient.Update(ctx, pluginsConfigMap)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
+)
+
+// dashboardDependencyIndex indexes dashboards by the kind, namespace and name of the folder, datasources and
+// library panels they reference
+const dashboardDependencyIndex = ".spec.dependencies"
+
+// dashboardDependency is a cr in the namespace of a dashboard that has to be ready before the dashboard is
+// imported
+type dashboardDependency struct {
+	kind string
+	name string
+	// the cr once it was read
+	cr client.Object
+}
+
+// getDashboardDependencies returns the folder, datasources and library panels referenced by a dashboard
+func getDashboardDependencies(dashboard *grafanav1beta1.GrafanaDashboard) []dashboardDependency {
+	var dependencies []dashboardDependency
+	if dashboard.Spec.FolderRef != "" {
+		dependencies = append(dependencies, dashboardDependency{kind: "GrafanaFolder", name: dashboard.Spec.FolderRef})
+	}
+	for _, datasource := range dashboard.Spec.Datasources {
+		if datasource.DatasourceRef != "" {
+			dependencies = append(dependencies, dashboardDependency{kind: "GrafanaDatasource", name: datasource.DatasourceRef})
+		}
+	}
+	for _, panel := range dashboard.Spec.LibraryPanelRefs {
+		dependencies = append(dependencies, dashboardDependency{kind: "GrafanaLibraryPanel", name: panel})
+	}
+	return dependencies
+}
+
+// getDependencyKeys returns the index keys of the dependencies of a dashboard
+func getDependencyKeys(dashboard *grafanav1beta1.GrafanaDashboard) []string {
+	var keys []string
+	for _, dependency := range getDashboardDependencies(dashboard) {
+		keys = append(keys, fmt.Sprintf("%s/%s/%s", dependency.kind, dashboard.Namespace, dependency.name))
+	}
+	return keys
+}
+
+// allInstances is returned as the ready instance of dependencies that don't hold back any instance
+const allInstances = "*"
+
+// getDependencies reads the folder, datasources and library panels of a dashboard and returns the missing
+// ones, as "kind name (reason)"
+func getDependencies(ctx context.Context, k8sClient client.Client, dashboard *grafanav1beta1.GrafanaDashboard) ([]dashboardDependency, []string, error) {
+	var dependencies []dashboardDependency
+	var missing []string
+	for _, dependency := range getDashboardDependencies(dashboard) {
+		var cr client.Object
+		switch dependency.kind {
+		case "GrafanaFolder":
+			cr = &grafanav1beta1.GrafanaFolder{}
+		case "GrafanaDatasource":
+			cr = &grafanav1beta1.GrafanaDatasource{}
+		default:
+			cr = &grafanav1beta1.GrafanaLibraryPanel{}
+		}
+
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: dashboard.Namespace, Name: dependency.name}, cr)
+		if errors.IsNotFound(err) {
+			missing = append(missing, fmt.Sprintf("%s %s (not found)", dependency.kind, dependency.name))
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if cr.GetDeletionTimestamp() != nil {
+			missing = append(missing, fmt.Sprintf("%s %s (deleted)", dependency.kind, dependency.name))
+			continue
+		}
+		dependency.cr = cr
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, missing, nil
+}
+
+// getPendingDependencies returns the dependencies that are not ready in an instance, as "kind name (reason)"
+func getPendingDependencies(dependencies []dashboardDependency, grafana *grafanav1beta1.Grafana) []string {
+	var pending []string
+	for _, dependency := range dependencies {
+		if !dependencyReady(dependency.cr, grafana) {
+			pending = append(pending, fmt.Sprintf("%s %s (not ready)", dependency.kind, dependency.name))
+		}
+	}
+	return pending
+}
+
+// appendPending adds the pending dependencies of an instance that other instances don't wait for already
+func appendPending(pending []string, instancePending []string) []string {
+	for _, dependency := range instancePending {
+		found := false
+		for _, p := range pending {
+			if p == dependency {
+				found = true
+				break
+			}
+		}
+		if !found {
+			pending = append(pending, dependency)
+		}
+	}
+	return pending
+}
+
+// dependencyReady returns true if a folder, datasource or library panel was imported into an instance
+func dependencyReady(cr client.Object, grafana *grafanav1beta1.Grafana) bool {
+	instance := fmt.Sprintf("%s/%s", grafana.Namespace, grafana.Name)
+	for _, ready := range getReadyInstances(cr) {
+		if ready == allInstances || ready == instance {
+			return true
+		}
+	}
+	return false
+}
+
+// getReadyInstances returns the instances a folder, datasource or library panel was imported into without
+// error. Crs without instance selector are not reconciled and only have to exist, datasources in dry run don't
+// change Grafana, neither holds back any instance.
+func getReadyInstances(cr client.Object) []string {
+	var selector *metav1.LabelSelector
+	var conditions []metav1.Condition
+	var statuses []grafanav1beta1.InstanceStatus
+	switch cr := cr.(type) {
+	case *grafanav1beta1.GrafanaFolder:
+		selector, conditions, statuses = cr.Spec.InstanceSelector, cr.Status.Conditions, cr.Status.Instances
+	case *grafanav1beta1.GrafanaDatasource:
+		selector, conditions, statuses = cr.Spec.InstanceSelector, cr.Status.Conditions, cr.Status.Instances
+	case *grafanav1beta1.GrafanaLibraryPanel:
+		selector, conditions, statuses = cr.Spec.InstanceSelector, cr.Status.Conditions, cr.Status.Instances
+	default:
+		return nil
+	}
+
+	if cr.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	if selector == nil {
+		return []string{allInstances}
+	}
+	if condition := meta.FindStatusCondition(conditions, conditionReady); condition != nil && condition.Reason == reasonDryRun {
+		return []string{allInstances}
+	}
+
+	var ready []string
+	for _, status := range statuses {
+		if status.UID != "" && status.Error == "" {
+			ready = append(ready, status.Instance)
+		}
+	}
+	return ready
+}
+
+// updateWaiting reports the dependencies a dashboard waits for, waiting is not a failed attempt and the
+// dashboard is reconciled again once its dependencies are ready
+func (r *GrafanaDashboardReconciler) updateWaiting(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard, status *grafanav1beta1.GrafanaDashboardStatus, pending []string) (reconcile.Result, error) {
+	message := fmt.Sprintf("waiting for %s", strings.Join(pending, ", "))
+	status.LastMessage = message
+	status.SyncRetryStatus = grafanav1beta1.SyncRetryStatus{}
+	setReadyConditions(&status.Conditions, metav1.Condition{
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dashboard.Generation,
+		Reason:             reasonWaitingForDependencies,
+		Message:            message,
+	})
+	// dependencies that are never reconciled, e.g. after a restart of the operator, are checked again
+	return reconcile.Result{RequeueAfter: getResyncPeriod(dashboard.Spec.ResyncPolicy)}, r.updateStatus(ctx, dashboard, status)
+}
+
+// requestsForDependency returns the dashboards referencing a folder, datasource or library panel
+func (r *GrafanaDashboardReconciler) requestsForDependency(kind string) func(object client.Object) []reconcile.Request {
+	return func(object client.Object) []reconcile.Request {
+		var list grafanav1beta1.GrafanaDashboardList
+		err := r.Client.List(context.Background(), &list, client.MatchingFields{
+			dashboardDependencyIndex: fmt.Sprintf("%s/%s/%s", kind, object.GetNamespace(), object.GetName()),
+		})
+		if err != nil {
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(list.Items))
+		for _, dashboard := range list.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: dashboard.Namespace,
+				Name:      dashboard.Name,
+			}})
+		}
+		return requests
+	}
+}
+
+// dependencyReadyPredicate passes dependencies that were created or whose ready instances changed, e.g. a
+// folder imported into another instance
+var dependencyReadyPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return true },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !reflect.DeepEqual(getReadyInstances(e.ObjectOld), getReadyInstances(e.ObjectNew))
+	},
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+)
+
+// dashboardInput is an entry of the __inputs of a dashboard exported for sharing
+type dashboardInput struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// substituteDashboardInputs replaces the ${...} placeholders of the inputs of a dashboard in the spec,
+// datasource inputs are mapped to the datasources of the spec and constant inputs use their exported value
+func substituteDashboardInputs(ctx context.Context, k8sClient client.Client, dashboard *grafanav1beta1.GrafanaDashboard) error {
+	if strings.TrimSpace(dashboard.Spec.Json) == "" {
+		return nil
+	}
+	if len(dashboard.Spec.Datasources) == 0 && !strings.Contains(dashboard.Spec.Json, "__inputs") {
+		return nil
+	}
+
+	var content map[string]interface{}
+	err := json.Unmarshal([]byte(dashboard.Spec.Json), &content)
+	if err != nil {
+		return err
+	}
+
+	var inputs []dashboardInput
+	if raw, ok := content["__inputs"]; ok {
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(encoded, &inputs)
+		if err != nil {
+			return fmt.Errorf("invalid __inputs: %w", err)
+		}
+	}
+
+	values := map[string]string{}
+	for _, input := range inputs {
+		if input.Type == "constant" {
+			values[input.Name] = input.Value
+		}
+	}
+
+	for _, datasource := range dashboard.Spec.Datasources {
+		value := datasource.DatasourceName
+		if datasource.DatasourceRef != "" || datasource.DatasourceUID != "" {
+			value, err = getDatasourceUID(ctx, k8sClient, dashboard.Namespace, datasource.DatasourceReference)
+			if err != nil {
+				return fmt.Errorf("datasource input %s: %w", datasource.InputName, err)
+			}
+		}
+		if value == "" {
+			return fmt.Errorf("one of datasourceRef, datasourceUid or datasourceName must be set for datasource input %s", datasource.InputName)
+		}
+		values[datasource.InputName] = value
+	}
+
+	for _, input := range inputs {
+		if _, ok := values[input.Name]; !ok && input.Type == "datasource" {
+			log.FromContext(ctx).Info("datasource input of dashboard is not mapped", "dashboard", dashboard.Name, "input", input.Name)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	content = substituteInputs(content, values).(map[string]interface{})
+	delete(content, "__inputs")
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	dashboard.Spec.Json = string(raw)
+	return nil
+}
+
+// substituteDashboardEnv replaces the ${NAME} placeholders of the envs and envFrom sources of a dashboard in
+// the spec, placeholders of unknown variables are kept for the template variables of Grafana
+func substituteDashboardEnv(ctx context.Context, k8sClient client.Client, dashboard *grafanav1beta1.GrafanaDashboard) error {
+	if strings.TrimSpace(dashboard.Spec.Json) == "" || (len(dashboard.Spec.Envs) == 0 && len(dashboard.Spec.EnvFrom) == 0) {
+		return nil
+	}
+
+	values := map[string]string{}
+	for _, source := range dashboard.Spec.EnvFrom {
+		data, err := getEnvFromData(ctx, k8sClient, dashboard.Namespace, source)
+		if err != nil {
+			return err
+		}
+		for key, value := range data {
+			values[source.Prefix+key] = value
+		}
+	}
+
+	for _, env := range dashboard.Spec.Envs {
+		value := env.Value
+		if env.ValueFrom != nil {
+			var err error
+			value, err = getReferencedValue(ctx, k8sClient, dashboard.Namespace, *env.ValueFrom)
+			if err != nil {
+				return fmt.Errorf("env %s: %w", env.Name, err)
+			}
+		}
+		values[env.Name] = value
+	}
+
+	var content map[string]interface{}
+	err := json.Unmarshal([]byte(dashboard.Spec.Json), &content)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(substituteInputs(content, values))
+	if err != nil {
+		return err
+	}
+	dashboard.Spec.Json = string(raw)
+	return nil
+}
+
+// getEnvFromData returns the keys of the ConfigMap or Secret of an envFrom source, missing optional sources
+// contain no keys
+func getEnvFromData(ctx context.Context, k8sClient client.Client, namespace string, source v1.EnvFromSource) (map[string]string, error) {
+	data := map[string]string{}
+
+	if source.ConfigMapRef != nil {
+		configMap := &v1.ConfigMap{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.ConfigMapRef.Name}, configMap)
+		if errors.IsNotFound(err) && source.ConfigMapRef.Optional != nil && *source.ConfigMapRef.Optional {
+			return data, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("envFrom config map %s/%s: %w", namespace, source.ConfigMapRef.Name, err)
+		}
+		for key, value := range configMap.Data {
+			data[key] = value
+		}
+		return data, nil
+	}
+
+	if source.SecretRef != nil {
+		secret := &v1.Secret{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.SecretRef.Name}, secret)
+		if errors.IsNotFound(err) && source.SecretRef.Optional != nil && *source.SecretRef.Optional {
+			return data, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("envFrom secret %s/%s: %w", namespace, source.SecretRef.Name, err)
+		}
+		for key, value := range secret.Data {
+			data[key] = string(value)
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("envFrom source must reference either a secret or a config map")
+}
+
+// substituteInputs replaces the placeholders of the inputs in all strings of a json value
+func substituteInputs(value interface{}, values map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		for name, replacement := range values {
+			v = strings.ReplaceAll(v, "${"+name+"}", replacement)
+		}
+		return v
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = substituteInputs(item, values)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = substituteInputs(item, values)
+		}
+		return v
+	default:
+		return v
+	}
+}
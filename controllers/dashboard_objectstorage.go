@@ -0,0 +1,486 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/backup"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	v1 "k8s.io/api/core/v1"
+	"net/http"
+	"net/url"
+	"os"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcsMetadataTokenUrl     = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	azureStorageScope       = "https://storage.azure.com/.default"
+	azureDefaultAuthority   = "https://login.microsoftonline.com/"
+	azureStorageApiVersion  = "2020-04-08"
+	identityExpiryTolerance = 5 * time.Minute
+
+	// listings of folders are limited to 100000 objects
+	maxObjectStorageListPages = 100
+)
+
+// pod identities of the operator, shared by all dashboards
+var (
+	s3Identity    identityCache
+	gcsIdentity   identityCache
+	azureIdentity identityCache
+)
+
+// identityCache holds the credentials of a pod identity until shortly before they expire
+type identityCache struct {
+	mu      sync.Mutex
+	value   interface{}
+	expires time.Time
+}
+
+// get returns the cached credentials, or fetches them. Fetch returns nil if the identity isn't available.
+func (c *identityCache) get(fetch func() (interface{}, time.Time, error)) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.value != nil && time.Now().Add(identityExpiryTolerance).Before(c.expires) {
+		return c.value, nil
+	}
+	value, expires, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.value = value
+	c.expires = expires
+	return value, nil
+}
+
+// getObjectStorageUrl returns the url recorded as the source of the cached content of an object
+func getObjectStorageUrl(storage *grafanav1beta1.GrafanaDashboardObjectStorage) string {
+	switch storage.Provider {
+	case grafanav1beta1.ObjectStorageProviderGCS:
+		return fmt.Sprintf("gs://%s/%s", storage.Bucket, storage.Key)
+	case grafanav1beta1.ObjectStorageProviderAzure:
+		return fmt.Sprintf("azure://%s/%s/%s", strings.TrimPrefix(getAzureBlobEndpoint(storage), "https://"), storage.Bucket, storage.Key)
+	default:
+		return fmt.Sprintf("s3://%s/%s", storage.Bucket, storage.Key)
+	}
+}
+
+// newObjectStorageRequest returns an authorized request downloading the object of a dashboard
+func newObjectStorageRequest(ctx context.Context, credentials map[string][]byte, storage *grafanav1beta1.GrafanaDashboardObjectStorage) (*http.Request, error) {
+	return newObjectStorageApiRequest(ctx, credentials, storage, storage.Key, nil)
+}
+
+// newObjectStorageListRequest returns an authorized request listing a page of the objects below a prefix,
+// starting at the continuation token or marker of the previous page
+func newObjectStorageListRequest(ctx context.Context, credentials map[string][]byte, storage *grafanav1beta1.GrafanaDashboardObjectStorage, prefix string, marker string) (*http.Request, error) {
+	if target := getObjectStorageS3Target(credentials, storage); target != nil {
+		err := setS3Identity(target, credentials, storage)
+		if err != nil {
+			return nil, err
+		}
+		return target.NewListObjectsRequest(ctx, prefix, marker)
+	}
+
+	query := url.Values{
+		"prefix": {prefix},
+	}
+	if storage.Provider == grafanav1beta1.ObjectStorageProviderAzure {
+		query.Set("restype", "container")
+		query.Set("comp", "list")
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+	} else {
+		query.Set("list-type", "2")
+		if marker != "" {
+			query.Set("continuation-token", marker)
+		}
+	}
+	return newObjectStorageApiRequest(ctx, credentials, storage, "", query)
+}
+
+// newObjectStorageApiRequest returns an authorized request of an object, or of the bucket if the key is empty
+func newObjectStorageApiRequest(ctx context.Context, credentials map[string][]byte, storage *grafanav1beta1.GrafanaDashboardObjectStorage, key string, query url.Values) (*http.Request, error) {
+	if target := getObjectStorageS3Target(credentials, storage); target != nil {
+		err := setS3Identity(target, credentials, storage)
+		if err != nil {
+			return nil, err
+		}
+		return target.NewGetObjectRequest(ctx, key)
+	}
+
+	switch storage.Provider {
+	case grafanav1beta1.ObjectStorageProviderGCS:
+		u, err := url.Parse(getObjectStoragePath(config.ObjectStorageGCSEndpoint, storage.Bucket, key))
+		if err != nil {
+			return nil, err
+		}
+		u.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		token, err := gcsIdentity.get(getGCSMetadataToken)
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			req.Header.Set("Authorization", "Bearer "+token.(string))
+		}
+		return req, nil
+
+	case grafanav1beta1.ObjectStorageProviderAzure:
+		endpoint := getAzureBlobEndpoint(storage)
+		if endpoint == "" {
+			return nil, fmt.Errorf("account or endpoint must be set for azure blob storage")
+		}
+		u, err := url.Parse(getObjectStoragePath(endpoint, storage.Bucket, key))
+		if err != nil {
+			return nil, err
+		}
+		var parameters []string
+		if credentials != nil {
+			parameters = append(parameters, strings.TrimPrefix(string(credentials[config.ObjectStorageSASTokenKey]), "?"))
+		}
+		if len(query) > 0 {
+			parameters = append(parameters, query.Encode())
+		}
+		u.RawQuery = strings.Join(parameters, "&")
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("x-ms-version", azureStorageApiVersion)
+		if credentials == nil {
+			token, err := azureIdentity.get(getAzureWorkloadIdentityToken)
+			if err != nil {
+				return nil, err
+			}
+			if token != nil {
+				req.Header.Set("Authorization", "Bearer "+token.(string))
+			}
+		}
+		return req, nil
+	}
+
+	return nil, fmt.Errorf("unknown object storage provider %s", storage.Provider)
+}
+
+// getObjectStorageS3Target returns the target of buckets requested with the s3 api, which are s3 buckets and
+// gcs buckets accessed with hmac keys
+func getObjectStorageS3Target(credentials map[string][]byte, storage *grafanav1beta1.GrafanaDashboardObjectStorage) *backup.S3Target {
+	switch {
+	case storage.Provider == grafanav1beta1.ObjectStorageProviderS3:
+		target := &backup.S3Target{
+			Endpoint: storage.Endpoint,
+			Region:   storage.Region,
+			Bucket:   storage.Bucket,
+		}
+		if target.Region == "" {
+			target.Region = config.BackupDefaultS3Region
+		}
+		return target
+	case storage.Provider == grafanav1beta1.ObjectStorageProviderGCS && credentials != nil:
+		return &backup.S3Target{
+			Endpoint: config.ObjectStorageGCSEndpoint,
+			Region:   config.ObjectStorageGCSRegion,
+			Bucket:   storage.Bucket,
+		}
+	}
+	return nil
+}
+
+// setS3Identity sets the credentials of the secret, or of the pod identity for s3 buckets
+func setS3Identity(target *backup.S3Target, credentials map[string][]byte, storage *grafanav1beta1.GrafanaDashboardObjectStorage) error {
+	if credentials != nil {
+		setS3Credentials(target, credentials)
+		return nil
+	}
+	if storage.Provider != grafanav1beta1.ObjectStorageProviderS3 {
+		return nil
+	}
+	identity, err := s3Identity.get(getS3WebIdentity)
+	if err != nil {
+		return err
+	}
+	if identity != nil {
+		setS3Credentials(target, identity.(map[string][]byte))
+	}
+	return nil
+}
+
+// getObjectStoragePath returns the url of an object, or of the bucket if the key is empty
+func getObjectStoragePath(endpoint string, bucket string, key string) string {
+	if key == "" {
+		return fmt.Sprintf("%s/%s", endpoint, url.PathEscape(bucket))
+	}
+	return fmt.Sprintf("%s/%s/%s", endpoint, url.PathEscape(bucket), escapeObjectKey(key))
+}
+
+// listObjectStorageKeys returns the keys of the json objects below a prefix of a bucket
+func listObjectStorageKeys(ctx context.Context, credentials map[string][]byte, storage *grafanav1beta1.GrafanaDashboardObjectStorage, prefix string) ([]string, error) {
+	var keys []string
+	marker := ""
+	for page := 0; page < maxObjectStorageListPages; page++ {
+		names, next, err := listObjectStoragePage(ctx, credentials, storage, prefix, marker)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if strings.HasSuffix(name, ".json") {
+				keys = append(keys, name)
+			}
+		}
+		if next == "" {
+			return keys, nil
+		}
+		marker = next
+	}
+	return nil, fmt.Errorf("more than %d pages of objects below prefix %s", maxObjectStorageListPages, prefix)
+}
+
+// listObjectStoragePage returns the names of a page of objects and the marker of the next page
+func listObjectStoragePage(ctx context.Context, credentials map[string][]byte, storage *grafanav1beta1.GrafanaDashboardObjectStorage, prefix string, marker string) ([]string, string, error) {
+	req, err := newObjectStorageListRequest(ctx, credentials, storage, prefix, marker)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := dashboardHttpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("listing bucket %s returned status %d", storage.Bucket, resp.StatusCode)
+	}
+
+	var names []string
+	if storage.Provider == grafanav1beta1.ObjectStorageProviderAzure {
+		var result struct {
+			Blobs []struct {
+				Name string `xml:"Name"`
+			} `xml:"Blobs>Blob"`
+			NextMarker string `xml:"NextMarker"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, blob := range result.Blobs {
+			names = append(names, blob.Name)
+		}
+		return names, result.NextMarker, nil
+	}
+
+	var result struct {
+		Contents []struct {
+			Key string `xml:"Key"`
+		} `xml:"Contents"`
+		IsTruncated           bool   `xml:"IsTruncated"`
+		NextContinuationToken string `xml:"NextContinuationToken"`
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, object := range result.Contents {
+		names = append(names, object.Key)
+	}
+	if !result.IsTruncated {
+		return names, "", nil
+	}
+	return names, result.NextContinuationToken, nil
+}
+
+// getObjectStorageCredentials returns the data of the credentials secret, nil if the pod identity is used
+func getObjectStorageCredentials(ctx context.Context, k8sClient client.Client, namespace string, storage *grafanav1beta1.GrafanaDashboardObjectStorage) (map[string][]byte, error) {
+	ref := storage.CredentialsSecretRef
+	if ref == nil {
+		return nil, nil
+	}
+
+	secret := &v1.Secret{}
+	err := k8sClient.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      ref.Name,
+	}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("object storage credentials: %w", err)
+	}
+	if secret.Data == nil {
+		return map[string][]byte{}, nil
+	}
+	return secret.Data, nil
+}
+
+func setS3Credentials(target *backup.S3Target, credentials map[string][]byte) {
+	target.AccessKeyID = string(credentials[config.BackupAccessKeyIDKey])
+	target.SecretAccessKey = string(credentials[config.BackupSecretAccessKeyKey])
+	target.SessionToken = string(credentials[config.ObjectStorageSessionTokenKey])
+}
+
+// getAzureBlobEndpoint returns the url of the storage account, empty if neither endpoint nor account is set
+func getAzureBlobEndpoint(storage *grafanav1beta1.GrafanaDashboardObjectStorage) string {
+	if storage.Endpoint != "" {
+		return strings.TrimSuffix(storage.Endpoint, "/")
+	}
+	if storage.Account == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s.blob.core.windows.net", storage.Account)
+}
+
+// escapeObjectKey escapes the segments of a key, keeping the slashes between them
+func escapeObjectKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// getS3WebIdentity exchanges the service account token projected by IRSA for temporary credentials
+func getS3WebIdentity() (interface{}, time.Time, error) {
+	roleArn := os.Getenv("AWS_ROLE_ARN")
+	tokenFile := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleArn == "" || tokenFile == "" {
+		return nil, time.Time{}, nil
+	}
+
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	endpoint := "https://sts.amazonaws.com"
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		endpoint = fmt.Sprintf("https://sts.%s.amazonaws.com", region)
+	}
+	query := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {roleArn},
+		"RoleSessionName":  {"grafana-operator"},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+
+	resp, err := dashboardHttpClient.Get(endpoint + "/?" + query.Encode())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("sts returned status %d assuming role %s", resp.StatusCode, roleArn)
+	}
+
+	var result struct {
+		Credentials struct {
+			AccessKeyId     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	credentials := map[string][]byte{
+		config.BackupAccessKeyIDKey:         []byte(result.Credentials.AccessKeyId),
+		config.BackupSecretAccessKeyKey:     []byte(result.Credentials.SecretAccessKey),
+		config.ObjectStorageSessionTokenKey: []byte(result.Credentials.SessionToken),
+	}
+	return credentials, result.Credentials.Expiration, nil
+}
+
+// getGCSMetadataToken returns an access token of the service account of the node or workload identity,
+// outside of gcp the metadata server isn't reachable and buckets are read anonymously
+func getGCSMetadataToken() (interface{}, time.Time, error) {
+	req, err := http.NewRequest(http.MethodGet, gcsMetadataTokenUrl, nil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	metadataClient := &http.Client{
+		Timeout: time.Second * 5,
+	}
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return nil, time.Time{}, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("gcp metadata server returned status %d", resp.StatusCode)
+	}
+	return decodeAccessToken(resp)
+}
+
+// getAzureWorkloadIdentityToken exchanges the service account token projected by azure workload identity
+// for an access token of azure storage
+func getAzureWorkloadIdentityToken() (interface{}, time.Time, error) {
+	clientId := os.Getenv("AZURE_CLIENT_ID")
+	tenantId := os.Getenv("AZURE_TENANT_ID")
+	tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+	if clientId == "" || tenantId == "" || tokenFile == "" {
+		return nil, time.Time{}, nil
+	}
+
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	authority := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authority == "" {
+		authority = azureDefaultAuthority
+	}
+	form := url.Values{
+		"client_id":             {clientId},
+		"scope":                 {azureStorageScope},
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(token))},
+	}
+
+	resp, err := dashboardHttpClient.PostForm(fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authority, "/"), tenantId), form)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("azure ad returned status %d for client %s", resp.StatusCode, clientId)
+	}
+	return decodeAccessToken(resp)
+}
+
+// decodeAccessToken reads an oauth2 token response
+func decodeAccessToken(resp *http.Response) (interface{}, time.Time, error) {
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	err := json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if token.AccessToken == "" {
+		return nil, time.Time{}, fmt.Errorf("token response contains no access token")
+	}
+	return token.AccessToken, time.Now().Add(time.Duration(token.ExpiresIn) * time.Second), nil
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"encoding/json"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"regexp"
+	"sort"
+)
+
+// ids of plugins published on grafana.com, placeholders like ${DS_PROMETHEUS} are skipped
+var pluginIdPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// corePlugins are the panels and datasources bundled with Grafana
+var corePlugins = map[string]bool{
+	// panels
+	"alertlist":      true,
+	"annolist":       true,
+	"barchart":       true,
+	"bargauge":       true,
+	"candlestick":    true,
+	"canvas":         true,
+	"dashlist":       true,
+	"datagrid":       true,
+	"debug":          true,
+	"flamegraph":     true,
+	"gauge":          true,
+	"geomap":         true,
+	"gettingstarted": true,
+	"graph":          true,
+	"heatmap":        true,
+	"histogram":      true,
+	"live":           true,
+	"logs":           true,
+	"news":           true,
+	"nodeGraph":      true,
+	"piechart":       true,
+	"row":            true,
+	"singlestat":     true,
+	"stat":           true,
+	"state-timeline": true,
+	"status-history": true,
+	"table":          true,
+	"table-old":      true,
+	"text":           true,
+	"timeseries":     true,
+	"traces":         true,
+	"trend":          true,
+	"welcome":        true,
+	"xychart":        true,
+
+	// datasources
+	"__expr__":                         true,
+	"alertmanager":                     true,
+	"cloudwatch":                       true,
+	"dashboard":                        true,
+	"datasource":                       true,
+	"elasticsearch":                    true,
+	"grafana":                          true,
+	"grafana-azure-monitor-datasource": true,
+	"grafana-postgresql-datasource":    true,
+	"grafana-pyroscope-datasource":     true,
+	"grafana-testdata-datasource":      true,
+	"graphite":                         true,
+	"influxdb":                         true,
+	"jaeger":                           true,
+	"loki":                             true,
+	"mixed":                            true,
+	"mssql":                            true,
+	"mysql":                            true,
+	"opentsdb":                         true,
+	"parca":                            true,
+	"phlare":                           true,
+	"postgres":                         true,
+	"prometheus":                       true,
+	"stackdriver":                      true,
+	"tempo":                            true,
+	"testdata":                         true,
+	"zipkin":                           true,
+}
+
+// deriveDashboardPlugins returns the panel and datasource plugins used by the json of a dashboard that are
+// neither bundled with Grafana nor listed in the spec, requested in their latest version
+func deriveDashboardPlugins(dashboard *grafanav1beta1.GrafanaDashboard) grafanav1beta1.PluginList {
+	var content map[string]interface{}
+	err := json.Unmarshal([]byte(dashboard.Spec.Json), &content)
+	if err != nil {
+		return nil
+	}
+
+	ids := map[string]bool{}
+
+	// dashboards exported for sharing list their plugins
+	if requires, ok := content["__requires"].([]interface{}); ok {
+		for _, item := range requires {
+			require, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, _ := require["type"].(string); kind == "panel" || kind == "datasource" {
+				id, _ := require["id"].(string)
+				ids[id] = true
+			}
+		}
+	}
+
+	collectPanelPlugins(content["panels"], ids)
+	if templating, ok := content["templating"].(map[string]interface{}); ok {
+		if list, ok := templating["list"].([]interface{}); ok {
+			for _, variable := range list {
+				if variable, ok := variable.(map[string]interface{}); ok {
+					collectDatasourcePlugin(variable["datasource"], ids)
+				}
+			}
+		}
+	}
+	if annotations, ok := content["annotations"].(map[string]interface{}); ok {
+		if list, ok := annotations["list"].([]interface{}); ok {
+			for _, annotation := range list {
+				if annotation, ok := annotation.(map[string]interface{}); ok {
+					collectDatasourcePlugin(annotation["datasource"], ids)
+				}
+			}
+		}
+	}
+
+	var derived grafanav1beta1.PluginList
+	for id := range ids {
+		plugin := grafanav1beta1.GrafanaPlugin{
+			Name:    id,
+			Version: grafanav1beta1.PluginVersionLatest,
+		}
+		if corePlugins[id] || !pluginIdPattern.MatchString(id) || dashboard.Spec.Plugins.HasSomeVersionOf(&plugin) {
+			continue
+		}
+		derived = append(derived, plugin)
+	}
+	sort.Slice(derived, func(i, j int) bool {
+		return derived[i].Name < derived[j].Name
+	})
+	return derived
+}
+
+// collectPanelPlugins adds the types of panels, their datasources and targets, the panels of collapsed rows
+// are nested
+func collectPanelPlugins(value interface{}, ids map[string]bool) {
+	panels, ok := value.([]interface{})
+	if !ok {
+		return
+	}
+	for _, item := range panels {
+		panel, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if kind, ok := panel["type"].(string); ok {
+			ids[kind] = true
+		}
+		collectDatasourcePlugin(panel["datasource"], ids)
+		if targets, ok := panel["targets"].([]interface{}); ok {
+			for _, target := range targets {
+				if target, ok := target.(map[string]interface{}); ok {
+					collectDatasourcePlugin(target["datasource"], ids)
+				}
+			}
+		}
+		collectPanelPlugins(panel["panels"], ids)
+	}
+}
+
+// collectDatasourcePlugin adds the type of a datasource reference, references by name have no type
+func collectDatasourcePlugin(value interface{}, ids map[string]bool) {
+	reference, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if kind, ok := reference["type"].(string); ok {
+		ids[kind] = true
+	}
+}
@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/backup"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"io"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+const (
+	defaultContentCacheDuration = 5 * time.Minute
+
+	// dashboards are cached in the status and have to fit into the resource once compressed
+	maxDashboardUrlContentSize = 10 * 1024 * 1024
+)
+
+var dashboardHttpClient = &http.Client{
+	Timeout: time.Second * 30,
+}
+
+// getContentCacheDuration returns how long content downloaded from the url of a dashboard is used
+func getContentCacheDuration(dashboard *grafanav1beta1.GrafanaDashboard) time.Duration {
+	if dashboard.Spec.ContentCacheDuration == nil || dashboard.Spec.ContentCacheDuration.Duration <= 0 {
+		return defaultContentCacheDuration
+	}
+	return dashboard.Spec.ContentCacheDuration.Duration
+}
+
+// isDashboardUrlSource returns true if the json of a dashboard is downloaded from its url, grafana.com,
+// object storage, an OCI artifact or a git repository
+func isDashboardUrlSource(dashboard *grafanav1beta1.GrafanaDashboard) bool {
+	spec := dashboard.Spec
+	return spec.Json == "" && len(spec.GzipJson) == 0 && spec.ConfigMapRef == nil && (spec.Url != "" || spec.GrafanaCom != nil || spec.ObjectStorage != nil || spec.OCI != nil || spec.GitRepository != nil)
+}
+
+// getDashboardContentUrl returns the url the json of a dashboard is downloaded from, dashboards of
+// grafana.com are downloaded in the revision recorded in the status
+func getDashboardContentUrl(dashboard *grafanav1beta1.GrafanaDashboard) string {
+	if dashboard.Spec.Url != "" {
+		return dashboard.Spec.Url
+	}
+	if dashboard.Spec.GrafanaCom != nil {
+		if dashboard.Status.GrafanaComRevision > 0 {
+			return fmt.Sprintf("%s/api/dashboards/%d/revisions/%d/download", config.GrafanaCloudAPIURL, dashboard.Spec.GrafanaCom.Id, dashboard.Status.GrafanaComRevision)
+		}
+		return ""
+	}
+	if dashboard.Spec.ObjectStorage != nil {
+		return getObjectStorageUrl(dashboard.Spec.ObjectStorage)
+	}
+	if dashboard.Spec.OCI != nil {
+		return dashboard.Spec.OCI.ContentUrl()
+	}
+	if dashboard.Spec.GitRepository != nil {
+		return dashboard.Spec.GitRepository.ContentUrl()
+	}
+	return ""
+}
+
+// hasCachedContent returns true if the status contains content of the current url
+func hasCachedContent(dashboard *grafanav1beta1.GrafanaDashboard) bool {
+	url := getDashboardContentUrl(dashboard)
+	return url != "" && len(dashboard.Status.ContentCache) > 0 && dashboard.Status.ContentUrl == url
+}
+
+// resolveGrafanaComRevision records the revision of a grafana.com dashboard in the status, the latest
+// revision is resolved once and again on the update schedule. It returns true if the status changed.
+func resolveGrafanaComRevision(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard) (bool, error) {
+	reference := dashboard.Spec.GrafanaCom
+	if reference == nil || dashboard.Spec.Url != "" {
+		return false, nil
+	}
+
+	status := &dashboard.Status
+	if reference.Revision != nil {
+		if status.GrafanaComRevision == *reference.Revision {
+			return false, nil
+		}
+		status.GrafanaComRevision = *reference.Revision
+		status.GrafanaComRevisionTime = nil
+		return true, nil
+	}
+
+	now := time.Now()
+	if status.GrafanaComRevision > 0 && status.GrafanaComRevisionTime != nil {
+		if reference.UpdateSchedule == "" {
+			return false, nil
+		}
+		schedule, err := backup.ParseSchedule(reference.UpdateSchedule)
+		if err != nil {
+			return false, err
+		}
+		next := schedule.Next(status.GrafanaComRevisionTime.Time)
+		if next.IsZero() || now.Before(next) {
+			return false, nil
+		}
+	}
+
+	latest, err := getLatestGrafanaComRevision(ctx, reference.Id)
+	if err != nil {
+		return false, err
+	}
+	if latest != status.GrafanaComRevision {
+		log.FromContext(ctx).Info("found revision of grafana.com dashboard", "dashboard", dashboard.Name, "id", reference.Id, "revision", latest)
+	}
+	status.GrafanaComRevision = latest
+	status.GrafanaComRevisionTime = &metav1.Time{Time: now}
+	return true, nil
+}
+
+// getLatestGrafanaComRevision returns the latest revision of a dashboard published on grafana.com
+func getLatestGrafanaComRevision(ctx context.Context, id int) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/dashboards/%d", config.GrafanaCloudAPIURL, id), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := dashboardHttpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("grafana.com returned status %d for dashboard %d", resp.StatusCode, id)
+	}
+
+	var published struct {
+		Revision int `json:"revision"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&published)
+	if err != nil {
+		return 0, err
+	}
+	if published.Revision <= 0 {
+		return 0, fmt.Errorf("grafana.com dashboard %d has no revision", id)
+	}
+	return published.Revision, nil
+}
+
+// fetchDashboardUrl refreshes the content cached in the status of a dashboard downloaded from a url,
+// grafana.com, object storage, an OCI artifact or a git repository. Cached content is revalidated with its ETag and Last-Modified once the cache duration passed,
+// and kept while the url is not reachable.
+func (r *GrafanaDashboardReconciler) fetchDashboardUrl(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard) error {
+	logger := log.FromContext(ctx)
+
+	if !isDashboardUrlSource(dashboard) {
+		return nil
+	}
+
+	status := &dashboard.Status
+	revisionChanged, err := resolveGrafanaComRevision(ctx, dashboard)
+	if err != nil {
+		if !hasCachedContent(dashboard) {
+			return err
+		}
+		logger.Info("error resolving grafana.com revision, using cached content", "dashboard", dashboard.Name, "error", err.Error())
+	}
+
+	url := getDashboardContentUrl(dashboard)
+	cached := hasCachedContent(dashboard)
+	if cached && status.ContentTimestamp != nil && time.Since(status.ContentTimestamp.Time) < getContentCacheDuration(dashboard) && !dashboard.ReconcileRequested() {
+		if revisionChanged {
+			return r.Status().Update(ctx, dashboard)
+		}
+		return nil
+	}
+
+	if isDashboardArtifactSource(dashboard) {
+		return r.fetchDashboardArtifact(ctx, dashboard, url, cached)
+	}
+	if isDashboardGitSource(dashboard) {
+		return r.fetchDashboardGit(ctx, dashboard, url, cached)
+	}
+
+	req, err := r.newDashboardContentRequest(ctx, dashboard, url)
+	if err != nil {
+		return err
+	}
+	if cached {
+		if status.ContentETag != "" {
+			req.Header.Set("If-None-Match", status.ContentETag)
+		}
+		if status.ContentLastModified != "" {
+			req.Header.Set("If-Modified-Since", status.ContentLastModified)
+		}
+	}
+
+	resp, err := dashboardHttpClient.Do(req)
+	if err != nil {
+		if cached {
+			logger.Info("dashboard url not reachable, using cached content", "dashboard", dashboard.Name, "error", err.Error())
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	now := metav1.Now()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		status.ContentTimestamp = &now
+	case resp.StatusCode == http.StatusOK:
+		content, err := io.ReadAll(io.LimitReader(resp.Body, maxDashboardUrlContentSize+1))
+		if err != nil {
+			return err
+		}
+		if len(content) > maxDashboardUrlContentSize {
+			return fmt.Errorf("dashboard url %s returned more than %d bytes", url, maxDashboardUrlContentSize)
+		}
+		content, err = gunzipContent(content)
+		if err != nil {
+			return fmt.Errorf("dashboard url %s: %w", url, err)
+		}
+		if !json.Valid(content) {
+			return fmt.Errorf("dashboard url %s returned invalid json", url)
+		}
+
+		compressed, err := gzipContent(content)
+		if err != nil {
+			return err
+		}
+		status.ContentCache = compressed
+		status.ContentUrl = url
+		status.ContentETag = resp.Header.Get("ETag")
+		status.ContentLastModified = resp.Header.Get("Last-Modified")
+		status.ContentTimestamp = &now
+	default:
+		err = fmt.Errorf("dashboard url %s returned status %d", url, resp.StatusCode)
+		if cached {
+			logger.Info("error downloading dashboard, using cached content", "dashboard", dashboard.Name, "error", err.Error())
+			return nil
+		}
+		return err
+	}
+
+	return r.Status().Update(ctx, dashboard)
+}
+
+// newDashboardContentRequest returns the request downloading the json of a dashboard, objects in a bucket
+// are requested from the api of the provider
+func (r *GrafanaDashboardReconciler) newDashboardContentRequest(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard, url string) (*http.Request, error) {
+	if dashboard.Spec.Url == "" && dashboard.Spec.GrafanaCom == nil {
+		credentials, err := getObjectStorageCredentials(ctx, r.Client, dashboard.Namespace, dashboard.Spec.ObjectStorage)
+		if err != nil {
+			return nil, err
+		}
+		return newObjectStorageRequest(ctx, credentials, dashboard.Spec.ObjectStorage)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// headers are only sent to the configured url
+	if dashboard.Spec.Url == "" {
+		return req, nil
+	}
+	for _, header := range dashboard.Spec.UrlHeaders {
+		value := header.Value
+		if header.ValueFrom != nil {
+			value, err = getReferencedValue(ctx, r.Client, dashboard.Namespace, *header.ValueFrom)
+			if err != nil {
+				return nil, fmt.Errorf("header %s: %w", header.Name, err)
+			}
+		}
+		req.Header.Set(header.Name, value)
+	}
+	return req, nil
+}
+
+// getCachedContent returns the json cached in the status of a dashboard downloaded from a url
+func getCachedContent(dashboard *grafanav1beta1.GrafanaDashboard) (string, error) {
+	if !hasCachedContent(dashboard) {
+		return "", fmt.Errorf("dashboard %s is not downloaded yet", dashboard.Name)
+	}
+
+	content, err := gunzipContent(dashboard.Status.ContentCache)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// isGzipped returns true if the content starts with the gzip magic number
+func isGzipped(content []byte) bool {
+	return len(content) > 2 && content[0] == 0x1f && content[1] == 0x8b
+}
+
+// gunzipContent decompresses gzipped content, other content is returned unchanged
+func gunzipContent(content []byte) ([]byte, error) {
+	if !isGzipped(content) {
+		return content, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxDashboardUrlContentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decompressed) > maxDashboardUrlContentSize {
+		return nil, fmt.Errorf("decompressed content is larger than %d bytes", maxDashboardUrlContentSize)
+	}
+	return decompressed, nil
+}
+
+func gzipContent(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(content)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// reasons of events about changes made in Grafana
+const (
+	eventDeleted        = "Deleted"
+	eventOrphaned       = "Orphaned"
+	eventPluginsChanged = "PluginsChanged"
+)
+
+// recordSyncEvent emits an event when the Synchronized condition of a cr changed, failures are reported as
+// warnings with the reason of the condition
+func recordSyncEvent(recorder record.EventRecorder, obj runtime.Object, previous []v1.Condition, conditions []v1.Condition) {
+	condition := meta.FindStatusCondition(conditions, conditionSynchronized)
+	if condition == nil {
+		return
+	}
+	last := meta.FindStatusCondition(previous, conditionSynchronized)
+	if last != nil && last.Status == condition.Status && last.Reason == condition.Reason && last.Message == condition.Message {
+		return
+	}
+
+	switch {
+	case condition.Status == v1.ConditionTrue:
+		recorder.Event(obj, v12.EventTypeNormal, condition.Reason, "synchronized with all matching instances")
+	case condition.Reason == reasonInProgress, condition.Reason == reasonDryRun, condition.Reason == reasonWaitingForDependencies:
+		recorder.Event(obj, v12.EventTypeNormal, condition.Reason, condition.Message)
+	default:
+		recorder.Event(obj, v12.EventTypeWarning, condition.Reason, condition.Message)
+	}
+}
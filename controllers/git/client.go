@@ -0,0 +1,379 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	uploadPackService = "git-upload-pack"
+	protocolHeader    = "Git-Protocol"
+	protocolVersion   = "version=2"
+	agent             = "grafana-operator"
+
+	// packs are read into memory
+	maxPackSize = 64 * 1024 * 1024
+	// ref advertisements and listings
+	maxResponseSize = 4 * 1024 * 1024
+)
+
+// trees of the last fetched commit of each repository, shared by the dashboards of a repository
+var (
+	treesMu sync.Mutex
+	trees   = map[string]*Tree{}
+)
+
+// Client fetches repositories over the smart http protocol version 2, the only transport available in
+// the distroless image of the operator
+type Client struct {
+	httpClient    *http.Client
+	repository    string
+	authorization string
+	advertised    bool
+}
+
+// NewClient returns a client of a repository like https://github.com/org/dashboards.git, the username and
+// password may be empty for public repositories. Tokens can be passed as password, the username then
+// defaults to git.
+func NewClient(httpClient *http.Client, repository string, username string, password string) (*Client, error) {
+	if !strings.HasPrefix(repository, "https://") && !strings.HasPrefix(repository, "http://") {
+		return nil, fmt.Errorf("unsupported repository %s, only http and https repositories are supported", repository)
+	}
+
+	c := &Client{
+		httpClient: httpClient,
+		repository: strings.TrimSuffix(repository, "/"),
+	}
+	if password != "" {
+		if username == "" {
+			username = "git"
+		}
+		c.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+	return c, nil
+}
+
+// Resolve returns the commit of a branch, tag, full ref name or commit sha, the default branch if the ref
+// is empty. The refs are listed for commit shas too, which verifies the credentials.
+func (c *Client) Resolve(ctx context.Context, ref string) (string, error) {
+	prefixes := []string{"HEAD"}
+	if ref != "" {
+		prefixes = []string{ref, "refs/heads/" + ref, "refs/tags/" + ref}
+	}
+
+	refs, err := c.listRefs(ctx, prefixes)
+	if err != nil {
+		return "", err
+	}
+	if ref == "" {
+		if commit, ok := refs["HEAD"]; ok {
+			return commit, nil
+		}
+		return "", fmt.Errorf("repository %s has no default branch", c.repository)
+	}
+	for _, prefix := range prefixes {
+		if commit, ok := refs[prefix]; ok {
+			return commit, nil
+		}
+	}
+	if isObjectID(ref) {
+		return strings.ToLower(ref), nil
+	}
+	return "", fmt.Errorf("repository %s has no branch or tag %s", c.repository, ref)
+}
+
+// Checkout returns the files of a commit, fetched without history. The tree of the last commit of a
+// repository is kept for the clients with the same credentials.
+func (c *Client) Checkout(ctx context.Context, commit string) (*Tree, error) {
+	key := c.cacheKey()
+
+	treesMu.Lock()
+	tree, ok := trees[key]
+	treesMu.Unlock()
+	if ok && tree.Commit == commit {
+		return tree, nil
+	}
+
+	pack, err := c.fetch(ctx, commit)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := readPack(pack)
+	if err != nil {
+		return nil, fmt.Errorf("pack of %s: %w", c.repository, err)
+	}
+	tree, err = newTree(objects, commit)
+	if err != nil {
+		return nil, fmt.Errorf("commit %s of %s: %w", commit, c.repository, err)
+	}
+
+	treesMu.Lock()
+	trees[key] = tree
+	treesMu.Unlock()
+	return tree, nil
+}
+
+// cacheKey identifies the repository and credentials of the client
+func (c *Client) cacheKey() string {
+	sum := sha256.Sum256([]byte(c.repository + "\n" + c.authorization))
+	return hex.EncodeToString(sum[:])
+}
+
+// listRefs returns the commits of the refs starting with the prefixes, annotated tags are peeled
+func (c *Client) listRefs(ctx context.Context, prefixes []string) (map[string]string, error) {
+	args := []string{"peel"}
+	for _, prefix := range prefixes {
+		args = append(args, "ref-prefix "+prefix)
+	}
+
+	resp, err := c.command(ctx, "ls-refs", args)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	refs := map[string]string{}
+	reader := newPktLineReader(io.LimitReader(resp, maxResponseSize))
+	for {
+		line, err := reader.next()
+		if err != nil {
+			return nil, fmt.Errorf("refs of %s: %w", c.repository, err)
+		}
+		if line == nil {
+			return refs, nil
+		}
+
+		fields := strings.Fields(string(line))
+		if len(fields) < 2 || !isObjectID(fields[0]) {
+			return nil, fmt.Errorf("refs of %s: invalid ref %q", c.repository, line)
+		}
+		commit := fields[0]
+		for _, attribute := range fields[2:] {
+			if strings.HasPrefix(attribute, "peeled:") {
+				commit = strings.TrimPrefix(attribute, "peeled:")
+			}
+		}
+		refs[fields[1]] = commit
+	}
+}
+
+// fetch returns the pack of a commit and its tree without history
+func (c *Client) fetch(ctx context.Context, commit string) ([]byte, error) {
+	resp, err := c.command(ctx, "fetch", []string{"want " + commit, "deepen 1", "no-progress", "ofs-delta", "done"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	reader := newPktLineReader(resp)
+	inPack := false
+	var pack bytes.Buffer
+	for {
+		line, err := reader.next()
+		if err == errDelimiter {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetch of %s: %w", c.repository, err)
+		}
+		if line == nil {
+			break
+		}
+
+		if !inPack {
+			inPack = string(line) == "packfile\n"
+			continue
+		}
+		if len(line) == 0 {
+			continue
+		}
+		switch line[0] {
+		case 1:
+			if pack.Len()+len(line)-1 > maxPackSize {
+				return nil, fmt.Errorf("pack of %s is larger than %d bytes", c.repository, maxPackSize)
+			}
+			pack.Write(line[1:])
+		case 3:
+			return nil, fmt.Errorf("fetch of %s: %s", c.repository, strings.TrimSpace(string(line[1:])))
+		}
+	}
+
+	if !inPack {
+		return nil, fmt.Errorf("repository %s returned no pack for %s", c.repository, commit)
+	}
+	return pack.Bytes(), nil
+}
+
+// command posts a command to the upload pack service, the capabilities are checked before the first one
+func (c *Client) command(ctx context.Context, command string, args []string) (io.ReadCloser, error) {
+	if !c.advertised {
+		err := c.advertise(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.advertised = true
+	}
+
+	var body bytes.Buffer
+	writePktLine(&body, "command="+command+"\n")
+	writePktLine(&body, "agent="+agent+"\n")
+	body.WriteString("0001")
+	for _, arg := range args {
+		writePktLine(&body, arg+"\n")
+	}
+	body.WriteString("0000")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.repository+"/"+uploadPackService, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-"+uploadPackService+"-request")
+	req.Header.Set("Accept", "application/x-"+uploadPackService+"-result")
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("repository %s returned status %d for %s", c.repository, resp.StatusCode, command)
+	}
+	return resp.Body, nil
+}
+
+// advertise checks that the repository talks protocol version 2, the repository url of redirected
+// requests is updated
+func (c *Client) advertise(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.repository+"/info/refs?service="+uploadPackService, nil)
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return fmt.Errorf("repository %s returned status %d, check the url and credentials", c.repository, resp.StatusCode)
+	default:
+		return fmt.Errorf("repository %s returned status %d", c.repository, resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Type") != "application/x-"+uploadPackService+"-advertisement" {
+		return fmt.Errorf("repository %s doesn't support the smart http protocol", c.repository)
+	}
+	c.repository = strings.TrimSuffix(strings.TrimSuffix(resp.Request.URL.String(), "?service="+uploadPackService), "/info/refs")
+
+	reader := newPktLineReader(io.LimitReader(resp.Body, maxResponseSize))
+	var capabilities []string
+	for {
+		line, err := reader.next()
+		if err != nil {
+			return fmt.Errorf("capabilities of %s: %w", c.repository, err)
+		}
+		if line == nil {
+			// servers send the service line and a flush before the capabilities
+			if len(capabilities) == 0 {
+				continue
+			}
+			break
+		}
+		if !strings.HasPrefix(string(line), "# service=") {
+			capabilities = append(capabilities, strings.TrimSuffix(string(line), "\n"))
+		}
+	}
+
+	if capabilities[0] != "version 2" {
+		return fmt.Errorf("repository %s doesn't support git protocol version 2", c.repository)
+	}
+	for _, capability := range capabilities[1:] {
+		if strings.HasPrefix(capability, "object-format=") && capability != "object-format=sha1" {
+			return fmt.Errorf("repository %s uses unsupported %s", c.repository, capability)
+		}
+		if strings.HasPrefix(capability, "fetch") && !strings.Contains(capability, "shallow") {
+			return fmt.Errorf("repository %s doesn't support shallow fetches", c.repository)
+		}
+	}
+	return nil
+}
+
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set(protocolHeader, protocolVersion)
+	req.Header.Set("User-Agent", "git/"+agent)
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+}
+
+var errDelimiter = errors.New("unexpected delimiter")
+
+// pktLineReader reads the length prefixed lines of the git protocol
+type pktLineReader struct {
+	reader io.Reader
+	header [4]byte
+}
+
+func newPktLineReader(reader io.Reader) *pktLineReader {
+	return &pktLineReader{reader: reader}
+}
+
+// next returns the next line, nil at a flush packet. Delimiter packets return errDelimiter and error lines
+// of the server an error.
+func (r *pktLineReader) next() ([]byte, error) {
+	_, err := io.ReadFull(r.reader, r.header[:])
+	if err != nil {
+		return nil, err
+	}
+	var length [2]byte
+	_, err = hex.Decode(length[:], r.header[:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid pkt-line length %q", r.header[:])
+	}
+
+	size := int(length[0])<<8 | int(length[1])
+	switch {
+	case size == 0:
+		return nil, nil
+	case size == 1:
+		return nil, errDelimiter
+	case size < 4:
+		return []byte{}, nil
+	}
+
+	line := make([]byte, size-4)
+	_, err = io.ReadFull(r.reader, line)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(line, []byte("ERR ")) {
+		return nil, errors.New(strings.TrimSpace(string(line[4:])))
+	}
+	return line, nil
+}
+
+func writePktLine(w *bytes.Buffer, line string) {
+	fmt.Fprintf(w, "%04x%s", len(line)+4, line)
+}
+
+// isObjectID returns true for hex sha1 object ids
+func isObjectID(value string) bool {
+	if len(value) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
@@ -0,0 +1,187 @@
+package git
+
+import (
+	"context"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestRepository serves a repository with two commits through git http-backend, the similar files of
+// the second commit are packed as deltas
+func newTestRepository(t *testing.T) (*httptest.Server, string, string) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "work")
+	run := func(args ...string) string {
+		cmd := exec.Command(gitPath, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name string, content string) {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run("init", "-q", "-b", "main")
+	panels := `{"title":"a","panels":[` + strings.Repeat(`{"type":"graph"},`, 200) + `{}]}`
+	write("dashboards/a.json", panels)
+	write("dashboards/nested/b.json", `{"title":"b"}`)
+	write("README.md", "dashboards")
+	run("add", "-A")
+	run("commit", "-q", "-m", "first")
+	first := run("rev-parse", "HEAD")
+	run("tag", "-a", "-m", "v1", "v1")
+
+	write("dashboards/a.json", strings.Replace(panels, `"a"`, `"a2"`, 1))
+	write("dashboards/c.json", strings.Replace(panels, `"a"`, `"c"`, 1))
+	run("add", "-A")
+	run("commit", "-q", "-m", "second")
+	second := run("rev-parse", "HEAD")
+	run("clone", "-q", "--bare", dir, filepath.Join(root, "dashboards.git"))
+
+	handler := &cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + root, "GIT_HTTP_EXPORT_ALL=1"},
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, first, second
+}
+
+func TestClient(t *testing.T) {
+	server, first, second := newTestRepository(t)
+	ctx := context.Background()
+
+	c, err := NewClient(http.DefaultClient, server.URL+"/dashboards.git", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ref, want := range map[string]string{
+		"":                second,
+		"main":            second,
+		"refs/heads/main": second,
+		"v1":              first,
+		first:             first,
+	} {
+		commit, err := c.Resolve(ctx, ref)
+		if err != nil {
+			t.Fatalf("ref %q: %v", ref, err)
+		}
+		if commit != want {
+			t.Errorf("ref %q resolved to %s, want %s", ref, commit, want)
+		}
+	}
+	_, err = c.Resolve(ctx, "missing")
+	if err == nil {
+		t.Error("missing ref resolved")
+	}
+
+	tree, err := c.Checkout(ctx, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFiles := []string{"README.md", "dashboards/a.json", "dashboards/c.json", "dashboards/nested/b.json"}
+	if files := tree.Files(); !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("files are %v, want %v", files, wantFiles)
+	}
+	content, err := tree.ReadFile("/dashboards/a.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), `{"title":"a2"`) {
+		t.Errorf("unexpected content %.20s", content)
+	}
+	_, err = tree.ReadFile("missing.json")
+	if err == nil {
+		t.Error("missing file read")
+	}
+
+	tree, err = c.Checkout(ctx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err = tree.ReadFile("dashboards/a.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), `{"title":"a"`) {
+		t.Errorf("unexpected content %.20s", content)
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	base := []byte("hello world")
+	tests := []struct {
+		name    string
+		delta   []byte
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "copy and insert",
+			// base size 11, result size 13, copy 6 bytes at 0, insert "grafana"
+			delta: append([]byte{11, 13, 0x90, 6, 7}, "grafana"...),
+			want:  "hello grafana",
+		},
+		{
+			name:  "copy with offset",
+			delta: []byte{11, 5, 0x91, 6, 5},
+			want:  "world",
+		},
+		{
+			name:    "wrong base size",
+			delta:   []byte{10, 5, 0x91, 6, 5},
+			wantErr: true,
+		},
+		{
+			name:    "copy beyond the base",
+			delta:   []byte{11, 5, 0x91, 8, 5},
+			wantErr: true,
+		},
+		{
+			name:    "wrong result size",
+			delta:   []byte{11, 6, 0x91, 6, 5},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := applyDelta(base, test.delta)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("applyDelta() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && string(got) != test.want {
+				t.Errorf("applyDelta() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
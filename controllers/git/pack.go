@@ -0,0 +1,400 @@
+package git
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1" // #nosec G505 git object ids are sha1
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	objectCommit   = 1
+	objectTree     = 2
+	objectBlob     = 3
+	objectTag      = 4
+	objectOfsDelta = 6
+	objectRefDelta = 7
+
+	// deltas of deltas are limited like in git
+	maxDeltaDepth = 50
+)
+
+var objectTypes = map[byte]string{
+	objectCommit: "commit",
+	objectTree:   "tree",
+	objectBlob:   "blob",
+	objectTag:    "tag",
+}
+
+type object struct {
+	kind byte
+	data []byte
+}
+
+// packEntry is an object of a pack, deltas are resolved against their base
+type packEntry struct {
+	kind       byte
+	data       []byte
+	baseOffset int
+	baseID     string
+	resolved   *object
+}
+
+// readPack returns the objects of a pack by their id, the checksum of the pack is verified
+func readPack(pack []byte) (map[string]*object, error) {
+	if len(pack) < 32 || string(pack[:4]) != "PACK" {
+		return nil, errors.New("invalid pack header")
+	}
+	body, checksum := pack[:len(pack)-20], pack[len(pack)-20:]
+	sum := sha1.Sum(body) // #nosec G401
+	if !bytes.Equal(sum[:], checksum) {
+		return nil, errors.New("invalid pack checksum")
+	}
+	version := readUint32(pack[4:8])
+	if version != 2 && version != 3 {
+		return nil, fmt.Errorf("unsupported pack version %d", version)
+	}
+	count := int(readUint32(pack[8:12]))
+
+	entries := map[int]*packEntry{}
+	offsets := make([]int, 0, count)
+	pos := 12
+	for i := 0; i < count; i++ {
+		entry, next, err := readPackEntry(body, pos)
+		if err != nil {
+			return nil, fmt.Errorf("object at offset %d: %w", pos, err)
+		}
+		entries[pos] = entry
+		offsets = append(offsets, pos)
+		pos = next
+	}
+
+	objects := map[string]*object{}
+	ids := map[string]*packEntry{}
+	// objects are resolved in the order of the pack, ref deltas may refer to bases resolved later
+	pending := offsets
+	for len(pending) > 0 {
+		var unresolved []int
+		for _, offset := range pending {
+			obj, err := resolveEntry(entries, ids, entries[offset], 0)
+			if err == errMissingBase {
+				unresolved = append(unresolved, offset)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("object at offset %d: %w", offset, err)
+			}
+			id := objectID(obj)
+			objects[id] = obj
+			ids[id] = entries[offset]
+		}
+		if len(unresolved) == len(pending) {
+			return nil, errors.New("pack contains deltas without base")
+		}
+		pending = unresolved
+	}
+	return objects, nil
+}
+
+var errMissingBase = errors.New("missing delta base")
+
+// resolveEntry returns the object of an entry, applying its deltas
+func resolveEntry(entries map[int]*packEntry, ids map[string]*packEntry, entry *packEntry, depth int) (*object, error) {
+	if entry.resolved != nil {
+		return entry.resolved, nil
+	}
+	if depth > maxDeltaDepth {
+		return nil, errors.New("delta chain is too long")
+	}
+
+	var base *packEntry
+	switch entry.kind {
+	case objectOfsDelta:
+		base = entries[entry.baseOffset]
+		if base == nil {
+			return nil, fmt.Errorf("no delta base at offset %d", entry.baseOffset)
+		}
+	case objectRefDelta:
+		base = ids[entry.baseID]
+		if base == nil {
+			return nil, errMissingBase
+		}
+	default:
+		if _, ok := objectTypes[entry.kind]; !ok {
+			return nil, fmt.Errorf("unsupported object type %d", entry.kind)
+		}
+		entry.resolved = &object{kind: entry.kind, data: entry.data}
+		return entry.resolved, nil
+	}
+
+	baseObject, err := resolveEntry(entries, ids, base, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := applyDelta(baseObject.data, entry.data)
+	if err != nil {
+		return nil, err
+	}
+	entry.resolved = &object{kind: baseObject.kind, data: data}
+	entry.data = nil
+	return entry.resolved, nil
+}
+
+// readPackEntry reads the header and inflates the data of the entry at pos, returning the position of the
+// next entry
+func readPackEntry(pack []byte, pos int) (*packEntry, int, error) {
+	start := pos
+	if pos >= len(pack) {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	c := pack[pos]
+	pos++
+	entry := &packEntry{kind: (c >> 4) & 7}
+	size := int(c & 15)
+	shift := 4
+	for c&0x80 != 0 {
+		if pos >= len(pack) || shift > 56 {
+			return nil, 0, errors.New("invalid object header")
+		}
+		c = pack[pos]
+		pos++
+		size |= int(c&0x7f) << shift
+		shift += 7
+	}
+
+	switch entry.kind {
+	case objectOfsDelta:
+		offset := 0
+		for i := 0; ; i++ {
+			if pos >= len(pack) || i > 8 {
+				return nil, 0, errors.New("invalid delta offset")
+			}
+			c = pack[pos]
+			pos++
+			if i > 0 {
+				offset++
+			}
+			offset = offset<<7 | int(c&0x7f)
+			if c&0x80 == 0 {
+				break
+			}
+		}
+		if offset <= 0 || offset > start {
+			return nil, 0, fmt.Errorf("invalid delta offset %d", offset)
+		}
+		entry.baseOffset = start - offset
+	case objectRefDelta:
+		if pos+20 > len(pack) {
+			return nil, 0, io.ErrUnexpectedEOF
+		}
+		entry.baseID = hex.EncodeToString(pack[pos : pos+20])
+		pos += 20
+	}
+
+	if size > maxPackSize {
+		return nil, 0, fmt.Errorf("object is larger than %d bytes", maxPackSize)
+	}
+	// bytes.Reader is a flate.Reader, the inflater doesn't read beyond the end of the stream
+	reader := bytes.NewReader(pack[pos:])
+	inflater, err := zlib.NewReader(reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	data := make([]byte, size)
+	_, err = io.ReadFull(inflater, data)
+	if err != nil {
+		return nil, 0, err
+	}
+	// reading to the end verifies the checksum of the stream
+	n, err := io.Copy(io.Discard, inflater)
+	if err != nil {
+		return nil, 0, err
+	}
+	if n > 0 {
+		return nil, 0, errors.New("object is larger than its header")
+	}
+	entry.data = data
+	return entry, len(pack) - reader.Len(), nil
+}
+
+// applyDelta applies the copy and insert instructions of a delta to its base
+func applyDelta(base []byte, delta []byte) ([]byte, error) {
+	baseSize, delta, err := readDeltaSize(delta)
+	if err != nil {
+		return nil, err
+	}
+	if baseSize != len(base) {
+		return nil, fmt.Errorf("delta of a base of %d bytes applied to %d bytes", baseSize, len(base))
+	}
+	size, delta, err := readDeltaSize(delta)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxPackSize {
+		return nil, fmt.Errorf("object is larger than %d bytes", maxPackSize)
+	}
+
+	result := make([]byte, 0, size)
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+
+		if op&0x80 == 0 {
+			if op == 0 || int(op) > len(delta) {
+				return nil, errors.New("invalid delta insert")
+			}
+			result = append(result, delta[:op]...)
+			delta = delta[op:]
+			continue
+		}
+
+		offset, length := 0, 0
+		for i := 0; i < 7; i++ {
+			if op&(1<<i) == 0 {
+				continue
+			}
+			if len(delta) == 0 {
+				return nil, errors.New("invalid delta copy")
+			}
+			if i < 4 {
+				offset |= int(delta[0]) << (8 * i)
+			} else {
+				length |= int(delta[0]) << (8 * (i - 4))
+			}
+			delta = delta[1:]
+		}
+		if length == 0 {
+			length = 0x10000
+		}
+		if offset+length > len(base) {
+			return nil, errors.New("delta copy beyond the base")
+		}
+		result = append(result, base[offset:offset+length]...)
+	}
+
+	if len(result) != size {
+		return nil, fmt.Errorf("delta result has %d bytes instead of %d", len(result), size)
+	}
+	return result, nil
+}
+
+// readDeltaSize reads a little endian base 128 size of a delta
+func readDeltaSize(delta []byte) (int, []byte, error) {
+	size, shift := 0, 0
+	for i, c := range delta {
+		if shift > 56 {
+			break
+		}
+		size |= int(c&0x7f) << shift
+		shift += 7
+		if c&0x80 == 0 {
+			return size, delta[i+1:], nil
+		}
+	}
+	return 0, nil, errors.New("invalid delta size")
+}
+
+func readUint32(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// objectID returns the sha1 of the header and data of an object
+func objectID(obj *object) string {
+	hash := sha1.New() // #nosec G401
+	hash.Write([]byte(objectTypes[obj.kind] + " " + strconv.Itoa(len(obj.data)) + "\x00"))
+	hash.Write(obj.data)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Tree holds the regular files of a commit
+type Tree struct {
+	Commit string
+	files  map[string][]byte
+}
+
+// newTree reads the files of a commit from the objects of a pack, submodules and symlinks are skipped
+func newTree(objects map[string]*object, commit string) (*Tree, error) {
+	obj, ok := objects[commit]
+	if !ok || obj.kind != objectCommit {
+		return nil, errors.New("the pack doesn't contain the commit")
+	}
+	header := strings.SplitN(string(obj.data), "\n", 2)[0]
+	if !strings.HasPrefix(header, "tree ") {
+		return nil, errors.New("the commit has no tree")
+	}
+
+	tree := &Tree{
+		Commit: commit,
+		files:  map[string][]byte{},
+	}
+	err := tree.read(objects, strings.TrimPrefix(header, "tree "), "")
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// read adds the files of a tree object and its subtrees below a directory
+func (t *Tree) read(objects map[string]*object, id string, dir string) error {
+	obj, ok := objects[id]
+	if !ok || obj.kind != objectTree {
+		return fmt.Errorf("the pack doesn't contain the tree of %s", dir+"/")
+	}
+
+	data := obj.data
+	for len(data) > 0 {
+		space := bytes.IndexByte(data, ' ')
+		if space < 0 {
+			return errors.New("invalid tree entry")
+		}
+		null := bytes.IndexByte(data[space:], 0)
+		if null < 0 || space+null+21 > len(data) {
+			return errors.New("invalid tree entry")
+		}
+		mode := string(data[:space])
+		name := path.Join(dir, string(data[space+1:space+null]))
+		entryID := hex.EncodeToString(data[space+null+1 : space+null+21])
+		data = data[space+null+21:]
+
+		switch {
+		case mode == "40000":
+			err := t.read(objects, entryID, name)
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(mode, "100"):
+			blob, ok := objects[entryID]
+			if !ok || blob.kind != objectBlob {
+				return fmt.Errorf("the pack doesn't contain the file %s", name)
+			}
+			t.files[name] = blob.data
+		}
+	}
+	return nil
+}
+
+// Files returns the sorted paths of the files
+func (t *Tree) Files() []string {
+	files := make([]string, 0, len(t.files))
+	for name := range t.files {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// ReadFile returns the content of a file, paths are relative to the root of the repository
+func (t *Tree) ReadFile(name string) ([]byte, error) {
+	content, ok := t.files[strings.TrimPrefix(path.Clean("/"+name), "/")]
+	if !ok {
+		return nil, fmt.Errorf("commit %s contains no file %s", t.Commit, name)
+	}
+	return content, nil
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/git"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+)
+
+// newGitClient returns a client of a repository with the username and password of its secret
+func newGitClient(ctx context.Context, k8sClient client.Client, namespace string, source *grafanav1beta1.GitRepositorySource) (*git.Client, error) {
+	var username, password string
+	if source.SecretRef != nil {
+		secret := &v1.Secret{}
+		err := k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      source.SecretRef.Name,
+		}, secret)
+		if err != nil {
+			return nil, fmt.Errorf("git repository secret: %w", err)
+		}
+		username = string(secret.Data[v1.BasicAuthUsernameKey])
+		password = string(secret.Data[v1.BasicAuthPasswordKey])
+	}
+	return git.NewClient(dashboardHttpClient, source.URL, username, password)
+}
+
+// checkoutGitRepository returns the files of the commit the ref of a repository points to
+func checkoutGitRepository(ctx context.Context, k8sClient client.Client, namespace string, source *grafanav1beta1.GitRepositorySource) (*git.Tree, error) {
+	gitClient, err := newGitClient(ctx, k8sClient, namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := gitClient.Resolve(ctx, source.Ref)
+	if err != nil {
+		return nil, err
+	}
+	return gitClient.Checkout(ctx, commit)
+}
+
+// listGitRepositoryKeys returns the paths of the json files of a repository matching the glob of the source
+func listGitRepositoryKeys(ctx context.Context, k8sClient client.Client, namespace string, source *grafanav1beta1.GitRepositorySource) ([]string, error) {
+	tree, err := checkoutGitRepository(ctx, k8sClient, namespace, source)
+	if err != nil {
+		return nil, err
+	}
+
+	glob := strings.TrimPrefix(source.Path, "/")
+	var keys []string
+	for _, file := range tree.Files() {
+		if !strings.HasSuffix(file, ".json") {
+			continue
+		}
+		if glob != "" {
+			matched, err := path.Match(glob, file)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %s: %w", source.Path, err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		keys = append(keys, file)
+	}
+	return keys, nil
+}
+
+// isDashboardGitSource returns true if the json of a dashboard is fetched from a git repository
+func isDashboardGitSource(dashboard *grafanav1beta1.GrafanaDashboard) bool {
+	spec := dashboard.Spec
+	return spec.Url == "" && spec.GrafanaCom == nil && spec.ObjectStorage == nil && spec.OCI == nil && spec.GitRepository != nil
+}
+
+// fetchDashboardGit refreshes the content of a dashboard fetched from a git repository, the file is only
+// fetched again when the ref points to another commit
+func (r *GrafanaDashboardReconciler) fetchDashboardGit(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard, url string, cached bool) error {
+	logger := log.FromContext(ctx)
+	status := &dashboard.Status
+	source := dashboard.Spec.GitRepository
+
+	if source.Path == "" {
+		return fmt.Errorf("the path of git repository %s must be set", source.URL)
+	}
+
+	gitClient, err := newGitClient(ctx, r.Client, dashboard.Namespace, source)
+	if err != nil {
+		return err
+	}
+	commit, err := gitClient.Resolve(ctx, source.Ref)
+	if err != nil {
+		if cached {
+			logger.Info("error resolving git ref, using cached content", "dashboard", dashboard.Name, "error", err.Error())
+			return nil
+		}
+		return err
+	}
+
+	now := metav1.Now()
+	if cached && status.ContentETag == commit {
+		status.ContentTimestamp = &now
+		return r.Status().Update(ctx, dashboard)
+	}
+
+	tree, err := gitClient.Checkout(ctx, commit)
+	if err != nil {
+		if cached {
+			logger.Info("error fetching git repository, using cached content", "dashboard", dashboard.Name, "error", err.Error())
+			return nil
+		}
+		return err
+	}
+	content, err := tree.ReadFile(source.Path)
+	if err != nil {
+		return err
+	}
+	if len(content) > maxDashboardUrlContentSize {
+		return fmt.Errorf("dashboard %s is larger than %d bytes", url, maxDashboardUrlContentSize)
+	}
+	if !json.Valid(content) {
+		return fmt.Errorf("dashboard %s contains invalid json", url)
+	}
+
+	compressed, err := gzipContent(content)
+	if err != nil {
+		return err
+	}
+	if status.ContentETag != commit {
+		logger.Info("fetched dashboard from git repository", "dashboard", dashboard.Name, "url", url, "commit", commit)
+	}
+	status.ContentCache = compressed
+	status.ContentUrl = url
+	status.ContentETag = commit
+	status.ContentLastModified = ""
+	status.ContentTimestamp = &now
+	return r.Status().Update(ctx, dashboard)
+}
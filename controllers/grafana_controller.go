@@ -18,20 +18,31 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-logr/logr"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/metrics"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers/grafana"
+	routev1 "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v12 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"reflect"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
 )
@@ -39,19 +50,31 @@ import (
 const (
 	RequeueDelaySuccess = 10 * time.Second
 	RequeueDelayError   = 10 * time.Second
+	// RequeueDelayDrift is the interval in which resources are compared to their state in Grafana
+	RequeueDelayDrift = 5 * time.Minute
 )
 
+// grafanaReferenceIndex indexes instances by the secrets and configmaps of their config, admin credentials,
+// smtp and database passwords, the secrets and configmaps owned by the instance are watched separately
+const grafanaReferenceIndex = ".spec.references"
+
 // GrafanaReconciler reconciles a Grafana object
 type GrafanaReconciler struct {
 	client.Client
 	Log       logr.Logger
 	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
 	Discovery discovery.DiscoveryInterface
+	// IsOpenShift is detected at startup, Routes are only created when the api is available
+	IsOpenShift bool
 }
 
 //+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanas,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanas/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanas/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
 
 func (r *GrafanaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	controllerLog := log.FromContext(ctx)
@@ -62,6 +85,7 @@ func (r *GrafanaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err != nil {
 		if errors.IsNotFound(err) {
 			controllerLog.Info("grafana cr has been deleted", "name", req.NamespacedName)
+			metrics.DeleteInstance(req.NamespacedName.String())
 			return ctrl.Result{}, nil
 		}
 
@@ -70,7 +94,8 @@ func (r *GrafanaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	var finished = true
-	stages := getInstallationStages()
+	var stageErr error
+	stages := getInstallationStages(grafana)
 	nextStatus := grafana.Status.DeepCopy()
 	vars := &grafanav1beta1.OperatorReconcileVars{}
 
@@ -89,6 +114,7 @@ func (r *GrafanaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		if err != nil {
 			controllerLog.Error(err, "reconciler error in stage", "stage", stage)
 			nextStatus.LastMessage = err.Error()
+			stageErr = err
 		} else {
 			nextStatus.LastMessage = ""
 		}
@@ -104,13 +130,28 @@ func (r *GrafanaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	if finished {
 		controllerLog.Info("grafana installation complete")
+		if vars.ConfigHash != "" {
+			nextStatus.ConfigHash = vars.ConfigHash
+		}
+		r.detectVersion(ctx, grafana, nextStatus)
+	}
+	if vars.PluginsChanged {
+		r.Recorder.Eventf(grafana, v12.EventTypeNormal, eventPluginsChanged, "restarting Grafana to install the plugins %s", vars.Plugins)
+		metrics.PluginRestarts.WithLabelValues(req.NamespacedName.String()).Inc()
 	}
 
+	err = r.countManagedResources(ctx, grafana, nextStatus)
+	if err != nil {
+		controllerLog.Error(err, "error counting resources of instance")
+	}
+	setGrafanaConditions(grafana, nextStatus, finished, stageErr)
+
 	return r.updateStatus(grafana, nextStatus)
 }
 
 func (r *GrafanaReconciler) updateStatus(cr *grafanav1beta1.Grafana, nextStatus *grafanav1beta1.GrafanaStatus) (ctrl.Result, error) {
 	if !reflect.DeepEqual(&cr.Status, nextStatus) {
+		recordSyncEvent(r.Recorder, cr, cr.Status.Conditions, nextStatus.Conditions)
 		nextStatus.DeepCopyInto(&cr.Status)
 		err := r.Client.Status().Update(context.Background(), cr)
 		if err != nil {
@@ -127,25 +168,218 @@ func (r *GrafanaReconciler) updateStatus(cr *grafanav1beta1.Grafana, nextStatus
 	}, nil
 }
 
+// detectVersion records the version reported by external instances and cloud stacks, the upgrade stage
+// sets the version of instances deployed by the operator
+func (r *GrafanaReconciler) detectVersion(ctx context.Context, cr *grafanav1beta1.Grafana, status *grafanav1beta1.GrafanaStatus) {
+	if !cr.IsExternal() && !cr.IsCloudStack() {
+		return
+	}
+	if cr.Status.AdminUrl == "" {
+		return
+	}
+
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, cr)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "error creating grafana client")
+		return
+	}
+	health, err := grafanaClient.GetHealth()
+	if err != nil {
+		log.FromContext(ctx).Info("error detecting grafana version", "error", err.Error())
+		return
+	}
+	status.Version = health.Version
+}
+
+// countManagedResources counts the dashboards and datasources imported into an instance without error, and
+// the folders synchronized into all of their instances
+func (r *GrafanaReconciler) countManagedResources(ctx context.Context, cr *grafanav1beta1.Grafana, status *grafanav1beta1.GrafanaStatus) error {
+	instance := fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)
+
+	var dashboards grafanav1beta1.GrafanaDashboardList
+	err := r.Client.List(ctx, &dashboards)
+	if err != nil {
+		return err
+	}
+	status.Dashboards = 0
+	for _, dashboard := range dashboards.Items {
+		if isAppliedTo(dashboard.Status.Instances, instance) {
+			status.Dashboards++
+		}
+	}
+	metrics.ManagedDashboards.WithLabelValues(instance).Set(float64(status.Dashboards))
+
+	var datasources grafanav1beta1.GrafanaDatasourceList
+	err = r.Client.List(ctx, &datasources)
+	if err != nil {
+		return err
+	}
+	status.Datasources = 0
+	for _, datasource := range datasources.Items {
+		if isAppliedTo(datasource.Status.Instances, instance) {
+			status.Datasources++
+		}
+	}
+
+	var folders grafanav1beta1.GrafanaFolderList
+	err = r.Client.List(ctx, &folders)
+	if err != nil {
+		return err
+	}
+	status.Folders = 0
+	for i, folder := range folders.Items {
+		if folder.Status.ObservedGeneration > 0 && instanceSelected(cr, &folders.Items[i], folder.Spec.InstanceSelector) {
+			status.Folders++
+		}
+	}
+	return nil
+}
+
+// isAppliedTo returns true if a resource was applied to an instance without error
+func isAppliedTo(statuses []grafanav1beta1.InstanceStatus, instance string) bool {
+	for _, status := range statuses {
+		if status.Instance == instance && status.LastApplied != nil && status.Error == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// setGrafanaConditions sets Ready and Synchronized once all stages finished, an unfinished stage is reported
+// as in progress
+func setGrafanaConditions(cr *grafanav1beta1.Grafana, status *grafanav1beta1.GrafanaStatus, finished bool, err error) {
+	condition := metav1.Condition{
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             reasonSynchronized,
+	}
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonStageFailed
+		condition.Message = fmt.Sprintf("stage %s: %s", status.Stage, err.Error())
+	case !finished:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonInProgress
+		condition.Message = fmt.Sprintf("stage %s is %s", status.Stage, status.StageStatus)
+	}
+	setReadyConditions(&status.Conditions, condition)
+}
+
+// getGrafanaReferenceKeys returns the index keys of the secrets and configmaps the operator reads the
+// settings of an instance from
+func getGrafanaReferenceKeys(grafana *grafanav1beta1.Grafana) []string {
+	sources := make([]grafanav1beta1.ValueFromSource, 0, len(grafana.Spec.ConfigValuesFrom)+2)
+	for _, value := range grafana.Spec.ConfigValuesFrom {
+		sources = append(sources, value.ValueFrom)
+	}
+	if grafana.Spec.SMTP != nil && grafana.Spec.SMTP.PasswordSecretRef != nil {
+		sources = append(sources, grafanav1beta1.ValueFromSource{SecretKeyRef: grafana.Spec.SMTP.PasswordSecretRef})
+	}
+	if grafana.Spec.Database != nil && grafana.Spec.Database.PasswordSecretRef != nil {
+		sources = append(sources, grafanav1beta1.ValueFromSource{SecretKeyRef: grafana.Spec.Database.PasswordSecretRef})
+	}
+
+	keys := getValueFromKeys(grafana.Namespace, sources...)
+	if grafana.Spec.AdminCredentials != nil && grafana.Spec.AdminCredentials.ExistingSecret != nil {
+		keys = append(keys, referenceKey(referenceSecret, grafana.Namespace, grafana.Spec.AdminCredentials.ExistingSecret.Name))
+	}
+	return keys
+}
+
+func newGrafanaList() client.ObjectList {
+	return &grafanav1beta1.GrafanaList{}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *GrafanaReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &grafanav1beta1.Grafana{}, grafanaReferenceIndex, func(object client.Object) []string {
+		grafana, ok := object.(*grafanav1beta1.Grafana)
+		if !ok {
+			return nil
+		}
+		return getGrafanaReferenceKeys(grafana)
+	})
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&grafanav1beta1.Grafana{}).
 		Owns(&v1.Deployment{}).
+		Owns(&v1.StatefulSet{}).
 		Owns(&v12.ConfigMap{}).
-		Complete(r)
+		Owns(&v12.Secret{}).
+		Owns(&v12.Service{}).
+		Owns(&networkingv1.Ingress{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Watches(&source.Kind{Type: &v12.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForReference(r.Client, newGrafanaList, grafanaReferenceIndex, referenceSecret))).
+		Watches(&source.Kind{Type: &v12.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForReference(r.Client, newGrafanaList, grafanaReferenceIndex, referenceConfigMap)))
+
+	if r.IsOpenShift {
+		builder = builder.Owns(&routev1.Route{})
+	}
+	return builder.Complete(newReconciler(mgr, "Grafana", r))
 }
 
-func getInstallationStages() []grafanav1beta1.OperatorStageName {
+// IsOpenShift returns true if the route api is served by the cluster
+func IsOpenShift(discoveryClient discovery.DiscoveryInterface) (bool, error) {
+	apiList, err := discoveryClient.ServerResourcesForGroupVersion(routev1.SchemeGroupVersion.String())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range apiList.APIResources {
+		if resource.Kind == "Route" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func getInstallationStages(cr *grafanav1beta1.Grafana) []grafanav1beta1.OperatorStageName {
+	// nothing is deployed for cloud stacks and external instances
+	if cr.IsCloudStack() {
+		return []grafanav1beta1.OperatorStageName{
+			grafanav1beta1.OperatorStageCloudStack,
+			grafanav1beta1.OperatorStagePlugins,
+		}
+	}
+
+	if cr.IsExternal() {
+		return []grafanav1beta1.OperatorStageName{
+			grafanav1beta1.OperatorStageExternal,
+			grafanav1beta1.OperatorStagePlugins,
+			grafanav1beta1.OperatorStageDiscovery,
+		}
+	}
+
 	return []grafanav1beta1.OperatorStageName{
 		grafanav1beta1.OperatorStageAdminUser,
+		grafanav1beta1.OperatorStageLdap,
+		grafanav1beta1.OperatorStageDatabase,
+		grafanav1beta1.OperatorStageHA,
+		grafanav1beta1.OperatorStageTLS,
+		grafanav1beta1.OperatorStageSMTP,
 		grafanav1beta1.OperatorStageGrafanaConfig,
 		grafanav1beta1.OperatorStagePvc,
 		grafanav1beta1.OperatorStageServiceAccount,
 		grafanav1beta1.OperatorStageService,
+		grafanav1beta1.OperatorStageServiceMonitor,
 		grafanav1beta1.OperatorStageIngress,
 		grafanav1beta1.OperatorStagePlugins,
+		grafanav1beta1.OperatorStageImageRenderer,
+		grafanav1beta1.OperatorStageUpgrade,
+		grafanav1beta1.OperatorStageExtraVolumes,
 		grafanav1beta1.OperatorStageDeployment,
+		grafanav1beta1.OperatorStagePDB,
+		grafanav1beta1.OperatorStageAutoscaling,
+		grafanav1beta1.OperatorStageDiscovery,
 	}
 }
 
@@ -155,6 +389,22 @@ func (r *GrafanaReconciler) getReconcilerForStage(stage grafanav1beta1.OperatorS
 		return grafana.NewConfigReconciler(r.Client)
 	case grafanav1beta1.OperatorStageAdminUser:
 		return grafana.NewAdminSecretReconciler(r.Client)
+	case grafanav1beta1.OperatorStageLdap:
+		return grafana.NewLdapReconciler(r.Client)
+	case grafanav1beta1.OperatorStageDatabase:
+		return grafana.NewDatabaseReconciler(r.Client)
+	case grafanav1beta1.OperatorStageHA:
+		return grafana.NewHAReconciler(r.Client)
+	case grafanav1beta1.OperatorStageTLS:
+		return grafana.NewTLSReconciler(r.Client)
+	case grafanav1beta1.OperatorStageServiceMonitor:
+		return grafana.NewServiceMonitorReconciler(r.Client, r.Discovery)
+	case grafanav1beta1.OperatorStagePDB:
+		return grafana.NewPodDisruptionBudgetReconciler(r.Client)
+	case grafanav1beta1.OperatorStageAutoscaling:
+		return grafana.NewAutoscalingReconciler(r.Client)
+	case grafanav1beta1.OperatorStageSMTP:
+		return grafana.NewSMTPReconciler(r.Client)
 	case grafanav1beta1.OperatorStagePvc:
 		return grafana.NewPvcReconciler(r.Client)
 	case grafanav1beta1.OperatorStageServiceAccount:
@@ -162,11 +412,23 @@ func (r *GrafanaReconciler) getReconcilerForStage(stage grafanav1beta1.OperatorS
 	case grafanav1beta1.OperatorStageService:
 		return grafana.NewServiceReconciler(r.Client)
 	case grafanav1beta1.OperatorStageIngress:
-		return grafana.NewIngressReconciler(r.Client, r.Discovery)
+		return grafana.NewIngressReconciler(r.Client, r.IsOpenShift)
 	case grafanav1beta1.OperatorStagePlugins:
-		return grafana.NewPluginsReconciler(r.Client)
+		return grafana.NewPluginsReconciler(r.Client, instanceSelected)
+	case grafanav1beta1.OperatorStageCloudStack:
+		return grafana.NewCloudStackReconciler(r.Client)
+	case grafanav1beta1.OperatorStageExternal:
+		return grafana.NewExternalReconciler(r.Client)
+	case grafanav1beta1.OperatorStageImageRenderer:
+		return grafana.NewImageRendererReconciler(r.Client)
+	case grafanav1beta1.OperatorStageUpgrade:
+		return grafana.NewUpgradeReconciler(r.Client)
+	case grafanav1beta1.OperatorStageExtraVolumes:
+		return grafana.NewExtraVolumesReconciler(r.Client)
 	case grafanav1beta1.OperatorStageDeployment:
 		return grafana.NewDeploymentReconciler(r.Client)
+	case grafanav1beta1.OperatorStageDiscovery:
+		return grafana.NewDatasourceDiscoveryReconciler(r.Client)
 	default:
 		return nil
 	}
@@ -0,0 +1,214 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaAnnotationReconciler reconciles a GrafanaAnnotation object
+type GrafanaAnnotationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaannotations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaannotations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaannotations/finalizers,verbs=update
+
+// Reconcile posts annotations to all matching Grafana instances and keeps them up to date
+func (r *GrafanaAnnotationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	annotation := &grafanav1beta1.GrafanaAnnotation{}
+	err := r.Get(ctx, req.NamespacedName, annotation)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana annotation cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana annotation cr")
+		return ctrl.Result{}, err
+	}
+
+	if annotation.GetDeletionTimestamp() != nil {
+		return r.onAnnotationDeleted(ctx, annotation)
+	}
+
+	// skip annotations without an instance selector
+	if annotation.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(annotation, grafanaFinalizer) {
+		controllerutil.AddFinalizer(annotation, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, annotation)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, annotation, annotation.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for annotation", "annotation", annotation.Name, "namespace", annotation.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	initialStatus := annotation.Status.DeepCopy()
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			err = r.reconcileAnnotation(grafanaClient, &grafana, annotation)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling annotation", "annotation", annotation.Name, "grafana", grafana.Name)
+		}
+	}
+
+	annotation.Status.LastMessage = ""
+	if lastErr != nil {
+		annotation.Status.LastMessage = lastErr.Error()
+	}
+	setSyncConditions(&annotation.Status.Conditions, annotation.Generation, len(instances.Items), complete, lastErr)
+	err = r.updateStatus(ctx, annotation, initialStatus)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaAnnotationReconciler) onAnnotationDeleted(ctx context.Context, annotation *grafanav1beta1.GrafanaAnnotation) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(annotation, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if annotation.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, annotation, annotation.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" || annotation.Status.GetAnnotationID(grafana.Name) == 0 {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteAnnotation(annotation.Status.GetAnnotationID(grafana.Name))
+		if err != nil {
+			controllerLog.Error(err, "error deleting annotation", "annotation", annotation.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(annotation, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, annotation)
+}
+
+// reconcileAnnotation creates the annotation once per instance and updates it afterwards, annotations are only
+// identified by the id returned on creation
+func (r *GrafanaAnnotationReconciler) reconcileAnnotation(grafanaClient client2.GrafanaClient, grafana *grafanav1beta1.Grafana, annotation *grafanav1beta1.GrafanaAnnotation) error {
+	id := annotation.Status.GetAnnotationID(grafana.Name)
+	if id != 0 {
+		err := grafanaClient.UpdateAnnotation(id, annotation)
+		if !client2.IsNotFound(err) {
+			return err
+		}
+		// the annotation was removed in grafana, create it again
+		r.forgetAnnotation(annotation, grafana.Name)
+	}
+
+	id, err := grafanaClient.CreateAnnotation(annotation)
+	if err != nil {
+		return err
+	}
+
+	annotation.Status.Annotations = append(annotation.Status.Annotations, grafanav1beta1.GrafanaAnnotationInstance{
+		Instance: grafana.Name,
+		ID:       id,
+	})
+	return nil
+}
+
+func (r *GrafanaAnnotationReconciler) forgetAnnotation(annotation *grafanav1beta1.GrafanaAnnotation, instance string) {
+	var annotations []grafanav1beta1.GrafanaAnnotationInstance
+	for _, item := range annotation.Status.Annotations {
+		if item.Instance != instance {
+			annotations = append(annotations, item)
+		}
+	}
+	annotation.Status.Annotations = annotations
+}
+
+// updateStatus writes the status of an annotation when it changed since the start of the reconcile
+func (r *GrafanaAnnotationReconciler) updateStatus(ctx context.Context, annotation *grafanav1beta1.GrafanaAnnotation, initialStatus *grafanav1beta1.GrafanaAnnotationStatus) error {
+	if equality.Semantic.DeepEqual(&annotation.Status, initialStatus) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, annotation, initialStatus.Conditions, annotation.Status.Conditions)
+	return r.Client.Status().Update(ctx, annotation)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaAnnotationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaAnnotation{}).
+		Complete(newReconciler(mgr, "GrafanaAnnotation", r))
+}
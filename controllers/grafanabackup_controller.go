@@ -0,0 +1,329 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/backup"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaBackupReconciler reconciles a GrafanaBackup object
+type GrafanaBackupReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Config   *rest.Config
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanabackups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanabackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanabackups/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile exports all matching Grafana instances into tarballs on the schedule of the backup
+func (r *GrafanaBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	grafanaBackup := &grafanav1beta1.GrafanaBackup{}
+	err := r.Get(ctx, req.NamespacedName, grafanaBackup)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana backup cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana backup cr")
+		return ctrl.Result{}, err
+	}
+
+	// skip backups without an instance selector
+	if grafanaBackup.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	status := grafanaBackup.Status.DeepCopy()
+
+	schedule, err := backup.ParseSchedule(grafanaBackup.Spec.Schedule)
+	if err != nil {
+		status.LastMessage = err.Error()
+		setSyncConditions(&status.Conditions, grafanaBackup.Generation, 0, false, err)
+		return ctrl.Result{}, r.updateStatus(ctx, grafanaBackup, status)
+	}
+
+	last := grafanaBackup.CreationTimestamp
+	if status.LastBackupTime != nil {
+		last = *status.LastBackupTime
+	}
+	next := schedule.Next(last.Time)
+	if next.IsZero() {
+		err = fmt.Errorf("schedule %s never runs", grafanaBackup.Spec.Schedule)
+		status.LastMessage = err.Error()
+		setSyncConditions(&status.Conditions, grafanaBackup.Generation, 0, false, err)
+		return ctrl.Result{}, r.updateStatus(ctx, grafanaBackup, status)
+	}
+
+	// the conditions report the last backup, a valid schedule is ready until the first backup ran
+	if meta.FindStatusCondition(status.Conditions, conditionSynchronized) == nil {
+		setReadyConditions(&status.Conditions, metav1.Condition{
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: grafanaBackup.Generation,
+			Reason:             reasonSynchronized,
+			Message:            "waiting for the first scheduled backup",
+		})
+	}
+
+	status.NextBackupTime = &metav1.Time{Time: next}
+	if grafanaBackup.Spec.Suspend {
+		status.NextBackupTime = nil
+		setSuspendedCondition(&status.Conditions, grafanaBackup.Generation, "no backups are scheduled until spec.suspend is removed")
+		return ctrl.Result{}, r.updateStatus(ctx, grafanaBackup, status)
+	}
+	meta.RemoveStatusCondition(&status.Conditions, conditionSuspended)
+
+	now := time.Now()
+	if now.Before(next) {
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, r.updateStatus(ctx, grafanaBackup, status)
+	}
+
+	storage, err := getBackupStorage(ctx, r.Client, grafanaBackup)
+	if err != nil {
+		status.LastMessage = err.Error()
+		setSyncConditions(&status.Conditions, grafanaBackup.Generation, 0, false, err)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, grafanaBackup, status)
+	}
+
+	// the writer pod has to be running before the tarballs can be streamed into the volume
+	if grafanaBackup.Spec.Storage.PVC != nil {
+		pod := model.GetBackupWriterPod(grafanaBackup, r.Scheme)
+		ready, err := ensureWriterPod(ctx, r.Client, pod, grafanaBackup.Spec.Storage.PVC.ClaimName)
+		if err != nil || !ready {
+			if err != nil {
+				status.LastMessage = err.Error()
+				setSyncConditions(&status.Conditions, grafanaBackup.Generation, 0, false, err)
+			}
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, grafanaBackup, status)
+		}
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, grafanaBackup, grafanaBackup.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for backup", "backup", grafanaBackup.Name, "namespace", grafanaBackup.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		var result *grafanav1beta1.GrafanaBackupInstance
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			result, err = r.backupInstance(ctx, grafanaClient, &grafana, grafanaBackup, storage, now)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error backing up grafana", "backup", grafanaBackup.Name, "grafana", grafana.Name)
+			continue
+		}
+		status.SetBackup(*result)
+	}
+
+	status.LastMessage = ""
+	if lastErr != nil {
+		status.LastMessage = lastErr.Error()
+	}
+	setSyncConditions(&status.Conditions, grafanaBackup.Generation, len(instances.Items), complete, lastErr)
+	if !complete {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, grafanaBackup, status)
+	}
+
+	if grafanaBackup.Spec.Storage.PVC != nil {
+		err = deleteWriterPod(ctx, r.Client, model.GetBackupWriterPod(grafanaBackup, nil))
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	status.LastBackupTime = &metav1.Time{Time: now}
+	next = schedule.Next(now)
+	status.NextBackupTime = &metav1.Time{Time: next}
+
+	err = r.updateStatus(ctx, grafanaBackup, status)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+	return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+}
+
+// getBackupStorage validates the storage of a backup and resolves the s3 credentials
+func getBackupStorage(ctx context.Context, k8sClient client.Client, grafanaBackup *grafanav1beta1.GrafanaBackup) (*backup.S3Target, error) {
+	storage := grafanaBackup.Spec.Storage
+	if (storage.PVC == nil) == (storage.S3 == nil) {
+		return nil, fmt.Errorf("exactly one of pvc or s3 storage must be set")
+	}
+
+	if storage.S3 == nil {
+		return nil, nil
+	}
+
+	secret := &v1.Secret{}
+	err := k8sClient.Get(ctx, client.ObjectKey{
+		Namespace: grafanaBackup.Namespace,
+		Name:      storage.S3.CredentialsSecretRef.Name,
+	}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	region := storage.S3.Region
+	if region == "" {
+		region = config.BackupDefaultS3Region
+	}
+
+	return &backup.S3Target{
+		Endpoint:        storage.S3.Endpoint,
+		Region:          region,
+		Bucket:          storage.S3.Bucket,
+		AccessKeyID:     string(secret.Data[config.BackupAccessKeyIDKey]),
+		SecretAccessKey: string(secret.Data[config.BackupSecretAccessKeyKey]),
+	}, nil
+}
+
+// backupInstance exports an instance and stores the tarball
+func (r *GrafanaBackupReconciler) backupInstance(ctx context.Context, grafanaClient client2.GrafanaClient, grafana *grafanav1beta1.Grafana, grafanaBackup *grafanav1beta1.GrafanaBackup, s3 *backup.S3Target, now time.Time) (*grafanav1beta1.GrafanaBackupInstance, error) {
+	files, err := grafanaClient.ExportBackup()
+	if err != nil {
+		return nil, err
+	}
+
+	tarball, err := backup.Archive(files, now)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s-%s-%s.tar.gz", grafanaBackup.Name, grafana.Name, now.UTC().Format("20060102T150405Z"))
+
+	var location string
+	if s3 != nil {
+		key := strings.TrimPrefix(path.Join(grafanaBackup.Spec.Storage.S3.Prefix, name), "/")
+		err = s3.PutObject(ctx, &http.Client{Timeout: time.Minute}, key, tarball)
+		location = fmt.Sprintf("s3://%s/%s", s3.Bucket, key)
+	} else {
+		pvc := grafanaBackup.Spec.Storage.PVC
+		file := path.Join(config.BackupMountPath, pvc.Path, name)
+		err = backup.WriteFile(r.Config, model.GetBackupWriterPod(grafanaBackup, nil), file, tarball)
+		location = fmt.Sprintf("pvc://%s/%s", pvc.ClaimName, strings.TrimPrefix(path.Join(pvc.Path, name), "/"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &grafanav1beta1.GrafanaBackupInstance{
+		Instance: grafana.Name,
+		Time:     metav1.Time{Time: now},
+		Size:     int64(len(tarball)),
+		Location: location,
+	}, nil
+}
+
+// ensureWriterPod creates a writer pod mounting a claim and returns true once it is running
+func ensureWriterPod(ctx context.Context, k8sClient client.Client, pod *v1.Pod, claimName string) (bool, error) {
+	err := k8sClient.Get(ctx, client.ObjectKey{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+	}, pod)
+
+	if errors.IsNotFound(err) {
+		pod.Spec = backup.WriterPodSpec(config.BackupWriterImage, claimName, config.BackupMountPath)
+		return false, k8sClient.Create(ctx, pod)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch pod.Status.Phase {
+	case v1.PodRunning:
+		return true, nil
+	case v1.PodSucceeded, v1.PodFailed:
+		// the writer pod only lives for a limited time, start a new one
+		return false, k8sClient.Delete(ctx, pod)
+	default:
+		return false, nil
+	}
+}
+
+func deleteWriterPod(ctx context.Context, k8sClient client.Client, pod *v1.Pod) error {
+	err := k8sClient.Delete(ctx, pod)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *GrafanaBackupReconciler) updateStatus(ctx context.Context, grafanaBackup *grafanav1beta1.GrafanaBackup, status *grafanav1beta1.GrafanaBackupStatus) error {
+	if equality.Semantic.DeepEqual(*status, grafanaBackup.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, grafanaBackup, grafanaBackup.Status.Conditions, status.Conditions)
+	grafanaBackup.Status = *status
+	return r.Client.Status().Update(ctx, grafanaBackup)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaBackup{}).
+		Owns(&v1.Pod{}).
+		Complete(newReconciler(mgr, "GrafanaBackup", r))
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// contactPointValuesFromIndex indexes contact points by the secrets and configmaps their values are injected from
+const contactPointValuesFromIndex = ".spec.valuesFrom"
+
+// GrafanaContactPointReconciler reconciles a GrafanaContactPoint object
+type GrafanaContactPointReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanacontactpoints,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanacontactpoints/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanacontactpoints/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
+
+// Reconcile provisions the contact point to all matching Grafana instances
+func (r *GrafanaContactPointReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	contactPoint := &grafanav1beta1.GrafanaContactPoint{}
+	err := r.Get(ctx, req.NamespacedName, contactPoint)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana contact point cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana contact point cr")
+		return ctrl.Result{}, err
+	}
+
+	if contactPoint.GetDeletionTimestamp() != nil {
+		return r.onContactPointDeleted(ctx, contactPoint)
+	}
+
+	// skip contact points without an instance selector
+	if contactPoint.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(contactPoint, grafanaFinalizer) {
+		controllerutil.AddFinalizer(contactPoint, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, contactPoint)
+	}
+
+	model, err := r.getContactPointModel(ctx, contactPoint)
+	if err != nil {
+		controllerLog.Error(err, "error resolving contact point settings", "contactPoint", contactPoint.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, contactPoint, 0, false, err)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, contactPoint, contactPoint.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for contact point", "contactPoint", contactPoint.Name, "namespace", contactPoint.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			err = grafanaClient.CreateOrUpdateContactPoint(model)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling contact point", "contactPoint", contactPoint.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, contactPoint, len(instances.Items), complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+// getContactPointModel builds the api representation of the contact point with all values from
+// secrets and config maps injected
+func (r *GrafanaContactPointReconciler) getContactPointModel(ctx context.Context, contactPoint *grafanav1beta1.GrafanaContactPoint) (*client2.GrafanaContactPoint, error) {
+	model := &client2.GrafanaContactPoint{
+		UID:                   contactPoint.ContactPointUID(),
+		Name:                  contactPoint.ContactPointName(),
+		Type:                  contactPoint.Spec.Type,
+		Settings:              json.RawMessage("{}"),
+		DisableResolveMessage: contactPoint.Spec.DisableResolveMessage,
+	}
+	if contactPoint.Spec.Settings != nil && len(contactPoint.Spec.Settings.Raw) > 0 {
+		model.Settings = contactPoint.Spec.Settings.Raw
+	}
+
+	if len(contactPoint.Spec.ValuesFrom) == 0 {
+		return model, nil
+	}
+
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err = applyValuesFrom(ctx, r.Client, contactPoint.Namespace, raw, contactPoint.Spec.ValuesFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &client2.GrafanaContactPoint{}
+	return resolved, json.Unmarshal(raw, resolved)
+}
+
+func (r *GrafanaContactPointReconciler) onContactPointDeleted(ctx context.Context, contactPoint *grafanav1beta1.GrafanaContactPoint) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(contactPoint, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if contactPoint.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, contactPoint, contactPoint.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteContactPoint(contactPoint.ContactPointUID())
+		if err != nil {
+			controllerLog.Error(err, "error deleting contact point", "contactPoint", contactPoint.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(contactPoint, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, contactPoint)
+}
+
+// updateStatus writes the last error and the conditions of the reconcile when they changed
+func (r *GrafanaContactPointReconciler) updateStatus(ctx context.Context, contactPoint *grafanav1beta1.GrafanaContactPoint, instances int, complete bool, lastErr error) error {
+	status := contactPoint.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, contactPoint.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(*status, contactPoint.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, contactPoint, contactPoint.Status.Conditions, status.Conditions)
+	contactPoint.Status = *status
+	return r.Client.Status().Update(ctx, contactPoint)
+}
+
+func newContactPointList() client.ObjectList {
+	return &grafanav1beta1.GrafanaContactPointList{}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaContactPointReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &grafanav1beta1.GrafanaContactPoint{}, contactPointValuesFromIndex, func(object client.Object) []string {
+		contactPoint, ok := object.(*grafanav1beta1.GrafanaContactPoint)
+		if !ok {
+			return nil
+		}
+		sources := make([]grafanav1beta1.ValueFromSource, 0, len(contactPoint.Spec.ValuesFrom))
+		for _, value := range contactPoint.Spec.ValuesFrom {
+			sources = append(sources, value.ValueFrom)
+		}
+		return getValueFromKeys(contactPoint.Namespace, sources...)
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaContactPoint{}).
+		Watches(&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForReference(r.Client, newContactPointList, contactPointValuesFromIndex, referenceSecret))).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForReference(r.Client, newContactPointList, contactPointValuesFromIndex, referenceConfigMap))).
+		Complete(newReconciler(mgr, "GrafanaContactPoint", r))
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaCorrelationReconciler reconciles a GrafanaCorrelation object
+type GrafanaCorrelationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanacorrelations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanacorrelations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanacorrelations/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes datasource correlations in all matching Grafana instances
+func (r *GrafanaCorrelationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	correlation := &grafanav1beta1.GrafanaCorrelation{}
+	err := r.Get(ctx, req.NamespacedName, correlation)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana correlation cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana correlation cr")
+		return ctrl.Result{}, err
+	}
+
+	if correlation.GetDeletionTimestamp() != nil {
+		return r.onCorrelationDeleted(ctx, correlation)
+	}
+
+	// skip correlations without an instance selector
+	if correlation.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(correlation, grafanaFinalizer) {
+		controllerutil.AddFinalizer(correlation, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, correlation)
+	}
+
+	sourceUID, err := getDatasourceUID(ctx, r.Client, correlation.Namespace, correlation.Spec.Source)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, correlation, correlation.Status.DeepCopy(), 0, false, fmt.Errorf("source: %w", err))
+	}
+
+	targetUID, err := getDatasourceUID(ctx, r.Client, correlation.Namespace, correlation.Spec.Target)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, correlation, correlation.Status.DeepCopy(), 0, false, fmt.Errorf("target: %w", err))
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, correlation, correlation.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for correlation", "correlation", correlation.Name, "namespace", correlation.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	initialStatus := correlation.Status.DeepCopy()
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			err = r.reconcileCorrelation(grafanaClient, &grafana, correlation, sourceUID, targetUID)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling correlation", "correlation", correlation.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, correlation, initialStatus, len(instances.Items), complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaCorrelationReconciler) onCorrelationDeleted(ctx context.Context, correlation *grafanav1beta1.GrafanaCorrelation) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(correlation, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if correlation.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, correlation, correlation.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		created := correlation.Status.GetCorrelation(grafana.Name)
+		if grafana.Status.AdminUrl == "" || created == nil {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteCorrelation(created.SourceUID, created.UID)
+		if err != nil {
+			controllerLog.Error(err, "error deleting correlation", "correlation", correlation.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(correlation, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, correlation)
+}
+
+// reconcileCorrelation creates or updates the correlation in an instance. Correlations belong to their source
+// datasource, a correlation is moved by deleting and recreating it when the source changes.
+func (r *GrafanaCorrelationReconciler) reconcileCorrelation(grafanaClient client2.GrafanaClient, grafana *grafanav1beta1.Grafana, correlation *grafanav1beta1.GrafanaCorrelation, sourceUID string, targetUID string) error {
+	created := correlation.Status.GetCorrelation(grafana.Name)
+	if created == nil {
+		correlation.Status.Correlations = append(correlation.Status.Correlations, grafanav1beta1.GrafanaCorrelationInstance{
+			Instance: grafana.Name,
+		})
+		created = &correlation.Status.Correlations[len(correlation.Status.Correlations)-1]
+	}
+
+	if created.UID != "" && created.SourceUID != sourceUID {
+		err := grafanaClient.DeleteCorrelation(created.SourceUID, created.UID)
+		if err != nil {
+			return err
+		}
+		created.UID = ""
+	}
+
+	uid, err := grafanaClient.CreateOrUpdateCorrelation(sourceUID, targetUID, created.UID, correlation)
+	if err != nil {
+		return err
+	}
+
+	created.SourceUID = sourceUID
+	created.UID = uid
+	return nil
+}
+
+// updateStatus writes the status of a correlation when it changed since the start of the reconcile
+func (r *GrafanaCorrelationReconciler) updateStatus(ctx context.Context, correlation *grafanav1beta1.GrafanaCorrelation, initialStatus *grafanav1beta1.GrafanaCorrelationStatus, instances int, complete bool, lastErr error) error {
+	correlation.Status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&correlation.Status.Conditions, correlation.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(&correlation.Status, initialStatus) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, correlation, initialStatus.Conditions, correlation.Status.Conditions)
+	return r.Client.Status().Update(ctx, correlation)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaCorrelationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaCorrelation{}).
+		Complete(newReconciler(mgr, "GrafanaCorrelation", r))
+}
@@ -17,13 +17,23 @@ limitations under the License.
 package controllers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"fmt"
 	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/tracing"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,10 +44,14 @@ import (
 	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
 )
 
+// dashboardReferenceIndex indexes dashboards by the secrets and configmaps of their content, envs and credentials
+const dashboardReferenceIndex = ".spec.references"
+
 // GrafanaDashboardReconciler reconciles a GrafanaDashboard object
 type GrafanaDashboardReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboards,verbs=get;list;watch;create;update;patch;delete
@@ -57,7 +71,9 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	controllerLog := log.FromContext(ctx)
 
 	dashboard := &grafanav1beta1.GrafanaDashboard{}
-	err := r.Get(ctx, req.NamespacedName, dashboard)
+	fetchCtx, span := tracing.Start(ctx, "fetch cr")
+	err := r.Get(fetchCtx, req.NamespacedName, dashboard)
+	span.End()
 
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -69,12 +85,43 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	if dashboard.GetDeletionTimestamp() != nil {
+		return r.onDashboardDeleted(ctx, dashboard)
+	}
+
 	// skip dashboards without an instance selector
 	if dashboard.Spec.InstanceSelector == nil {
 		return ctrl.Result{}, nil
 	}
 
-	instances, err := r.getMatchingInstances(ctx, dashboard.Spec.InstanceSelector)
+	if !controllerutil.ContainsFinalizer(dashboard, grafanaFinalizer) {
+		controllerutil.AddFinalizer(dashboard, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, dashboard)
+	}
+
+	// requested resyncs are not delayed by the backoff of failed attempts
+	if delay := getRetryDelay(dashboard.Status.SyncRetryStatus, dashboard.Status.Conditions, dashboard.Generation); delay > 0 && !dashboard.ReconcileRequested() {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	// the folder, datasources and library panels are imported first, the dashboard is reconciled again once
+	// they exist and are ready in an instance instead of failing
+	dependencies, missing, err := getDependencies(ctx, r.Client, dashboard)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(missing) > 0 {
+		controllerLog.Info("dashboard is waiting for its dependencies", "dashboard", dashboard.Name, "dependencies", missing)
+		return r.updateWaiting(ctx, dashboard, dashboard.Status.DeepCopy(), missing)
+	}
+
+	urlSource := isDashboardUrlSource(dashboard)
+	err = r.renderDashboard(ctx, dashboard)
+	if err != nil {
+		return r.updateError(ctx, dashboard, err)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, dashboard, dashboard.Spec.InstanceSelector)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -85,7 +132,39 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	controllerLog.Info("found matching Grafana instances", "count", len(instances.Items))
 
+	// dashboards without uid get a uid generated from the cr
+	uid := ""
+	contentHash := ""
+	if strings.TrimSpace(dashboard.Spec.Json) != "" {
+		uid, err = dashboard.DashboardUID()
+		if err == nil {
+			contentHash, err = client2.DashboardContentHash(dashboard)
+		}
+		if err != nil {
+			controllerLog.Error(err, "error reading dashboard uid", "dashboard", dashboard.Name)
+			return r.updateError(ctx, dashboard, err)
+		}
+	}
+
+	if dashboard.Spec.DryRun {
+		return r.reconcileDryRun(ctx, dashboard, instances, uid)
+	}
+
+	plugins := dashboard.Spec.Plugins
+	var derivedPlugins grafanav1beta1.PluginList
+	if dashboard.Spec.DerivePlugins {
+		derivedPlugins = deriveDashboardPlugins(dashboard)
+		plugins = append(append(grafanav1beta1.PluginList{}, plugins...), derivedPlugins...)
+	}
+
 	complete := true
+	var lastErr error
+	var pending []string
+	waiting := 0
+	status := dashboard.Status.DeepCopy()
+	status.Instances = nil
+	status.DryRun = nil
+	resyncPeriod := getResyncPeriod(dashboard.Spec.ResyncPolicy)
 
 	for _, grafana := range instances.Items {
 		// an admin url is required to interact with grafana
@@ -93,93 +172,612 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		if grafana.Status.AdminUrl == "" {
 			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
 			complete = false
+			status.Instances = appendInstanceStatus(status.Instances, dashboard.Status.Instances, &grafana, uid, errInstanceNotReady, resyncPeriod)
+			continue
+		}
+
+		// dashboards are imported again once the database migrations of an upgrade finished
+		if grafana.IsUpgrading() {
+			controllerLog.Info("grafana instance is upgrading", "grafana", grafana.Name)
+			complete = false
+			status.Instances = appendInstanceStatus(status.Instances, dashboard.Status.Instances, &grafana, uid, errInstanceUpgrading, resyncPeriod)
+			continue
+		}
+
+		// instances the dependencies are not imported into yet are skipped until they are ready
+		if instancePending := getPendingDependencies(dependencies, &grafana); len(instancePending) > 0 {
+			controllerLog.Info("dashboard is waiting for its dependencies", "dashboard", dashboard.Name, "grafana", grafana.Name, "dependencies", instancePending)
+			complete = false
+			waiting++
+			pending = appendPending(pending, instancePending)
+			status.Instances = appendInstanceStatus(status.Instances, dashboard.Status.Instances, &grafana, uid, fmt.Errorf("waiting for %s", strings.Join(instancePending, ", ")), resyncPeriod)
+			continue
+		}
+
+		// a uid claimed by another dashboard on the instance would be overwritten silently
+		err = checkDashboardConflicts(ctx, r.Client, &grafana, dashboard, uid)
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error checking dashboard uid", "dashboard", dashboard.Name, "grafana", grafana.Name)
+			status.Instances = appendInstanceStatus(status.Instances, dashboard.Status.Instances, &grafana, uid, err, resyncPeriod)
 			continue
 		}
 
 		// first reconcile the plugins
 		// append the requested dashboards to a configmap from where the
 		// grafana reconciler will pick them up
-		err = r.reconcilePlugins(ctx, &grafana, dashboard)
-		if err != nil {
+		pluginErr := ReconcilePlugins(ctx, r.Client, r.Scheme, &grafana, plugins, model.PluginsKindDashboard, dashboard)
+		if pluginErr != nil {
 			complete = false
-			controllerLog.Error(err, "error reconciling plugins", "dashboard", dashboard.Name, "grafana", grafana.Name)
+			lastErr = pluginErr
+			controllerLog.Error(pluginErr, "error reconciling plugins", "dashboard", dashboard.Name, "grafana", grafana.Name)
+		}
+
+		// the dashboard imported with a previous uid is removed first, Grafana rejects a second dashboard with
+		// the same title in a folder
+		if previousUID := getInstanceUID(dashboard.Status.Instances, &grafana); uid != "" && previousUID != "" && previousUID != uid {
+			err = r.removeDashboard(ctx, &grafana, dashboard, previousUID)
+			if err != nil {
+				complete = false
+				lastErr = err
+				controllerLog.Error(err, "error removing dashboard of the previous uid", "dashboard", dashboard.Name, "grafana", grafana.Name, "uid", previousUID)
+				status.Instances = appendInstanceStatus(status.Instances, dashboard.Status.Instances, &grafana, uid, err, resyncPeriod)
+				continue
+			}
 		}
 
 		// then import the dashboard into the matching grafana instances
-		err = r.reconcileDashboard(ctx, &grafana, dashboard)
+		result, adopted, err := r.reconcileDashboard(ctx, &grafana, dashboard)
+		if adopted != nil {
+			adopted.Instance = fmt.Sprintf("%s/%s", grafana.Namespace, grafana.Name)
+			status.Adopted = appendAdopted(status.Adopted, *adopted)
+			controllerLog.Info("adopted existing dashboard", "dashboard", dashboard.Name, "grafana", grafana.Name, "version", adopted.Version)
+		}
 		if err != nil {
 			complete = false
+			lastErr = err
 			controllerLog.Error(err, "error reconciling dashboard", "dashboard", dashboard.Name, "grafana", grafana.Name)
 		}
+		if result != client2.ApplyUnchanged {
+			r.Recorder.Eventf(dashboard, v1.EventTypeNormal, string(result), "%s dashboard %s in grafana %s/%s", strings.ToLower(string(result)), uid, grafana.Namespace, grafana.Name)
+		}
+		status.Instances = appendInstanceStatus(status.Instances, dashboard.Status.Instances, &grafana, uid, err, resyncPeriod)
+		// the uid of an imported dashboard is recorded even if its plugins failed, it is removed by that uid
+		if err == nil && pluginErr != nil {
+			status.Instances[len(status.Instances)-1].Error = pluginErr.Error()
+		}
+	}
+
+	// waiting for the dependencies of every instance is not a failed attempt
+	status.MatchedInstances = len(instances.Items)
+	if waiting > 0 && waiting == len(instances.Items) {
+		return r.updateWaiting(ctx, dashboard, status, pending)
+	}
+
+	status.LastMessage = getLastMessage(lastErr)
+	if lastErr == nil && len(pending) > 0 {
+		status.LastMessage = fmt.Sprintf("waiting for %s", strings.Join(pending, ", "))
+	}
+	setSyncConditions(&status.Conditions, dashboard.Generation, len(instances.Items), complete, lastErr)
+	status.LastSynced = getLastSynced(status.Instances)
+	retryDelay := setSyncRetry(&status.SyncRetryStatus, lastErr)
+	status.DerivedPlugins = derivedPlugins
+	if len(derivedPlugins) == 0 {
+		status.DerivedPlugins = nil
+	}
+	// the uid is claimed once the dashboard was imported into any instance, the instances record the uid
+	// they were imported with
+	if complete || isAppliedWith(status.Instances, uid) {
+		status.UID = uid
+	}
+	if complete {
+		status.ContentHash = contentHash
+	}
+	err = r.updateStatus(ctx, dashboard, status)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
 	}
 
 	// another reconcile needed?
 	if complete {
+		// drift in grafana is repaired after the resync period, content of a url is checked for changes
+		// once the cache duration passed
+		if urlSource && getContentCacheDuration(dashboard) < resyncPeriod {
+			resyncPeriod = getContentCacheDuration(dashboard)
+		}
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: retryDelay}, nil
+}
+
+// reconcileDryRun reports the changes the dashboard would make in the matching instances in the status,
+// neither the dashboards nor the plugins of the instances are changed
+func (r *GrafanaDashboardReconciler) reconcileDryRun(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard, instances grafanav1beta1.GrafanaList, uid string) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	complete := true
+	var lastErr error
+	status := dashboard.Status.DeepCopy()
+	status.DryRun = nil
+	for i := range instances.Items {
+		grafana := &instances.Items[i]
+		if grafana.Status.AdminUrl == "" {
+			complete = false
+			status.DryRun = append(status.DryRun, newDryRunResult(grafana, client2.ApplyUnchanged, nil, errInstanceNotReady))
+			continue
+		}
+
+		action, changes, err := r.diffDashboard(ctx, grafana, dashboard, uid)
+		if err != nil {
+			lastErr = err
+			controllerLog.Error(err, "error comparing dashboard", "dashboard", dashboard.Name, "grafana", grafana.Name)
+		}
+		status.DryRun = append(status.DryRun, newDryRunResult(grafana, action, changes, err))
+	}
+
+	status.LastMessage = getLastMessage(lastErr)
+	setDryRunConditions(&status.Conditions, dashboard.Generation, status.DryRun, complete, lastErr)
+	status.MatchedInstances = len(instances.Items)
+	retryDelay := setSyncRetry(&status.SyncRetryStatus, lastErr)
+	err := r.updateStatus(ctx, dashboard, status)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+	if complete && lastErr == nil {
+		return ctrl.Result{RequeueAfter: getResyncPeriod(dashboard.Spec.ResyncPolicy)}, nil
+	}
+	return ctrl.Result{RequeueAfter: retryDelay}, nil
+}
+
+// diffDashboard compares a dashboard to the dashboard in an instance, including the checks made before it
+// is imported
+func (r *GrafanaDashboardReconciler) diffDashboard(ctx context.Context, grafana *grafanav1beta1.Grafana, dashboard *grafanav1beta1.GrafanaDashboard, uid string) (client2.ApplyResult, []string, error) {
+	if uid == "" {
+		return client2.ApplyUnchanged, nil, nil
+	}
+
+	err := checkDashboardConflicts(ctx, r.Client, grafana, dashboard, uid)
+	if err != nil {
+		return client2.ApplyUnchanged, nil, err
+	}
+
+	folderUID, err := getFolderUID(ctx, r.Client, dashboard.Namespace, dashboard.Spec.FolderRef, dashboard.Spec.OrgReference)
+	if err != nil {
+		return client2.ApplyUnchanged, nil, err
+	}
+
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
+	if err == nil {
+		grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, dashboard.Namespace, dashboard.Spec.OrgReference)
+	}
+	if err != nil {
+		return client2.ApplyUnchanged, nil, err
+	}
+	action, changes, err := grafanaClient.DiffDashboard(dashboard, folderUID)
+	// the dashboard of the previous uid is removed on import
+	if previousUID := getInstanceUID(dashboard.Status.Instances, grafana); err == nil && previousUID != "" && previousUID != uid {
+		changes = append([]string{fmt.Sprintf("uid: %s -> %s", previousUID, uid)}, changes...)
+	}
+	return action, changes, err
+}
+
+// renderDashboard downloads or loads the json of a dashboard and substitutes its inputs and envs
+func (r *GrafanaDashboardReconciler) renderDashboard(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard) error {
+	controllerLog := log.FromContext(ctx)
+	ctx, span := tracing.Start(ctx, "render content")
+	defer span.End()
+
+	err := r.fetchDashboardUrl(ctx, dashboard)
+	if err != nil {
+		controllerLog.Error(err, "error downloading dashboard json", "dashboard", dashboard.Name, "url", dashboard.Spec.Url)
+		span.SetError(err)
+		return err
+	}
+
+	// dashboards are reconciled again when the referenced configmap changes
+	err = loadDashboardJson(ctx, r.Client, dashboard)
+	if err != nil {
+		controllerLog.Error(err, "error loading dashboard json", "dashboard", dashboard.Name)
+		span.SetError(err)
+		return err
+	}
+
+	err = substituteDashboardInputs(ctx, r.Client, dashboard)
+	if err != nil {
+		controllerLog.Error(err, "error substituting dashboard inputs", "dashboard", dashboard.Name)
+		span.SetError(err)
+		return err
+	}
+
+	err = substituteDashboardEnv(ctx, r.Client, dashboard)
+	if err != nil {
+		controllerLog.Error(err, "error substituting dashboard envs", "dashboard", dashboard.Name)
+		span.SetError(err)
+	}
+	return err
+}
+
+// onDashboardDeleted removes, orphans or retains the dashboard in the instances it was imported into
+// according to its deletion policy, the finalizer is removed once every instance is cleaned up
+func (r *GrafanaDashboardReconciler) onDashboardDeleted(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(dashboard, grafanaFinalizer) {
 		return ctrl.Result{}, nil
 	}
 
-	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+	// dashboards are only known to Grafana by the uid they were imported with, dashboards in dry run are
+	// left unchanged like retained dashboards
+	if dashboard.Spec.DeletionPolicy != grafanav1beta1.DeletionPolicyRetain && !dashboard.Spec.DryRun {
+		var lastErr error
+		var remaining []grafanav1beta1.InstanceStatus
+		for _, instance := range dashboard.Status.Instances {
+			if instance.UID == "" {
+				continue
+			}
+
+			// deleted instances have nothing left to clean up
+			grafana, err := getRecordedInstance(ctx, r.Client, instance)
+			if err == nil && grafana != nil {
+				if grafana.Status.AdminUrl == "" {
+					err = errInstanceNotReady
+				} else {
+					err = r.removeDashboard(ctx, grafana, dashboard, instance.UID)
+				}
+			}
+			if err != nil {
+				controllerLog.Error(err, "error deleting dashboard", "dashboard", dashboard.Name, "grafana", instance.Instance)
+				lastErr = err
+				remaining = append(remaining, instance)
+			}
+		}
+		// instances that were cleaned up are not repeated on retries
+		if lastErr != nil {
+			status := dashboard.Status.DeepCopy()
+			status.Instances = remaining
+			err := r.updateStatus(ctx, dashboard, status)
+			if err != nil {
+				return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+			}
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, lastErr
+		}
+	}
+
+	controllerutil.RemoveFinalizer(dashboard, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, dashboard)
 }
 
-func (r *GrafanaDashboardReconciler) reconcileDashboard(ctx context.Context, grafana *grafanav1beta1.Grafana, dashboard *grafanav1beta1.GrafanaDashboard) error {
-	if strings.TrimSpace(dashboard.Spec.Json) == "" {
+// removeDashboard deletes or orphans the dashboard of a uid in an instance according to the deletion policy,
+// dashboards another cr imported with the uid since are left alone
+func (r *GrafanaDashboardReconciler) removeDashboard(ctx context.Context, grafana *grafanav1beta1.Grafana, dashboard *grafanav1beta1.GrafanaDashboard, uid string) error {
+	policy := dashboard.Spec.DeletionPolicy
+	if policy == grafanav1beta1.DeletionPolicyRetain {
 		return nil
 	}
 
-	_, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
-	if err != nil {
+	other, err := getDashboardClaim(ctx, r.Client, grafana, dashboard, uid)
+	if err != nil || other != nil {
 		return err
 	}
 
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
+	if err == nil {
+		grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, dashboard.Namespace, dashboard.Spec.OrgReference)
+	}
+	if err == nil {
+		if policy == grafanav1beta1.DeletionPolicyOrphan {
+			err = grafanaClient.OrphanDashboard(uid)
+		} else {
+			err = grafanaClient.DeleteDashboard(uid)
+		}
+	}
+	if err != nil {
+		r.Recorder.Eventf(dashboard, v1.EventTypeWarning, reasonGrafanaAPIError, "error deleting dashboard %s from grafana %s/%s: %s", uid, grafana.Namespace, grafana.Name, err.Error())
+		return err
+	}
+	if policy == grafanav1beta1.DeletionPolicyOrphan {
+		r.Recorder.Eventf(dashboard, v1.EventTypeNormal, eventOrphaned, "orphaned dashboard %s in grafana %s/%s", uid, grafana.Namespace, grafana.Name)
+	} else {
+		r.Recorder.Eventf(dashboard, v1.EventTypeNormal, eventDeleted, "deleted dashboard %s from grafana %s/%s", uid, grafana.Namespace, grafana.Name)
+	}
 	return nil
 }
 
-func (r *GrafanaDashboardReconciler) reconcilePlugins(ctx context.Context, grafana *grafanav1beta1.Grafana, dashboard *grafanav1beta1.GrafanaDashboard) error {
-	if dashboard.Spec.Plugins == nil || len(dashboard.Spec.Plugins) == 0 {
+// updateStatus writes the status of a dashboard when it changed
+func (r *GrafanaDashboardReconciler) updateStatus(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard, status *grafanav1beta1.GrafanaDashboardStatus) error {
+	// a reconcile request is handled by any reconcile, failed attempts are retried with backoff
+	status.LastHandledReconcileAt = dashboard.Annotations[grafanav1beta1.ReconcileRequestAnnotation]
+	if equality.Semantic.DeepEqual(&dashboard.Status, status) {
 		return nil
 	}
+	recordSyncEvent(r.Recorder, dashboard, dashboard.Status.Conditions, status.Conditions)
+	dashboard.Status = *status
+	return r.Client.Status().Update(ctx, dashboard)
+}
+
+// updateError records an error that stopped the reconcile before the dashboard was imported into any instance
+func (r *GrafanaDashboardReconciler) updateError(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard, err error) (ctrl.Result, error) {
+	status := dashboard.Status.DeepCopy()
+	status.LastMessage = err.Error()
+	setSyncConditions(&status.Conditions, dashboard.Generation, 0, false, err)
+	retryDelay := setSyncRetry(&status.SyncRetryStatus, err)
+	return ctrl.Result{RequeueAfter: retryDelay}, r.updateStatus(ctx, dashboard, status)
+}
 
-	pluginsConfigMap := model.GetPluginsConfigMap(grafana, r.Scheme)
-	selector := client.ObjectKey{
-		Namespace: pluginsConfigMap.Namespace,
-		Name:      pluginsConfigMap.Name,
+// checkDashboardConflicts returns an error if another dashboard imported into the same organization of an
+// instance claimed the uid before
+func checkDashboardConflicts(ctx context.Context, k8sClient client.Client, grafana *grafanav1beta1.Grafana, dashboard *grafanav1beta1.GrafanaDashboard, uid string) error {
+	other, err := getDashboardClaim(ctx, k8sClient, grafana, dashboard, uid)
+	if err != nil || other == nil {
+		return err
 	}
+	return fmt.Errorf("uid %s is already used by dashboard %s/%s on grafana %s", uid, other.Namespace, other.Name, grafana.Name)
+}
 
-	err := r.Client.Get(ctx, selector, pluginsConfigMap)
+// getDashboardClaim returns another dashboard imported with the uid into the same organization of an instance,
+// the uid recorded for the instance is checked as well as the uid of dashboards that select the instance
+func getDashboardClaim(ctx context.Context, k8sClient client.Client, grafana *grafanav1beta1.Grafana, dashboard *grafanav1beta1.GrafanaDashboard, uid string) (*grafanav1beta1.GrafanaDashboard, error) {
+	if uid == "" {
+		return nil, nil
+	}
+
+	var list grafanav1beta1.GrafanaDashboardList
+	err := k8sClient.List(ctx, &list)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	org := getOrgKey(dashboard.Namespace, dashboard.Spec.OrgReference)
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.UID == dashboard.UID || other.DeletionTimestamp != nil || getOrgKey(other.Namespace, other.Spec.OrgReference) != org {
+			continue
+		}
+		if getInstanceUID(other.Status.Instances, grafana) == uid || (other.Status.UID == uid && instanceSelected(grafana, other, other.Spec.InstanceSelector)) {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// getOrgKey identifies the organization a cr is imported into, organization crs are local to the namespace
+func getOrgKey(namespace string, reference grafanav1beta1.OrgReference) string {
+	if reference.OrgRef != "" {
+		return fmt.Sprintf("%s/%s", namespace, reference.OrgRef)
+	}
+	if reference.OrgID != nil && *reference.OrgID != 1 {
+		return fmt.Sprintf("%d", *reference.OrgID)
+	}
+	return ""
+}
+
+// reconcileDashboard imports a dashboard into an instance and returns the existing dashboard if it was adopted
+func (r *GrafanaDashboardReconciler) reconcileDashboard(ctx context.Context, grafana *grafanav1beta1.Grafana, dashboard *grafanav1beta1.GrafanaDashboard) (client2.ApplyResult, *grafanav1beta1.AdoptedResource, error) {
+	if strings.TrimSpace(dashboard.Spec.Json) == "" {
+		return client2.ApplyUnchanged, nil, nil
 	}
 
-	val, err := json.Marshal(dashboard.Spec.Plugins.Sanitize())
+	folderUID, err := getFolderUID(ctx, r.Client, dashboard.Namespace, dashboard.Spec.FolderRef, dashboard.Spec.OrgReference)
 	if err != nil {
-		return err
+		return client2.ApplyUnchanged, nil, err
 	}
 
-	if pluginsConfigMap.BinaryData == nil {
-		pluginsConfigMap.BinaryData = make(map[string][]byte)
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
+	if err != nil {
+		return client2.ApplyUnchanged, nil, err
 	}
 
-	if bytes.Compare(val, pluginsConfigMap.BinaryData[dashboard.Name]) != 0 {
-		pluginsConfigMap.BinaryData[dashboard.Name] = val
-		return r.Client.Update(ctx, pluginsConfigMap)
+	grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, dashboard.Namespace, dashboard.Spec.OrgReference)
+	if err != nil {
+		return client2.ApplyUnchanged, nil, err
 	}
 
+	// library panels have to exist in the organization before a dashboard using them is imported
+	err = r.reconcileLibraryPanels(ctx, grafanaClient, dashboard)
+	if err != nil {
+		return client2.ApplyUnchanged, nil, err
+	}
+
+	result, adopted, err := grafanaClient.CreateOrUpdateDashboard(dashboard, folderUID)
+	if err != nil || !dashboard.Spec.IsHomeDashboard {
+		return result, adopted, err
+	}
+
+	managed, err := r.isHomeDashboardManaged(ctx, grafana, dashboard)
+	if err != nil || managed {
+		return result, adopted, err
+	}
+	uid, err := dashboard.DashboardUID()
+	if err != nil {
+		return result, adopted, err
+	}
+	return result, adopted, grafanaClient.SetOrgHomeDashboard(uid)
+}
+
+// isHomeDashboardManaged returns true if a GrafanaPreferences sets the home dashboard of the organization a
+// dashboard is imported into
+func (r *GrafanaDashboardReconciler) isHomeDashboardManaged(ctx context.Context, grafana *grafanav1beta1.Grafana, dashboard *grafanav1beta1.GrafanaDashboard) (bool, error) {
+	var list grafanav1beta1.GrafanaPreferencesList
+	err := r.Client.List(ctx, &list)
+	if err != nil {
+		return false, err
+	}
+
+	org := getOrgKey(dashboard.Namespace, dashboard.Spec.OrgReference)
+	for _, preferences := range list.Items {
+		if preferences.Spec.HomeDashboardRef == "" && preferences.Spec.HomeDashboardUID == "" {
+			continue
+		}
+		if instanceSelected(grafana, &preferences, preferences.Spec.InstanceSelector) && getOrgKey(preferences.Namespace, preferences.Spec.OrgReference) == org {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reconcileLibraryPanels imports the library panels of a dashboard with the client of its organization
+func (r *GrafanaDashboardReconciler) reconcileLibraryPanels(ctx context.Context, grafanaClient client2.GrafanaClient, dashboard *grafanav1beta1.GrafanaDashboard) error {
+	for _, ref := range dashboard.Spec.LibraryPanelRefs {
+		panel := &grafanav1beta1.GrafanaLibraryPanel{}
+		err := r.Client.Get(ctx, client.ObjectKey{
+			Namespace: dashboard.Namespace,
+			Name:      ref,
+		}, panel)
+		if err != nil {
+			return fmt.Errorf("library panel %s: %w", ref, err)
+		}
+		if getOrgKey(panel.Namespace, panel.Spec.OrgReference) != getOrgKey(dashboard.Namespace, dashboard.Spec.OrgReference) {
+			return fmt.Errorf("library panel %s is imported into another organization than the dashboard", ref)
+		}
+
+		err = loadLibraryPanelJson(ctx, r.Client, panel)
+		if err != nil {
+			return fmt.Errorf("library panel %s: %w", ref, err)
+		}
+
+		folderUID, err := getFolderUID(ctx, r.Client, panel.Namespace, panel.Spec.FolderRef, panel.Spec.OrgReference)
+		if err != nil {
+			return err
+		}
+
+		err = grafanaClient.CreateOrUpdateLibraryPanel(panel, folderUID)
+		if err != nil {
+			return fmt.Errorf("library panel %s: %w", ref, err)
+		}
+	}
 	return nil
 }
 
-func (r *GrafanaDashboardReconciler) getMatchingInstances(ctx context.Context, labelSelector *v1.LabelSelector) (grafanav1beta1.GrafanaList, error) {
-	var list grafanav1beta1.GrafanaList
-	opts := []client.ListOption{
-		client.MatchingLabels(labelSelector.MatchLabels),
+// requestsForUpgradedInstance returns the dashboards selecting an instance, they are imported again after an
+// upgrade of the instance
+func (r *GrafanaDashboardReconciler) requestsForUpgradedInstance(object client.Object) []reconcile.Request {
+	grafana, ok := object.(*grafanav1beta1.Grafana)
+	if !ok {
+		return nil
 	}
 
-	err := r.Client.List(ctx, &list, opts...)
-	return list, err
+	var list grafanav1beta1.GrafanaDashboardList
+	err := r.Client.List(context.Background(), &list)
+	if err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, dashboard := range list.Items {
+		if instanceSelected(grafana, &dashboard, dashboard.Spec.InstanceSelector) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: dashboard.Namespace,
+				Name:      dashboard.Name,
+			}})
+		}
+	}
+	return requests
+}
+
+// upgradeCompletedPredicate passes updates of instances that finished an upgrade
+var upgradeCompletedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		previous, ok := e.ObjectOld.(*grafanav1beta1.Grafana)
+		if !ok {
+			return false
+		}
+		current, ok := e.ObjectNew.(*grafanav1beta1.Grafana)
+		if !ok {
+			return false
+		}
+		return previous.IsUpgrading() && !current.IsUpgrading()
+	},
+}
+
+// getDashboardReferenceKeys returns the index keys of the secrets and configmaps a dashboard is rendered
+// and downloaded with, the configmap of the json may be in another namespace
+func getDashboardReferenceKeys(dashboard *grafanav1beta1.GrafanaDashboard) []string {
+	var keys []string
+	if dashboard.Spec.ConfigMapRef != nil {
+		namespace, name := dashboard.ConfigMapKey()
+		keys = append(keys, referenceKey(referenceConfigMap, namespace, name))
+	}
+	if dashboard.Spec.ObjectStorage != nil && dashboard.Spec.ObjectStorage.CredentialsSecretRef != nil {
+		keys = append(keys, referenceKey(referenceSecret, dashboard.Namespace, dashboard.Spec.ObjectStorage.CredentialsSecretRef.Name))
+	}
+	if dashboard.Spec.OCI != nil && dashboard.Spec.OCI.PullSecretRef != nil {
+		keys = append(keys, referenceKey(referenceSecret, dashboard.Namespace, dashboard.Spec.OCI.PullSecretRef.Name))
+	}
+	if dashboard.Spec.GitRepository != nil && dashboard.Spec.GitRepository.SecretRef != nil {
+		keys = append(keys, referenceKey(referenceSecret, dashboard.Namespace, dashboard.Spec.GitRepository.SecretRef.Name))
+	}
+	for _, source := range dashboard.Spec.EnvFrom {
+		if source.ConfigMapRef != nil {
+			keys = append(keys, referenceKey(referenceConfigMap, dashboard.Namespace, source.ConfigMapRef.Name))
+		}
+		if source.SecretRef != nil {
+			keys = append(keys, referenceKey(referenceSecret, dashboard.Namespace, source.SecretRef.Name))
+		}
+	}
+
+	var sources []grafanav1beta1.ValueFromSource
+	for _, env := range dashboard.Spec.Envs {
+		if env.ValueFrom != nil {
+			sources = append(sources, *env.ValueFrom)
+		}
+	}
+	for _, header := range dashboard.Spec.UrlHeaders {
+		if header.ValueFrom != nil {
+			sources = append(sources, *header.ValueFrom)
+		}
+	}
+	return append(keys, getValueFromKeys(dashboard.Namespace, sources...)...)
+}
+
+func newDashboardList() client.ObjectList {
+	return &grafanav1beta1.GrafanaDashboardList{}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GrafanaDashboardReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &grafanav1beta1.GrafanaDashboard{}, dashboardReferenceIndex, func(object client.Object) []string {
+		dashboard, ok := object.(*grafanav1beta1.GrafanaDashboard)
+		if !ok {
+			return nil
+		}
+		return getDashboardReferenceKeys(dashboard)
+	})
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &grafanav1beta1.GrafanaDashboard{}, dashboardDependencyIndex, func(object client.Object) []string {
+		dashboard, ok := object.(*grafanav1beta1.GrafanaDashboard)
+		if !ok {
+			return nil
+		}
+		return getDependencyKeys(dashboard)
+	})
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&grafanav1beta1.GrafanaDashboard{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &grafanav1beta1.Grafana{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForUpgradedInstance),
+			builder.WithPredicates(upgradeCompletedPredicate)).
+		Watches(&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForReference(r.Client, newDashboardList, dashboardReferenceIndex, referenceSecret))).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForReference(r.Client, newDashboardList, dashboardReferenceIndex, referenceConfigMap))).
+		Watches(&source.Kind{Type: &grafanav1beta1.GrafanaFolder{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForDependency("GrafanaFolder")),
+			builder.WithPredicates(dependencyReadyPredicate)).
+		Watches(&source.Kind{Type: &grafanav1beta1.GrafanaDatasource{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForDependency("GrafanaDatasource")),
+			builder.WithPredicates(dependencyReadyPredicate)).
+		Watches(&source.Kind{Type: &grafanav1beta1.GrafanaLibraryPanel{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForDependency("GrafanaLibraryPanel")),
+			builder.WithPredicates(dependencyReadyPredicate)).
+		Complete(newReconciler(mgr, "GrafanaDashboard", r))
 }
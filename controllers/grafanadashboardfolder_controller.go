@@ -0,0 +1,353 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"path"
+	"regexp"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	dashboardFolderLabel               = "grafana.integreatly.org/dashboard-folder"
+	defaultDashboardFolderResyncPeriod = 5 * time.Minute
+
+	// names of the dashboards leave room for the hash of the key
+	maxDashboardFolderChildNameLength = 200
+)
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// GrafanaDashboardFolderReconciler reconciles a GrafanaDashboardFolder object
+type GrafanaDashboardFolderReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboardfolders,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboardfolders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboardfolders/finalizers,verbs=update
+
+// Reconcile creates a GrafanaDashboard for every key of the source of a folder and deletes the dashboards
+// of removed keys. The dashboards are owned by the folder and deleted with it.
+func (r *GrafanaDashboardFolderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	folder := &grafanav1beta1.GrafanaDashboardFolder{}
+	err := r.Get(ctx, req.NamespacedName, folder)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana dashboard folder cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana dashboard folder cr")
+		return ctrl.Result{}, err
+	}
+
+	if folder.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	keys, err := r.getKeys(ctx, folder)
+	if err != nil {
+		controllerLog.Error(err, "error listing dashboards of folder", "folder", folder.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, folder, err, folder.Status.Dashboards)
+	}
+
+	items := make([]grafanav1beta1.GrafanaDashboardFolderItem, 0, len(keys))
+	names := map[string]bool{}
+	var lastErr error
+	for _, key := range keys {
+		dashboard, err := r.reconcileDashboard(ctx, folder, key)
+		if err != nil {
+			lastErr = err
+			controllerLog.Error(err, "error reconciling dashboard of folder", "folder", folder.Name, "key", key)
+			continue
+		}
+		names[dashboard.Name] = true
+		items = append(items, grafanav1beta1.GrafanaDashboardFolderItem{
+			Key:         key,
+			Name:        dashboard.Name,
+			LastMessage: dashboard.Status.LastMessage,
+		})
+	}
+
+	err = r.pruneDashboards(ctx, folder, names)
+	if err != nil {
+		lastErr = err
+		controllerLog.Error(err, "error deleting removed dashboards of folder", "folder", folder.Name)
+	}
+
+	err = r.updateStatus(ctx, folder, lastErr, items)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	if lastErr != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+	}
+	// buckets and repositories are listed again after the resync period
+	if folder.Spec.ObjectStorage != nil || folder.Spec.GitRepository != nil {
+		return ctrl.Result{RequeueAfter: getDashboardFolderResyncPeriod(folder)}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// getKeys returns the sorted keys of the json dashboards in the source of a folder
+func (r *GrafanaDashboardFolderReconciler) getKeys(ctx context.Context, folder *grafanav1beta1.GrafanaDashboardFolder) ([]string, error) {
+	sources := 0
+	for _, set := range []bool{folder.Spec.ConfigMapRef != nil, folder.Spec.ObjectStorage != nil, folder.Spec.GitRepository != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, fmt.Errorf("exactly one of configMapRef, objectStorage or gitRepository must be set")
+	}
+
+	var keys []string
+	switch {
+	case folder.Spec.ConfigMapRef != nil:
+		configMap := &v1.ConfigMap{}
+		err := r.Client.Get(ctx, client.ObjectKey{
+			Namespace: folder.Namespace,
+			Name:      folder.Spec.ConfigMapRef.Name,
+		}, configMap)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard folder configmap %s/%s: %w", folder.Namespace, folder.Spec.ConfigMapRef.Name, err)
+		}
+		for key := range configMap.Data {
+			if strings.HasSuffix(key, ".json") {
+				keys = append(keys, key)
+			}
+		}
+		for key := range configMap.BinaryData {
+			if strings.HasSuffix(key, ".json") {
+				keys = append(keys, key)
+			}
+		}
+	case folder.Spec.ObjectStorage != nil:
+		storage := folder.Spec.ObjectStorage
+		credentials, err := getObjectStorageCredentials(ctx, r.Client, folder.Namespace, storage)
+		if err != nil {
+			return nil, err
+		}
+		keys, err = listObjectStorageKeys(ctx, credentials, storage, storage.Key)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		var err error
+		keys, err = listGitRepositoryKeys(ctx, r.Client, folder.Namespace, folder.Spec.GitRepository)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// reconcileDashboard creates or updates the dashboard of a key, the dashboards inherit the settings of the
+// folder while the other fields of their spec are kept
+func (r *GrafanaDashboardFolderReconciler) reconcileDashboard(ctx context.Context, folder *grafanav1beta1.GrafanaDashboardFolder, key string) (*grafanav1beta1.GrafanaDashboard, error) {
+	dashboard := &grafanav1beta1.GrafanaDashboard{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getDashboardFolderChildName(folder.Name, key),
+			Namespace: folder.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, dashboard, func() error {
+		if !dashboard.CreationTimestamp.IsZero() && !metav1.IsControlledBy(dashboard, folder) {
+			return fmt.Errorf("dashboard %s is not managed by folder %s", dashboard.Name, folder.Name)
+		}
+
+		if dashboard.Labels == nil {
+			dashboard.Labels = map[string]string{}
+		}
+		dashboard.Labels[dashboardFolderLabel] = folder.Name
+
+		// only the fields of the folder are set, the defaults the webhook added to the dashboard are kept so that
+		// the dashboard isn't updated on every reconcile
+		spec := &dashboard.Spec
+		if folder.Spec.InstanceSelector != nil {
+			spec.InstanceSelector = folder.Spec.InstanceSelector
+		}
+		if folder.Spec.FolderRef != "" {
+			spec.FolderRef = folder.Spec.FolderRef
+		}
+		spec.Datasources = folder.Spec.Datasources
+		spec.Envs = folder.Spec.Envs
+		spec.EnvFrom = folder.Spec.EnvFrom
+		spec.DerivePlugins = folder.Spec.DerivePlugins
+		spec.AllowCrossNamespaceImport = folder.Spec.AllowCrossNamespaceImport
+		spec.ContentCacheDuration = folder.Spec.ContentCacheDuration
+		spec.OrgReference = folder.Spec.OrgReference
+		spec.ConfigMapRef = nil
+		spec.ObjectStorage = nil
+		spec.GitRepository = nil
+		switch {
+		case folder.Spec.ConfigMapRef != nil:
+			spec.ConfigMapRef = &grafanav1beta1.GrafanaDashboardConfigMapRef{
+				Name: folder.Spec.ConfigMapRef.Name,
+				Key:  key,
+			}
+		case folder.Spec.ObjectStorage != nil:
+			storage := folder.Spec.ObjectStorage.DeepCopy()
+			storage.Key = key
+			spec.ObjectStorage = storage
+		default:
+			repository := folder.Spec.GitRepository.DeepCopy()
+			repository.Path = key
+			spec.GitRepository = repository
+		}
+
+		return controllerutil.SetControllerReference(folder, dashboard, r.Scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dashboard, nil
+}
+
+// pruneDashboards deletes the dashboards of a folder whose keys were removed from the source
+func (r *GrafanaDashboardFolderReconciler) pruneDashboards(ctx context.Context, folder *grafanav1beta1.GrafanaDashboardFolder, names map[string]bool) error {
+	var list grafanav1beta1.GrafanaDashboardList
+	err := r.Client.List(ctx, &list, client.InNamespace(folder.Namespace), client.MatchingLabels{
+		dashboardFolderLabel: folder.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		dashboard := &list.Items[i]
+		if names[dashboard.Name] || !metav1.IsControlledBy(dashboard, folder) {
+			continue
+		}
+		log.FromContext(ctx).Info("deleting dashboard of removed key", "folder", folder.Name, "dashboard", dashboard.Name)
+		err = r.Client.Delete(ctx, dashboard)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStatus writes the status only when it changed, the folder is reconciled whenever the status of one
+// of its dashboards changes. The folder is synchronized once all its dashboards are created, the dashboards
+// report their own instances.
+func (r *GrafanaDashboardFolderReconciler) updateStatus(ctx context.Context, folder *grafanav1beta1.GrafanaDashboardFolder, lastErr error, items []grafanav1beta1.GrafanaDashboardFolderItem) error {
+	status := grafanav1beta1.GrafanaDashboardFolderStatus{
+		LastMessage: getLastMessage(lastErr),
+		Dashboards:  items,
+		Conditions:  folder.Status.DeepCopy().Conditions,
+	}
+	if len(status.Dashboards) == 0 {
+		status.Dashboards = nil
+	}
+	condition := metav1.Condition{
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: folder.Generation,
+		Reason:             reasonSynchronized,
+	}
+	if lastErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = getErrorReason(lastErr)
+		condition.Message = lastErr.Error()
+	}
+	setReadyConditions(&status.Conditions, condition)
+	if equality.Semantic.DeepEqual(folder.Status, status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, folder, folder.Status.Conditions, status.Conditions)
+	folder.Status = status
+	return r.Client.Status().Update(ctx, folder)
+}
+
+func getDashboardFolderResyncPeriod(folder *grafanav1beta1.GrafanaDashboardFolder) time.Duration {
+	if folder.Spec.ResyncPeriod == nil || folder.Spec.ResyncPeriod.Duration <= 0 {
+		return defaultDashboardFolderResyncPeriod
+	}
+	return folder.Spec.ResyncPeriod.Duration
+}
+
+// getDashboardFolderChildName derives a valid and unique resource name from the file name of a key
+func getDashboardFolderChildName(folder string, key string) string {
+	base := strings.TrimSuffix(path.Base(key), ".json")
+	name := folder + "-" + invalidNameCharacters.ReplaceAllString(strings.ToLower(base), "-")
+	if len(name) > maxDashboardFolderChildNameLength {
+		name = name[:maxDashboardFolderChildNameLength]
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return strings.TrimRight(name, "-.") + "-" + hex.EncodeToString(sum[:4])
+}
+
+// requestsForConfigMap returns the folders of a ConfigMap
+func (r *GrafanaDashboardFolderReconciler) requestsForConfigMap(object client.Object) []reconcile.Request {
+	var list grafanav1beta1.GrafanaDashboardFolderList
+	err := r.Client.List(context.Background(), &list, client.InNamespace(object.GetNamespace()))
+	if err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, folder := range list.Items {
+		if folder.Spec.ConfigMapRef != nil && folder.Spec.ConfigMapRef.Name == object.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: folder.Namespace,
+				Name:      folder.Name,
+			}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaDashboardFolderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaDashboardFolder{}).
+		Owns(&grafanav1beta1.GrafanaDashboard{}).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.requestsForConfigMap)).
+		Complete(newReconciler(mgr, "GrafanaDashboardFolder", r))
+}
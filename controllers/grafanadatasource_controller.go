@@ -0,0 +1,627 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/tracing"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+const (
+	conditionDatasourceHealthy = "Healthy"
+)
+
+// datasourceValuesFromIndex indexes datasources by the secrets and configmaps their values are injected from
+const datasourceValuesFromIndex = ".spec.valuesFrom"
+
+// GrafanaDatasourceReconciler reconciles a GrafanaDatasource object
+type GrafanaDatasourceReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadatasources,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadatasources/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadatasources/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
+
+// Reconcile creates, updates and deletes datasources in all matching Grafana instances
+func (r *GrafanaDatasourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	datasource := &grafanav1beta1.GrafanaDatasource{}
+	fetchCtx, span := tracing.Start(ctx, "fetch cr")
+	err := r.Get(fetchCtx, req.NamespacedName, datasource)
+	span.End()
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana datasource cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana datasource cr")
+		return ctrl.Result{}, err
+	}
+
+	if datasource.GetDeletionTimestamp() != nil {
+		return r.onDatasourceDeleted(ctx, datasource)
+	}
+
+	// skip datasources without an instance selector
+	if datasource.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(datasource, grafanaFinalizer) {
+		controllerutil.AddFinalizer(datasource, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, datasource)
+	}
+
+	// requested resyncs are not delayed by the backoff of failed attempts
+	if delay := getRetryDelay(datasource.Status.SyncRetryStatus, datasource.Status.Conditions, datasource.Generation); delay > 0 && !datasource.ReconcileRequested() {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	// datasources are reconciled again when the referenced secrets and configmaps change
+	renderCtx, span := tracing.Start(ctx, "render content")
+	body, err := r.getDatasourceModel(renderCtx, datasource)
+	span.SetError(err)
+	span.End()
+	if err != nil {
+		controllerLog.Error(err, "error resolving datasource values", "datasource", datasource.Name)
+		status := datasource.Status.DeepCopy()
+		status.LastMessage = err.Error()
+		setSyncConditions(&status.Conditions, datasource.Generation, 0, false, err)
+		retryDelay := setSyncRetry(&status.SyncRetryStatus, err)
+		return ctrl.Result{RequeueAfter: retryDelay}, r.updateStatus(ctx, datasource, status)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, datasource, datasource.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for datasource", "datasource", datasource.Name, "namespace", datasource.Namespace)
+	}
+
+	if datasource.Spec.DryRun {
+		return r.reconcileDryRun(ctx, datasource, instances, body)
+	}
+
+	// the plugin of the type keeps the datasource off instances that can't serve it
+	derivedPlugins := getDatasourcePlugins(datasource)
+	plugins := append(append(grafanav1beta1.PluginList{}, datasource.Spec.Plugins...), derivedPlugins...)
+
+	complete := true
+	var lastErr error
+	status := datasource.Status.DeepCopy()
+	status.DerivedPlugins = derivedPlugins
+	status.Instances = nil
+	status.DryRun = nil
+	var health []grafanav1beta1.GrafanaDatasourceHealth
+	uid := datasource.DatasourceUID()
+	resyncPeriod := getResyncPeriod(datasource.Spec.ResyncPolicy)
+
+	for _, grafana := range instances.Items {
+		instance := fmt.Sprintf("%s/%s", grafana.Namespace, grafana.Name)
+
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			health = appendPreviousHealth(health, datasource, instance)
+			status.Instances = appendInstanceStatus(status.Instances, datasource.Status.Instances, &grafana, uid, errInstanceNotReady, resyncPeriod)
+			continue
+		}
+
+		// plugins requested by the datasource are installed by the grafana reconciler
+		pluginErr := ReconcilePlugins(ctx, r.Client, r.Scheme, &grafana, plugins, model.PluginsKindDatasource, datasource)
+		if pluginErr != nil {
+			complete = false
+			lastErr = pluginErr
+			controllerLog.Error(pluginErr, "error reconciling plugins", "datasource", datasource.Name, "grafana", grafana.Name)
+		}
+
+		// datasources claimed by another cr would be overwritten on every reconcile
+		err = checkDatasourceConflicts(ctx, r.Client, &grafana, datasource)
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error checking datasource uid", "datasource", datasource.Name, "grafana", grafana.Name)
+			health = appendPreviousHealth(health, datasource, instance)
+			status.Instances = appendInstanceStatus(status.Instances, datasource.Status.Instances, &grafana, uid, err, resyncPeriod)
+			continue
+		}
+
+		grafanaClient, err := r.getClient(ctx, &grafana, datasource)
+		if err == nil {
+			var result client2.ApplyResult
+			var existing *grafanav1beta1.AdoptedResource
+			result, existing, err = grafanaClient.CreateOrUpdateDatasource(datasource, body)
+			// datasources are written on every reconcile, updates are only reported for changes of the cr
+			if result == client2.ApplyCreated || (result == client2.ApplyUpdated && datasource.Status.ObservedGeneration != datasource.Generation) {
+				r.Recorder.Eventf(datasource, v1.EventTypeNormal, string(result), "%s datasource %s in grafana %s", strings.ToLower(string(result)), uid, instance)
+			}
+			if existing != nil {
+				existing.Instance = instance
+				status.Adopted = appendAdopted(status.Adopted, *existing)
+				controllerLog.Info("adopted existing datasource", "datasource", datasource.Name, "grafana", grafana.Name, "uid", existing.UID)
+			}
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling datasource", "datasource", datasource.Name, "grafana", grafana.Name)
+			health = appendPreviousHealth(health, datasource, instance)
+			status.Instances = appendInstanceStatus(status.Instances, datasource.Status.Instances, &grafana, uid, err, resyncPeriod)
+			continue
+		}
+		status.Instances = appendInstanceStatus(status.Instances, datasource.Status.Instances, &grafana, uid, nil, resyncPeriod)
+		// the uid of an imported datasource is recorded even if its plugins failed, it is removed by that uid
+		if pluginErr != nil {
+			status.Instances[len(status.Instances)-1].Error = pluginErr.Error()
+		}
+
+		// broken credentials are reported long before dashboards stay empty
+		result, err := grafanaClient.CheckDatasourceHealth(datasource.DatasourceUID())
+		if err != nil {
+			controllerLog.Error(err, "error checking datasource health", "datasource", datasource.Name, "grafana", grafana.Name)
+			health = appendPreviousHealth(health, datasource, instance)
+			continue
+		}
+		health = appendHealth(health, datasource, instance, result)
+	}
+
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, datasource.Generation, len(instances.Items), complete, lastErr)
+	status.MatchedInstances = len(instances.Items)
+	status.LastSynced = getLastSynced(status.Instances)
+	retryDelay := setSyncRetry(&status.SyncRetryStatus, lastErr)
+	status.Health = health
+	meta.SetStatusCondition(&status.Conditions, getHealthyCondition(datasource, health))
+	// the generation is only observed once the datasource was imported into all instances, the uid once it
+	// was imported into any instance
+	if complete {
+		status.ObservedGeneration = datasource.Generation
+	}
+	if complete || isAppliedWith(status.Instances, uid) {
+		status.UID = uid
+	}
+	err = r.updateStatus(ctx, datasource, status)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		// drift in grafana is repaired after the resync period
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: retryDelay}, nil
+}
+
+// reconcileDryRun reports the changes the datasource would make in the matching instances in the status,
+// neither the datasources nor the plugins of the instances are changed
+func (r *GrafanaDatasourceReconciler) reconcileDryRun(ctx context.Context, datasource *grafanav1beta1.GrafanaDatasource, instances grafanav1beta1.GrafanaList, body map[string]interface{}) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	complete := true
+	var lastErr error
+	status := datasource.Status.DeepCopy()
+	status.DryRun = nil
+	for i := range instances.Items {
+		grafana := &instances.Items[i]
+		if grafana.Status.AdminUrl == "" {
+			complete = false
+			status.DryRun = append(status.DryRun, newDryRunResult(grafana, client2.ApplyUnchanged, nil, errInstanceNotReady))
+			continue
+		}
+
+		var action client2.ApplyResult
+		var changes []string
+		err := checkDatasourceConflicts(ctx, r.Client, grafana, datasource)
+		if err == nil {
+			var grafanaClient client2.GrafanaClient
+			grafanaClient, err = r.getClient(ctx, grafana, datasource)
+			if err == nil {
+				action, changes, err = grafanaClient.DiffDatasource(datasource, body)
+			}
+		}
+		if err != nil {
+			lastErr = err
+			controllerLog.Error(err, "error comparing datasource", "datasource", datasource.Name, "grafana", grafana.Name)
+		}
+		status.DryRun = append(status.DryRun, newDryRunResult(grafana, action, changes, err))
+	}
+
+	status.LastMessage = getLastMessage(lastErr)
+	setDryRunConditions(&status.Conditions, datasource.Generation, status.DryRun, complete, lastErr)
+	status.MatchedInstances = len(instances.Items)
+	retryDelay := setSyncRetry(&status.SyncRetryStatus, lastErr)
+	err := r.updateStatus(ctx, datasource, status)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+	if complete && lastErr == nil {
+		return ctrl.Result{RequeueAfter: getResyncPeriod(datasource.Spec.ResyncPolicy)}, nil
+	}
+	return ctrl.Result{RequeueAfter: retryDelay}, nil
+}
+
+// onDatasourceDeleted removes the datasource from the instances it was imported into, the finalizer is removed
+// once every instance is cleaned up
+func (r *GrafanaDatasourceReconciler) onDatasourceDeleted(ctx context.Context, datasource *grafanav1beta1.GrafanaDatasource) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(datasource, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// orphaned and retained datasources and datasources in dry run are left in Grafana, the others are deleted
+	// by the uid recorded for the instance
+	if (datasource.Spec.DeletionPolicy == "" || datasource.Spec.DeletionPolicy == grafanav1beta1.DeletionPolicyDelete) && !datasource.Spec.DryRun {
+		var lastErr error
+		var remaining []grafanav1beta1.InstanceStatus
+		for _, instance := range datasource.Status.Instances {
+			if instance.UID == "" {
+				continue
+			}
+
+			// deleted instances have nothing left to clean up
+			grafana, err := getRecordedInstance(ctx, r.Client, instance)
+			if err == nil && grafana != nil {
+				if grafana.Status.AdminUrl == "" {
+					err = errInstanceNotReady
+				} else {
+					err = r.deleteDatasource(ctx, grafana, datasource, instance.UID)
+				}
+			}
+			if err != nil {
+				controllerLog.Error(err, "error deleting datasource", "datasource", datasource.Name, "grafana", instance.Instance)
+				lastErr = err
+				remaining = append(remaining, instance)
+			}
+		}
+		// instances that were cleaned up are not repeated on retries
+		if lastErr != nil {
+			status := datasource.Status.DeepCopy()
+			status.Instances = remaining
+			err := r.updateStatus(ctx, datasource, status)
+			if err != nil {
+				return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+			}
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, lastErr
+		}
+	}
+
+	controllerutil.RemoveFinalizer(datasource, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, datasource)
+}
+
+// deleteDatasource deletes the datasource of a uid from an instance, datasources another cr imported with the
+// uid since are left alone
+func (r *GrafanaDatasourceReconciler) deleteDatasource(ctx context.Context, grafana *grafanav1beta1.Grafana, datasource *grafanav1beta1.GrafanaDatasource, uid string) error {
+	other, err := getDatasourceClaim(ctx, r.Client, grafana, datasource, uid)
+	if err != nil || other != nil {
+		return err
+	}
+
+	grafanaClient, err := r.getClient(ctx, grafana, datasource)
+	if err == nil {
+		err = grafanaClient.DeleteDatasource(uid)
+	}
+	if err != nil {
+		r.Recorder.Eventf(datasource, v1.EventTypeWarning, reasonGrafanaAPIError, "error deleting datasource %s from grafana %s/%s: %s", uid, grafana.Namespace, grafana.Name, err.Error())
+		return err
+	}
+	r.Recorder.Eventf(datasource, v1.EventTypeNormal, eventDeleted, "deleted datasource %s from grafana %s/%s", uid, grafana.Namespace, grafana.Name)
+	return nil
+}
+
+// checkDatasourceConflicts returns an error if another datasource imported into the same organization of an
+// instance claimed the uid or name of the datasource
+func checkDatasourceConflicts(ctx context.Context, k8sClient client.Client, grafana *grafanav1beta1.Grafana, datasource *grafanav1beta1.GrafanaDatasource) error {
+	var list grafanav1beta1.GrafanaDatasourceList
+	err := k8sClient.List(ctx, &list)
+	if err != nil {
+		return err
+	}
+
+	uid, name := datasource.DatasourceUID(), datasource.DatasourceName()
+	org := getOrgKey(datasource.Namespace, datasource.Spec.OrgReference)
+	for i := range list.Items {
+		other := &list.Items[i]
+		otherUID := getClaimedDatasourceUID(grafana, datasource, other, org)
+		if otherUID == "" {
+			continue
+		}
+		if otherUID == uid {
+			return fmt.Errorf("uid %s is already used by datasource %s/%s on grafana %s", uid, other.Namespace, other.Name, grafana.Name)
+		}
+		if other.DatasourceName() == name {
+			return fmt.Errorf("name %s is already used by datasource %s/%s on grafana %s", name, other.Namespace, other.Name, grafana.Name)
+		}
+	}
+	return nil
+}
+
+// getDatasourceClaim returns another datasource imported with the uid into the same organization of an instance
+func getDatasourceClaim(ctx context.Context, k8sClient client.Client, grafana *grafanav1beta1.Grafana, datasource *grafanav1beta1.GrafanaDatasource, uid string) (*grafanav1beta1.GrafanaDatasource, error) {
+	if uid == "" {
+		return nil, nil
+	}
+
+	var list grafanav1beta1.GrafanaDatasourceList
+	err := k8sClient.List(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	org := getOrgKey(datasource.Namespace, datasource.Spec.OrgReference)
+	for i := range list.Items {
+		other := &list.Items[i]
+		if getClaimedDatasourceUID(grafana, datasource, other, org) == uid {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// getClaimedDatasourceUID returns the uid another datasource was imported with into the organization of an
+// instance, the uid recorded for the instance is preferred over the uid of datasources selecting the instance
+func getClaimedDatasourceUID(grafana *grafanav1beta1.Grafana, datasource *grafanav1beta1.GrafanaDatasource, other *grafanav1beta1.GrafanaDatasource, org string) string {
+	if other.UID == datasource.UID || other.DeletionTimestamp != nil || getOrgKey(other.Namespace, other.Spec.OrgReference) != org {
+		return ""
+	}
+	if uid := getInstanceUID(other.Status.Instances, grafana); uid != "" {
+		return uid
+	}
+	if instanceSelected(grafana, other, other.Spec.InstanceSelector) {
+		return other.Status.UID
+	}
+	return ""
+}
+
+// getDatasourcePlugins returns the plugin of the datasource type if it is neither bundled with Grafana nor
+// listed in the plugins of the spec
+func getDatasourcePlugins(datasource *grafanav1beta1.GrafanaDatasource) grafanav1beta1.PluginList {
+	if datasource.Spec.Datasource == nil {
+		return nil
+	}
+
+	plugin := grafanav1beta1.GrafanaPlugin{
+		Name:    datasource.Spec.Datasource.Type,
+		Version: datasource.Spec.PluginVersion,
+	}
+	if plugin.Version == "" {
+		plugin.Version = grafanav1beta1.PluginVersionLatest
+	}
+	if corePlugins[plugin.Name] || !pluginIdPattern.MatchString(plugin.Name) || datasource.Spec.Plugins.HasSomeVersionOf(&plugin) {
+		return nil
+	}
+	return grafanav1beta1.PluginList{plugin}
+}
+
+// getDatasourceModel returns the api representation of the datasource with the http headers and the values
+// of secrets and configmaps injected
+func (r *GrafanaDatasourceReconciler) getDatasourceModel(ctx context.Context, datasource *grafanav1beta1.GrafanaDatasource) (map[string]interface{}, error) {
+	model, err := client2.ToGrafanaDatasource(datasource)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.applyHttpHeaders(ctx, datasource, model)
+	if err != nil || len(datasource.Spec.ValuesFrom) == 0 {
+		return model, err
+	}
+
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err = applyValuesFrom(ctx, r.Client, datasource.Namespace, raw, datasource.Spec.ValuesFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := map[string]interface{}{}
+	return resolved, json.Unmarshal(raw, &resolved)
+}
+
+// applyHttpHeaders sets the http headers of a datasource as numbered name and value pairs, numbers already
+// used by the jsonData of the datasource are skipped
+func (r *GrafanaDatasourceReconciler) applyHttpHeaders(ctx context.Context, datasource *grafanav1beta1.GrafanaDatasource, model map[string]interface{}) error {
+	if len(datasource.Spec.HttpHeaders) == 0 {
+		return nil
+	}
+
+	jsonData, ok := model["jsonData"].(map[string]interface{})
+	if !ok {
+		jsonData = map[string]interface{}{}
+		model["jsonData"] = jsonData
+	}
+	secureJsonData, ok := model["secureJsonData"].(map[string]interface{})
+	if !ok {
+		secureJsonData = map[string]interface{}{}
+		model["secureJsonData"] = secureJsonData
+	}
+
+	index := 0
+	for _, header := range datasource.Spec.HttpHeaders {
+		value := header.Value
+		if header.ValueFrom != nil {
+			var err error
+			value, err = getReferencedValue(ctx, r.Client, datasource.Namespace, *header.ValueFrom)
+			if err != nil {
+				return fmt.Errorf("header %s: %w", header.Name, err)
+			}
+		}
+
+		index++
+		for jsonData[fmt.Sprintf("httpHeaderName%d", index)] != nil {
+			index++
+		}
+		jsonData[fmt.Sprintf("httpHeaderName%d", index)] = header.Name
+		secureJsonData[fmt.Sprintf("httpHeaderValue%d", index)] = value
+	}
+	return nil
+}
+
+// getValuesFromKeys returns the index keys of the secrets and configmaps referenced by the values and http
+// headers of a datasource
+func getValuesFromKeys(datasource *grafanav1beta1.GrafanaDatasource) []string {
+	sources := make([]grafanav1beta1.ValueFromSource, 0, len(datasource.Spec.ValuesFrom)+len(datasource.Spec.HttpHeaders))
+	for _, value := range datasource.Spec.ValuesFrom {
+		sources = append(sources, value.ValueFrom)
+	}
+	for _, header := range datasource.Spec.HttpHeaders {
+		if header.ValueFrom != nil {
+			sources = append(sources, *header.ValueFrom)
+		}
+	}
+	return getValueFromKeys(datasource.Namespace, sources...)
+}
+
+// getClient returns a client for the organization of the datasource
+func (r *GrafanaDatasourceReconciler) getClient(ctx context.Context, grafana *grafanav1beta1.Grafana, datasource *grafanav1beta1.GrafanaDatasource) (client2.GrafanaClient, error) {
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
+	if err != nil {
+		return nil, err
+	}
+	return getOrgClient(ctx, r.Client, grafanaClient, datasource.Namespace, datasource.Spec.OrgReference)
+}
+
+func (r *GrafanaDatasourceReconciler) updateStatus(ctx context.Context, datasource *grafanav1beta1.GrafanaDatasource, status *grafanav1beta1.GrafanaDatasourceStatus) error {
+	// a reconcile request is handled by any reconcile, failed attempts are retried with backoff
+	status.LastHandledReconcileAt = datasource.Annotations[grafanav1beta1.ReconcileRequestAnnotation]
+	if equality.Semantic.DeepEqual(*status, datasource.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, datasource, datasource.Status.Conditions, status.Conditions)
+	datasource.Status = *status
+	return r.Client.Status().Update(ctx, datasource)
+}
+
+// appendHealth records the result of a health check, unchanged results keep their time until the resync
+// period passed so that the status is not written on every reconcile
+func appendHealth(health []grafanav1beta1.GrafanaDatasourceHealth, datasource *grafanav1beta1.GrafanaDatasource, instance string, result *client2.GrafanaDatasourceHealth) []grafanav1beta1.GrafanaDatasourceHealth {
+	for _, previous := range datasource.Status.Health {
+		if previous.Instance == instance && previous.Status == result.Status && previous.Message == result.Message &&
+			time.Since(previous.LastChecked.Time) < getResyncPeriod(datasource.Spec.ResyncPolicy) {
+			return append(health, previous)
+		}
+	}
+	return append(health, grafanav1beta1.GrafanaDatasourceHealth{
+		Instance:    instance,
+		Status:      result.Status,
+		Message:     result.Message,
+		LastChecked: metav1.Now(),
+	})
+}
+
+// appendPreviousHealth keeps the last result of an instance that could not be checked
+func appendPreviousHealth(health []grafanav1beta1.GrafanaDatasourceHealth, datasource *grafanav1beta1.GrafanaDatasource, instance string) []grafanav1beta1.GrafanaDatasourceHealth {
+	for _, previous := range datasource.Status.Health {
+		if previous.Instance == instance {
+			return append(health, previous)
+		}
+	}
+	return health
+}
+
+// getHealthyCondition summarizes the health checks of all instances
+func getHealthyCondition(datasource *grafanav1beta1.GrafanaDatasource, health []grafanav1beta1.GrafanaDatasourceHealth) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               conditionDatasourceHealthy,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: datasource.Generation,
+		Reason:             "NotChecked",
+	}
+
+	var failed []string
+	for _, result := range health {
+		switch result.Status {
+		case client2.DatasourceHealthOK:
+			if condition.Status == metav1.ConditionUnknown {
+				condition.Status = metav1.ConditionTrue
+				condition.Reason = "HealthCheckSucceeded"
+			}
+		case client2.DatasourceHealthError:
+			failed = append(failed, fmt.Sprintf("%s: %s", result.Instance, result.Message))
+		}
+	}
+	if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "HealthCheckFailed"
+		condition.Message = strings.Join(failed, ", ")
+	}
+	return condition
+}
+
+func newDatasourceList() client.ObjectList {
+	return &grafanav1beta1.GrafanaDatasourceList{}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaDatasourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &grafanav1beta1.GrafanaDatasource{}, datasourceValuesFromIndex, func(object client.Object) []string {
+		datasource, ok := object.(*grafanav1beta1.GrafanaDatasource)
+		if !ok {
+			return nil
+		}
+		return getValuesFromKeys(datasource)
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaDatasource{}).
+		Watches(&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForReference(r.Client, newDatasourceList, datasourceValuesFromIndex, referenceSecret))).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForReference(r.Client, newDatasourceList, datasourceValuesFromIndex, referenceConfigMap))).
+		Complete(newReconciler(mgr, "GrafanaDatasource", r))
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaDatasourcePermissionReconciler reconciles a GrafanaDatasourcePermission object
+type GrafanaDatasourcePermissionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadatasourcepermissions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadatasourcepermissions/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadatasourcepermissions/finalizers,verbs=update
+
+// Reconcile applies the access list to the datasource in all matching Grafana Enterprise instances. The access
+// list is compared periodically, changes made in the Grafana UI are reverted.
+func (r *GrafanaDatasourcePermissionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	permission := &grafanav1beta1.GrafanaDatasourcePermission{}
+	err := r.Get(ctx, req.NamespacedName, permission)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana datasource permission cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana datasource permission cr")
+		return ctrl.Result{}, err
+	}
+
+	// skip permissions without an instance selector
+	if permission.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	uid, err := getDatasourceUID(ctx, r.Client, permission.Namespace, permission.Spec.DatasourceReference)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, permission, 0, false, err, nil)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, permission, permission.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for datasource permission", "permission", permission.Name, "namespace", permission.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	var unsupported []string
+	var unresolved []string
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			var enterprise bool
+			enterprise, err = grafanaClient.IsEnterprise()
+			if err == nil && !enterprise {
+				unsupported = append(unsupported, grafana.Name)
+				continue
+			}
+		}
+		if err == nil {
+			grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, permission.Namespace, permission.Spec.OrgReference)
+		}
+		if err == nil {
+			var instanceUnresolved []string
+			instanceUnresolved, err = grafanaClient.SyncDatasourcePermissions(uid, permission.Spec.Permissions)
+			for _, subject := range instanceUnresolved {
+				unresolved = append(unresolved, fmt.Sprintf("%s: %s", grafana.Name, subject))
+			}
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling datasource permissions", "permission", permission.Name, "grafana", grafana.Name)
+		}
+	}
+
+	// teams and users might be created later on
+	if len(unresolved) > 0 {
+		complete = false
+		if lastErr == nil {
+			lastErr = fmt.Errorf("teams or users not found: %s", strings.Join(unresolved, ", "))
+		}
+	}
+
+	err = r.updateStatus(ctx, permission, len(instances.Items), complete, lastErr, unsupported)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{RequeueAfter: RequeueDelayDrift}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaDatasourcePermissionReconciler) updateStatus(ctx context.Context, permission *grafanav1beta1.GrafanaDatasourcePermission, instances int, complete bool, lastErr error, unsupported []string) error {
+	status := permission.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, permission.Generation, instances, complete, lastErr)
+	setUnsupportedCondition(&status.Conditions, permission.Generation, unsupported)
+
+	if equality.Semantic.DeepEqual(*status, permission.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, permission, permission.Status.Conditions, status.Conditions)
+	permission.Status = *status
+	return r.Client.Status().Update(ctx, permission)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaDatasourcePermissionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaDatasourcePermission{}).
+		Complete(newReconciler(mgr, "GrafanaDatasourcePermission", r))
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaFolderReconciler reconciles a GrafanaFolder object
+type GrafanaFolderReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanafolders,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanafolders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanafolders/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes folders in all matching Grafana instances
+func (r *GrafanaFolderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	folder := &grafanav1beta1.GrafanaFolder{}
+	err := r.Get(ctx, req.NamespacedName, folder)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana folder cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana folder cr")
+		return ctrl.Result{}, err
+	}
+
+	if folder.GetDeletionTimestamp() != nil {
+		return r.onFolderDeleted(ctx, folder)
+	}
+
+	// skip folders without an instance selector
+	if folder.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(folder, grafanaFinalizer) {
+		controllerutil.AddFinalizer(folder, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, folder)
+	}
+
+	// requested resyncs are not delayed by the backoff of failed attempts
+	if delay := getRetryDelay(folder.Status.SyncRetryStatus, folder.Status.Conditions, folder.Generation); delay > 0 && !folder.ReconcileRequested() {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, folder, folder.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for folder", "folder", folder.Name, "namespace", folder.Namespace)
+	}
+
+	controllerLog.Info("found matching Grafana instances", "count", len(instances.Items))
+
+	complete := true
+	var lastErr error
+	status := folder.Status.DeepCopy()
+	status.Instances = nil
+	resyncPeriod := getResyncPeriod(folder.Spec.ResyncPolicy)
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			status.Instances = appendInstanceStatus(status.Instances, folder.Status.Instances, &grafana, folder.FolderUID(), errInstanceNotReady, resyncPeriod)
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, folder.Namespace, folder.Spec.OrgReference)
+		}
+		if err == nil {
+			err = grafanaClient.CreateOrUpdateFolder(folder)
+		}
+		if err == nil && folder.Spec.Prune {
+			var pruned []string
+			pruned, err = grafanaClient.PruneFolderDashboards(folder.FolderUID())
+			if len(pruned) > 0 {
+				controllerLog.Info("pruned unmanaged dashboards", "folder", folder.Name, "grafana", grafana.Name, "dashboards", pruned)
+			}
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling folder", "folder", folder.Name, "grafana", grafana.Name)
+		}
+		status.Instances = appendInstanceStatus(status.Instances, folder.Status.Instances, &grafana, folder.FolderUID(), err, resyncPeriod)
+	}
+
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, folder.Generation, len(instances.Items), complete, lastErr)
+	retryDelay := setSyncRetry(&status.SyncRetryStatus, lastErr)
+	// the generation is only observed once the folder was imported into all instances
+	if complete {
+		status.ObservedGeneration = folder.Generation
+	}
+	err = r.updateStatus(ctx, folder, status)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		// drift in grafana is repaired after the resync period
+		return ctrl.Result{RequeueAfter: getResyncPeriod(folder.Spec.ResyncPolicy)}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: retryDelay}, nil
+}
+
+// onFolderDeleted removes the folder from the instances it was created in, as long as no dashboard or library
+// panel is still assigned to it. Deleting a folder in Grafana also deletes all dashboards it contains.
+func (r *GrafanaFolderReconciler) onFolderDeleted(ctx context.Context, folder *grafanav1beta1.GrafanaFolder) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(folder, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	contents, err := r.getFolderContents(ctx, folder)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(contents) > 0 {
+		status := folder.Status.DeepCopy()
+		status.LastMessage = fmt.Sprintf("folder still contains %s", strings.Join(contents, ", "))
+		controllerLog.Info("folder deletion blocked", "folder", folder.Name, "contents", contents)
+		err = r.updateStatus(ctx, folder, status)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// the folder is deleted by the uid recorded for each instance, instances no longer selected are cleaned up
+	// as well
+	var lastErr error
+	var remaining []grafanav1beta1.InstanceStatus
+	for _, instance := range folder.Status.Instances {
+		if instance.UID == "" {
+			continue
+		}
+
+		// deleted instances have nothing left to clean up
+		grafana, err := getRecordedInstance(ctx, r.Client, instance)
+		if err == nil && grafana != nil {
+			if grafana.Status.AdminUrl == "" {
+				err = errInstanceNotReady
+			} else {
+				err = r.deleteFolder(ctx, grafana, folder, instance.UID)
+			}
+		}
+		if err != nil {
+			controllerLog.Error(err, "error deleting folder", "folder", folder.Name, "grafana", instance.Instance)
+			lastErr = err
+			remaining = append(remaining, instance)
+		}
+	}
+	// instances that were cleaned up are not repeated on retries
+	if lastErr != nil {
+		status := folder.Status.DeepCopy()
+		status.Instances = remaining
+		err := r.updateStatus(ctx, folder, status)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, lastErr
+	}
+
+	controllerutil.RemoveFinalizer(folder, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, folder)
+}
+
+// deleteFolder deletes the folder of a uid from an instance
+func (r *GrafanaFolderReconciler) deleteFolder(ctx context.Context, grafana *grafanav1beta1.Grafana, folder *grafanav1beta1.GrafanaFolder, uid string) error {
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
+	if err != nil {
+		return err
+	}
+	grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, folder.Namespace, folder.Spec.OrgReference)
+	if err != nil {
+		return err
+	}
+	return grafanaClient.DeleteFolder(uid)
+}
+
+// getFolderContents returns the dashboards and library panels referencing the folder
+func (r *GrafanaFolderReconciler) getFolderContents(ctx context.Context, folder *grafanav1beta1.GrafanaFolder) ([]string, error) {
+	var dashboards grafanav1beta1.GrafanaDashboardList
+	err := r.Client.List(ctx, &dashboards, client.InNamespace(folder.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	var panels grafanav1beta1.GrafanaLibraryPanelList
+	err = r.Client.List(ctx, &panels, client.InNamespace(folder.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []string
+	for _, dashboard := range dashboards.Items {
+		if dashboard.Spec.FolderRef == folder.Name {
+			contents = append(contents, "dashboard "+dashboard.Name)
+		}
+	}
+	for _, panel := range panels.Items {
+		if panel.Spec.FolderRef == folder.Name {
+			contents = append(contents, "library panel "+panel.Name)
+		}
+	}
+	return contents, nil
+}
+
+// updateStatus writes the status of a folder when it changed
+func (r *GrafanaFolderReconciler) updateStatus(ctx context.Context, folder *grafanav1beta1.GrafanaFolder, status *grafanav1beta1.GrafanaFolderStatus) error {
+	// a reconcile request is handled by any reconcile, failed attempts are retried with backoff
+	status.LastHandledReconcileAt = folder.Annotations[grafanav1beta1.ReconcileRequestAnnotation]
+	if equality.Semantic.DeepEqual(*status, folder.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, folder, folder.Status.Conditions, status.Conditions)
+	folder.Status = *status
+	return r.Client.Status().Update(ctx, folder)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaFolderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaFolder{}).
+		Complete(newReconciler(mgr, "GrafanaFolder", r))
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaLDAPConfigReconciler reconciles a GrafanaLDAPConfig object
+type GrafanaLDAPConfigReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaldapconfigs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaldapconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaldapconfigs/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile renders ldap.toml into a secret for every matching Grafana instance, the grafana reconciler
+// mounts the secret and restarts Grafana when its content changes
+func (r *GrafanaLDAPConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	ldapConfig := &grafanav1beta1.GrafanaLDAPConfig{}
+	err := r.Get(ctx, req.NamespacedName, ldapConfig)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana ldap config cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana ldap config cr")
+		return ctrl.Result{}, err
+	}
+
+	if ldapConfig.GetDeletionTimestamp() != nil {
+		return r.onLDAPConfigDeleted(ctx, ldapConfig)
+	}
+
+	// skip ldap configs without an instance selector
+	if ldapConfig.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(ldapConfig, grafanaFinalizer) {
+		controllerutil.AddFinalizer(ldapConfig, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, ldapConfig)
+	}
+
+	content, err := r.renderLdapToml(ctx, ldapConfig)
+	if err != nil {
+		controllerLog.Error(err, "error rendering ldap config", "ldapConfig", ldapConfig.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, ldapConfig, 0, false, err)
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	err = r.Client.List(ctx, &instances)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	complete := true
+	var lastErr error
+	selected := 0
+
+	for _, grafana := range instances.Items {
+		if instanceSelected(&grafana, ldapConfig, ldapConfig.Spec.InstanceSelector) {
+			selected++
+			err = r.reconcileSecret(ctx, &grafana, ldapConfig, content)
+		} else {
+			// the instance might have been selected before
+			err = r.removeSecret(ctx, &grafana, ldapConfig)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling ldap config", "ldapConfig", ldapConfig.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, ldapConfig, selected, complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+// renderLdapToml resolves the bind passwords and renders ldap.toml
+func (r *GrafanaLDAPConfigReconciler) renderLdapToml(ctx context.Context, ldapConfig *grafanav1beta1.GrafanaLDAPConfig) (string, error) {
+	bindPasswords := make([]string, len(ldapConfig.Spec.Servers))
+	for i, server := range ldapConfig.Spec.Servers {
+		if server.BindPasswordSecretRef == nil {
+			continue
+		}
+
+		password, err := getReferencedValue(ctx, r.Client, ldapConfig.Namespace, grafanav1beta1.ValueFromSource{
+			SecretKeyRef: server.BindPasswordSecretRef,
+		})
+		if err != nil {
+			return "", err
+		}
+		bindPasswords[i] = password
+	}
+
+	content, _ := config.NewLdapToml(&ldapConfig.Spec, bindPasswords).Write()
+	return content, nil
+}
+
+// ldapConfigOwner identifies the ldap config that maintains the ldap secret of an instance
+func ldapConfigOwner(ldapConfig *grafanav1beta1.GrafanaLDAPConfig) string {
+	return fmt.Sprintf("%s/%s", ldapConfig.Namespace, ldapConfig.Name)
+}
+
+// reconcileSecret writes ldap.toml to the ldap secret of an instance, an instance can only be configured
+// by a single ldap config
+func (r *GrafanaLDAPConfigReconciler) reconcileSecret(ctx context.Context, grafana *grafanav1beta1.Grafana, ldapConfig *grafanav1beta1.GrafanaLDAPConfig, content string) error {
+	owner := ldapConfigOwner(ldapConfig)
+	secret := model.GetGrafanaLdapSecret(grafana, r.Scheme)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if current, ok := secret.Annotations[config.GrafanaLdapConfigAnnotation]; ok && current != owner {
+			return fmt.Errorf("grafana %s is already configured by ldap config %s", grafana.Name, current)
+		}
+
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[config.GrafanaLdapConfigAnnotation] = owner
+
+		secret.Data = map[string][]byte{
+			config.GrafanaLdapConfigKey: []byte(content),
+		}
+		if ldapConfig.Spec.AllowSignUp != nil {
+			secret.Data[config.GrafanaLdapAllowSignUpKey] = []byte(strconv.FormatBool(*ldapConfig.Spec.AllowSignUp))
+		}
+		return nil
+	})
+	return err
+}
+
+// removeSecret deletes the ldap secret of an instance if it is maintained by the ldap config
+func (r *GrafanaLDAPConfigReconciler) removeSecret(ctx context.Context, grafana *grafanav1beta1.Grafana, ldapConfig *grafanav1beta1.GrafanaLDAPConfig) error {
+	secret := model.GetGrafanaLdapSecret(grafana, nil)
+	err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+	}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if secret.Annotations[config.GrafanaLdapConfigAnnotation] != ldapConfigOwner(ldapConfig) {
+		return nil
+	}
+
+	err = r.Client.Delete(ctx, secret)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// onLDAPConfigDeleted removes the ldap secrets of all instances, which disables ldap on the next reconcile
+// of the instance
+func (r *GrafanaLDAPConfigReconciler) onLDAPConfigDeleted(ctx context.Context, ldapConfig *grafanav1beta1.GrafanaLDAPConfig) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(ldapConfig, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	err := r.Client.List(ctx, &instances)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, grafana := range instances.Items {
+		err = r.removeSecret(ctx, &grafana, ldapConfig)
+		if err != nil {
+			controllerLog.Error(err, "error removing ldap config", "ldapConfig", ldapConfig.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(ldapConfig, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, ldapConfig)
+}
+
+// updateStatus writes the last error and the conditions of the reconcile when they changed
+func (r *GrafanaLDAPConfigReconciler) updateStatus(ctx context.Context, ldapConfig *grafanav1beta1.GrafanaLDAPConfig, instances int, complete bool, lastErr error) error {
+	status := ldapConfig.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, ldapConfig.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(*status, ldapConfig.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, ldapConfig, ldapConfig.Status.Conditions, status.Conditions)
+	ldapConfig.Status = *status
+	return r.Client.Status().Update(ctx, ldapConfig)
+}
+
+// requestsForSecret enqueues all ldap configs in the namespace of a secret that read a bind password from it
+func (r *GrafanaLDAPConfigReconciler) requestsForSecret(secret client.Object) []reconcile.Request {
+	var list grafanav1beta1.GrafanaLDAPConfigList
+	err := r.Client.List(context.Background(), &list, client.InNamespace(secret.GetNamespace()))
+	if err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, ldapConfig := range list.Items {
+		for _, server := range ldapConfig.Spec.Servers {
+			if server.BindPasswordSecretRef != nil && server.BindPasswordSecretRef.Name == secret.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: ldapConfig.Namespace,
+					Name:      ldapConfig.Name,
+				}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaLDAPConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaLDAPConfig{}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.requestsForSecret)).
+		Complete(newReconciler(mgr, "GrafanaLDAPConfig", r))
+}
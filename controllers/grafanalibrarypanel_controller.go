@@ -0,0 +1,198 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaLibraryPanelReconciler reconciles a GrafanaLibraryPanel object
+type GrafanaLibraryPanelReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanalibrarypanels,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanalibrarypanels/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanalibrarypanels/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes library panels in all matching Grafana instances
+func (r *GrafanaLibraryPanelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	panel := &grafanav1beta1.GrafanaLibraryPanel{}
+	err := r.Get(ctx, req.NamespacedName, panel)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana library panel cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana library panel cr")
+		return ctrl.Result{}, err
+	}
+
+	if panel.GetDeletionTimestamp() != nil {
+		return r.onLibraryPanelDeleted(ctx, panel)
+	}
+
+	// skip library panels without an instance selector
+	if panel.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(panel, grafanaFinalizer) {
+		controllerutil.AddFinalizer(panel, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, panel)
+	}
+
+	err = loadLibraryPanelJson(ctx, r.Client, panel)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, panel, 0, panel.Status.Instances, false, err)
+	}
+
+	folderUID, err := getFolderUID(ctx, r.Client, panel.Namespace, panel.Spec.FolderRef, panel.Spec.OrgReference)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, panel, 0, panel.Status.Instances, false, err)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, panel, panel.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for library panel", "panel", panel.Name, "namespace", panel.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	var statuses []grafanav1beta1.InstanceStatus
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			statuses = appendInstanceStatus(statuses, panel.Status.Instances, &grafana, panel.LibraryPanelUID(), errInstanceNotReady, DefaultResyncPeriod)
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, panel.Namespace, panel.Spec.OrgReference)
+		}
+		if err == nil {
+			err = grafanaClient.CreateOrUpdateLibraryPanel(panel, folderUID)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling library panel", "panel", panel.Name, "grafana", grafana.Name)
+		}
+		statuses = appendInstanceStatus(statuses, panel.Status.Instances, &grafana, panel.LibraryPanelUID(), err, DefaultResyncPeriod)
+	}
+
+	err = r.updateStatus(ctx, panel, len(instances.Items), statuses, complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaLibraryPanelReconciler) onLibraryPanelDeleted(ctx context.Context, panel *grafanav1beta1.GrafanaLibraryPanel) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(panel, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if panel.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, panel, panel.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, panel.Namespace, panel.Spec.OrgReference)
+		}
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteLibraryPanel(panel.LibraryPanelUID())
+		if err != nil {
+			controllerLog.Error(err, "error deleting library panel", "panel", panel.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(panel, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, panel)
+}
+
+// updateStatus writes the last error, the instance results and the conditions of the reconcile when they
+// changed
+func (r *GrafanaLibraryPanelReconciler) updateStatus(ctx context.Context, panel *grafanav1beta1.GrafanaLibraryPanel, instances int, statuses []grafanav1beta1.InstanceStatus, complete bool, lastErr error) error {
+	status := panel.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	status.Instances = statuses
+	setSyncConditions(&status.Conditions, panel.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(*status, panel.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, panel, panel.Status.Conditions, status.Conditions)
+	panel.Status = *status
+	return r.Client.Status().Update(ctx, panel)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaLibraryPanelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaLibraryPanel{}).
+		Complete(newReconciler(mgr, "GrafanaLibraryPanel", r))
+}
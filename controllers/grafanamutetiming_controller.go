@@ -0,0 +1,177 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaMuteTimingReconciler reconciles a GrafanaMuteTiming object
+type GrafanaMuteTimingReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanamutetimings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanamutetimings/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanamutetimings/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes mute timings in all matching Grafana instances
+func (r *GrafanaMuteTimingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	muteTiming := &grafanav1beta1.GrafanaMuteTiming{}
+	err := r.Get(ctx, req.NamespacedName, muteTiming)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana mute timing cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana mute timing cr")
+		return ctrl.Result{}, err
+	}
+
+	if muteTiming.GetDeletionTimestamp() != nil {
+		return r.onMuteTimingDeleted(ctx, muteTiming)
+	}
+
+	// skip mute timings without an instance selector
+	if muteTiming.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(muteTiming, grafanaFinalizer) {
+		controllerutil.AddFinalizer(muteTiming, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, muteTiming)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, muteTiming, muteTiming.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for mute timing", "muteTiming", muteTiming.Name, "namespace", muteTiming.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			err = grafanaClient.CreateOrUpdateMuteTiming(muteTiming)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling mute timing", "muteTiming", muteTiming.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, muteTiming, len(instances.Items), complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaMuteTimingReconciler) onMuteTimingDeleted(ctx context.Context, muteTiming *grafanav1beta1.GrafanaMuteTiming) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(muteTiming, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if muteTiming.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, muteTiming, muteTiming.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteMuteTiming(muteTiming.MuteTimingName())
+		if err != nil {
+			controllerLog.Error(err, "error deleting mute timing", "muteTiming", muteTiming.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(muteTiming, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, muteTiming)
+}
+
+// updateStatus writes the last error and the conditions of the reconcile when they changed
+func (r *GrafanaMuteTimingReconciler) updateStatus(ctx context.Context, muteTiming *grafanav1beta1.GrafanaMuteTiming, instances int, complete bool, lastErr error) error {
+	status := muteTiming.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, muteTiming.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(*status, muteTiming.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, muteTiming, muteTiming.Status.Conditions, status.Conditions)
+	muteTiming.Status = *status
+	return r.Client.Status().Update(ctx, muteTiming)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaMuteTimingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaMuteTiming{}).
+		Complete(newReconciler(mgr, "GrafanaMuteTiming", r))
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+const (
+	conditionNotificationPolicyConflict = "Conflict"
+)
+
+// GrafanaNotificationPolicyReconciler reconciles a GrafanaNotificationPolicy object
+type GrafanaNotificationPolicyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafananotificationpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafananotificationpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafananotificationpolicies/finalizers,verbs=update
+
+// Reconcile merges all policies selecting the same Grafana instances into a single policy tree and applies it
+func (r *GrafanaNotificationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	policy := &grafanav1beta1.GrafanaNotificationPolicy{}
+	err := r.Get(ctx, req.NamespacedName, policy)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana notification policy cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana notification policy cr")
+		return ctrl.Result{}, err
+	}
+
+	if policy.GetDeletionTimestamp() != nil {
+		return r.onPolicyDeleted(ctx, policy)
+	}
+
+	// skip policies without an instance selector
+	if policy.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(policy, grafanaFinalizer) {
+		controllerutil.AddFinalizer(policy, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, policy)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, policy, policy.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for notification policy", "policy", policy.Name, "namespace", policy.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	var conflicts []string
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		instanceConflicts, err := r.reconcileInstance(ctx, &grafana, nil)
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling notification policy", "policy", policy.Name, "grafana", grafana.Name)
+			continue
+		}
+
+		for _, conflict := range instanceConflicts[policyKey(policy)] {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s", grafana.Name, conflict))
+		}
+	}
+
+	err = r.updateStatus(ctx, policy, len(instances.Items), complete, lastErr, conflicts)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+// reconcileInstance merges all policies selecting the instance and applies the resulting tree. The excluded
+// policy is left out of the tree, even if it still exists. Returns the conflicts found per policy.
+func (r *GrafanaNotificationPolicyReconciler) reconcileInstance(ctx context.Context, grafana *grafanav1beta1.Grafana, excluded *grafanav1beta1.GrafanaNotificationPolicy) (map[string][]string, error) {
+	var list grafanav1beta1.GrafanaNotificationPolicyList
+	err := r.Client.List(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []grafanav1beta1.GrafanaNotificationPolicy
+	for _, candidate := range list.Items {
+		if candidate.GetDeletionTimestamp() != nil || !instanceSelected(grafana, &candidate, candidate.Spec.InstanceSelector) {
+			continue
+		}
+		if excluded != nil && candidate.UID == excluded.UID {
+			continue
+		}
+		policies = append(policies, candidate)
+	}
+
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, conflicts := mergeNotificationPolicies(policies)
+	return conflicts, grafanaClient.SetNotificationPolicy(tree)
+}
+
+// mergeNotificationPolicies builds a single policy tree from the default root and all policies, so that the
+// root settings of a deleted policy don't outlive it. Policies are merged in a deterministic order, routes that
+// conflict with routes of a policy merged earlier are skipped and reported as conflicts of the later policy.
+func mergeNotificationPolicies(policies []grafanav1beta1.GrafanaNotificationPolicy) (*client2.GrafanaNotificationRoute, map[string][]string) {
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].Spec.Priority != policies[j].Spec.Priority {
+			return policies[i].Spec.Priority < policies[j].Spec.Priority
+		}
+		return policyKey(&policies[i]) < policyKey(&policies[j])
+	})
+
+	// the root policy Grafana creates and restores when the policy tree is reset
+	tree := &client2.GrafanaNotificationRoute{
+		Receiver: config.GrafanaDefaultContactPoint,
+		GroupBy:  []string{"grafana_folder", "alertname"},
+	}
+
+	conflicts := map[string][]string{}
+	rootOwner := ""
+	routeOwners := map[string]string{}
+
+	for i := range policies {
+		policy := &policies[i]
+		key := policyKey(policy)
+
+		if policy.Spec.Root != nil {
+			if rootOwner != "" {
+				conflicts[key] = append(conflicts[key], fmt.Sprintf("root settings already defined by %s", rootOwner))
+			} else {
+				rootOwner = key
+				tree.ApplySettings(*policy.Spec.Root)
+			}
+		}
+
+		for _, route := range policy.Spec.Routes {
+			matchers := matchersKey(route.ObjectMatchers)
+			if owner, ok := routeOwners[matchers]; ok && owner != key {
+				conflicts[key] = append(conflicts[key], fmt.Sprintf("route matching {%s} already defined by %s", matchers, owner))
+				continue
+			}
+			routeOwners[matchers] = key
+			tree.Routes = append(tree.Routes, client2.ToGrafanaRoute(route))
+		}
+	}
+
+	return tree, conflicts
+}
+
+// matchersKey returns a canonical representation of a set of matchers
+func matchersKey(matchers []grafanav1beta1.ObjectMatcher) string {
+	var items []string
+	for _, matcher := range matchers {
+		items = append(items, fmt.Sprintf("%s%s%q", matcher.Name, matcher.Type, matcher.Value))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ", ")
+}
+
+func policyKey(policy *grafanav1beta1.GrafanaNotificationPolicy) string {
+	return fmt.Sprintf("%s/%s", policy.Namespace, policy.Name)
+}
+
+// onPolicyDeleted rebuilds the policy tree of all matching instances without the deleted policy
+func (r *GrafanaNotificationPolicyReconciler) onPolicyDeleted(ctx context.Context, policy *grafanav1beta1.GrafanaNotificationPolicy) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(policy, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if policy.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, policy, policy.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		_, err = r.reconcileInstance(ctx, &grafana, policy)
+		if err != nil {
+			controllerLog.Error(err, "error removing notification policy", "policy", policy.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(policy, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, policy)
+}
+
+func (r *GrafanaNotificationPolicyReconciler) updateStatus(ctx context.Context, policy *grafanav1beta1.GrafanaNotificationPolicy, instances int, complete bool, lastErr error, conflicts []string) error {
+	status := policy.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, policy.Generation, instances, complete, lastErr)
+
+	condition := metav1.Condition{
+		Type:               conditionNotificationPolicyConflict,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: policy.Generation,
+		Reason:             "NoConflicts",
+	}
+	if len(conflicts) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "RoutesSkipped"
+		condition.Message = strings.Join(conflicts, "; ")
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(*status, policy.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, policy, policy.Status.Conditions, status.Conditions)
+	policy.Status = *status
+	return r.Client.Status().Update(ctx, policy)
+}
+
+// requestAllPolicies enqueues all policies, a change to one policy can resolve or cause conflicts in others
+func (r *GrafanaNotificationPolicyReconciler) requestAllPolicies(object client.Object) []reconcile.Request {
+	var list grafanav1beta1.GrafanaNotificationPolicyList
+	err := r.Client.List(context.Background(), &list)
+	if err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, policy := range list.Items {
+		if policy.UID == object.GetUID() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: policy.Namespace,
+			Name:      policy.Name,
+		}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaNotificationPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaNotificationPolicy{}).
+		Watches(&source.Kind{Type: &grafanav1beta1.GrafanaNotificationPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestAllPolicies),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(newReconciler(mgr, "GrafanaNotificationPolicy", r))
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newNotificationPolicy(name string, priority int32, root *grafanav1beta1.NotificationPolicySettings, receivers ...string) grafanav1beta1.GrafanaNotificationPolicy {
+	policy := grafanav1beta1.GrafanaNotificationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "grafana", Name: name},
+		Spec: grafanav1beta1.GrafanaNotificationPolicySpec{
+			Root:     root,
+			Priority: priority,
+		},
+	}
+	for _, receiver := range receivers {
+		policy.Spec.Routes = append(policy.Spec.Routes, grafanav1beta1.NotificationPolicyRoute{
+			NotificationPolicySettings: grafanav1beta1.NotificationPolicySettings{Receiver: receiver},
+			ObjectMatchers:             []grafanav1beta1.ObjectMatcher{{Name: "team", Type: "=", Value: receiver}},
+		})
+	}
+	return policy
+}
+
+func TestMergeNotificationPolicies(t *testing.T) {
+	defaultGroupBy := []string{"grafana_folder", "alertname"}
+	tests := []struct {
+		name      string
+		policies  []grafanav1beta1.GrafanaNotificationPolicy
+		root      client2.GrafanaNotificationRoute
+		receivers []string
+		conflicts map[string][]string
+	}{
+		{
+			name: "default root without policies",
+			root: client2.GrafanaNotificationRoute{Receiver: "grafana-default-email", GroupBy: defaultGroupBy},
+		},
+		{
+			name: "default root without root settings",
+			policies: []grafanav1beta1.GrafanaNotificationPolicy{
+				newNotificationPolicy("a", 0, nil, "a"),
+			},
+			root:      client2.GrafanaNotificationRoute{Receiver: "grafana-default-email", GroupBy: defaultGroupBy},
+			receivers: []string{"a"},
+		},
+		{
+			name: "root settings of a policy",
+			policies: []grafanav1beta1.GrafanaNotificationPolicy{
+				newNotificationPolicy("a", 0, &grafanav1beta1.NotificationPolicySettings{Receiver: "oncall", GroupWait: "1m"}),
+			},
+			root: client2.GrafanaNotificationRoute{Receiver: "oncall", GroupBy: defaultGroupBy, GroupWait: "1m"},
+		},
+		{
+			name: "root settings of the first policy",
+			policies: []grafanav1beta1.GrafanaNotificationPolicy{
+				newNotificationPolicy("b", 0, &grafanav1beta1.NotificationPolicySettings{Receiver: "b"}),
+				newNotificationPolicy("a", 0, &grafanav1beta1.NotificationPolicySettings{Receiver: "a"}),
+			},
+			root:      client2.GrafanaNotificationRoute{Receiver: "a", GroupBy: defaultGroupBy},
+			conflicts: map[string][]string{"grafana/b": {"root settings already defined by grafana/a"}},
+		},
+		{
+			name: "routes ordered by priority",
+			policies: []grafanav1beta1.GrafanaNotificationPolicy{
+				newNotificationPolicy("a", 2, nil, "a"),
+				newNotificationPolicy("b", 1, nil, "b", "c"),
+			},
+			root:      client2.GrafanaNotificationRoute{Receiver: "grafana-default-email", GroupBy: defaultGroupBy},
+			receivers: []string{"b", "c", "a"},
+		},
+		{
+			name: "conflicting routes",
+			policies: []grafanav1beta1.GrafanaNotificationPolicy{
+				newNotificationPolicy("a", 0, nil, "a"),
+				newNotificationPolicy("b", 0, nil, "a", "b"),
+			},
+			root:      client2.GrafanaNotificationRoute{Receiver: "grafana-default-email", GroupBy: defaultGroupBy},
+			receivers: []string{"a", "b"},
+			conflicts: map[string][]string{"grafana/b": {`route matching {team="a"} already defined by grafana/a`}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree, conflicts := mergeNotificationPolicies(test.policies)
+
+			var receivers []string
+			for _, route := range tree.Routes {
+				receivers = append(receivers, route.Receiver)
+			}
+			tree.Routes = nil
+			if !reflect.DeepEqual(*tree, test.root) {
+				t.Errorf("mergeNotificationPolicies() root = %+v, expected %+v", *tree, test.root)
+			}
+			if !reflect.DeepEqual(receivers, test.receivers) {
+				t.Errorf("mergeNotificationPolicies() routes = %v, expected %v", receivers, test.receivers)
+			}
+			if test.conflicts == nil {
+				test.conflicts = map[string][]string{}
+			}
+			if !reflect.DeepEqual(conflicts, test.conflicts) {
+				t.Errorf("mergeNotificationPolicies() conflicts = %v, expected %v", conflicts, test.conflicts)
+			}
+		})
+	}
+}
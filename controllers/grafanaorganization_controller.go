@@ -0,0 +1,177 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaOrganizationReconciler reconciles a GrafanaOrganization object
+type GrafanaOrganizationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaorganizations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaorganizations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaorganizations/finalizers,verbs=update
+
+// Reconcile creates and deletes organizations in all matching Grafana instances
+func (r *GrafanaOrganizationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	org := &grafanav1beta1.GrafanaOrganization{}
+	err := r.Get(ctx, req.NamespacedName, org)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana organization cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana organization cr")
+		return ctrl.Result{}, err
+	}
+
+	if org.GetDeletionTimestamp() != nil {
+		return r.onOrganizationDeleted(ctx, org)
+	}
+
+	// skip organizations without an instance selector
+	if org.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(org, grafanaFinalizer) {
+		controllerutil.AddFinalizer(org, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, org)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, org, org.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for organization", "org", org.Name, "namespace", org.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			err = grafanaClient.CreateOrg(org.OrgName())
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling organization", "org", org.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, org, len(instances.Items), complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaOrganizationReconciler) onOrganizationDeleted(ctx context.Context, org *grafanav1beta1.GrafanaOrganization) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(org, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if org.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, org, org.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteOrg(org.OrgName())
+		if err != nil {
+			controllerLog.Error(err, "error deleting organization", "org", org.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(org, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, org)
+}
+
+// updateStatus writes the last error and the conditions of the reconcile when they changed
+func (r *GrafanaOrganizationReconciler) updateStatus(ctx context.Context, org *grafanav1beta1.GrafanaOrganization, instances int, complete bool, lastErr error) error {
+	status := org.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, org.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(*status, org.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, org, org.Status.Conditions, status.Conditions)
+	org.Status = *status
+	return r.Client.Status().Update(ctx, org)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaOrganizationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaOrganization{}).
+		Complete(newReconciler(mgr, "GrafanaOrganization", r))
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaPreferencesReconciler reconciles a GrafanaPreferences object
+type GrafanaPreferencesReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanapreferences,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanapreferences/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanapreferences/finalizers,verbs=update
+
+// Reconcile sets the organization preferences in all matching Grafana instances and repairs manual changes
+func (r *GrafanaPreferencesReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	preferences := &grafanav1beta1.GrafanaPreferences{}
+	err := r.Get(ctx, req.NamespacedName, preferences)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana preferences cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana preferences cr")
+		return ctrl.Result{}, err
+	}
+
+	if preferences.GetDeletionTimestamp() != nil {
+		return r.onPreferencesDeleted(ctx, preferences)
+	}
+
+	// skip preferences without an instance selector
+	if preferences.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(preferences, grafanaFinalizer) {
+		controllerutil.AddFinalizer(preferences, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, preferences)
+	}
+
+	desired, err := r.getGrafanaPreferences(ctx, preferences)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, preferences, 0, false, err)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, preferences, preferences.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for preferences", "preferences", preferences.Name, "namespace", preferences.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, preferences.Namespace, preferences.Spec.OrgReference)
+		}
+		if err == nil {
+			err = setOrgPreferences(grafanaClient, preferences, *desired)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling preferences", "preferences", preferences.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, preferences, len(instances.Items), complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{RequeueAfter: RequeueDelayDrift}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+// onPreferencesDeleted resets the preferences of the organization to the Grafana defaults
+func (r *GrafanaPreferencesReconciler) onPreferencesDeleted(ctx context.Context, preferences *grafanav1beta1.GrafanaPreferences) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(preferences, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if preferences.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, preferences, preferences.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, preferences.Namespace, preferences.Spec.OrgReference)
+		if err == nil {
+			err = setOrgPreferences(grafanaClient, preferences, client2.GrafanaPreferences{})
+		}
+		if err != nil {
+			controllerLog.Error(err, "error resetting preferences", "preferences", preferences.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(preferences, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, preferences)
+}
+
+// getGrafanaPreferences resolves the home dashboard of the preferences
+func (r *GrafanaPreferencesReconciler) getGrafanaPreferences(ctx context.Context, preferences *grafanav1beta1.GrafanaPreferences) (*client2.GrafanaPreferences, error) {
+	result := &client2.GrafanaPreferences{
+		Theme:     preferences.Spec.Theme,
+		Timezone:  preferences.Spec.Timezone,
+		WeekStart: preferences.Spec.WeekStart,
+	}
+
+	if preferences.Spec.HomeDashboardRef == "" && preferences.Spec.HomeDashboardUID == "" {
+		return result, nil
+	}
+
+	uid, err := getDashboardUID(ctx, r.Client, preferences.Namespace, preferences.Spec.HomeDashboardRef, preferences.Spec.HomeDashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	result.HomeDashboardUID = uid
+	return result, nil
+}
+
+// setOrgPreferences keeps the home dashboard set by a GrafanaDashboard when the preferences don't manage
+// the home dashboard
+func setOrgPreferences(grafanaClient client2.GrafanaClient, preferences *grafanav1beta1.GrafanaPreferences, desired client2.GrafanaPreferences) error {
+	if preferences.Spec.HomeDashboardRef == "" && preferences.Spec.HomeDashboardUID == "" {
+		existing, err := grafanaClient.GetOrgPreferences()
+		if err != nil {
+			return err
+		}
+		desired.HomeDashboardUID = existing.HomeDashboardUID
+	}
+	return grafanaClient.SetOrgPreferences(&desired)
+}
+
+// updateStatus writes the last error and the conditions of the reconcile when they changed
+func (r *GrafanaPreferencesReconciler) updateStatus(ctx context.Context, preferences *grafanav1beta1.GrafanaPreferences, instances int, complete bool, lastErr error) error {
+	status := preferences.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, preferences.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(*status, preferences.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, preferences, preferences.Status.Conditions, status.Conditions)
+	preferences.Status = *status
+	return r.Client.Status().Update(ctx, preferences)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaPreferencesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaPreferences{}).
+		Complete(newReconciler(mgr, "GrafanaPreferences", r))
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaPublicDashboardReconciler reconciles a GrafanaPublicDashboard object
+type GrafanaPublicDashboardReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanapublicdashboards,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanapublicdashboards/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanapublicdashboards/finalizers,verbs=update
+
+// Reconcile configures public sharing of the dashboard in all matching Grafana instances
+func (r *GrafanaPublicDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	publicDashboard := &grafanav1beta1.GrafanaPublicDashboard{}
+	err := r.Get(ctx, req.NamespacedName, publicDashboard)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana public dashboard cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana public dashboard cr")
+		return ctrl.Result{}, err
+	}
+
+	if publicDashboard.GetDeletionTimestamp() != nil {
+		return r.onPublicDashboardDeleted(ctx, publicDashboard)
+	}
+
+	// skip public dashboards without an instance selector
+	if publicDashboard.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(publicDashboard, grafanaFinalizer) {
+		controllerutil.AddFinalizer(publicDashboard, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, publicDashboard)
+	}
+
+	dashboardUID, err := getDashboardUID(ctx, r.Client, publicDashboard.Namespace, publicDashboard.Spec.DashboardRef, publicDashboard.Spec.DashboardUID)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, publicDashboard, 0, false, err, nil)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, publicDashboard, publicDashboard.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for public dashboard", "publicDashboard", publicDashboard.Name, "namespace", publicDashboard.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	var urls []grafanav1beta1.GrafanaPublicDashboardURL
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			var publicURL string
+			publicURL, err = grafanaClient.CreateOrUpdatePublicDashboard(dashboardUID, publicDashboard)
+			if err == nil {
+				urls = append(urls, grafanav1beta1.GrafanaPublicDashboardURL{
+					Instance: grafana.Name,
+					URL:      publicURL,
+				})
+			}
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling public dashboard", "publicDashboard", publicDashboard.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, publicDashboard, len(instances.Items), complete, lastErr, urls)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaPublicDashboardReconciler) onPublicDashboardDeleted(ctx context.Context, publicDashboard *grafanav1beta1.GrafanaPublicDashboard) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(publicDashboard, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	dashboardUID, err := getDashboardUID(ctx, r.Client, publicDashboard.Namespace, publicDashboard.Spec.DashboardRef, publicDashboard.Spec.DashboardUID)
+	if err != nil {
+		// the dashboard is gone, and with it the public dashboard
+		controllerutil.RemoveFinalizer(publicDashboard, grafanaFinalizer)
+		return ctrl.Result{}, r.Update(ctx, publicDashboard)
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	if publicDashboard.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, publicDashboard, publicDashboard.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeletePublicDashboard(dashboardUID)
+		if err != nil {
+			controllerLog.Error(err, "error deleting public dashboard", "publicDashboard", publicDashboard.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(publicDashboard, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, publicDashboard)
+}
+
+// updateStatus writes the urls, the last error and the conditions of the reconcile when they changed
+func (r *GrafanaPublicDashboardReconciler) updateStatus(ctx context.Context, publicDashboard *grafanav1beta1.GrafanaPublicDashboard, instances int, complete bool, lastErr error, urls []grafanav1beta1.GrafanaPublicDashboardURL) error {
+	status := publicDashboard.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	status.URLs = urls
+	setSyncConditions(&status.Conditions, publicDashboard.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(*status, publicDashboard.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, publicDashboard, publicDashboard.Status.Conditions, status.Conditions)
+	publicDashboard.Status = *status
+	return r.Client.Status().Update(ctx, publicDashboard)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaPublicDashboardReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaPublicDashboard{}).
+		Complete(newReconciler(mgr, "GrafanaPublicDashboard", r))
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/backup"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaRestoreReconciler reconciles a GrafanaRestore object
+type GrafanaRestoreReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Config   *rest.Config
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanarestores,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanarestores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanarestores/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile replays a backup into all matching Grafana instances, once per generation of the restore
+func (r *GrafanaRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	restore := &grafanav1beta1.GrafanaRestore{}
+	err := r.Get(ctx, req.NamespacedName, restore)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana restore cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana restore cr")
+		return ctrl.Result{}, err
+	}
+
+	// skip restores without an instance selector
+	if restore.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	status := restore.Status.DeepCopy()
+	if status.ObservedGeneration != restore.Generation {
+		status = &grafanav1beta1.GrafanaRestoreStatus{
+			ObservedGeneration: restore.Generation,
+			Conditions:         status.Conditions,
+		}
+	}
+
+	if status.Completed {
+		return ctrl.Result{}, r.updateStatus(ctx, restore, status)
+	}
+
+	grafanaBackup := &grafanav1beta1.GrafanaBackup{}
+	err = r.Client.Get(ctx, client.ObjectKey{
+		Namespace: restore.Namespace,
+		Name:      restore.Spec.BackupRef,
+	}, grafanaBackup)
+	if err != nil {
+		status.LastMessage = err.Error()
+		setSyncConditions(&status.Conditions, restore.Generation, 0, false, err)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, restore, status)
+	}
+
+	// the location is kept for the generation, backups taken during a restore don't affect it
+	if status.Location == "" {
+		status.Location, err = getRestoreLocation(restore, grafanaBackup)
+		if err != nil {
+			status.LastMessage = err.Error()
+			setSyncConditions(&status.Conditions, restore.Generation, 0, false, err)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, restore, status)
+		}
+	}
+
+	tarball, err := r.readTarball(ctx, restore, grafanaBackup, status.Location)
+	if err != nil || tarball == nil {
+		if err != nil {
+			status.LastMessage = err.Error()
+			setSyncConditions(&status.Conditions, restore.Generation, 0, false, err)
+		} else {
+			setReadyConditions(&status.Conditions, metav1.Condition{
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: restore.Generation,
+				Reason:             reasonInProgress,
+				Message:            "reading the backup " + status.Location,
+			})
+		}
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, restore, status)
+	}
+
+	files, err := backup.Extract(tarball)
+	if err != nil {
+		err = fmt.Errorf("error extracting %s: %w", status.Location, err)
+		status.LastMessage = err.Error()
+		setSyncConditions(&status.Conditions, restore.Generation, 0, false, err)
+		return ctrl.Result{}, r.updateStatus(ctx, restore, status)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, restore, restore.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for restore", "restore", restore.Name, "namespace", restore.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+
+	for _, grafana := range instances.Items {
+		// every instance is restored only once
+		if status.GetInstance(grafana.Name) != nil {
+			continue
+		}
+
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		var changes []string
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			changes, err = grafanaClient.RestoreBackup(files, restore.Spec.Mode == grafanav1beta1.RestoreModeReplace, restore.Spec.DryRun)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error restoring grafana", "restore", restore.Name, "grafana", grafana.Name)
+			continue
+		}
+
+		status.Instances = append(status.Instances, grafanav1beta1.GrafanaRestoreInstance{
+			Instance: grafana.Name,
+			Time:     metav1.Time{Time: time.Now()},
+			Changes:  changes,
+		})
+	}
+
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, restore.Generation, len(instances.Items), complete, lastErr)
+	if !complete {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, restore, status)
+	}
+
+	err = deleteWriterPod(ctx, r.Client, model.GetRestoreReaderPod(restore, nil))
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	status.Completed = true
+	return ctrl.Result{}, r.updateStatus(ctx, restore, status)
+}
+
+// getRestoreLocation returns the location of the tarball to restore, falling back to the last backup of the
+// source instance
+func getRestoreLocation(restore *grafanav1beta1.GrafanaRestore, grafanaBackup *grafanav1beta1.GrafanaBackup) (string, error) {
+	if restore.Spec.Location != "" {
+		return restore.Spec.Location, nil
+	}
+
+	backups := grafanaBackup.Status.Backups
+	if restore.Spec.SourceInstance != "" {
+		for _, instance := range backups {
+			if instance.Instance == restore.Spec.SourceInstance {
+				return instance.Location, nil
+			}
+		}
+		return "", fmt.Errorf("backup %s contains no backup of instance %s", grafanaBackup.Name, restore.Spec.SourceInstance)
+	}
+
+	switch len(backups) {
+	case 0:
+		return "", fmt.Errorf("backup %s has not completed yet", grafanaBackup.Name)
+	case 1:
+		return backups[0].Location, nil
+	default:
+		return "", fmt.Errorf("backup %s contains multiple instances, sourceInstance must be set", grafanaBackup.Name)
+	}
+}
+
+// readTarball downloads a tarball from s3 or reads it through a reader pod mounting the claim, nil is
+// returned while the reader pod is starting
+func (r *GrafanaRestoreReconciler) readTarball(ctx context.Context, restore *grafanav1beta1.GrafanaRestore, grafanaBackup *grafanav1beta1.GrafanaBackup, location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "s3":
+		s3, err := getBackupStorage(ctx, r.Client, grafanaBackup)
+		if err != nil {
+			return nil, err
+		}
+		if s3 == nil {
+			return nil, fmt.Errorf("backup %s has no s3 storage to read %s", grafanaBackup.Name, location)
+		}
+		s3.Bucket = u.Host
+		return s3.GetObject(ctx, &http.Client{Timeout: time.Minute}, strings.TrimPrefix(u.Path, "/"))
+	case "pvc":
+		pod := model.GetRestoreReaderPod(restore, r.Scheme)
+		ready, err := ensureWriterPod(ctx, r.Client, pod, u.Host)
+		if err != nil || !ready {
+			return nil, err
+		}
+		return backup.ReadFile(r.Config, pod, path.Join(config.BackupMountPath, u.Path))
+	default:
+		return nil, fmt.Errorf("unsupported backup location %s, expected a pvc:// or s3:// url", location)
+	}
+}
+
+func (r *GrafanaRestoreReconciler) updateStatus(ctx context.Context, restore *grafanav1beta1.GrafanaRestore, status *grafanav1beta1.GrafanaRestoreStatus) error {
+	if equality.Semantic.DeepEqual(*status, restore.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, restore, restore.Status.Conditions, status.Conditions)
+	restore.Status = *status
+	return r.Client.Status().Update(ctx, restore)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaRestore{}).
+		Owns(&v1.Pod{}).
+		Complete(newReconciler(mgr, "GrafanaRestore", r))
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaRoleReconciler reconciles a GrafanaRole object
+type GrafanaRoleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaroles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaroles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaroles/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes custom roles in all matching Grafana Enterprise instances
+func (r *GrafanaRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	role := &grafanav1beta1.GrafanaRole{}
+	err := r.Get(ctx, req.NamespacedName, role)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana role cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana role cr")
+		return ctrl.Result{}, err
+	}
+
+	if role.GetDeletionTimestamp() != nil {
+		return r.onRoleDeleted(ctx, role)
+	}
+
+	// skip roles without an instance selector
+	if role.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(role, grafanaFinalizer) {
+		controllerutil.AddFinalizer(role, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, role)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, role, role.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for role", "role", role.Name, "namespace", role.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	var unsupported []string
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			var enterprise bool
+			enterprise, err = grafanaClient.IsEnterprise()
+			if err == nil && !enterprise {
+				unsupported = append(unsupported, grafana.Name)
+				continue
+			}
+		}
+		if err == nil {
+			grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, role.Namespace, role.Spec.OrgReference)
+		}
+		if err == nil {
+			err = grafanaClient.CreateOrUpdateRole(role)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling role", "role", role.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, role, len(instances.Items), complete, lastErr, unsupported)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaRoleReconciler) onRoleDeleted(ctx context.Context, role *grafanav1beta1.GrafanaRole) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(role, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if role.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, role, role.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		enterprise, err := grafanaClient.IsEnterprise()
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+		if !enterprise {
+			continue
+		}
+
+		grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, role.Namespace, role.Spec.OrgReference)
+		if err == nil {
+			err = grafanaClient.DeleteRole(role.RoleUID())
+		}
+		if err != nil {
+			controllerLog.Error(err, "error deleting role", "role", role.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(role, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, role)
+}
+
+func (r *GrafanaRoleReconciler) updateStatus(ctx context.Context, role *grafanav1beta1.GrafanaRole, instances int, complete bool, lastErr error, unsupported []string) error {
+	status := role.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, role.Generation, instances, complete, lastErr)
+	setUnsupportedCondition(&status.Conditions, role.Generation, unsupported)
+
+	if equality.Semantic.DeepEqual(*status, role.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, role, role.Status.Conditions, status.Conditions)
+	role.Status = *status
+	return r.Client.Status().Update(ctx, role)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaRole{}).
+		Complete(newReconciler(mgr, "GrafanaRole", r))
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaRoleBindingReconciler reconciles a GrafanaRoleBinding object
+type GrafanaRoleBindingReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanarolebindings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanarolebindings/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanarolebindings/finalizers,verbs=update
+
+// Reconcile assigns a role to users, teams and service accounts in all matching Grafana Enterprise instances,
+// subjects removed from the binding lose the role
+func (r *GrafanaRoleBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	binding := &grafanav1beta1.GrafanaRoleBinding{}
+	err := r.Get(ctx, req.NamespacedName, binding)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana role binding cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana role binding cr")
+		return ctrl.Result{}, err
+	}
+
+	if binding.GetDeletionTimestamp() != nil {
+		return r.onRoleBindingDeleted(ctx, binding)
+	}
+
+	// skip role bindings without an instance selector
+	if binding.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(binding, grafanaFinalizer) {
+		controllerutil.AddFinalizer(binding, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, binding)
+	}
+
+	roleUID, err := getRoleUID(ctx, r.Client, binding.Namespace, binding.Spec.RoleRef, binding.Spec.RoleUID)
+	if err != nil {
+		controllerLog.Error(err, "error resolving role", "roleBinding", binding.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, binding, 0, false, err, nil)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, binding, binding.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for role binding", "roleBinding", binding.Name, "namespace", binding.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	var unsupported []string
+	var unresolved []string
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			var enterprise bool
+			enterprise, err = grafanaClient.IsEnterprise()
+			if err == nil && !enterprise {
+				unsupported = append(unsupported, grafana.Name)
+				continue
+			}
+		}
+		if err == nil {
+			grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, binding.Namespace, binding.Spec.OrgReference)
+		}
+		if err == nil {
+			err = grafanaClient.UnassignRole(roleUID, binding.RemovedSubjects())
+		}
+		if err == nil {
+			var instanceUnresolved []string
+			instanceUnresolved, err = grafanaClient.AssignRole(roleUID, binding.Spec.GrafanaRoleSubjects)
+			for _, subject := range instanceUnresolved {
+				unresolved = append(unresolved, fmt.Sprintf("%s: %s", grafana.Name, subject))
+			}
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling role binding", "roleBinding", binding.Name, "grafana", grafana.Name)
+		}
+	}
+
+	// subjects might be created later on
+	if len(unresolved) > 0 {
+		complete = false
+		if lastErr == nil {
+			lastErr = fmt.Errorf("subjects not found: %s", strings.Join(unresolved, ", "))
+		}
+	}
+
+	err = r.updateStatus(ctx, binding, len(instances.Items), complete, lastErr, unsupported)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+// onRoleBindingDeleted removes the role from all subjects of the binding
+func (r *GrafanaRoleBindingReconciler) onRoleBindingDeleted(ctx context.Context, binding *grafanav1beta1.GrafanaRoleBinding) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(binding, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	roleUID, err := getRoleUID(ctx, r.Client, binding.Namespace, binding.Spec.RoleRef, binding.Spec.RoleUID)
+	if errors.IsNotFound(err) {
+		// the role has already been deleted, which removes all of its assignments
+		controllerutil.RemoveFinalizer(binding, grafanaFinalizer)
+		return ctrl.Result{}, r.Update(ctx, binding)
+	}
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	if binding.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, binding, binding.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		enterprise, err := grafanaClient.IsEnterprise()
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+		if !enterprise {
+			continue
+		}
+
+		grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, binding.Namespace, binding.Spec.OrgReference)
+		if err == nil {
+			err = grafanaClient.UnassignRole(roleUID, binding.Spec.GrafanaRoleSubjects)
+		}
+		if err == nil {
+			err = grafanaClient.UnassignRole(roleUID, binding.RemovedSubjects())
+		}
+		if err != nil {
+			controllerLog.Error(err, "error deleting role binding", "roleBinding", binding.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(binding, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, binding)
+}
+
+// updateStatus records the bound subjects once they have been assigned in all instances, so that subjects
+// removed later on can be unassigned
+func (r *GrafanaRoleBindingReconciler) updateStatus(ctx context.Context, binding *grafanav1beta1.GrafanaRoleBinding, instances int, complete bool, lastErr error, unsupported []string) error {
+	status := binding.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, binding.Generation, instances, complete, lastErr)
+	setUnsupportedCondition(&status.Conditions, binding.Generation, unsupported)
+	if complete {
+		binding.Spec.GrafanaRoleSubjects.DeepCopyInto(&status.Bound)
+	}
+
+	if equality.Semantic.DeepEqual(*status, binding.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, binding, binding.Status.Conditions, status.Conditions)
+	binding.Status = *status
+	return r.Client.Status().Update(ctx, binding)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaRoleBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaRoleBinding{}).
+		Complete(newReconciler(mgr, "GrafanaRoleBinding", r))
+}
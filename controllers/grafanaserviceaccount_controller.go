@@ -0,0 +1,310 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaServiceAccountReconciler reconciles a GrafanaServiceAccount object
+type GrafanaServiceAccountReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaserviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaserviceaccounts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanaserviceaccounts/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+
+// Reconcile creates service accounts in all matching Grafana instances and writes their tokens to a secret
+func (r *GrafanaServiceAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	serviceAccount := &grafanav1beta1.GrafanaServiceAccount{}
+	err := r.Get(ctx, req.NamespacedName, serviceAccount)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana service account cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana service account cr")
+		return ctrl.Result{}, err
+	}
+
+	if serviceAccount.GetDeletionTimestamp() != nil {
+		return r.onServiceAccountDeleted(ctx, serviceAccount)
+	}
+
+	// skip service accounts without an instance selector
+	if serviceAccount.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(serviceAccount, grafanaFinalizer) {
+		controllerutil.AddFinalizer(serviceAccount, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, serviceAccount)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, serviceAccount, serviceAccount.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for service account", "serviceAccount", serviceAccount.Name, "namespace", serviceAccount.Namespace)
+	}
+
+	secret, err := r.getTokenSecret(ctx, serviceAccount)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	complete := true
+	var lastErr error
+	initialStatus := serviceAccount.Status.DeepCopy()
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			err = r.reconcileToken(ctx, grafanaClient, &grafana, serviceAccount, secret)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling service account", "serviceAccount", serviceAccount.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, serviceAccount, initialStatus, len(instances.Items), complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{RequeueAfter: r.nextRotation(serviceAccount)}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaServiceAccountReconciler) onServiceAccountDeleted(ctx context.Context, serviceAccount *grafanav1beta1.GrafanaServiceAccount) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(serviceAccount, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if serviceAccount.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, serviceAccount, serviceAccount.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteServiceAccount(serviceAccount.ServiceAccountName())
+		if err != nil {
+			controllerLog.Error(err, "error deleting service account", "serviceAccount", serviceAccount.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(serviceAccount, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, serviceAccount)
+}
+
+// getTokenSecret returns the secret the tokens are written to, the secret is created if it doesn't exist
+func (r *GrafanaServiceAccountReconciler) getTokenSecret(ctx context.Context, serviceAccount *grafanav1beta1.GrafanaServiceAccount) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: serviceAccount.Namespace,
+		Name:      serviceAccount.Spec.TokenSecretName,
+	}, secret)
+	if err == nil || !errors.IsNotFound(err) {
+		return secret, err
+	}
+
+	secret = &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceAccount.Spec.TokenSecretName,
+			Namespace: serviceAccount.Namespace,
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+	// garbage collect the tokens together with the service account
+	err = controllerutil.SetControllerReference(serviceAccount, secret, r.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	return secret, r.Client.Create(ctx, secret)
+}
+
+// reconcileToken creates the service account and mints a new token when rotation is due. The old token is
+// only revoked after the new one was written to the secret.
+func (r *GrafanaServiceAccountReconciler) reconcileToken(ctx context.Context, grafanaClient client2.GrafanaClient, grafana *grafanav1beta1.Grafana, serviceAccount *grafanav1beta1.GrafanaServiceAccount, secret *v1.Secret) error {
+	id, err := grafanaClient.CreateOrUpdateServiceAccount(serviceAccount)
+	if err != nil {
+		return err
+	}
+
+	current := serviceAccount.Status.GetToken(grafana.Name)
+	if !r.rotationDue(serviceAccount, current, secret, grafana.Name) {
+		return nil
+	}
+
+	now := metav1.Now()
+	token, err := grafanaClient.CreateServiceAccountToken(id, fmt.Sprintf("%s-%d", serviceAccount.ServiceAccountName(), now.Unix()))
+	if err != nil {
+		return err
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[grafana.Name] = []byte(token.Key)
+	err = r.Client.Update(ctx, secret)
+	if err != nil {
+		return err
+	}
+
+	// the old token is only revoked once the status records the new one, a failed status update leaves the
+	// recorded token valid
+	previous := current != nil
+	var previousID int64
+	if previous {
+		previousID = current.TokenID
+	}
+	serviceAccount.Status.SetToken(grafanav1beta1.GrafanaServiceAccountToken{
+		Instance: grafana.Name,
+		TokenID:  token.ID,
+		Created:  now,
+		Trigger:  r.rotationTrigger(serviceAccount),
+	})
+	err = r.Client.Status().Update(ctx, serviceAccount)
+	if err != nil {
+		return err
+	}
+
+	if previous {
+		return grafanaClient.DeleteServiceAccountToken(id, previousID)
+	}
+	return nil
+}
+
+func (r *GrafanaServiceAccountReconciler) rotationDue(serviceAccount *grafanav1beta1.GrafanaServiceAccount, current *grafanav1beta1.GrafanaServiceAccountToken, secret *v1.Secret, instance string) bool {
+	if current == nil {
+		return true
+	}
+
+	if _, ok := secret.Data[instance]; !ok {
+		return true
+	}
+
+	if current.Trigger != r.rotationTrigger(serviceAccount) {
+		return true
+	}
+
+	rotation := serviceAccount.Spec.TokenRotation
+	return rotation != nil && rotation.Interval != nil && time.Since(current.Created.Time) >= rotation.Interval.Duration
+}
+
+func (r *GrafanaServiceAccountReconciler) rotationTrigger(serviceAccount *grafanav1beta1.GrafanaServiceAccount) string {
+	if serviceAccount.Spec.TokenRotation == nil {
+		return ""
+	}
+	return serviceAccount.Spec.TokenRotation.Trigger
+}
+
+// nextRotation returns the time until the next token expires, zero if tokens are not rotated periodically
+func (r *GrafanaServiceAccountReconciler) nextRotation(serviceAccount *grafanav1beta1.GrafanaServiceAccount) time.Duration {
+	rotation := serviceAccount.Spec.TokenRotation
+	if rotation == nil || rotation.Interval == nil {
+		return 0
+	}
+
+	next := rotation.Interval.Duration
+	for _, token := range serviceAccount.Status.Tokens {
+		remaining := rotation.Interval.Duration - time.Since(token.Created.Time)
+		if remaining < next {
+			next = remaining
+		}
+	}
+
+	if next < time.Second {
+		next = time.Second
+	}
+	return next
+}
+
+// updateStatus writes the status of a service account when it changed since the start of the reconcile
+func (r *GrafanaServiceAccountReconciler) updateStatus(ctx context.Context, serviceAccount *grafanav1beta1.GrafanaServiceAccount, initialStatus *grafanav1beta1.GrafanaServiceAccountStatus, instances int, complete bool, lastErr error) error {
+	serviceAccount.Status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&serviceAccount.Status.Conditions, serviceAccount.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(&serviceAccount.Status, initialStatus) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, serviceAccount, initialStatus.Conditions, serviceAccount.Status.Conditions)
+	return r.Client.Status().Update(ctx, serviceAccount)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaServiceAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaServiceAccount{}).
+		Owns(&v1.Secret{}).
+		Complete(newReconciler(mgr, "GrafanaServiceAccount", r))
+}
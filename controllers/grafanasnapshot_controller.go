@@ -0,0 +1,256 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaSnapshotReconciler reconciles a GrafanaSnapshot object
+type GrafanaSnapshotReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanasnapshots,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanasnapshots/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanasnapshots/finalizers,verbs=update
+
+// Reconcile takes a snapshot of the referenced dashboard once in every matching Grafana instance, snapshots
+// are not updated when the dashboard changes
+func (r *GrafanaSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	snapshot := &grafanav1beta1.GrafanaSnapshot{}
+	err := r.Get(ctx, req.NamespacedName, snapshot)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana snapshot cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana snapshot cr")
+		return ctrl.Result{}, err
+	}
+
+	if snapshot.GetDeletionTimestamp() != nil {
+		return r.onSnapshotDeleted(ctx, snapshot)
+	}
+
+	// skip snapshots without an instance selector
+	if snapshot.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(snapshot, grafanaFinalizer) {
+		controllerutil.AddFinalizer(snapshot, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, snapshot)
+	}
+
+	dashboardUID, orgReference, err := r.getSnapshotSource(ctx, snapshot)
+	if err != nil {
+		controllerLog.Error(err, "error resolving snapshot dashboard", "snapshot", snapshot.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, snapshot, snapshot.Status.DeepCopy(), 0, false, err)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, snapshot, snapshot.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for snapshot", "snapshot", snapshot.Name, "namespace", snapshot.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	initialStatus := snapshot.Status.DeepCopy()
+
+	for _, grafana := range instances.Items {
+		if snapshot.Status.GetSnapshot(grafana.Name) != nil {
+			continue
+		}
+
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, snapshot.Namespace, orgReference)
+		}
+		if err == nil {
+			err = r.takeSnapshot(grafanaClient, &grafana, snapshot, dashboardUID)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error taking snapshot", "snapshot", snapshot.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, snapshot, initialStatus, len(instances.Items), complete, lastErr)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaSnapshotReconciler) onSnapshotDeleted(ctx context.Context, snapshot *grafanav1beta1.GrafanaSnapshot) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(snapshot, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// snapshots of a deleted dashboard can still be removed from the main organization
+	_, orgReference, err := r.getSnapshotSource(ctx, snapshot)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	if snapshot.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, snapshot, snapshot.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		taken := snapshot.Status.GetSnapshot(grafana.Name)
+		if grafana.Status.AdminUrl == "" || taken == nil {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		grafanaClient, err = getOrgClient(ctx, r.Client, grafanaClient, snapshot.Namespace, orgReference)
+		if err == nil {
+			err = grafanaClient.DeleteSnapshot(taken.Key)
+		}
+		if err != nil {
+			controllerLog.Error(err, "error deleting snapshot", "snapshot", snapshot.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(snapshot, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, snapshot)
+}
+
+// getSnapshotSource returns the uid and organization of the dashboard to take a snapshot of
+func (r *GrafanaSnapshotReconciler) getSnapshotSource(ctx context.Context, snapshot *grafanav1beta1.GrafanaSnapshot) (string, grafanav1beta1.OrgReference, error) {
+	if snapshot.Spec.DashboardRef == "" {
+		if snapshot.Spec.DashboardUID == "" {
+			return "", grafanav1beta1.OrgReference{}, fmt.Errorf("either dashboardRef or dashboardUid must be set")
+		}
+		return snapshot.Spec.DashboardUID, grafanav1beta1.OrgReference{}, nil
+	}
+
+	dashboard := &grafanav1beta1.GrafanaDashboard{}
+	err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: snapshot.Namespace,
+		Name:      snapshot.Spec.DashboardRef,
+	}, dashboard)
+	if err != nil {
+		return "", grafanav1beta1.OrgReference{}, err
+	}
+	err = loadDashboardJson(ctx, r.Client, dashboard)
+	if err != nil {
+		return "", grafanav1beta1.OrgReference{}, err
+	}
+
+	uid, err := dashboard.DashboardUID()
+	return uid, dashboard.Spec.OrgReference, err
+}
+
+// takeSnapshot snapshots the dashboard as currently stored in the instance
+func (r *GrafanaSnapshotReconciler) takeSnapshot(grafanaClient client2.GrafanaClient, grafana *grafanav1beta1.Grafana, snapshot *grafanav1beta1.GrafanaSnapshot, dashboardUID string) error {
+	dashboard, err := grafanaClient.GetDashboardJson(dashboardUID)
+	if err != nil {
+		return err
+	}
+
+	var expiresSeconds int64
+	var expires *metav1.Time
+	if snapshot.Spec.Expires != nil && snapshot.Spec.Expires.Duration > 0 {
+		expiresSeconds = int64(snapshot.Spec.Expires.Duration / time.Second)
+		expires = &metav1.Time{Time: time.Now().Add(snapshot.Spec.Expires.Duration)}
+	}
+
+	created, err := grafanaClient.CreateSnapshot(dashboard, snapshot.SnapshotName(), expiresSeconds)
+	if err != nil {
+		return err
+	}
+
+	snapshot.Status.Snapshots = append(snapshot.Status.Snapshots, grafanav1beta1.GrafanaSnapshotInstance{
+		Instance: grafana.Name,
+		Key:      created.Key,
+		URL:      created.URL,
+		Expires:  expires,
+	})
+	return nil
+}
+
+// updateStatus writes the status of a snapshot when it changed since the start of the reconcile
+func (r *GrafanaSnapshotReconciler) updateStatus(ctx context.Context, snapshot *grafanav1beta1.GrafanaSnapshot, initialStatus *grafanav1beta1.GrafanaSnapshotStatus, instances int, complete bool, lastErr error) error {
+	snapshot.Status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&snapshot.Status.Conditions, snapshot.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(&snapshot.Status, initialStatus) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, snapshot, initialStatus.Conditions, snapshot.Status.Conditions)
+	return r.Client.Status().Update(ctx, snapshot)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaSnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaSnapshot{}).
+		Complete(newReconciler(mgr, "GrafanaSnapshot", r))
+}
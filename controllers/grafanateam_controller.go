@@ -0,0 +1,203 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+const (
+	conditionTeamMembersResolved = "MembersResolved"
+)
+
+// GrafanaTeamReconciler reconciles a GrafanaTeam object
+type GrafanaTeamReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanateams,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanateams/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanateams/finalizers,verbs=update
+
+// Reconcile creates teams and converges their members in all matching Grafana instances
+func (r *GrafanaTeamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	team := &grafanav1beta1.GrafanaTeam{}
+	err := r.Get(ctx, req.NamespacedName, team)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana team cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana team cr")
+		return ctrl.Result{}, err
+	}
+
+	if team.GetDeletionTimestamp() != nil {
+		return r.onTeamDeleted(ctx, team)
+	}
+
+	// skip teams without an instance selector
+	if team.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(team, grafanaFinalizer) {
+		controllerutil.AddFinalizer(team, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, team)
+	}
+
+	instances, err := GetMatchingInstances(ctx, r.Client, team, team.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for team", "team", team.Name, "namespace", team.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+	var unresolved []string
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			var instanceUnresolved []string
+			instanceUnresolved, err = grafanaClient.CreateOrUpdateTeam(team)
+			for _, member := range instanceUnresolved {
+				unresolved = append(unresolved, fmt.Sprintf("%s: %s", grafana.Name, member))
+			}
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling team", "team", team.Name, "grafana", grafana.Name)
+		}
+	}
+
+	err = r.updateStatus(ctx, team, len(instances.Items), complete, lastErr, unresolved)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaTeamReconciler) onTeamDeleted(ctx context.Context, team *grafanav1beta1.GrafanaTeam) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(team, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if team.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, team, team.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteTeam(team.TeamName())
+		if err != nil {
+			controllerLog.Error(err, "error deleting team", "team", team.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(team, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, team)
+}
+
+func (r *GrafanaTeamReconciler) updateStatus(ctx context.Context, team *grafanav1beta1.GrafanaTeam, instances int, complete bool, lastErr error, unresolved []string) error {
+	status := team.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	setSyncConditions(&status.Conditions, team.Generation, instances, complete, lastErr)
+
+	condition := metav1.Condition{
+		Type:               conditionTeamMembersResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: team.Generation,
+		Reason:             "MembersResolved",
+	}
+	if len(unresolved) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "UnknownMembers"
+		condition.Message = fmt.Sprintf("users not found: %s", strings.Join(unresolved, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(*status, team.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, team, team.Status.Conditions, status.Conditions)
+	team.Status = *status
+	return r.Client.Status().Update(ctx, team)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaTeamReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaTeam{}).
+		Complete(newReconciler(mgr, "GrafanaTeam", r))
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// GrafanaUserReconciler reconciles a GrafanaUser object
+type GrafanaUserReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanausers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanausers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanausers/finalizers,verbs=update
+
+// Reconcile creates users and converges their roles and org memberships in all matching Grafana instances
+func (r *GrafanaUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	user := &grafanav1beta1.GrafanaUser{}
+	err := r.Get(ctx, req.NamespacedName, user)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			controllerLog.Info("grafana user cr has been deleted", "name", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		controllerLog.Error(err, "error getting grafana user cr")
+		return ctrl.Result{}, err
+	}
+
+	if user.GetDeletionTimestamp() != nil {
+		return r.onUserDeleted(ctx, user)
+	}
+
+	// skip users without an instance selector
+	if user.Spec.InstanceSelector == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(user, grafanaFinalizer) {
+		controllerutil.AddFinalizer(user, grafanaFinalizer)
+		return ctrl.Result{Requeue: true}, r.Update(ctx, user)
+	}
+
+	password, passwordVersion, err := r.getPassword(ctx, user)
+	if err != nil {
+		controllerLog.Error(err, "error reading user password", "user", user.Name)
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, r.updateStatus(ctx, user, 0, false, err, user.Status.PasswordSecretVersion)
+	}
+
+	// the password is only set on existing users when the secret changed since the last successful reconcile
+	updatePassword := passwordVersion != user.Status.PasswordSecretVersion
+
+	instances, err := GetMatchingInstances(ctx, r.Client, user, user.Spec.InstanceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(instances.Items) == 0 {
+		controllerLog.Info("no matching instances found for user", "user", user.Name, "namespace", user.Namespace)
+	}
+
+	complete := true
+	var lastErr error
+
+	for _, grafana := range instances.Items {
+		// an admin url is required to interact with grafana
+		// the instance or route might not yet be ready
+		if grafana.Status.AdminUrl == "" {
+			controllerLog.Info("grafana instance not ready", "grafana", grafana.Name)
+			complete = false
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err == nil {
+			err = grafanaClient.CreateOrUpdateUser(user, password, updatePassword)
+		}
+		if err != nil {
+			complete = false
+			lastErr = err
+			controllerLog.Error(err, "error reconciling user", "user", user.Name, "grafana", grafana.Name)
+		}
+	}
+
+	if !complete {
+		passwordVersion = user.Status.PasswordSecretVersion
+	}
+
+	err = r.updateStatus(ctx, user, len(instances.Items), complete, lastErr, passwordVersion)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+	}
+
+	// another reconcile needed?
+	if complete {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+}
+
+func (r *GrafanaUserReconciler) onUserDeleted(ctx context.Context, user *grafanav1beta1.GrafanaUser) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(user, grafanaFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var instances grafanav1beta1.GrafanaList
+	var err error
+	if user.Spec.InstanceSelector != nil {
+		instances, err = GetMatchingInstances(ctx, r.Client, user, user.Spec.InstanceSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, grafana := range instances.Items {
+		if grafana.Status.AdminUrl == "" {
+			continue
+		}
+
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, &grafana)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+
+		err = grafanaClient.DeleteUser(user.UserLogin())
+		if err != nil {
+			controllerLog.Error(err, "error deleting user", "user", user.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(user, grafanaFinalizer)
+	return ctrl.Result{}, r.Update(ctx, user)
+}
+
+// updateStatus writes the version of the password secret, the last error and the conditions of the reconcile
+// when they changed
+func (r *GrafanaUserReconciler) updateStatus(ctx context.Context, user *grafanav1beta1.GrafanaUser, instances int, complete bool, lastErr error, passwordVersion string) error {
+	status := user.Status.DeepCopy()
+	status.LastMessage = getLastMessage(lastErr)
+	status.PasswordSecretVersion = passwordVersion
+	setSyncConditions(&status.Conditions, user.Generation, instances, complete, lastErr)
+	if equality.Semantic.DeepEqual(*status, user.Status) {
+		return nil
+	}
+	recordSyncEvent(r.Recorder, user, user.Status.Conditions, status.Conditions)
+	user.Status = *status
+	return r.Client.Status().Update(ctx, user)
+}
+
+// getPassword returns the password of a user and the version of its secret, the uid and resource version of
+// the secret and the key change with the password without recording anything derived from it
+func (r *GrafanaUserReconciler) getPassword(ctx context.Context, user *grafanav1beta1.GrafanaUser) (string, string, error) {
+	ref := user.Spec.PasswordSecretRef
+	if ref == nil {
+		return "", "", fmt.Errorf("user %s has no password secret", user.Name)
+	}
+
+	secret := &v1.Secret{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: user.Namespace, Name: ref.Name}, secret)
+	if err != nil {
+		return "", "", err
+	}
+	password, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", "", fmt.Errorf("secret %s/%s does not contain key %s", user.Namespace, ref.Name, ref.Key)
+	}
+	return string(password), fmt.Sprintf("%s/%s/%s", secret.UID, secret.ResourceVersion, ref.Key), nil
+}
+
+// requestsForSecret enqueues all users in the namespace of a secret that read their password from it
+func (r *GrafanaUserReconciler) requestsForSecret(secret client.Object) []reconcile.Request {
+	var list grafanav1beta1.GrafanaUserList
+	err := r.Client.List(context.Background(), &list, client.InNamespace(secret.GetNamespace()))
+	if err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, user := range list.Items {
+		if user.Spec.PasswordSecretRef != nil && user.Spec.PasswordSecretRef.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: user.Namespace,
+				Name:      user.Name,
+			}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&grafanav1beta1.GrafanaUser{}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.requestsForSecret)).
+		Complete(newReconciler(mgr, "GrafanaUser", r))
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// NewGrafanaHealthCheck returns a check that succeeds once the health api of at least one Grafana instance
+// responds, so that an operator that can't reach any instance is reported as not ready. The instances are
+// requested in parallel, the check passes if no instance is ready to be reached yet.
+func NewGrafanaHealthCheck(c client.Client) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx := req.Context()
+
+		var list grafanav1beta1.GrafanaList
+		err := c.List(ctx, &list)
+		if err != nil {
+			return err
+		}
+
+		// buffered so that the requests still running once an instance responded don't block
+		results := make(chan error, len(list.Items))
+		requested := 0
+		for i := range list.Items {
+			grafana := &list.Items[i]
+			if grafana.Status.AdminUrl == "" {
+				continue
+			}
+			requested++
+			go func() {
+				grafanaClient, err := client2.NewGrafanaClient(ctx, c, grafana)
+				if err == nil {
+					_, err = grafanaClient.GetHealth()
+				}
+				if err != nil {
+					err = fmt.Errorf("grafana %s/%s: %w", grafana.Namespace, grafana.Name, err)
+				}
+				results <- err
+			}()
+		}
+		if requested == 0 {
+			return nil
+		}
+
+		var lastErr error
+		for i := 0; i < requested; i++ {
+			lastErr = <-results
+			if lastErr == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("none of %d grafana instances is reachable, last error: %w", requested, lastErr)
+	}
+}
@@ -0,0 +1,95 @@
+// Package metrics exports the metrics of the operator on the controller-runtime metrics endpoint. Reconcile
+// counts, durations and errors per CRD are exported by controller-runtime as controller_runtime_reconcile_total,
+// controller_runtime_reconcile_time_seconds and controller_runtime_reconcile_errors_total with the kind of the
+// CRD as controller label.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "grafana_operator"
+
+var (
+	// GrafanaAPIRequests counts the requests to the Grafana api by instance, method and status code, requests
+	// without response have the status error
+	GrafanaAPIRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grafana_api_requests_total",
+		Help:      "Requests to the api of Grafana instances by instance, method and status code",
+	}, []string{"instance", "method", "status"})
+
+	// GrafanaAPIRequestDuration observes the latency of requests to the Grafana api
+	GrafanaAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grafana_api_request_duration_seconds",
+		Help:      "Latency of requests to the api of Grafana instances by instance and method",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"instance", "method"})
+
+	// ManagedDashboards is the number of dashboards imported into an instance
+	ManagedDashboards = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "managed_dashboards",
+		Help:      "Dashboards imported into a Grafana instance",
+	}, []string{"instance"})
+
+	// PluginRestarts counts the restarts of an instance to install changed plugins
+	PluginRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "plugin_restarts_total",
+		Help:      "Restarts of a Grafana instance to install changed plugins",
+	}, []string{"instance"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		GrafanaAPIRequests,
+		GrafanaAPIRequestDuration,
+		ManagedDashboards,
+		PluginRestarts,
+	)
+}
+
+// DeleteInstance removes the gauges, restart counts and api requests of a deleted instance
+func DeleteInstance(instance string) {
+	ManagedDashboards.DeleteLabelValues(instance)
+	PluginRestarts.DeleteLabelValues(instance)
+	deleteInstanceSeries(GrafanaAPIRequests, instance)
+	deleteInstanceSeries(GrafanaAPIRequestDuration, instance)
+}
+
+type metricVec interface {
+	prometheus.Collector
+	Delete(labels prometheus.Labels) bool
+}
+
+// deleteInstanceSeries removes the series of an instance from a vector with more labels than the instance,
+// the label values of the series are read from the collected metrics
+func deleteInstanceSeries(vec metricVec, instance string) {
+	collected := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(collected)
+		close(collected)
+	}()
+
+	var series []prometheus.Labels
+	for metric := range collected {
+		var m dto.Metric
+		if metric.Write(&m) != nil {
+			continue
+		}
+		labels := prometheus.Labels{}
+		for _, pair := range m.GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if labels["instance"] == instance {
+			series = append(series, labels)
+		}
+	}
+	for _, labels := range series {
+		vec.Delete(labels)
+	}
+}
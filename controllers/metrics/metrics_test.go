@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDeleteInstance(t *testing.T) {
+	for _, instance := range []string{"grafana/a", "grafana/b"} {
+		GrafanaAPIRequests.WithLabelValues(instance, "GET", "200").Inc()
+		GrafanaAPIRequests.WithLabelValues(instance, "POST", "error").Inc()
+		GrafanaAPIRequestDuration.WithLabelValues(instance, "GET").Observe(0.1)
+		ManagedDashboards.WithLabelValues(instance).Set(1)
+		PluginRestarts.WithLabelValues(instance).Inc()
+	}
+
+	DeleteInstance("grafana/a")
+
+	for name, test := range map[string]struct {
+		vec      metricVec
+		expected int
+	}{
+		"requests":           {vec: GrafanaAPIRequests, expected: 2},
+		"request durations":  {vec: GrafanaAPIRequestDuration, expected: 1},
+		"managed dashboards": {vec: ManagedDashboards, expected: 1},
+		"plugin restarts":    {vec: PluginRestarts, expected: 1},
+	} {
+		if count := testutil.CollectAndCount(test.vec); count != test.expected {
+			t.Errorf("%s has %d series after DeleteInstance(), expected %d", name, count, test.expected)
+		}
+	}
+}
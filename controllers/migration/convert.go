@@ -0,0 +1,400 @@
+// Package migration converts the Grafana, GrafanaDashboard and GrafanaDataSource resources of the v1alpha1 api
+// of grafana-operator v4 into v1beta1 resources. The v1alpha1 api has its own group and kinds, so it can't be
+// served as a version of the v1beta1 crds and converted by a conversion webhook.
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strings"
+)
+
+const (
+	// MigratedFromAnnotation records the v1alpha1 resource a resource was converted from, resources without
+	// it are never overwritten
+	MigratedFromAnnotation = "grafana.integreatly.org/migrated-from"
+	// SkippedFieldsAnnotation lists the fields of the v1alpha1 spec without an equivalent in v1beta1
+	SkippedFieldsAnnotation = "grafana.integreatly.org/migration-skipped"
+	// InstanceLabel selects the instances converted from v1alpha1, v4 imported the dashboards and datasources
+	// of a namespace into its instance
+	InstanceLabel = "grafana.integreatly.org/migrated-instance"
+)
+
+var (
+	invalidNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+	// uids of v1beta1 datasources are validated by the crd
+	validDatasourceUID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,40}$`)
+)
+
+// Kind is a v1alpha1 kind and its conversion
+type Kind struct {
+	GroupVersionKind schema.GroupVersionKind
+	Convert          func(source *unstructured.Unstructured) (*Conversion, error)
+}
+
+// Kinds are the converted v1alpha1 kinds
+var Kinds = []Kind{
+	{
+		GroupVersionKind: schema.GroupVersionKind{Group: "integreatly.org", Version: "v1alpha1", Kind: "Grafana"},
+		Convert:          ConvertGrafana,
+	},
+	{
+		GroupVersionKind: schema.GroupVersionKind{Group: "integreatly.org", Version: "v1alpha1", Kind: "GrafanaDashboard"},
+		Convert:          ConvertDashboard,
+	},
+	{
+		GroupVersionKind: schema.GroupVersionKind{Group: "integreatly.org", Version: "v1alpha1", Kind: "GrafanaDataSource"},
+		Convert:          ConvertDatasource,
+	},
+}
+
+// Conversion holds the resources a v1alpha1 resource is converted into and the fields of its spec that were
+// not converted
+type Conversion struct {
+	Objects []client.Object
+	Skipped []string
+}
+
+// ConvertGrafana converts the config, replicas, version and client timeout of an instance
+func ConvertGrafana(source *unstructured.Unstructured) (*Conversion, error) {
+	var spec grafanaSpec
+	err := decodeSpec(source, &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	grafana := &grafanav1beta1.Grafana{
+		ObjectMeta: getObjectMeta(source, source.GetName()),
+		Spec: grafanav1beta1.GrafanaSpec{
+			Config:  spec.Config,
+			Version: getImageTag(spec.BaseImage),
+		},
+	}
+	grafana.Labels[InstanceLabel] = "true"
+	if spec.Deployment != nil {
+		grafana.Spec.Replicas = spec.Deployment.Replicas
+	}
+	if spec.Client != nil && spec.Client.TimeoutSeconds != nil {
+		grafana.Spec.Client = &grafanav1beta1.GrafanaClient{
+			TimeoutSeconds: spec.Client.TimeoutSeconds,
+		}
+	}
+
+	return newConversion(source, map[string][]string{
+		"config":     nil,
+		"baseImage":  nil,
+		"deployment": {"replicas"},
+		"client":     {"timeout"},
+	}, grafana), nil
+}
+
+// ConvertDashboard converts a dashboard, the custom folder is converted into a GrafanaFolder shared by the
+// dashboards of the folder
+func ConvertDashboard(source *unstructured.Unstructured) (*Conversion, error) {
+	var spec dashboardSpec
+	err := decodeSpec(source, &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &grafanav1beta1.GrafanaDashboard{
+		ObjectMeta: getObjectMeta(source, source.GetName()),
+		Spec: grafanav1beta1.GrafanaDashboardSpec{
+			Json:                 spec.Json,
+			GzipJson:             spec.GzipJson,
+			Url:                  spec.Url,
+			Plugins:              spec.Plugins,
+			ContentCacheDuration: spec.ContentCacheDuration,
+			InstanceSelector:     getInstanceSelector(),
+		},
+	}
+	// configmaps of gzipped json are read from the binary data
+	for _, ref := range []*v1.ConfigMapKeySelector{spec.ConfigMapRef, spec.GzipConfigMapRef} {
+		if ref != nil {
+			dashboard.Spec.ConfigMapRef = &grafanav1beta1.GrafanaDashboardConfigMapRef{
+				Name: ref.Name,
+				Key:  ref.Key,
+			}
+		}
+	}
+	if spec.GrafanaCom != nil {
+		dashboard.Spec.GrafanaCom = &grafanav1beta1.GrafanaComDashboardReference{
+			Id:       spec.GrafanaCom.Id,
+			Revision: spec.GrafanaCom.Revision,
+		}
+	}
+	for _, datasource := range spec.Datasources {
+		dashboard.Spec.Datasources = append(dashboard.Spec.Datasources, grafanav1beta1.GrafanaDashboardDatasource{
+			InputName:      datasource.InputName,
+			DatasourceName: datasource.DatasourceName,
+		})
+	}
+
+	objects := []client.Object{dashboard}
+	if spec.CustomFolderName != "" {
+		folder := &grafanav1beta1.GrafanaFolder{
+			ObjectMeta: getObjectMeta(source, getName("folder", spec.CustomFolderName)),
+			Spec: grafanav1beta1.GrafanaFolderSpec{
+				Title:            spec.CustomFolderName,
+				InstanceSelector: getInstanceSelector(),
+			},
+		}
+		folder.Annotations[MigratedFromAnnotation] = fmt.Sprintf("GrafanaDashboard customFolderName %s", spec.CustomFolderName)
+		dashboard.Spec.FolderRef = folder.Name
+		objects = append([]client.Object{folder}, objects...)
+	}
+
+	return newConversion(source, map[string][]string{
+		"json":                 nil,
+		"gzipJson":             nil,
+		"url":                  nil,
+		"configMapRef":         nil,
+		"gzipConfigMapRef":     nil,
+		"grafanaCom":           nil,
+		"datasources":          nil,
+		"plugins":              nil,
+		"customFolderName":     nil,
+		"contentCacheDuration": nil,
+	}, objects...), nil
+}
+
+// ConvertDatasource converts each datasource of a GrafanaDataSource into a GrafanaDatasource, passwords and
+// secure json data are moved into a secret
+func ConvertDatasource(source *unstructured.Unstructured) (*Conversion, error) {
+	var spec datasourceSpec
+	err := decodeSpec(source, &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []client.Object
+	var skipped []string
+	for i, datasource := range spec.Datasources {
+		name := source.GetName()
+		if len(spec.Datasources) > 1 {
+			name = getName(source.GetName(), datasource.Name)
+		}
+
+		jsonData := map[string]interface{}{}
+		for key, value := range datasource.JsonData {
+			jsonData[key] = value
+		}
+		err = mergeRawJson(jsonData, datasource.CustomJsonData)
+		if err != nil {
+			return nil, fmt.Errorf("datasources[%d].customJsonData: %w", i, err)
+		}
+		secureJsonData := map[string]interface{}{}
+		for key, value := range datasource.SecureJsonData {
+			secureJsonData[key] = value
+		}
+		err = mergeRawJson(secureJsonData, datasource.CustomSecureJsonData)
+		if err != nil {
+			return nil, fmt.Errorf("datasources[%d].customSecureJsonData: %w", i, err)
+		}
+		if datasource.Password != "" {
+			secureJsonData["password"] = datasource.Password
+		}
+		if datasource.BasicAuthPassword != "" {
+			secureJsonData["basicAuthPassword"] = datasource.BasicAuthPassword
+		}
+
+		converted := &grafanav1beta1.GrafanaDatasource{
+			ObjectMeta: getObjectMeta(source, name),
+			Spec: grafanav1beta1.GrafanaDatasourceSpec{
+				Datasource: &grafanav1beta1.GrafanaDatasourceInternal{
+					Name:          datasource.Name,
+					Type:          datasource.Type,
+					URL:           datasource.Url,
+					Access:        datasource.Access,
+					Database:      datasource.Database,
+					User:          datasource.User,
+					IsDefault:     datasource.IsDefault,
+					BasicAuth:     datasource.BasicAuth,
+					BasicAuthUser: datasource.BasicAuthUser,
+					Editable:      datasource.Editable,
+				},
+				InstanceSelector: getInstanceSelector(),
+			},
+		}
+		if len(jsonData) > 0 {
+			raw, err := json.Marshal(jsonData)
+			if err != nil {
+				return nil, err
+			}
+			converted.Spec.Datasource.JSONData = &apiextensionsv1.JSON{Raw: raw}
+		}
+		if validDatasourceUID.MatchString(datasource.Uid) {
+			converted.Spec.UID = datasource.Uid
+		} else if datasource.Uid != "" {
+			skipped = append(skipped, fmt.Sprintf("datasources[%d].uid", i))
+		}
+
+		if len(secureJsonData) > 0 {
+			secret := &v1.Secret{
+				ObjectMeta: getObjectMeta(source, name+"-secure-json-data"),
+				Data:       map[string][]byte{},
+			}
+			for key, value := range secureJsonData {
+				secret.Data[key] = []byte(fmt.Sprint(value))
+			}
+			for _, key := range sortedKeys(secureJsonData) {
+				converted.Spec.ValuesFrom = append(converted.Spec.ValuesFrom, grafanav1beta1.ValueFrom{
+					TargetPath: "secureJsonData." + key,
+					ValueFrom: grafanav1beta1.ValueFromSource{
+						SecretKeyRef: &v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: secret.Name},
+							Key:                  key,
+						},
+					},
+				})
+			}
+			objects = append(objects, secret)
+		}
+		objects = append(objects, converted)
+
+		// organizations are referenced by GrafanaOrganization resources in v1beta1
+		if datasource.OrgId > 1 {
+			skipped = append(skipped, fmt.Sprintf("datasources[%d].orgId", i))
+		}
+		if datasource.WithCredentials {
+			skipped = append(skipped, fmt.Sprintf("datasources[%d].withCredentials", i))
+		}
+	}
+
+	conversion := newConversion(source, map[string][]string{
+		"name":        nil,
+		"datasources": nil,
+	}, objects...)
+	conversion.Skipped = append(conversion.Skipped, skipped...)
+	return conversion, nil
+}
+
+func decodeSpec(source *unstructured.Unstructured, spec interface{}) error {
+	raw, err := json.Marshal(source.Object["spec"])
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(raw, spec)
+	if err != nil {
+		return fmt.Errorf("spec of %s %s/%s: %w", source.GetKind(), source.GetNamespace(), source.GetName(), err)
+	}
+	return nil
+}
+
+// newConversion returns the conversion of the objects, fields of the spec not in supported are skipped. Nested
+// fields are only supported if they are listed for their parent.
+func newConversion(source *unstructured.Unstructured, supported map[string][]string, objects ...client.Object) *Conversion {
+	spec, _ := source.Object["spec"].(map[string]interface{})
+
+	var skipped []string
+	for _, key := range sortedKeys(spec) {
+		nested, ok := supported[key]
+		if !ok {
+			skipped = append(skipped, key)
+			continue
+		}
+		value, _ := spec[key].(map[string]interface{})
+		if nested == nil || value == nil {
+			continue
+		}
+		for _, nestedKey := range sortedKeys(value) {
+			if !contains(nested, nestedKey) {
+				skipped = append(skipped, key+"."+nestedKey)
+			}
+		}
+	}
+
+	return &Conversion{
+		Objects: objects,
+		Skipped: skipped,
+	}
+}
+
+// getObjectMeta keeps the labels of the v1alpha1 resource and records where the resource was converted from
+func getObjectMeta(source *unstructured.Unstructured, name string) metav1.ObjectMeta {
+	labels := map[string]string{}
+	for key, value := range source.GetLabels() {
+		labels[key] = value
+	}
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: source.GetNamespace(),
+		Labels:    labels,
+		Annotations: map[string]string{
+			MigratedFromAnnotation: fmt.Sprintf("%s %s", source.GetKind(), source.GetName()),
+		},
+	}
+}
+
+func getInstanceSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			InstanceLabel: "true",
+		},
+	}
+}
+
+// getName returns a valid resource name of a prefix and a title, titles that can't be used in a name are
+// replaced by a hash
+func getName(prefix string, title string) string {
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if name == "" || len(prefix)+len(name) > 62 {
+		name = fmt.Sprintf("%x", sha256.Sum256([]byte(title)))[:10]
+	}
+	return prefix + "-" + name
+}
+
+// getImageTag returns the tag of an image, the version of v1beta1 instances defaults to the version of the
+// operator if empty
+func getImageTag(image string) string {
+	if digest := strings.Index(image, "@"); digest >= 0 {
+		image = image[:digest]
+	}
+	if tag := strings.LastIndex(image, ":"); tag > strings.LastIndex(image, "/") {
+		return image[tag+1:]
+	}
+	return ""
+}
+
+func mergeRawJson(values map[string]interface{}, raw json.RawMessage) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	var custom map[string]interface{}
+	err := json.Unmarshal(raw, &custom)
+	if err != nil {
+		return err
+	}
+	for key, value := range custom {
+		values[key] = value
+	}
+	return nil
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
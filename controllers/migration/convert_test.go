@@ -0,0 +1,306 @@
+package migration
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newSource(kind string, name string, spec string) *unstructured.Unstructured {
+	source := &unstructured.Unstructured{}
+	source.SetAPIVersion("integreatly.org/v1alpha1")
+	source.SetKind(kind)
+	source.SetNamespace("grafana")
+	source.SetName(name)
+	source.SetLabels(map[string]string{"app": "grafana"})
+	var values map[string]interface{}
+	err := json.Unmarshal([]byte(spec), &values)
+	if err != nil {
+		panic(err)
+	}
+	source.Object["spec"] = values
+	return source
+}
+
+func objectNames(objects []client.Object) []string {
+	var names []string
+	for _, object := range objects {
+		names = append(names, object.GetName())
+	}
+	return names
+}
+
+func TestConvertDatasource(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		objects  []string
+		skipped  []string
+		expected map[string]grafanav1beta1.GrafanaDatasourceSpec
+	}{
+		{
+			name:    "a single datasource keeps the name",
+			spec:    `{"name":"ds.yaml","datasources":[{"name":"Prometheus","type":"prometheus","url":"http://prometheus:9090","access":"proxy","uid":"prom"}]}`,
+			objects: []string{"ds"},
+			expected: map[string]grafanav1beta1.GrafanaDatasourceSpec{
+				"ds": {
+					UID: "prom",
+					Datasource: &grafanav1beta1.GrafanaDatasourceInternal{
+						Name:   "Prometheus",
+						Type:   "prometheus",
+						URL:    "http://prometheus:9090",
+						Access: "proxy",
+					},
+				},
+			},
+		},
+		{
+			name:    "a list of datasources is split",
+			spec:    `{"name":"ds.yaml","datasources":[{"name":"Prometheus","type":"prometheus"},{"name":"Loki Logs","type":"loki","database":"logs","user":"admin"}]}`,
+			objects: []string{"ds-prometheus", "ds-loki-logs"},
+			expected: map[string]grafanav1beta1.GrafanaDatasourceSpec{
+				"ds-prometheus": {
+					Datasource: &grafanav1beta1.GrafanaDatasourceInternal{Name: "Prometheus", Type: "prometheus"},
+				},
+				"ds-loki-logs": {
+					Datasource: &grafanav1beta1.GrafanaDatasourceInternal{Name: "Loki Logs", Type: "loki", Database: "logs", User: "admin"},
+				},
+			},
+		},
+		{
+			name:    "json data is merged",
+			spec:    `{"name":"ds.yaml","datasources":[{"name":"a","type":"loki","jsonData":{"maxLines":100,"timeout":10},"customJsonData":{"timeout":30}}]}`,
+			objects: []string{"ds"},
+			expected: map[string]grafanav1beta1.GrafanaDatasourceSpec{
+				"ds": {
+					Datasource: &grafanav1beta1.GrafanaDatasourceInternal{Name: "a", Type: "loki"},
+				},
+			},
+		},
+		{
+			name:    "passwords are moved into a secret",
+			spec:    `{"name":"ds.yaml","datasources":[{"name":"a","type":"postgres","password":"secret","basicAuthPassword":"basic","secureJsonData":{"tlsCACert":"ca"}}]}`,
+			objects: []string{"ds-secure-json-data", "ds"},
+			expected: map[string]grafanav1beta1.GrafanaDatasourceSpec{
+				"ds": {
+					Datasource: &grafanav1beta1.GrafanaDatasourceInternal{Name: "a", Type: "postgres"},
+					ValuesFrom: []grafanav1beta1.ValueFrom{
+						newSecretValueFrom("ds-secure-json-data", "basicAuthPassword"),
+						newSecretValueFrom("ds-secure-json-data", "password"),
+						newSecretValueFrom("ds-secure-json-data", "tlsCACert"),
+					},
+				},
+			},
+		},
+		{
+			name:    "fields without equivalent are skipped",
+			spec:    `{"name":"ds.yaml","datasources":[{"name":"a","type":"loki","uid":"invalid uid","orgId":2,"withCredentials":true}],"extra":true}`,
+			objects: []string{"ds"},
+			skipped: []string{"extra", "datasources[0].uid", "datasources[0].orgId", "datasources[0].withCredentials"},
+			expected: map[string]grafanav1beta1.GrafanaDatasourceSpec{
+				"ds": {
+					Datasource: &grafanav1beta1.GrafanaDatasourceInternal{Name: "a", Type: "loki"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conversion, err := ConvertDatasource(newSource("GrafanaDataSource", "ds", test.spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := objectNames(conversion.Objects); !reflect.DeepEqual(names, test.objects) {
+				t.Errorf("ConvertDatasource() objects = %v, expected %v", names, test.objects)
+			}
+			if !reflect.DeepEqual(conversion.Skipped, test.skipped) {
+				t.Errorf("ConvertDatasource() skipped = %v, expected %v", conversion.Skipped, test.skipped)
+			}
+			for _, object := range conversion.Objects {
+				datasource, ok := object.(*grafanav1beta1.GrafanaDatasource)
+				if !ok {
+					continue
+				}
+				if datasource.Annotations[MigratedFromAnnotation] != "GrafanaDataSource ds" {
+					t.Errorf("%s annotation = %q, expected %q", datasource.Name, datasource.Annotations[MigratedFromAnnotation], "GrafanaDataSource ds")
+				}
+				if datasource.Labels["app"] != "grafana" {
+					t.Errorf("%s labels = %v, expected the labels of the source", datasource.Name, datasource.Labels)
+				}
+				if !reflect.DeepEqual(datasource.Spec.InstanceSelector, getInstanceSelector()) {
+					t.Errorf("%s instance selector = %v, expected %v", datasource.Name, datasource.Spec.InstanceSelector, getInstanceSelector())
+				}
+				// json data is compared separately, the order of the marshalled keys is not part of the test
+				datasource.Spec.Datasource.JSONData = nil
+				datasource.Spec.InstanceSelector = nil
+				expected := test.expected[datasource.Name]
+				if !reflect.DeepEqual(datasource.Spec, expected) {
+					t.Errorf("%s spec = %+v, expected %+v", datasource.Name, datasource.Spec, expected)
+				}
+			}
+		})
+	}
+}
+
+func newSecretValueFrom(name string, key string) grafanav1beta1.ValueFrom {
+	return grafanav1beta1.ValueFrom{
+		TargetPath: "secureJsonData." + key,
+		ValueFrom: grafanav1beta1.ValueFromSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: name},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+func TestConvertDatasourceSecureJsonData(t *testing.T) {
+	source := newSource("GrafanaDataSource", "ds", `{"name":"ds.yaml","datasources":[{"name":"a","type":"loki","jsonData":{"maxLines":100,"timeout":10},"customJsonData":{"timeout":30},"password":"secret","customSecureJsonData":{"token":"abc"}}]}`)
+	conversion, err := ConvertDatasource(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := conversion.Objects[0].(*v1.Secret)
+	expectedData := map[string][]byte{"password": []byte("secret"), "token": []byte("abc")}
+	if !reflect.DeepEqual(secret.Data, expectedData) {
+		t.Errorf("secret data = %q, expected %q", secret.Data, expectedData)
+	}
+
+	datasource := conversion.Objects[1].(*grafanav1beta1.GrafanaDatasource)
+	var jsonData map[string]interface{}
+	err = json.Unmarshal(datasource.Spec.Datasource.JSONData.Raw, &jsonData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJsonData := map[string]interface{}{"maxLines": float64(100), "timeout": float64(30)}
+	if !reflect.DeepEqual(jsonData, expectedJsonData) {
+		t.Errorf("json data = %v, expected %v", jsonData, expectedJsonData)
+	}
+}
+
+func TestConvertDashboard(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		objects   []string
+		skipped   []string
+		folderRef string
+		configMap *grafanav1beta1.GrafanaDashboardConfigMapRef
+	}{
+		{
+			name:    "json dashboard",
+			spec:    `{"json":"{}"}`,
+			objects: []string{"dashboard"},
+		},
+		{
+			name:      "custom folder",
+			spec:      `{"json":"{}","customFolderName":"My Team"}`,
+			objects:   []string{"folder-my-team", "dashboard"},
+			folderRef: "folder-my-team",
+		},
+		{
+			name:      "gzip configmap",
+			spec:      `{"gzipConfigMapRef":{"name":"dashboards","key":"a.json.gz"}}`,
+			objects:   []string{"dashboard"},
+			configMap: &grafanav1beta1.GrafanaDashboardConfigMapRef{Name: "dashboards", Key: "a.json.gz"},
+		},
+		{
+			name:    "fields without equivalent are skipped",
+			spec:    `{"json":"{}","jsonnet":"{}","customFolderName":""}`,
+			objects: []string{"dashboard"},
+			skipped: []string{"jsonnet"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conversion, err := ConvertDashboard(newSource("GrafanaDashboard", "dashboard", test.spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := objectNames(conversion.Objects); !reflect.DeepEqual(names, test.objects) {
+				t.Errorf("ConvertDashboard() objects = %v, expected %v", names, test.objects)
+			}
+			if !reflect.DeepEqual(conversion.Skipped, test.skipped) {
+				t.Errorf("ConvertDashboard() skipped = %v, expected %v", conversion.Skipped, test.skipped)
+			}
+			dashboard := conversion.Objects[len(conversion.Objects)-1].(*grafanav1beta1.GrafanaDashboard)
+			if dashboard.Spec.FolderRef != test.folderRef {
+				t.Errorf("ConvertDashboard() folderRef = %q, expected %q", dashboard.Spec.FolderRef, test.folderRef)
+			}
+			if !reflect.DeepEqual(dashboard.Spec.ConfigMapRef, test.configMap) {
+				t.Errorf("ConvertDashboard() configMapRef = %v, expected %v", dashboard.Spec.ConfigMapRef, test.configMap)
+			}
+		})
+	}
+}
+
+func TestConvertGrafana(t *testing.T) {
+	source := newSource("Grafana", "grafana", `{"baseImage":"grafana/grafana:9.1.0","deployment":{"replicas":2,"nodeSelector":{}},"client":{"timeout":10,"preferService":true},"ingress":{}}`)
+	conversion, err := ConvertGrafana(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grafana := conversion.Objects[0].(*grafanav1beta1.Grafana)
+	if grafana.Spec.Version != "9.1.0" {
+		t.Errorf("ConvertGrafana() version = %q, expected %q", grafana.Spec.Version, "9.1.0")
+	}
+	if grafana.Spec.Replicas == nil || *grafana.Spec.Replicas != 2 {
+		t.Errorf("ConvertGrafana() replicas = %v, expected 2", grafana.Spec.Replicas)
+	}
+	if grafana.Spec.Client == nil || *grafana.Spec.Client.TimeoutSeconds != 10 {
+		t.Errorf("ConvertGrafana() client = %v, expected a timeout of 10", grafana.Spec.Client)
+	}
+	if grafana.Labels[InstanceLabel] != "true" {
+		t.Errorf("ConvertGrafana() labels = %v, expected %s", grafana.Labels, InstanceLabel)
+	}
+	expectedSkipped := []string{"client.preferService", "deployment.nodeSelector", "ingress"}
+	if !reflect.DeepEqual(conversion.Skipped, expectedSkipped) {
+		t.Errorf("ConvertGrafana() skipped = %v, expected %v", conversion.Skipped, expectedSkipped)
+	}
+}
+
+func TestGetImageTag(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{image: "grafana/grafana:9.1.0", expected: "9.1.0"},
+		{image: "registry:5000/grafana/grafana", expected: ""},
+		{image: "registry:5000/grafana/grafana:9.1.0@sha256:abc", expected: "9.1.0"},
+		{image: "", expected: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			if tag := getImageTag(test.image); tag != test.expected {
+				t.Errorf("getImageTag(%q) = %q, expected %q", test.image, tag, test.expected)
+			}
+		})
+	}
+}
+
+func TestGetName(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{title: "Loki Logs", expected: "ds-loki-logs"},
+		{title: "  prod/eu_1 ", expected: "ds-prod-eu-1"},
+		{title: "日本", expected: "ds-cf2abf0c5b"},
+	}
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if name := getName("ds", test.title); name != test.expected {
+				t.Errorf("getName(%q) = %q, expected %q", test.title, name, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package migration
+
+import (
+	"encoding/json"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the parts of the v1alpha1 api of grafana-operator v4 that have an equivalent in v1beta1, other fields are
+// reported as skipped
+
+type grafanaSpec struct {
+	// the config of v1alpha1 has the same sections and keys as v1beta1
+	Config     grafanav1beta1.GrafanaConfig `json:"config,omitempty"`
+	BaseImage  string                       `json:"baseImage,omitempty"`
+	Deployment *grafanaDeployment           `json:"deployment,omitempty"`
+	Client     *grafanaClient               `json:"client,omitempty"`
+}
+
+type grafanaDeployment struct {
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+type grafanaClient struct {
+	TimeoutSeconds *int `json:"timeout,omitempty"`
+}
+
+type dashboardSpec struct {
+	Json                 string                    `json:"json,omitempty"`
+	GzipJson             []byte                    `json:"gzipJson,omitempty"`
+	Url                  string                    `json:"url,omitempty"`
+	ConfigMapRef         *v1.ConfigMapKeySelector  `json:"configMapRef,omitempty"`
+	GzipConfigMapRef     *v1.ConfigMapKeySelector  `json:"gzipConfigMapRef,omitempty"`
+	GrafanaCom           *dashboardGrafanaCom      `json:"grafanaCom,omitempty"`
+	Datasources          []dashboardDatasource     `json:"datasources,omitempty"`
+	Plugins              grafanav1beta1.PluginList `json:"plugins,omitempty"`
+	CustomFolderName     string                    `json:"customFolderName,omitempty"`
+	ContentCacheDuration *metav1.Duration          `json:"contentCacheDuration,omitempty"`
+}
+
+type dashboardGrafanaCom struct {
+	Id       int  `json:"id"`
+	Revision *int `json:"revision,omitempty"`
+}
+
+type dashboardDatasource struct {
+	InputName      string `json:"inputName"`
+	DatasourceName string `json:"datasourceName"`
+}
+
+type datasourceSpec struct {
+	Name        string       `json:"name"`
+	Datasources []datasource `json:"datasources"`
+}
+
+type datasource struct {
+	Name                 string                 `json:"name"`
+	Type                 string                 `json:"type"`
+	Uid                  string                 `json:"uid,omitempty"`
+	Url                  string                 `json:"url,omitempty"`
+	Access               string                 `json:"access,omitempty"`
+	Database             string                 `json:"database,omitempty"`
+	User                 string                 `json:"user,omitempty"`
+	Password             string                 `json:"password,omitempty"`
+	OrgId                int                    `json:"orgId,omitempty"`
+	IsDefault            *bool                  `json:"isDefault,omitempty"`
+	BasicAuth            *bool                  `json:"basicAuth,omitempty"`
+	BasicAuthUser        string                 `json:"basicAuthUser,omitempty"`
+	BasicAuthPassword    string                 `json:"basicAuthPassword,omitempty"`
+	WithCredentials      bool                   `json:"withCredentials,omitempty"`
+	Editable             *bool                  `json:"editable,omitempty"`
+	JsonData             map[string]interface{} `json:"jsonData,omitempty"`
+	SecureJsonData       map[string]interface{} `json:"secureJsonData,omitempty"`
+	CustomJsonData       json.RawMessage        `json:"customJsonData,omitempty"`
+	CustomSecureJsonData json.RawMessage        `json:"customSecureJsonData,omitempty"`
+}
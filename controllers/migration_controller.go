@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/migration"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+)
+
+const (
+	eventMigrated        = "Migrated"
+	eventMigrationFailed = "MigrationFailed"
+)
+
+// MigrationReconciler converts the resources of a kind of the v1alpha1 api of grafana-operator v4 into v1beta1
+// resources. The converted resources follow the v1alpha1 resource until it is deleted, they are kept after
+// that and are changed directly from then on.
+type MigrationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Kind     migration.Kind
+}
+
+//+kubebuilder:rbac:groups=integreatly.org,resources=grafanas;grafanadashboards;grafanadatasources,verbs=get;list;watch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanas;grafanadashboards;grafanadatasources;grafanafolders,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+
+func (r *MigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	controllerLog := log.FromContext(ctx)
+
+	source := &unstructured.Unstructured{}
+	source.SetGroupVersionKind(r.Kind.GroupVersionKind)
+	err := r.Get(ctx, req.NamespacedName, source)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if source.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	conversion, err := r.Kind.Convert(source)
+	if err != nil {
+		r.Recorder.Eventf(source, v1.EventTypeWarning, eventMigrationFailed, "error converting to v1beta1: %s", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	var changed []string
+	for _, object := range conversion.Objects {
+		if len(conversion.Skipped) > 0 {
+			object.GetAnnotations()[migration.SkippedFieldsAnnotation] = strings.Join(conversion.Skipped, ",")
+		}
+		updated, err := r.apply(ctx, object)
+		if err != nil {
+			controllerLog.Error(err, "error migrating v1alpha1 resource", "kind", source.GetKind(), "name", source.GetName())
+			r.Recorder.Eventf(source, v1.EventTypeWarning, eventMigrationFailed, "error converting to v1beta1: %s", err.Error())
+			return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+		}
+		if updated {
+			kind, _, _ := r.Scheme.ObjectKinds(object)
+			changed = append(changed, fmt.Sprintf("%s %s", kind[0].Kind, object.GetName()))
+		}
+	}
+
+	if len(changed) > 0 {
+		controllerLog.Info("migrated v1alpha1 resource", "kind", source.GetKind(), "name", source.GetName(), "resources", changed)
+		r.Recorder.Eventf(source, v1.EventTypeNormal, eventMigrated, "converted to %s", strings.Join(changed, ", "))
+		if len(conversion.Skipped) > 0 {
+			r.Recorder.Eventf(source, v1.EventTypeWarning, eventMigrated, "fields without an equivalent in v1beta1 were not converted: %s", strings.Join(conversion.Skipped, ", "))
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// apply creates or updates a converted resource and returns true if it changed, resources that were not
+// converted from v1alpha1 are not overwritten
+func (r *MigrationReconciler) apply(ctx context.Context, desired client.Object) (bool, error) {
+	existing := desired.DeepCopyObject().(client.Object)
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if errors.IsNotFound(err) {
+		return true, r.Create(ctx, desired)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := existing.GetAnnotations()[migration.MigratedFromAnnotation]; !ok {
+		return false, fmt.Errorf("%s/%s already exists and was not converted from v1alpha1", existing.GetNamespace(), existing.GetName())
+	}
+
+	// metadata added by users and controllers is kept
+	labels := existing.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range desired.GetLabels() {
+		labels[key] = value
+	}
+	annotations := existing.GetAnnotations()
+	delete(annotations, migration.SkippedFieldsAnnotation)
+	for key, value := range desired.GetAnnotations() {
+		annotations[key] = value
+	}
+	desired.SetLabels(labels)
+	desired.SetAnnotations(annotations)
+	desired.SetFinalizers(existing.GetFinalizers())
+	desired.SetResourceVersion(existing.GetResourceVersion())
+
+	// updates without changes keep the resource version
+	err = r.Update(ctx, desired)
+	if err != nil {
+		return false, err
+	}
+	return desired.GetResourceVersion() != existing.GetResourceVersion(), nil
+}
+
+func (r *MigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	source := &unstructured.Unstructured{}
+	source.SetGroupVersionKind(r.Kind.GroupVersionKind)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("v1alpha1-" + strings.ToLower(r.Kind.GroupVersionKind.Kind)).
+		For(source).
+		Complete(newReconciler(mgr, "Migration"+r.Kind.GroupVersionKind.Kind, r))
+}
+
+// SetupMigrationWithManager starts the conversion of all v1alpha1 kinds, their crds have to be installed
+func SetupMigrationWithManager(mgr ctrl.Manager) error {
+	for _, kind := range migration.Kinds {
+		err := (&MigrationReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+			Kind:     kind,
+		}).SetupWithManager(mgr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func GetBackupWriterPod(cr *grafanav1beta1.GrafanaBackup, scheme *runtime.Scheme) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-backup-writer", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+
+	if scheme != nil {
+		controllerutil.SetOwnerReference(cr, pod, scheme)
+	}
+	return pod
+}
+
+func GetRestoreReaderPod(cr *grafanav1beta1.GrafanaRestore, scheme *runtime.Scheme) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-restore-reader", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+
+	if scheme != nil {
+		controllerutil.SetOwnerReference(cr, pod, scheme)
+	}
+	return pod
+}
@@ -19,3 +19,23 @@ func GetPluginsConfigMap(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1
 	controllerutil.SetOwnerReference(cr, config, scheme)
 	return config
 }
+
+// kinds of the resources requesting plugins in the plugins config map
+const (
+	PluginsKindDashboard  = "dashboard"
+	PluginsKindDatasource = "datasource"
+)
+
+// GetPluginsKey returns the key of the plugins requested by a resource in the plugins config map. Namespaces
+// contain no dots, so resources of the same name in different namespaces have their own entries.
+func GetPluginsKey(kind string, namespace string, name string) string {
+	return fmt.Sprintf("%s.%s.%s", kind, namespace, name)
+}
+
+// GetLegacyPluginsKey returns the key previous versions of the operator stored the plugins of a resource with
+func GetLegacyPluginsKey(kind string, name string) string {
+	if kind == PluginsKindDatasource {
+		return fmt.Sprintf("%v-datasource", name)
+	}
+	return name
+}
@@ -5,9 +5,12 @@ import (
 	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
 	routev1 "github.com/openshift/api/route/v1"
 	v13 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
 	v12 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -23,6 +26,18 @@ func GetGrafanaConfigMap(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1
 	return config
 }
 
+// GetGrafanaConfigSecret returns the secret holding the rendered grafana.ini
+func GetGrafanaConfigSecret(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-ini", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+	controllerutil.SetOwnerReference(cr, secret, scheme)
+	return secret
+}
+
 func GetGrafanaAdminSecret(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.Secret {
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -37,6 +52,49 @@ func GetGrafanaAdminSecret(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *
 	return secret
 }
 
+// GetGrafanaUpgradeBackupSecret returns the secret the backup taken before an upgrade is stored in
+func GetGrafanaUpgradeBackupSecret(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-upgrade-backup", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+
+	if scheme != nil {
+		controllerutil.SetOwnerReference(cr, secret, scheme)
+	}
+	return secret
+}
+
+func GetGrafanaLdapSecret(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-ldap", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+
+	if scheme != nil {
+		controllerutil.SetOwnerReference(cr, secret, scheme)
+	}
+	return secret
+}
+
+func GetGrafanaCloudStackSecret(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-cloud-stack", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+
+	if scheme != nil {
+		controllerutil.SetOwnerReference(cr, secret, scheme)
+	}
+	return secret
+}
+
 func GetGrafanaDataPVC(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.PersistentVolumeClaim {
 	pvc := &v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -48,14 +106,23 @@ func GetGrafanaDataPVC(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.P
 	return pvc
 }
 
+// GetGrafanaServiceAccount returns the service account of the Grafana pods, a service account that isn't
+// created by the operator has no owner reference
 func GetGrafanaServiceAccount(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.ServiceAccount {
+	name := fmt.Sprintf("%s-sa", cr.Name)
+	if cr.Spec.ServiceAccount != nil && cr.Spec.ServiceAccount.Name != "" {
+		name = cr.Spec.ServiceAccount.Name
+	}
+
 	sa := &v1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-sa", cr.Name),
+			Name:      name,
 			Namespace: cr.Namespace,
 		},
 	}
-	controllerutil.SetOwnerReference(cr, sa, scheme)
+	if cr.Spec.ServiceAccount.IsCreated() {
+		controllerutil.SetOwnerReference(cr, sa, scheme)
+	}
 	return sa
 }
 
@@ -70,6 +137,17 @@ func GetGrafanaService(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.S
 	return service
 }
 
+func GetGrafanaAlertingService(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.Service {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-alerting", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+	controllerutil.SetOwnerReference(cr, service, scheme)
+	return service
+}
+
 func GetGrafanaIngress(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v12.Ingress {
 	ingress := &v12.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
@@ -92,6 +170,51 @@ func GetGrafanaRoute(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *routev
 	return route
 }
 
+// GetGrafanaCertificate returns the cert-manager Certificate requested for the instance, cert-manager is
+// not a dependency of the operator
+func GetGrafanaCertificate(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *unstructured.Unstructured {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetAPIVersion("cert-manager.io/v1")
+	certificate.SetKind("Certificate")
+	certificate.SetName(fmt.Sprintf("%s-certificate", cr.Name))
+	certificate.SetNamespace(cr.Namespace)
+	controllerutil.SetOwnerReference(cr, certificate, scheme)
+	return certificate
+}
+
+// GetGrafanaServiceMonitor returns the prometheus-operator ServiceMonitor of the instance
+func GetGrafanaServiceMonitor(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *unstructured.Unstructured {
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetAPIVersion("monitoring.coreos.com/v1")
+	serviceMonitor.SetKind("ServiceMonitor")
+	serviceMonitor.SetName(fmt.Sprintf("%s-servicemonitor", cr.Name))
+	serviceMonitor.SetNamespace(cr.Namespace)
+	controllerutil.SetOwnerReference(cr, serviceMonitor, scheme)
+	return serviceMonitor
+}
+
+func GetGrafanaPodDisruptionBudget(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-pdb", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+	controllerutil.SetOwnerReference(cr, pdb, scheme)
+	return pdb
+}
+
+func GetGrafanaHorizontalPodAutoscaler(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *autoscalingv2.HorizontalPodAutoscaler {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-hpa", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+	controllerutil.SetOwnerReference(cr, hpa, scheme)
+	return hpa
+}
+
 func GetGrafanaDeployment(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v13.Deployment {
 	deployment := &v13.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -102,3 +225,36 @@ func GetGrafanaDeployment(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v
 	controllerutil.SetOwnerReference(cr, deployment, scheme)
 	return deployment
 }
+
+func GetGrafanaStatefulSet(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v13.StatefulSet {
+	statefulSet := &v13.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-statefulset", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+	controllerutil.SetOwnerReference(cr, statefulSet, scheme)
+	return statefulSet
+}
+
+func GetGrafanaImageRendererDeployment(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v13.Deployment {
+	deployment := &v13.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-renderer-deployment", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+	controllerutil.SetOwnerReference(cr, deployment, scheme)
+	return deployment
+}
+
+func GetGrafanaImageRendererService(cr *grafanav1beta1.Grafana, scheme *runtime.Scheme) *v1.Service {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-renderer-service", cr.Name),
+			Namespace: cr.Namespace,
+		},
+	}
+	controllerutil.SetOwnerReference(cr, service, scheme)
+	return service
+}
@@ -0,0 +1,448 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	dockerHubAuthKey  = "https://index.docker.io/v1/"
+
+	titleAnnotation = "org.opencontainers.image.title"
+
+	// artifacts are read into memory
+	maxBlobSize = 64 * 1024 * 1024
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Reference is a tag or digest of a repository in a registry
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference splits a repository like ghcr.io/org/dashboards into registry and repository, repositories
+// without a registry are pulled from docker hub
+func ParseReference(repository string, tag string, digest string) (Reference, error) {
+	if repository == "" {
+		return Reference{}, fmt.Errorf("repository must be set")
+	}
+	if tag == "" && digest == "" {
+		tag = "latest"
+	}
+
+	ref := Reference{
+		Registry:   dockerHubRegistry,
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest,
+	}
+	parts := strings.SplitN(repository, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		ref.Repository = parts[1]
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if digest != "" && !strings.HasPrefix(digest, "sha256:") {
+		return Reference{}, fmt.Errorf("unsupported digest %s, only sha256 digests are supported", digest)
+	}
+	return ref, nil
+}
+
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// Descriptor is a layer of a manifest
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an image or artifact manifest
+type Manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []Descriptor `json:"layers"`
+}
+
+type credentials struct {
+	username string
+	password string
+}
+
+// Client pulls artifacts from registries implementing the distribution api
+type Client struct {
+	httpClient  *http.Client
+	insecure    bool
+	credentials map[string]credentials
+	tokens      map[string]string
+}
+
+// NewClient returns a client using the auths of a .dockerconfigjson, which may be empty for public
+// registries. Insecure clients talk plain http.
+func NewClient(httpClient *http.Client, dockerConfigJson []byte, insecure bool) (*Client, error) {
+	c := &Client{
+		httpClient:  httpClient,
+		insecure:    insecure,
+		credentials: map[string]credentials{},
+		tokens:      map[string]string{},
+	}
+	if len(dockerConfigJson) == 0 {
+		return c, nil
+	}
+
+	var config struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}
+	err := json.Unmarshal(dockerConfigJson, &config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker config: %w", err)
+	}
+
+	for server, auth := range config.Auths {
+		creds := credentials{
+			username: auth.Username,
+			password: auth.Password,
+		}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of registry %s: %w", server, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid auth of registry %s", server)
+			}
+			creds.username, creds.password = parts[0], parts[1]
+		}
+		c.credentials[registryHost(server)] = creds
+	}
+	return c, nil
+}
+
+// registryHost returns the host of a server of a docker config, e.g. https://index.docker.io/v1/
+func registryHost(server string) string {
+	if server == dockerHubAuthKey || server == "docker.io" || server == "index.docker.io" {
+		return dockerHubRegistry
+	}
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return strings.TrimSuffix(server, "/")
+}
+
+// GetManifest returns the manifest of a reference and its digest, the manifest of a digest reference is
+// verified against the digest
+func (c *Client) GetManifest(ctx context.Context, ref Reference) (*Manifest, string, error) {
+	reference := ref.Tag
+	if ref.Digest != "" {
+		reference = ref.Digest
+	}
+
+	content, err := c.get(ctx, ref, "manifests/"+reference, strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return nil, "", err
+	}
+
+	digest := digestOf(content)
+	if ref.Digest != "" && digest != ref.Digest {
+		return nil, "", fmt.Errorf("manifest of %s has digest %s", ref, digest)
+	}
+
+	manifest := &Manifest{}
+	err = json.Unmarshal(content, manifest)
+	if err != nil {
+		return nil, "", err
+	}
+	if manifest.MediaType != "" && !contains(manifestMediaTypes, manifest.MediaType) {
+		return nil, "", fmt.Errorf("unsupported manifest %s of %s", manifest.MediaType, ref)
+	}
+	return manifest, digest, nil
+}
+
+// GetFile returns a file of an artifact: the layer titled with the path as pushed by oras, or the file at
+// the path in a tar layer. Artifacts with a single layer don't need a path.
+func (c *Client) GetFile(ctx context.Context, ref Reference, manifest *Manifest, file string) ([]byte, error) {
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+
+	if file == "" {
+		if len(manifest.Layers) != 1 {
+			return nil, fmt.Errorf("%s has %d layers, a path must be set", ref, len(manifest.Layers))
+		}
+		return c.getBlob(ctx, ref, manifest.Layers[0])
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.Annotations[titleAnnotation] == file {
+			return c.getBlob(ctx, ref, layer)
+		}
+	}
+
+	for _, layer := range manifest.Layers {
+		if !strings.Contains(layer.MediaType, "tar") {
+			continue
+		}
+		blob, err := c.getBlob(ctx, ref, layer)
+		if err != nil {
+			return nil, err
+		}
+		content, err := readTarFile(blob, strings.Contains(layer.MediaType, "gzip"), file)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			return content, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s contains no file %s", ref, file)
+}
+
+// getBlob downloads a layer and verifies its digest
+func (c *Client) getBlob(ctx context.Context, ref Reference, layer Descriptor) ([]byte, error) {
+	if layer.Size > maxBlobSize {
+		return nil, fmt.Errorf("layer %s of %s is larger than %d bytes", layer.Digest, ref, maxBlobSize)
+	}
+
+	content, err := c.get(ctx, ref, "blobs/"+layer.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	if digest := digestOf(content); digest != layer.Digest {
+		return nil, fmt.Errorf("layer %s of %s has digest %s", layer.Digest, ref, digest)
+	}
+	return content, nil
+}
+
+// get requests a path of the repository, registries answering with a bearer challenge are asked for a
+// token of the repository first
+func (c *Client) get(ctx context.Context, ref Reference, resource string, accept string) ([]byte, error) {
+	scheme := "https"
+	if c.insecure {
+		scheme = "http"
+	}
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", scheme, ref.Registry, ref.Repository, resource)
+
+	resp, err := c.do(ctx, ref, u, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		err = c.authenticate(ctx, ref, challenge)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = c.do(ctx, ref, u, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry %s returned status %d for %s of %s", ref.Registry, resp.StatusCode, resource, ref)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxBlobSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxBlobSize {
+		return nil, fmt.Errorf("%s of %s is larger than %d bytes", resource, ref, maxBlobSize)
+	}
+	return content, nil
+}
+
+func (c *Client) do(ctx context.Context, ref Reference, u string, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token, ok := c.tokens[ref.Registry]; ok {
+		req.Header.Set("Authorization", token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// authenticate answers the challenge of a registry, the authorization is reused for later requests
+func (c *Client) authenticate(ctx context.Context, ref Reference, challenge string) error {
+	creds, hasCredentials := c.credentials[ref.Registry]
+	scheme, params := parseChallenge(challenge)
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if !hasCredentials {
+			return fmt.Errorf("registry %s requires credentials", ref.Registry)
+		}
+		c.tokens[ref.Registry] = "Basic " + base64.StdEncoding.EncodeToString([]byte(creds.username+":"+creds.password))
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("registry %s returned unsupported challenge %q", ref.Registry, challenge)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return fmt.Errorf("registry %s returned invalid realm %q", ref.Registry, params["realm"])
+	}
+	query := realm.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if hasCredentials {
+		req.SetBasicAuth(creds.username, creds.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token service of registry %s returned status %d", ref.Registry, resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return err
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return fmt.Errorf("token service of registry %s returned no token", ref.Registry)
+	}
+	c.tokens[ref.Registry] = "Bearer " + token.Token
+	return nil
+}
+
+// parseChallenge splits a WWW-Authenticate header like Bearer realm="...",service="..." into the scheme
+// and its parameters
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	if len(parts) < 2 {
+		return parts[0], params
+	}
+
+	rest := parts[1]
+	for rest != "" {
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else if comma := strings.Index(rest, ","); comma >= 0 {
+			value, rest = rest[:comma], rest[comma:]
+		} else {
+			value, rest = rest, ""
+		}
+		params[key] = value
+		rest = strings.TrimLeft(rest, ", ")
+	}
+	return parts[0], params
+}
+
+// readTarFile returns the content of a file in a tarball, nil if the tarball doesn't contain it
+func readTarFile(blob []byte, compressed bool, file string) ([]byte, error) {
+	var reader io.Reader = bytes.NewReader(blob)
+	if compressed {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || strings.TrimPrefix(path.Clean("/"+header.Name), "/") != file {
+			continue
+		}
+		return io.ReadAll(io.LimitReader(tarReader, maxBlobSize))
+	}
+}
+
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
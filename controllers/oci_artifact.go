@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/oci"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// newArtifactClient returns a registry client with the credentials of the pull secret of an artifact
+func newArtifactClient(ctx context.Context, k8sClient client.Client, namespace string, source *grafanav1beta1.OCIArtifactSource) (*oci.Client, oci.Reference, error) {
+	ref, err := oci.ParseReference(source.Repository, source.Tag, source.Digest)
+	if err != nil {
+		return nil, oci.Reference{}, err
+	}
+
+	var dockerConfig []byte
+	if source.PullSecretRef != nil {
+		secret := &v1.Secret{}
+		err = k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      source.PullSecretRef.Name,
+		}, secret)
+		if err != nil {
+			return nil, oci.Reference{}, fmt.Errorf("pull secret: %w", err)
+		}
+		dockerConfig = secret.Data[v1.DockerConfigJsonKey]
+	}
+
+	ociClient, err := oci.NewClient(dashboardHttpClient, dockerConfig, source.Insecure)
+	if err != nil {
+		return nil, oci.Reference{}, err
+	}
+	return ociClient, ref, nil
+}
+
+// isDashboardArtifactSource returns true if the json of a dashboard is pulled from an OCI artifact
+func isDashboardArtifactSource(dashboard *grafanav1beta1.GrafanaDashboard) bool {
+	spec := dashboard.Spec
+	return spec.Url == "" && spec.GrafanaCom == nil && spec.ObjectStorage == nil && spec.OCI != nil
+}
+
+// fetchDashboardArtifact refreshes the content of a dashboard pulled from an OCI artifact, the file is only
+// pulled again when the digest of the manifest changed
+func (r *GrafanaDashboardReconciler) fetchDashboardArtifact(ctx context.Context, dashboard *grafanav1beta1.GrafanaDashboard, url string, cached bool) error {
+	logger := log.FromContext(ctx)
+	status := &dashboard.Status
+
+	ociClient, ref, err := newArtifactClient(ctx, r.Client, dashboard.Namespace, dashboard.Spec.OCI)
+	if err != nil {
+		return err
+	}
+
+	manifest, digest, err := ociClient.GetManifest(ctx, ref)
+	if err != nil {
+		if cached {
+			logger.Info("error pulling dashboard artifact, using cached content", "dashboard", dashboard.Name, "error", err.Error())
+			return nil
+		}
+		return err
+	}
+
+	now := metav1.Now()
+	if cached && status.ContentETag == digest {
+		status.ContentTimestamp = &now
+		return r.Status().Update(ctx, dashboard)
+	}
+
+	content, err := ociClient.GetFile(ctx, ref, manifest, dashboard.Spec.OCI.Path)
+	if err != nil {
+		if cached {
+			logger.Info("error pulling dashboard artifact, using cached content", "dashboard", dashboard.Name, "error", err.Error())
+			return nil
+		}
+		return err
+	}
+	if len(content) > maxDashboardUrlContentSize {
+		return fmt.Errorf("dashboard artifact %s is larger than %d bytes", url, maxDashboardUrlContentSize)
+	}
+	content, err = gunzipContent(content)
+	if err != nil {
+		return fmt.Errorf("dashboard artifact %s: %w", url, err)
+	}
+	if !json.Valid(content) {
+		return fmt.Errorf("dashboard artifact %s contains invalid json", url)
+	}
+
+	compressed, err := gzipContent(content)
+	if err != nil {
+		return err
+	}
+	if status.ContentETag != digest {
+		logger.Info("pulled dashboard artifact", "dashboard", dashboard.Name, "artifact", ref.String(), "digest", digest)
+	}
+	status.ContentCache = compressed
+	status.ContentUrl = url
+	status.ContentETag = digest
+	status.ContentLastModified = ""
+	status.ContentTimestamp = &now
+	return r.Status().Update(ctx, dashboard)
+}
+
+// loadLibraryPanelJson pulls the json of a library panel from its OCI artifact into the spec, panels with
+// inline json are not changed
+func loadLibraryPanelJson(ctx context.Context, k8sClient client.Client, panel *grafanav1beta1.GrafanaLibraryPanel) error {
+	if panel.Spec.Json != "" {
+		return nil
+	}
+	if panel.Spec.OCI == nil {
+		return fmt.Errorf("library panel %s has neither json nor an oci artifact", panel.Name)
+	}
+
+	ociClient, ref, err := newArtifactClient(ctx, k8sClient, panel.Namespace, panel.Spec.OCI)
+	if err != nil {
+		return err
+	}
+	manifest, _, err := ociClient.GetManifest(ctx, ref)
+	if err != nil {
+		return err
+	}
+	content, err := ociClient.GetFile(ctx, ref, manifest, panel.Spec.OCI.Path)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(content) {
+		return fmt.Errorf("library panel artifact %s contains invalid json", panel.Spec.OCI.ContentUrl())
+	}
+	panel.Spec.Json = string(content)
+	return nil
+}
@@ -0,0 +1,214 @@
+package plugincache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URL is the address managed instances reach the cache at, empty if the cache is disabled
+var URL string
+
+// segments of plugin ids, versions, operating systems and architectures
+var pathSegment = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Server is a pull-through cache of a plugin repository for grafana-cli. Plugin archives are downloaded from
+// the upstream repository once and served from the cache directory, all other requests like the versions of
+// a plugin are passed through so that grafana-cli verifies the archives against the upstream checksums.
+type Server struct {
+	addr       string
+	dir        string
+	upstream   string
+	httpClient *http.Client
+
+	locks sync.Map
+}
+
+func NewServer(addr string, dir string, upstream string) *Server {
+	return &Server{
+		addr:     addr,
+		dir:      dir,
+		upstream: strings.TrimSuffix(upstream, "/"),
+		httpClient: &http.Client{
+			Timeout: time.Minute * 5,
+		},
+	}
+}
+
+// Start serves the cache until the context is done
+func (s *Server) Start(ctx context.Context) error {
+	err := os.MkdirAll(s.dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           s,
+		ReadHeaderTimeout: time.Second * 10,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background()) // nolint
+	}()
+
+	log.FromContext(ctx).Info("serving plugin cache", "address", s.addr, "upstream", s.upstream)
+	err = server.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// NeedLeaderElection returns false, every replica of the operator serves the cache
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// archives are requested as /<plugin>/versions/<version>/download
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 4 && parts[1] == "versions" && parts[3] == "download" {
+		s.serveArchive(w, r, parts[0], parts[2])
+		return
+	}
+	s.proxy(w, r)
+}
+
+// serveArchive serves an archive from the cache directory, archives not in the cache are downloaded first.
+// Concurrent requests of the same archive wait for a single download.
+func (s *Server) serveArchive(w http.ResponseWriter, r *http.Request, plugin string, version string) {
+	platform := "any"
+	if goos, arch := r.URL.Query().Get("os"), r.URL.Query().Get("arch"); goos != "" && arch != "" {
+		platform = goos + "-" + arch
+	}
+	for _, segment := range []string{plugin, version, platform} {
+		if !pathSegment.MatchString(segment) || segment == "." || segment == ".." {
+			http.Error(w, "invalid plugin archive", http.StatusBadRequest)
+			return
+		}
+	}
+
+	path := filepath.Join(s.dir, plugin, version, platform+".zip")
+	lock, _ := s.locks.LoadOrStore(path, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		err = s.download(r, path)
+	}
+	lock.(*sync.Mutex).Unlock()
+
+	var upstreamErr *upstreamError
+	if errors.As(err, &upstreamErr) {
+		http.Error(w, upstreamErr.Error(), upstreamErr.status)
+		return
+	}
+	if err != nil {
+		log.FromContext(r.Context()).Error(err, "error caching plugin archive", "plugin", plugin, "version", version)
+		http.Error(w, "error caching plugin archive", http.StatusBadGateway)
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "error reading plugin archive", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "error reading plugin archive", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), file)
+}
+
+// download stores an archive of the upstream repository in the cache directory, the file is only renamed
+// into place once it is complete
+func (s *Server) download(r *http.Request, path string) error {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, s.upstream+r.URL.RequestURI(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", r.UserAgent())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &upstreamError{status: resp.StatusCode}
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint
+
+	_, err = io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// proxy passes a request through to the upstream repository
+func (s *Server) proxy(w http.ResponseWriter, r *http.Request) {
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, s.upstream+r.URL.RequestURI(), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Header.Set("Accept", r.Header.Get("Accept"))
+	req.Header.Set("User-Agent", r.UserAgent())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		http.Error(w, "plugin repository not reachable", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, header := range []string{"Content-Type", "Content-Length"} {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body) // nolint
+}
+
+// upstreamError is returned for archives the upstream repository doesn't serve
+type upstreamError struct {
+	status int
+}
+
+func (e *upstreamError) Error() string {
+	return fmt.Sprintf("plugin repository returned status %d", e.status)
+}
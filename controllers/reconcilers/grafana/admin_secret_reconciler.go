@@ -1,16 +1,21 @@
 package grafana
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type AdminSecretReconciler struct {
@@ -24,18 +29,147 @@ func NewAdminSecretReconciler(client client.Client) reconcilers.OperatorGrafanaR
 }
 
 func (r *AdminSecretReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
 	secret := model.GetGrafanaAdminSecret(cr, scheme)
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
-		secret.Data = getData(cr, secret)
+	err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+	}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	var current *v1.Secret
+	if err == nil {
+		current = secret
+	}
+
+	user := getAdminUser(cr, current)
+	if cr.Spec.AdminCredentials != nil && cr.Spec.AdminCredentials.ExistingSecret != nil {
+		user, err = r.getExistingSecretValue(ctx, cr, cr.Spec.AdminCredentials.ExistingSecret.UserKey, "admin-user")
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+	}
+
+	password, err := r.getAdminPassword(ctx, cr, current, status)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	// a running instance keeps the admin password in its database, the password is changed through the api
+	// before the secret is updated
+	if current != nil && status.AdminUrl != "" && !bytes.Equal(current.Data[config.GrafanaAdminPasswordEnvVar], password) {
+		err = r.changeAdminPassword(ctx, cr, secret, user, password)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		logger.Info("admin password changed", "secret", secret.Name)
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+		secret.Data = getData(user, password)
 		return nil
 	})
 
 	if err != nil {
 		return v1beta1.OperatorStageResultFailed, err
 	}
+
+	status.AdminCredentialsRotation = getAdminRotation(cr)
 	return v1beta1.OperatorStageResultSuccess, nil
 }
 
+// changeAdminPassword stores the new password as pending first, so that a change interrupted after the api
+// call is completed on the next reconcile
+func (r *AdminSecretReconciler) changeAdminPassword(ctx context.Context, cr *v1beta1.Grafana, secret *v1.Secret, user []byte, password []byte) error {
+	if !bytes.Equal(secret.Data[config.GrafanaAdminPendingPasswordKey], password) {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[config.GrafanaAdminPendingPasswordKey] = password
+		err := r.client.Update(ctx, secret)
+		if err != nil {
+			return err
+		}
+	}
+
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.client, cr)
+	if err != nil {
+		return err
+	}
+
+	err = grafanaClient.ChangeAdminPassword(string(password))
+	if client2.IsUnauthorized(err) {
+		changed, verifyErr := grafanaClient.VerifyCredentials(string(user), string(password))
+		if verifyErr != nil {
+			return verifyErr
+		}
+		if changed {
+			return nil
+		}
+	}
+	return err
+}
+
+// getAdminPassword returns the password the instance should use, in order of precedence: a pending change,
+// the existing secret, the config, a requested rotation and finally the current or a generated password
+func (r *AdminSecretReconciler) getAdminPassword(ctx context.Context, cr *v1beta1.Grafana, current *v1.Secret, status *v1beta1.GrafanaStatus) ([]byte, error) {
+	if current != nil && current.Data[config.GrafanaAdminPendingPasswordKey] != nil {
+		return current.Data[config.GrafanaAdminPendingPasswordKey], nil
+	}
+
+	if cr.Spec.AdminCredentials != nil && cr.Spec.AdminCredentials.ExistingSecret != nil {
+		return r.getExistingSecretValue(ctx, cr, cr.Spec.AdminCredentials.ExistingSecret.PasswordKey, "admin-password")
+	}
+
+	if cr.Spec.Config.Security != nil && cr.Spec.Config.Security.AdminPassword != "" {
+		return []byte(cr.Spec.Config.Security.AdminPassword), nil
+	}
+
+	rotation := getAdminRotation(cr)
+	if rotation != "" && rotation != status.AdminCredentialsRotation && current != nil {
+		return []byte(model.RandStringRunes(10)), nil
+	}
+
+	// If a password is already set, don't change it
+	if current != nil && current.Data[config.GrafanaAdminPasswordEnvVar] != nil {
+		return current.Data[config.GrafanaAdminPasswordEnvVar], nil
+	}
+	return []byte(model.RandStringRunes(10)), nil
+}
+
+// getExistingSecretValue reads a key of the admin credentials secret supplied by the user
+func (r *AdminSecretReconciler) getExistingSecretValue(ctx context.Context, cr *v1beta1.Grafana, key string, defaultKey string) ([]byte, error) {
+	ref := cr.Spec.AdminCredentials.ExistingSecret
+	existing := &v1.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      ref.Name,
+	}, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == "" {
+		key = defaultKey
+	}
+	value, ok := existing.Data[key]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("secret %s/%s does not contain key %s", cr.Namespace, ref.Name, key)
+	}
+	return value, nil
+}
+
+// getAdminRotation returns the rotation requested through the spec, or through the annotation
+func getAdminRotation(cr *v1beta1.Grafana) string {
+	if cr.Spec.AdminCredentials != nil && cr.Spec.AdminCredentials.Rotate != "" {
+		return cr.Spec.AdminCredentials.Rotate
+	}
+	return cr.Annotations[config.GrafanaAdminRotateAnnotation]
+}
+
 func getAdminUser(cr *v1beta1.Grafana, current *v1.Secret) []byte {
 	if cr.Spec.Config.Security == nil || cr.Spec.Config.Security.AdminUser == "" {
 		// If a user is already set, don't change it
@@ -47,21 +181,10 @@ func getAdminUser(cr *v1beta1.Grafana, current *v1.Secret) []byte {
 	return []byte(cr.Spec.Config.Security.AdminUser)
 }
 
-func getAdminPassword(cr *v1beta1.Grafana, current *v1.Secret) []byte {
-	if cr.Spec.Config.Security == nil || cr.Spec.Config.Security.AdminPassword == "" {
-		// If a password is already set, don't change it
-		if current != nil && current.Data[config.GrafanaAdminPasswordEnvVar] != nil {
-			return current.Data[config.GrafanaAdminPasswordEnvVar]
-		}
-		return []byte(model.RandStringRunes(10))
-	}
-	return []byte(cr.Spec.Config.Security.AdminPassword)
-}
-
-func getData(cr *v1beta1.Grafana, current *v1.Secret) map[string][]byte {
+func getData(user []byte, password []byte) map[string][]byte {
 	credentials := map[string][]byte{
-		config.GrafanaAdminUserEnvVar:     getAdminUser(cr, current),
-		config.GrafanaAdminPasswordEnvVar: getAdminPassword(cr, current),
+		config.GrafanaAdminUserEnvVar:     user,
+		config.GrafanaAdminPasswordEnvVar: password,
 	}
 
 	// Make the credentials available to the environment when running the operator
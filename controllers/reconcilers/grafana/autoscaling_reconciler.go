@@ -0,0 +1,103 @@
+package grafana
+
+import (
+	"context"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const DefaultTargetCPUUtilizationPercentage int32 = 80
+
+// AutoscalingReconciler creates a HorizontalPodAutoscaler for the Grafana workload, the deployment stage
+// keeps the replicas chosen by the autoscaler
+type AutoscalingReconciler struct {
+	client client.Client
+}
+
+func NewAutoscalingReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &AutoscalingReconciler{
+		client: client,
+	}
+}
+
+func (r *AutoscalingReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	hpa := model.GetGrafanaHorizontalPodAutoscaler(cr, scheme)
+
+	if cr.Spec.Autoscaling == nil {
+		err := r.client.Delete(ctx, hpa)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, hpa, func() error {
+		hpa.Spec = autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: getScaleTargetRef(cr, scheme),
+			MinReplicas:    cr.Spec.Autoscaling.MinReplicas,
+			MaxReplicas:    cr.Spec.Autoscaling.MaxReplicas,
+			Metrics:        getAutoscalingMetrics(cr),
+			Behavior:       cr.Spec.Autoscaling.Behavior,
+		}
+		return nil
+	})
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+func getScaleTargetRef(cr *v1beta1.Grafana, scheme *runtime.Scheme) autoscalingv2.CrossVersionObjectReference {
+	if cr.Spec.Persistence != nil {
+		return autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+			Name:       model.GetGrafanaStatefulSet(cr, scheme).Name,
+		}
+	}
+
+	return autoscalingv2.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       model.GetGrafanaDeployment(cr, scheme).Name,
+	}
+}
+
+func getAutoscalingMetrics(cr *v1beta1.Grafana) []autoscalingv2.MetricSpec {
+	settings := cr.Spec.Autoscaling
+
+	var metrics []autoscalingv2.MetricSpec
+	if settings.TargetCPUUtilizationPercentage != nil {
+		metrics = append(metrics, getResourceMetric(v1.ResourceCPU, *settings.TargetCPUUtilizationPercentage))
+	}
+	if settings.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, getResourceMetric(v1.ResourceMemory, *settings.TargetMemoryUtilizationPercentage))
+	}
+	metrics = append(metrics, settings.Metrics...)
+
+	if len(metrics) == 0 {
+		metrics = append(metrics, getResourceMetric(v1.ResourceCPU, DefaultTargetCPUUtilizationPercentage))
+	}
+	return metrics
+}
+
+func getResourceMetric(name v1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
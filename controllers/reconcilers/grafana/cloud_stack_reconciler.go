@@ -0,0 +1,88 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+	"time"
+)
+
+// CloudStackReconciler creates the Grafana Cloud stack of the instance if missing, and an api key for the
+// operator to manage resources in the stack
+type CloudStackReconciler struct {
+	client client.Client
+}
+
+func NewCloudStackReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &CloudStackReconciler{
+		client: client,
+	}
+}
+
+func (r *CloudStackReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	cloudClient, err := client2.NewGrafanaCloudClient(ctx, r.client, cr)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	stack, err := cloudClient.GetStack(cr.Spec.Cloud.Slug)
+	if client2.IsNotFound(err) {
+		logger.Info("creating grafana cloud stack", "stack", cr.Spec.Cloud.Slug)
+		stack, err = cloudClient.CreateStack(cr.Spec.Cloud)
+	}
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	status.StackSlug = stack.Slug
+	status.StackUrl = stack.URL
+
+	// a new stack takes a while to become available
+	if stack.Status != "active" {
+		logger.Info("grafana cloud stack not ready", "stack", stack.Slug, "status", stack.Status)
+		return v1beta1.OperatorStageResultInProgress, nil
+	}
+
+	err = r.reconcileAPIKey(ctx, cloudClient, cr, scheme)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	status.AdminUrl = strings.TrimSuffix(stack.URL, "/")
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// reconcileAPIKey creates an api key in the stack once, keys can't be read back after creation
+func (r *CloudStackReconciler) reconcileAPIKey(ctx context.Context, cloudClient client2.GrafanaCloudClient, cr *v1beta1.Grafana, scheme *runtime.Scheme) error {
+	secret := model.GetGrafanaCloudStackSecret(cr, scheme)
+	err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+	}, secret)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	// key names are unique in a stack, a key may be left over from a deleted secret
+	name := fmt.Sprintf("grafana-operator-%s-%d", cr.Name, time.Now().Unix())
+	key, err := cloudClient.CreateStackAPIKey(cr.Spec.Cloud.Slug, name)
+	if err != nil {
+		return err
+	}
+
+	secret.Data = map[string][]byte{
+		config.GrafanaCloudStackAPIKeyKey: []byte(key),
+	}
+	return r.client.Create(ctx, secret)
+}
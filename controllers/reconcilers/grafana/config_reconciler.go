@@ -2,16 +2,26 @@ package grafana
 
 import (
 	"context"
+	"fmt"
 	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"path"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+	"strings"
 )
 
+const conditionFeatureToggles = "FeatureToggles"
+
 type ConfigReconciler struct {
 	client client.Client
 }
@@ -25,21 +35,221 @@ func NewConfigReconciler(client client.Client) reconcilers.OperatorGrafanaReconc
 func (r *ConfigReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
 	_ = log.FromContext(ctx)
 
-	ini := config.NewGrafanaIni(&cr.Spec.Config)
+	ini := config.NewGrafanaIni(getGrafanaConfig(cr, vars, scheme))
+	for name, enabled := range cr.Spec.FeatureToggles {
+		ini.SetValue("feature_toggles", name, strconv.FormatBool(enabled))
+	}
+	setFeatureTogglesCondition(cr, status)
+
+	for _, value := range cr.Spec.ConfigValuesFrom {
+		separator := strings.LastIndex(value.TargetPath, ".")
+		if separator <= 0 || separator == len(value.TargetPath)-1 {
+			return v1beta1.OperatorStageResultFailed, fmt.Errorf("invalid target path %s, expected <section>.<key>", value.TargetPath)
+		}
+
+		resolved, err := r.getReferencedValue(ctx, cr.Namespace, value.ValueFrom)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		ini.SetValue(value.TargetPath[:separator], value.TargetPath[separator+1:], resolved)
+	}
+
+	// the hash covers the referenced values, so changing them rolls the pods
 	config, hash := ini.Write()
 	vars.ConfigHash = hash
 
-	configMap := model.GetGrafanaConfigMap(cr, scheme)
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, configMap, func() error {
-		if configMap.Data == nil {
-			configMap.Data = make(map[string]string)
+	// the config contains credentials and is stored in a secret
+	secret := model.GetGrafanaConfigSecret(cr, scheme)
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
 		}
-		configMap.Data["grafana.ini"] = config
+		secret.Data["grafana.ini"] = []byte(config)
 		return nil
 	})
 
 	if err != nil {
 		return v1beta1.OperatorStageResultFailed, err
 	}
+
+	// remove the config map used by previous versions of the operator
+	err = r.client.Delete(ctx, model.GetGrafanaConfigMap(cr, scheme))
+	if err != nil && !errors.IsNotFound(err) {
+		return v1beta1.OperatorStageResultFailed, err
+	}
 	return v1beta1.OperatorStageResultSuccess, nil
 }
+
+// setFeatureTogglesCondition reports toggles unknown to the version of Grafana, unknown toggles are still
+// rendered as Grafana ignores them
+func setFeatureTogglesCondition(cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus) {
+	if len(cr.Spec.FeatureToggles) == 0 {
+		meta.RemoveStatusCondition(&status.Conditions, conditionFeatureToggles)
+		return
+	}
+
+	version := getGrafanaVersion(cr)
+	condition := metav1.Condition{
+		Type:               conditionFeatureToggles,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             "Valid",
+		Message:            fmt.Sprintf("all feature toggles are known to Grafana %s", version),
+	}
+
+	unknown, known := config.UnknownFeatureToggles(version, cr.Spec.FeatureToggles)
+	if !known {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "UnknownVersion"
+		condition.Message = fmt.Sprintf("feature toggles of Grafana %s are not known to the operator", version)
+	} else if len(unknown) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "UnknownToggles"
+		condition.Message = fmt.Sprintf("feature toggles unknown to Grafana %s: %s", version, strings.Join(unknown, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// getReferencedValue reads the value of a Secret or ConfigMap key in the namespace of the instance
+func (r *ConfigReconciler) getReferencedValue(ctx context.Context, namespace string, source v1beta1.ValueFromSource) (string, error) {
+	if source.SecretKeyRef != nil {
+		secret := &v1.Secret{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.SecretKeyRef.Name}, secret)
+		if err != nil {
+			return "", err
+		}
+		if val, ok := secret.Data[source.SecretKeyRef.Key]; ok {
+			return string(val), nil
+		}
+		return "", fmt.Errorf("secret %s/%s does not contain key %s", namespace, source.SecretKeyRef.Name, source.SecretKeyRef.Key)
+	}
+
+	if source.ConfigMapKeyRef != nil {
+		configMap := &v1.ConfigMap{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.ConfigMapKeyRef.Name}, configMap)
+		if err != nil {
+			return "", err
+		}
+		if val, ok := configMap.Data[source.ConfigMapKeyRef.Key]; ok {
+			return val, nil
+		}
+		return "", fmt.Errorf("config map %s/%s does not contain key %s", namespace, source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key)
+	}
+
+	return "", fmt.Errorf("value source must reference either a secret or a config map")
+}
+
+// getGrafanaConfig returns the config of the instance, with ldap enabled when a GrafanaLDAPConfig selects it,
+// the database section set by the database spec, the alerting peers set when running replicas and the
+// root_url derived from the ingress or route host, https enabled by the tls spec and the smtp section set
+// by the smtp spec
+func getGrafanaConfig(cr *v1beta1.Grafana, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) *v1beta1.GrafanaConfig {
+	externalURL := getIngressExternalURL(cr)
+	if externalURL == "" {
+		externalURL = getRouteExternalURL(cr)
+	}
+	if vars.LdapHash == "" && cr.Spec.Database == nil && !vars.HighAvailability && externalURL == "" && cr.Spec.TLS == nil && cr.Spec.SMTP == nil {
+		return &cr.Spec.Config
+	}
+
+	cfg := cr.Spec.Config.DeepCopy()
+
+	if vars.LdapHash != "" {
+		if cfg.AuthLdap == nil {
+			cfg.AuthLdap = &v1beta1.GrafanaConfigAuthLdap{}
+		}
+
+		enabled := true
+		cfg.AuthLdap.Enabled = &enabled
+		cfg.AuthLdap.ConfigFile = config.GrafanaLdapConfigPath
+		if vars.LdapAllowSignUp != nil {
+			cfg.AuthLdap.AllowSignUp = vars.LdapAllowSignUp
+		}
+	}
+
+	if cr.Spec.Database != nil {
+		applyDatabaseConfig(cfg, cr.Spec.Database, vars)
+	}
+
+	// the advertise address is the pod ip, passed as env var by the deployment
+	if vars.HighAvailability {
+		if cfg.UnifiedAlerting == nil {
+			cfg.UnifiedAlerting = &v1beta1.GrafanaConfigUnifiedAlerting{}
+		}
+		cfg.UnifiedAlerting.HaPeers = getHAPeers(cr, scheme)
+		cfg.UnifiedAlerting.HaListenAddress = fmt.Sprintf(":%d", config.GrafanaAlertingHAPort)
+	}
+
+	// the password is passed as env var
+	if cr.Spec.SMTP != nil {
+		if cfg.Smtp == nil {
+			cfg.Smtp = &v1beta1.GrafanaConfigSmtp{}
+		}
+		enabled := true
+		cfg.Smtp.Enabled = &enabled
+		cfg.Smtp.Host = cr.Spec.SMTP.Host
+		cfg.Smtp.User = cr.Spec.SMTP.User
+		cfg.Smtp.Password = ""
+		cfg.Smtp.FromAddress = cr.Spec.SMTP.FromAddress
+		cfg.Smtp.FromName = cr.Spec.SMTP.FromName
+		cfg.Smtp.StartTLSPolicy = cr.Spec.SMTP.StartTLS
+		if cr.Spec.SMTP.SkipVerify {
+			skipVerify := true
+			cfg.Smtp.SkipVerify = &skipVerify
+		}
+	}
+
+	if cr.Spec.TLS != nil {
+		if cfg.Server == nil {
+			cfg.Server = &v1beta1.GrafanaConfigServer{}
+		}
+		cfg.Server.Protocol = "https"
+		cfg.Server.CertFile = path.Join(config.GrafanaTLSPath, v1.TLSCertKey)
+		cfg.Server.CertKey = path.Join(config.GrafanaTLSPath, v1.TLSPrivateKeyKey)
+	}
+
+	// a root_url in the config takes precedence over the ingress
+	if externalURL != "" {
+		if cfg.Server == nil {
+			cfg.Server = &v1beta1.GrafanaConfigServer{}
+		}
+		if cfg.Server.RootUrl == "" {
+			cfg.Server.RootUrl = externalURL
+			if cfg.Server.ServeFromSubPath == nil && cr.Spec.Ingress != nil && getIngressPath(cr) != "/" {
+				serveFromSubPath := true
+				cfg.Server.ServeFromSubPath = &serveFromSubPath
+			}
+		}
+	}
+	return cfg
+}
+
+// applyDatabaseConfig sets the connection settings of the database spec, pool and logging settings of the
+// config are kept. The password is passed as env var and never written to the config map.
+func applyDatabaseConfig(cfg *v1beta1.GrafanaConfig, database *v1beta1.GrafanaDatabase, vars *v1beta1.OperatorReconcileVars) {
+	if cfg.Database == nil {
+		cfg.Database = &v1beta1.GrafanaConfigDatabase{}
+	}
+
+	cfg.Database.Url = ""
+	cfg.Database.Path = ""
+	cfg.Database.Password = ""
+	cfg.Database.Type = database.Type
+	cfg.Database.Host = database.Host
+	cfg.Database.Name = database.Name
+	cfg.Database.User = database.User
+
+	if database.TLS == nil {
+		return
+	}
+
+	cfg.Database.SslMode = database.TLS.Mode
+	cfg.Database.ServerCertName = database.TLS.ServerCertName
+	if database.TLS.SecretName != "" {
+		cfg.Database.CaCertPath = path.Join(config.GrafanaDatabaseTLSPath, v1.ServiceAccountRootCAKey)
+		if vars.DatabaseClientCertificate {
+			cfg.Database.ClientCertPath = path.Join(config.GrafanaDatabaseTLSPath, v1.TLSCertKey)
+			cfg.Database.ClientKeyPath = path.Join(config.GrafanaDatabaseTLSPath, v1.TLSPrivateKeyKey)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+// DatabaseReconciler checks the secrets referenced by the database spec, the deployment stage restarts
+// Grafana when their versions change
+type DatabaseReconciler struct {
+	client client.Client
+}
+
+func NewDatabaseReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &DatabaseReconciler{
+		client: client,
+	}
+}
+
+func (r *DatabaseReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	database := cr.Spec.Database
+	if database == nil {
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	var versions []string
+
+	if database.PasswordSecretRef != nil {
+		secret, err := r.getSecret(ctx, cr.Namespace, database.PasswordSecretRef.Name)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		_, ok := secret.Data[database.PasswordSecretRef.Key]
+		if !ok {
+			return v1beta1.OperatorStageResultFailed, fmt.Errorf("secret %s/%s does not contain key %s", cr.Namespace, secret.Name, database.PasswordSecretRef.Key)
+		}
+		versions = append(versions, secretVersion(secret))
+	}
+
+	if database.TLS != nil && database.TLS.SecretName != "" {
+		secret, err := r.getSecret(ctx, cr.Namespace, database.TLS.SecretName)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		versions = append(versions, secretVersion(secret))
+
+		_, hasCert := secret.Data[v1.TLSCertKey]
+		_, hasKey := secret.Data[v1.TLSPrivateKeyKey]
+		vars.DatabaseClientCertificate = hasCert && hasKey
+	}
+
+	vars.DatabaseSecretVersion = strings.Join(versions, ",")
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+func (r *DatabaseReconciler) getSecret(ctx context.Context, namespace string, name string) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}, secret)
+	return secret, err
+}
+
+// secretVersion returns the uid and resource version of a secret, they change with its data without
+// exposing anything derived from it
+func secretVersion(secret *v1.Secret) string {
+	return fmt.Sprintf("%s/%s", secret.UID, secret.ResourceVersion)
+}
@@ -0,0 +1,268 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+)
+
+// discoveredService describes how services of a kind are found and imported
+type discoveredService struct {
+	kind           v1beta1.DiscoveredDatasourceType
+	datasourceType string
+	// any of the labels identifies a service
+	labels      []map[string]string
+	portNames   []string
+	defaultPort int32
+}
+
+var discoveredServices = []discoveredService{
+	{
+		kind:           v1beta1.DiscoveredDatasourcePrometheus,
+		datasourceType: "prometheus",
+		labels: []map[string]string{
+			{"app.kubernetes.io/name": "prometheus"},
+		},
+		portNames:   []string{"web", "http-web", "http"},
+		defaultPort: 9090,
+	},
+	{
+		kind:           v1beta1.DiscoveredDatasourceThanos,
+		datasourceType: "prometheus",
+		labels: []map[string]string{
+			{"app.kubernetes.io/name": "thanos-query"},
+			{"app.kubernetes.io/name": "thanos", "app.kubernetes.io/component": "query"},
+		},
+		portNames:   []string{"http"},
+		defaultPort: 10902,
+	},
+	{
+		kind:           v1beta1.DiscoveredDatasourceLoki,
+		datasourceType: "loki",
+		labels: []map[string]string{
+			{"app.kubernetes.io/name": "loki"},
+		},
+		portNames:   []string{"http-metrics", "http"},
+		defaultPort: 3100,
+	},
+	{
+		kind:           v1beta1.DiscoveredDatasourceTempo,
+		datasourceType: "tempo",
+		labels: []map[string]string{
+			{"app.kubernetes.io/name": "tempo"},
+		},
+		portNames:   []string{"http", "tempo-prom-metrics"},
+		defaultPort: 3200,
+	},
+}
+
+// DatasourceDiscoveryReconciler creates a GrafanaDatasource for every service found by the discovery of an
+// instance and deletes the datasources of services that are gone. The datasources are owned by the instance.
+type DatasourceDiscoveryReconciler struct {
+	client client.Client
+}
+
+func NewDatasourceDiscoveryReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &DatasourceDiscoveryReconciler{
+		client: client,
+	}
+}
+
+func (r *DatasourceDiscoveryReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	desired := map[string]*v1beta1.GrafanaDatasource{}
+	if cr.Spec.DatasourceDiscovery != nil {
+		services, err := r.findServices(ctx, cr)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		for _, found := range services {
+			datasource := getDiscoveredDatasource(cr, found.service, found.kind)
+			desired[datasource.Name] = datasource
+		}
+	}
+
+	for _, datasource := range desired {
+		spec := datasource.Spec
+		_, err := controllerutil.CreateOrUpdate(ctx, r.client, datasource, func() error {
+			if !datasource.CreationTimestamp.IsZero() && datasource.Labels[config.DatasourceDiscoveredLabel] != cr.Name {
+				return fmt.Errorf("datasource %s/%s exists and was not created by the discovery", datasource.Namespace, datasource.Name)
+			}
+			if datasource.Labels == nil {
+				datasource.Labels = map[string]string{}
+			}
+			datasource.Labels[config.DatasourceDiscoveredLabel] = cr.Name
+			datasource.Spec.Datasource = spec.Datasource
+			datasource.Spec.InstanceSelector = spec.InstanceSelector
+			return controllerutil.SetControllerReference(cr, datasource, scheme)
+		})
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+	}
+
+	// datasources of services that are gone or no longer selected
+	var existing v1beta1.GrafanaDatasourceList
+	err := r.client.List(ctx, &existing, client.InNamespace(cr.Namespace), client.MatchingLabels{
+		config.DatasourceDiscoveredLabel: cr.Name,
+	})
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+	for i := range existing.Items {
+		datasource := &existing.Items[i]
+		if desired[datasource.Name] != nil || !metav1.IsControlledBy(datasource, cr) {
+			continue
+		}
+		logger.Info("deleting discovered datasource", "datasource", datasource.Name)
+		err = r.client.Delete(ctx, datasource)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+	}
+
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+type foundService struct {
+	service *v1.Service
+	kind    discoveredService
+}
+
+// findServices returns the services of the kinds enabled for the discovery, headless services are
+// skipped because they address the pods of a service individually
+func (r *DatasourceDiscoveryReconciler) findServices(ctx context.Context, cr *v1beta1.Grafana) ([]foundService, error) {
+	discovery := cr.Spec.DatasourceDiscovery
+
+	selector := labels.Everything()
+	if discovery.Selector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(discovery.Selector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	namespaces := discovery.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{cr.Namespace}
+	}
+
+	var found []foundService
+	for _, namespace := range namespaces {
+		var services v1.ServiceList
+		err := r.client.List(ctx, &services, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return nil, err
+		}
+		for i := range services.Items {
+			service := &services.Items[i]
+			if service.Spec.ClusterIP == v1.ClusterIPNone {
+				continue
+			}
+			kind, ok := getDiscoveredService(service)
+			if ok && discoveryEnabled(discovery, kind.kind) {
+				found = append(found, foundService{service: service, kind: kind})
+			}
+		}
+	}
+	return found, nil
+}
+
+// getDiscoveredService returns the kind of a service, the datasource type label takes precedence over
+// the well known labels
+func getDiscoveredService(service *v1.Service) (discoveredService, bool) {
+	if value, ok := service.Labels[config.DatasourceTypeLabel]; ok {
+		for _, known := range discoveredServices {
+			if string(known.kind) == value {
+				return known, true
+			}
+		}
+		return discoveredService{}, false
+	}
+
+	for _, known := range discoveredServices {
+		for _, matchLabels := range known.labels {
+			if labels.SelectorFromSet(matchLabels).Matches(labels.Set(service.Labels)) {
+				return known, true
+			}
+		}
+	}
+	return discoveredService{}, false
+}
+
+func discoveryEnabled(discovery *v1beta1.GrafanaDatasourceDiscovery, kind v1beta1.DiscoveredDatasourceType) bool {
+	if len(discovery.Types) == 0 {
+		return true
+	}
+	for _, enabled := range discovery.Types {
+		if enabled == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// getServicePort returns the port of a service by the well known port names, the default port of the kind
+// or the first port of the service
+func getServicePort(service *v1.Service, kind discoveredService) int32 {
+	for _, name := range kind.portNames {
+		for _, port := range service.Spec.Ports {
+			if port.Name == name {
+				return port.Port
+			}
+		}
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Port == kind.defaultPort {
+			return port.Port
+		}
+	}
+	if len(service.Spec.Ports) > 0 {
+		return service.Spec.Ports[0].Port
+	}
+	return kind.defaultPort
+}
+
+// getDiscoveredDatasource returns the datasource of a service, named after the instance and the service
+func getDiscoveredDatasource(cr *v1beta1.Grafana, service *v1.Service, kind discoveredService) *v1beta1.GrafanaDatasource {
+	matchLabels := make(map[string]string, len(cr.Labels))
+	for key, value := range cr.Labels {
+		matchLabels[key] = value
+	}
+
+	name := strings.ToLower(fmt.Sprintf("%s-%s-%s", cr.Name, service.Namespace, service.Name))
+	if len(name) > 253 {
+		name = name[:253]
+	}
+
+	return &v1beta1.GrafanaDatasource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      strings.TrimRight(name, "-."),
+			Namespace: cr.Namespace,
+		},
+		Spec: v1beta1.GrafanaDatasourceSpec{
+			Datasource: &v1beta1.GrafanaDatasourceInternal{
+				Name:   fmt.Sprintf("%s (%s/%s)", kind.kind, service.Namespace, service.Name),
+				Type:   kind.datasourceType,
+				URL:    fmt.Sprintf("http://%s.%s.svc:%d", service.Name, service.Namespace, getServicePort(service, kind)),
+				Access: "proxy",
+			},
+			InstanceSelector: &metav1.LabelSelector{
+				MatchLabels: matchLabels,
+			},
+		},
+	}
+}
@@ -6,16 +6,21 @@ import (
 	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
 	config2 "github.com/grafana-operator/grafana-operator-experimental/controllers/config"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/plugincache"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
 	v12 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"net/url"
+	"path"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
 )
 
 const (
@@ -42,10 +47,74 @@ func NewDeploymentReconciler(client client.Client) reconcilers.OperatorGrafanaRe
 func (r *DeploymentReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
 	_ = log.FromContext(ctx)
 
+	if cr.Spec.Persistence != nil {
+		return r.reconcileStatefulSet(ctx, cr, vars, scheme)
+	}
+
+	// switching back from persistence mode, the volume claims of the statefulset are retained
+	retired, err := r.retireStatefulSet(ctx, cr, scheme)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+	if !retired {
+		return v1beta1.OperatorStageResultInProgress, nil
+	}
+
 	deployment := model.GetGrafanaDeployment(cr, scheme)
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, deployment, func() error {
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, deployment, func() error {
+		replicas := deployment.Spec.Replicas
+		plugins := getPluginsEnv(deployment.Spec.Template)
 		deployment.Spec = getDeploymentSpec(cr, deployment.Name, scheme, vars)
-		return v1beta1.Merge(deployment, cr.Spec.Deployment)
+		err := v1beta1.Merge(deployment, cr.Spec.Deployment)
+		deployment.Spec.Replicas = getManagedReplicas(cr, deployment.CreationTimestamp, replicas, deployment.Spec.Replicas)
+		vars.PluginsChanged = !deployment.CreationTimestamp.IsZero() && plugins != getPluginsEnv(deployment.Spec.Template)
+		return err
+	})
+
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// reconcileStatefulSet runs Grafana as a statefulset. An existing deployment is scaled down and removed
+// first, so that two Grafana pods never run against the same database.
+func (r *DeploymentReconciler) reconcileStatefulSet(ctx context.Context, cr *v1beta1.Grafana, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	retired, err := r.retireDeployment(ctx, cr, scheme)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+	if !retired {
+		return v1beta1.OperatorStageResultInProgress, nil
+	}
+
+	statefulSet := model.GetGrafanaStatefulSet(cr, scheme)
+	service := model.GetGrafanaService(cr, scheme)
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, statefulSet, func() error {
+		// the pod template is generated and overridden the same way as in deployment mode
+		generated := &v12.Deployment{
+			ObjectMeta: statefulSet.ObjectMeta,
+			Spec:       getDeploymentSpec(cr, statefulSet.Name, scheme, vars),
+		}
+		err := v1beta1.Merge(generated, cr.Spec.Deployment)
+		if err != nil {
+			return err
+		}
+
+		vars.PluginsChanged = !statefulSet.CreationTimestamp.IsZero() && getPluginsEnv(statefulSet.Spec.Template) != getPluginsEnv(generated.Spec.Template)
+		statefulSet.ObjectMeta = generated.ObjectMeta
+		statefulSet.Spec.Replicas = getManagedReplicas(cr, statefulSet.CreationTimestamp, statefulSet.Spec.Replicas, generated.Spec.Replicas)
+		statefulSet.Spec.Selector = generated.Spec.Selector
+		statefulSet.Spec.Template = generated.Spec.Template
+		statefulSet.Spec.ServiceName = service.Name
+
+		// volume claim templates are immutable
+		if statefulSet.CreationTimestamp.IsZero() {
+			statefulSet.Spec.VolumeClaimTemplates = []v1.PersistentVolumeClaim{getDataVolumeClaimTemplate(cr)}
+		}
+		return nil
 	})
 
 	if err != nil {
@@ -55,6 +124,117 @@ func (r *DeploymentReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafan
 	return v1beta1.OperatorStageResultSuccess, nil
 }
 
+// getPluginsEnv returns the plugins installed by the containers of a pod template
+func getPluginsEnv(template v1.PodTemplateSpec) string {
+	for _, container := range template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == config2.GrafanaPluginsEnvVar {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// retireDeployment scales the deployment down and deletes it once all pods are gone, returns true when no
+// deployment is left
+func (r *DeploymentReconciler) retireDeployment(ctx context.Context, cr *v1beta1.Grafana, scheme *runtime.Scheme) (bool, error) {
+	deployment := model.GetGrafanaDeployment(cr, scheme)
+	err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: deployment.Namespace,
+		Name:      deployment.Name,
+	}, deployment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0 {
+		var replicas int32
+		deployment.Spec.Replicas = &replicas
+		return false, r.client.Update(ctx, deployment)
+	}
+
+	if deployment.Status.Replicas > 0 {
+		return false, nil
+	}
+
+	err = r.client.Delete(ctx, deployment)
+	return err == nil, client.IgnoreNotFound(err)
+}
+
+// retireStatefulSet scales the statefulset down and deletes it once all pods are gone, returns true when no
+// statefulset is left
+func (r *DeploymentReconciler) retireStatefulSet(ctx context.Context, cr *v1beta1.Grafana, scheme *runtime.Scheme) (bool, error) {
+	statefulSet := model.GetGrafanaStatefulSet(cr, scheme)
+	err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: statefulSet.Namespace,
+		Name:      statefulSet.Name,
+	}, statefulSet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	if statefulSet.Spec.Replicas == nil || *statefulSet.Spec.Replicas != 0 {
+		var replicas int32
+		statefulSet.Spec.Replicas = &replicas
+		return false, r.client.Update(ctx, statefulSet)
+	}
+
+	if statefulSet.Status.Replicas > 0 {
+		return false, nil
+	}
+
+	err = r.client.Delete(ctx, statefulSet)
+	return err == nil, client.IgnoreNotFound(err)
+}
+
+// getManagedReplicas returns the replicas of the workload, when autoscaling the replicas chosen by the
+// autoscaler are kept and new workloads start with the minimum replicas
+func getManagedReplicas(cr *v1beta1.Grafana, created v13.Time, current *int32, desired *int32) *int32 {
+	if cr.Spec.Autoscaling == nil {
+		return desired
+	}
+	if !created.IsZero() {
+		return current
+	}
+	return cr.Spec.Autoscaling.MinReplicas
+}
+
+func getDataVolumeClaimTemplate(cr *v1beta1.Grafana) v1.PersistentVolumeClaim {
+	persistence := cr.Spec.Persistence
+
+	size := persistence.Size
+	if size.IsZero() {
+		size = resource.MustParse(config2.GrafanaDefaultPersistenceSize)
+	}
+
+	accessModes := persistence.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+	}
+
+	return v1.PersistentVolumeClaim{
+		ObjectMeta: v13.ObjectMeta{
+			Name: config2.GrafanaDataVolumeName,
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      accessModes,
+			StorageClassName: persistence.StorageClassName,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+}
+
 func getResources() v1.ResourceRequirements {
 	return v1.ResourceRequirements{
 		Requests: v1.ResourceList{
@@ -77,19 +257,17 @@ func getRollingUpdateStrategy() *v12.RollingUpdateDeployment {
 	}
 }
 
-func getVolumes(cr *v1beta1.Grafana, scheme *runtime.Scheme) []v1.Volume { // nolint
+func getVolumes(cr *v1beta1.Grafana, scheme *runtime.Scheme, vars *v1beta1.OperatorReconcileVars) []v1.Volume { // nolint
 	var volumes []v1.Volume // nolint
 
-	config := model.GetGrafanaConfigMap(cr, scheme)
+	config := model.GetGrafanaConfigSecret(cr, scheme)
 
-	// Volume to mount the config file from a config map
+	// Volume to mount the config file from a secret
 	volumes = append(volumes, v1.Volume{
 		Name: config.Name,
 		VolumeSource: v1.VolumeSource{
-			ConfigMap: &v1.ConfigMapVolumeSource{
-				LocalObjectReference: v1.LocalObjectReference{
-					Name: config.Name,
-				},
+			Secret: &v1.SecretVolumeSource{
+				SecretName: config.Name,
 			},
 		},
 	})
@@ -102,20 +280,62 @@ func getVolumes(cr *v1beta1.Grafana, scheme *runtime.Scheme) []v1.Volume { // no
 		},
 	})
 
-	volumes = append(volumes, v1.Volume{
-		Name: config2.GrafanaDataVolumeName,
-		VolumeSource: v1.VolumeSource{
-			EmptyDir: &v1.EmptyDirVolumeSource{},
-		},
-	})
+	// the data volume is provided by the volume claim template in persistence mode
+	if cr.Spec.Persistence == nil {
+		volumes = append(volumes, v1.Volume{
+			Name: config2.GrafanaDataVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	// Volume to mount ldap.toml from the secret maintained by the ldap config controller
+	if vars.LdapHash != "" {
+		ldap := model.GetGrafanaLdapSecret(cr, scheme)
+		volumes = append(volumes, v1.Volume{
+			Name: config2.GrafanaLdapVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: ldap.Name,
+				},
+			},
+		})
+	}
+
+	// Volume to mount the certificate Grafana serves https with
+	if cr.Spec.TLS != nil {
+		volumes = append(volumes, v1.Volume{
+			Name: config2.GrafanaTLSVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: cr.TLSSecretName(),
+				},
+			},
+		})
+	}
+
+	// Volume to mount the certificates of the database connection
+	if cr.Spec.Database != nil && cr.Spec.Database.TLS != nil && cr.Spec.Database.TLS.SecretName != "" {
+		volumes = append(volumes, v1.Volume{
+			Name: config2.GrafanaDatabaseTLSVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: cr.Spec.Database.TLS.SecretName,
+				},
+			},
+		})
+	}
 
+	volumes = append(volumes, getPluginBundleVolume(cr)...)
+	volumes = append(volumes, cr.Spec.ExtraVolumes...)
 	return volumes
 }
 
-func getVolumeMounts(cr *v1beta1.Grafana, scheme *runtime.Scheme) []v1.VolumeMount {
+func getVolumeMounts(cr *v1beta1.Grafana, scheme *runtime.Scheme, vars *v1beta1.OperatorReconcileVars) []v1.VolumeMount {
 	var mounts []v1.VolumeMount // nolint
 
-	config := model.GetGrafanaConfigMap(cr, scheme)
+	config := model.GetGrafanaConfigSecret(cr, scheme)
 
 	mounts = append(mounts, v1.VolumeMount{
 		Name:      config.Name,
@@ -132,14 +352,73 @@ func getVolumeMounts(cr *v1beta1.Grafana, scheme *runtime.Scheme) []v1.VolumeMou
 		MountPath: config2.GrafanaLogsPath,
 	})
 
+	if vars.LdapHash != "" {
+		mounts = append(mounts, v1.VolumeMount{
+			Name:      config2.GrafanaLdapVolumeName,
+			MountPath: path.Dir(config2.GrafanaLdapConfigPath),
+			ReadOnly:  true,
+		})
+	}
+
+	if cr.Spec.TLS != nil {
+		mounts = append(mounts, v1.VolumeMount{
+			Name:      config2.GrafanaTLSVolumeName,
+			MountPath: config2.GrafanaTLSPath,
+			ReadOnly:  true,
+		})
+	}
+
+	if cr.Spec.Database != nil && cr.Spec.Database.TLS != nil && cr.Spec.Database.TLS.SecretName != "" {
+		mounts = append(mounts, v1.VolumeMount{
+			Name:      config2.GrafanaDatabaseTLSVolumeName,
+			MountPath: config2.GrafanaDatabaseTLSPath,
+			ReadOnly:  true,
+		})
+	}
+
+	mounts = append(mounts, cr.Spec.ExtraVolumeMounts...)
 	return mounts
 }
 
+// getGrafanaVersion returns the version of Grafana run by the instance, taken from the tag of an image set
+// for the grafana container in the deployment overrides or spec.version
+func getGrafanaVersion(cr *v1beta1.Grafana) string {
+	if cr.Spec.Deployment != nil && cr.Spec.Deployment.Spec.Template != nil {
+		for _, container := range cr.Spec.Deployment.Spec.Template.Spec.Containers {
+			if container.Name != "grafana" || container.Image == "" {
+				continue
+			}
+			if version := getImageVersion(container.Image); version != "" {
+				return version
+			}
+		}
+	}
+	if cr.Spec.Version != "" {
+		return cr.Spec.Version
+	}
+	return config2.GrafanaVersion
+}
+
+// getImageVersion returns the tag of a container image, or an empty string for untagged images
+func getImageVersion(image string) string {
+	if digest := strings.Index(image, "@"); digest >= 0 {
+		image = image[:digest]
+	}
+	if tag := strings.LastIndex(image, ":"); tag > strings.LastIndex(image, "/") {
+		return image[tag+1:]
+	}
+	return ""
+}
+
 func getContainers(cr *v1beta1.Grafana, scheme *runtime.Scheme, vars *v1beta1.OperatorReconcileVars) []v1.Container { // nolint
 	var containers []v1.Container // nolint
 	var image string
 
-	image = fmt.Sprintf("%s:%s", config2.GrafanaImage, config2.GrafanaVersion)
+	version := cr.Spec.Version
+	if version == "" {
+		version = config2.GrafanaVersion
+	}
+	image = fmt.Sprintf("%s:%s", config2.GrafanaImage, version)
 	plugins := model.GetPluginsConfigMap(cr, scheme)
 
 	// env var to restart containers if plugins change
@@ -164,27 +443,128 @@ func getContainers(cr *v1beta1.Grafana, scheme *runtime.Scheme, vars *v1beta1.Op
 		Value: vars.ConfigHash,
 	})
 
-	// env var to restart container if plugins change
-	envVars = append(envVars, v1.EnvVar{
-		Name:  "GF_INSTALL_PLUGINS",
-		Value: vars.Plugins,
-	})
+	// env var to restart container if the ldap config changes
+	if vars.LdapHash != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "LDAP_HASH",
+			Value: vars.LdapHash,
+		})
+	}
 
-	containers = append(containers, v1.Container{
-		Name:       "grafana",
-		Image:      image,
-		Args:       []string{"-config=/etc/grafana/grafana.ini"},
-		WorkingDir: "",
-		Ports: []v1.ContainerPort{
-			{
-				Name:          "grafana-http",
-				ContainerPort: int32(GetGrafanaPort(cr)),
-				Protocol:      "TCP",
+	// env var to restart container if the database password or certificates change
+	if vars.DatabaseSecretVersion != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "DATABASE_SECRET_VERSION",
+			Value: vars.DatabaseSecretVersion,
+		})
+	}
+
+	// env var to restart container if the certificate is renewed
+	if vars.TLSHash != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "TLS_HASH",
+			Value: vars.TLSHash,
+		})
+	}
+
+	// env var to restart container if the smtp password changes
+	if vars.SMTPSecretVersion != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "SMTP_SECRET_VERSION",
+			Value: vars.SMTPSecretVersion,
+		})
+	}
+
+	if cr.Spec.SMTP != nil && cr.Spec.SMTP.PasswordSecretRef != nil {
+		envVars = append(envVars, v1.EnvVar{
+			Name: config2.GrafanaSMTPPasswordEnvVar,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: cr.Spec.SMTP.PasswordSecretRef,
+			},
+		})
+	}
+
+	if cr.Spec.Database != nil && cr.Spec.Database.PasswordSecretRef != nil {
+		envVars = append(envVars, v1.EnvVar{
+			Name: config2.GrafanaDatabasePasswordEnvVar,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: cr.Spec.Database.PasswordSecretRef,
 			},
+		})
+	}
+
+	// env var to restart container if plugins change, plugins of an offline bundle are unpacked by an init
+	// container instead of downloading them
+	if getPluginBundle(cr) == nil {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  config2.GrafanaPluginsEnvVar,
+			Value: vars.Plugins,
+		})
+		if repository := getPluginDownloadRepository(cr); repository != "" {
+			envVars = append(envVars, v1.EnvVar{
+				Name:  config2.GrafanaPluginRepoEnvVar,
+				Value: repository,
+			})
+		}
+	}
+
+	// the env var overrides the config, plugins allowed by the config are kept
+	if len(vars.UnsignedPlugins) > 0 {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  config2.GrafanaUnsignedPluginsEnvVar,
+			Value: getUnsignedPlugins(cr, vars),
+		})
+	}
+
+	if cr.Spec.ImageRenderer != nil {
+		envVars = append(envVars, getImageRendererEnv(cr, scheme)...)
+	}
+
+	ports := []v1.ContainerPort{
+		{
+			Name:          "grafana-http",
+			ContainerPort: int32(GetGrafanaPort(cr)),
+			Protocol:      "TCP",
 		},
+	}
+
+	// replicas advertise their pod ip to the other alertmanager peers
+	if vars.HighAvailability {
+		envVars = append(envVars, v1.EnvVar{
+			Name: "POD_IP",
+			ValueFrom: &v1.EnvVarSource{
+				FieldRef: &v1.ObjectFieldSelector{
+					FieldPath: "status.podIP",
+				},
+			},
+		}, v1.EnvVar{
+			Name:  "GF_UNIFIED_ALERTING_HA_ADVERTISE_ADDRESS",
+			Value: fmt.Sprintf("$(POD_IP):%d", config2.GrafanaAlertingHAPort),
+		})
+
+		ports = append(ports, v1.ContainerPort{
+			Name:          config2.GrafanaAlertingHAPortNameTCP,
+			ContainerPort: int32(config2.GrafanaAlertingHAPort),
+			Protocol:      "TCP",
+		}, v1.ContainerPort{
+			Name:          config2.GrafanaAlertingHAPortNameUDP,
+			ContainerPort: int32(config2.GrafanaAlertingHAPort),
+			Protocol:      "UDP",
+		})
+	}
+
+	containers = append(containers, v1.Container{
+		Name:                     "grafana",
+		Image:                    image,
+		Args:                     []string{"-config=/etc/grafana/grafana.ini"},
+		WorkingDir:               "",
+		Ports:                    ports,
 		Env:                      envVars,
 		Resources:                getResources(),
-		VolumeMounts:             getVolumeMounts(cr, scheme),
+		VolumeMounts:             getVolumeMounts(cr, scheme, vars),
+		ReadinessProbe:           getReadinessProbe(cr),
+		LivenessProbe:            getLivenessProbe(cr),
+		StartupProbe:             getStartupProbe(cr),
 		TerminationMessagePath:   "/dev/termination-log",
 		TerminationMessagePolicy: "File",
 		ImagePullPolicy:          "IfNotPresent",
@@ -217,13 +597,136 @@ func getContainers(cr *v1beta1.Grafana, scheme *runtime.Scheme, vars *v1beta1.Op
 			}})
 	}
 
+	// the renderer doesn't need the admin credentials
+	if isImageRendererSidecar(cr) {
+		containers = append(containers, getImageRendererContainer(cr))
+	}
+
 	return containers
 }
 
+// getHealthProbeHandler returns a check of the health api, served below the sub path when Grafana serves
+// from a sub path
+func getHealthProbeHandler(cr *v1beta1.Grafana) v1.ProbeHandler {
+	scheme := v1.URISchemeHTTP
+	if cr.Spec.TLS != nil {
+		scheme = v1.URISchemeHTTPS
+	}
+
+	return v1.ProbeHandler{
+		HTTPGet: &v1.HTTPGetAction{
+			Path:   path.Join(getServeSubPath(cr), "/api/health"),
+			Port:   intstr.FromString("grafana-http"),
+			Scheme: scheme,
+		},
+	}
+}
+
+// getServeSubPath returns the path of the root url when serve_from_sub_path is enabled in the config or
+// by the ingress stage
+func getServeSubPath(cr *v1beta1.Grafana) string {
+	server := cr.Spec.Config.Server
+	if server != nil && server.ServeFromSubPath != nil {
+		if !*server.ServeFromSubPath || server.RootUrl == "" {
+			return "/"
+		}
+		u, err := url.Parse(server.RootUrl)
+		if err != nil || u.Path == "" {
+			return "/"
+		}
+		return u.Path
+	}
+
+	if cr.Spec.Ingress != nil && (server == nil || server.RootUrl == "") {
+		return getIngressPath(cr)
+	}
+	return "/"
+}
+
+// getProbe returns the probe of the spec, defaulting the handler to the health api
+func getProbe(cr *v1beta1.Grafana, probe *v1.Probe, defaults v1.Probe) *v1.Probe {
+	if probe == nil {
+		probe = &defaults
+	} else {
+		probe = probe.DeepCopy()
+	}
+
+	handler := probe.ProbeHandler
+	if handler.Exec == nil && handler.HTTPGet == nil && handler.TCPSocket == nil && handler.GRPC == nil {
+		probe.ProbeHandler = getHealthProbeHandler(cr)
+	}
+	return probe
+}
+
+func getReadinessProbe(cr *v1beta1.Grafana) *v1.Probe {
+	var probe *v1.Probe
+	if cr.Spec.Probes != nil {
+		probe = cr.Spec.Probes.Readiness
+	}
+	return getProbe(cr, probe, v1.Probe{
+		TimeoutSeconds:   3,
+		PeriodSeconds:    10,
+		SuccessThreshold: 1,
+		FailureThreshold: 3,
+	})
+}
+
+func getLivenessProbe(cr *v1beta1.Grafana) *v1.Probe {
+	var probe *v1.Probe
+	if cr.Spec.Probes != nil {
+		probe = cr.Spec.Probes.Liveness
+	}
+	return getProbe(cr, probe, v1.Probe{
+		TimeoutSeconds:   10,
+		PeriodSeconds:    10,
+		SuccessThreshold: 1,
+		FailureThreshold: 6,
+	})
+}
+
+// getStartupProbe allows Grafana five minutes to install plugins and migrate the database by default
+func getStartupProbe(cr *v1beta1.Grafana) *v1.Probe {
+	var probe *v1.Probe
+	if cr.Spec.Probes != nil {
+		probe = cr.Spec.Probes.Startup
+	}
+	return getProbe(cr, probe, v1.Probe{
+		TimeoutSeconds:   3,
+		PeriodSeconds:    10,
+		SuccessThreshold: 1,
+		FailureThreshold: 30,
+	})
+}
+
+// applyScheduling sets the scheduling fields of spec.scheduling on the pod spec
+func applyScheduling(cr *v1beta1.Grafana, spec *v1.PodSpec) {
+	scheduling := cr.Spec.Scheduling
+	if scheduling == nil {
+		return
+	}
+
+	spec.NodeSelector = scheduling.NodeSelector
+	spec.Tolerations = scheduling.Tolerations
+	spec.Affinity = scheduling.Affinity
+	spec.PriorityClassName = scheduling.PriorityClassName
+
+	for _, constraint := range scheduling.TopologySpreadConstraints {
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &v13.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": cr.Name,
+				},
+			}
+		}
+		spec.TopologySpreadConstraints = append(spec.TopologySpreadConstraints, constraint)
+	}
+}
+
 func getDeploymentSpec(cr *v1beta1.Grafana, deploymentName string, scheme *runtime.Scheme, vars *v1beta1.OperatorReconcileVars) v12.DeploymentSpec {
 	sa := model.GetGrafanaServiceAccount(cr, scheme)
 
-	return v12.DeploymentSpec{
+	spec := v12.DeploymentSpec{
+		Replicas: cr.Spec.Replicas,
 		Selector: &v13.LabelSelector{
 			MatchLabels: map[string]string{
 				"app": cr.Name,
@@ -237,10 +740,57 @@ func getDeploymentSpec(cr *v1beta1.Grafana, deploymentName string, scheme *runti
 				},
 			},
 			Spec: v1.PodSpec{
-				Volumes:            getVolumes(cr, scheme),
+				Volumes:            getVolumes(cr, scheme, vars),
+				InitContainers:     getPluginBundleInitContainers(cr),
 				Containers:         getContainers(cr, scheme, vars),
 				ServiceAccountName: sa.Name,
 			},
 		},
 	}
+	applyScheduling(cr, &spec.Template.Spec)
+
+	if vars.ExtraVolumesHash != "" {
+		spec.Template.Annotations = map[string]string{
+			config2.GrafanaExtraVolumesAnnotation: vars.ExtraVolumesHash,
+		}
+	}
+	return spec
+}
+
+// getPluginDownloadRepository returns the repository grafana-cli downloads plugins from, the plugin cache of
+// the operator is used for grafana.com when it is enabled. An empty repository downloads from grafana.com.
+func getPluginDownloadRepository(cr *v1beta1.Grafana) string {
+	if repository := cr.GetPluginRepository(); repository != "" {
+		return repository
+	}
+	if cr.Spec.Plugins != nil && cr.Spec.Plugins.DisableCache {
+		return ""
+	}
+	return plugincache.URL
+}
+
+// getUnsignedPlugins returns the plugins allowed to load without signature by the config, followed by the
+// plugins requested with signature level unsigned
+func getUnsignedPlugins(cr *v1beta1.Grafana, vars *v1beta1.OperatorReconcileVars) string {
+	var plugins []string
+	if cr.Spec.Config.Plugins != nil {
+		for _, plugin := range strings.Split(cr.Spec.Config.Plugins.AllowLoadingUnsignedPlugins, ",") {
+			if plugin = strings.TrimSpace(plugin); plugin != "" {
+				plugins = append(plugins, plugin)
+			}
+		}
+	}
+	for _, plugin := range vars.UnsignedPlugins {
+		found := false
+		for _, allowed := range plugins {
+			if allowed == plugin {
+				found = true
+				break
+			}
+		}
+		if !found {
+			plugins = append(plugins, plugin)
+		}
+	}
+	return strings.Join(plugins, ",")
 }
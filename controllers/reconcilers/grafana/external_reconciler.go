@@ -0,0 +1,38 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	"k8s.io/apimachinery/pkg/runtime"
+	"net/url"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+// ExternalReconciler points the admin url of an external instance at its configured url, it replaces all
+// stages deploying Grafana
+type ExternalReconciler struct {
+	client client.Client
+}
+
+func NewExternalReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &ExternalReconciler{
+		client: client,
+	}
+}
+
+func (r *ExternalReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	u, err := url.Parse(cr.Spec.External.URL)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return v1beta1.OperatorStageResultFailed, fmt.Errorf("external url %s must use http or https", cr.Spec.External.URL)
+	}
+
+	status.AdminUrl = strings.TrimSuffix(cr.Spec.External.URL, "/")
+	return v1beta1.OperatorStageResultSuccess, nil
+}
@@ -0,0 +1,124 @@
+package grafana
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	"hash"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+)
+
+// ExtraVolumesReconciler hashes the ConfigMaps and Secrets referenced by the extra volumes, the deployment
+// stage sets the hash as pod annotation so that changed contents restart the pods
+type ExtraVolumesReconciler struct {
+	client client.Client
+}
+
+func NewExtraVolumesReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &ExtraVolumesReconciler{
+		client: client,
+	}
+}
+
+func (r *ExtraVolumesReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	configMaps, secrets := getExtraVolumeSources(cr.Spec.ExtraVolumes)
+	if len(configMaps) == 0 && len(secrets) == 0 {
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	h := sha256.New()
+
+	for _, name := range configMaps {
+		configMap := &v1.ConfigMap{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: name}, configMap)
+		// missing sources are reported by the kubelet, or optional
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+
+		binaryData := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+		for key, value := range configMap.Data {
+			binaryData[key] = []byte(value)
+		}
+		for key, value := range configMap.BinaryData {
+			binaryData[key] = value
+		}
+		writeHashData(h, "configmap/"+name, binaryData)
+	}
+
+	for _, name := range secrets {
+		secret := &v1.Secret{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: name}, secret)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		writeHashData(h, "secret/"+name, secret.Data)
+	}
+
+	vars.ExtraVolumesHash = fmt.Sprintf("%x", h.Sum(nil))
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// getExtraVolumeSources returns the sorted names of the ConfigMaps and Secrets mounted by the volumes,
+// including the sources of projected volumes
+func getExtraVolumeSources(volumes []v1.Volume) ([]string, []string) {
+	configMaps := map[string]bool{}
+	secrets := map[string]bool{}
+
+	for _, volume := range volumes {
+		if volume.ConfigMap != nil {
+			configMaps[volume.ConfigMap.Name] = true
+		}
+		if volume.Secret != nil {
+			secrets[volume.Secret.SecretName] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps[source.ConfigMap.Name] = true
+				}
+				if source.Secret != nil {
+					secrets[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+
+	return sortedNames(configMaps), sortedNames(secrets)
+}
+
+func sortedNames(names map[string]bool) []string {
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// writeHashData writes the keys and values of a ConfigMap or Secret in a stable order
+func writeHashData(h hash.Hash, name string, data map[string][]byte) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h.Write([]byte(name))
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write(data[key])
+	}
+}
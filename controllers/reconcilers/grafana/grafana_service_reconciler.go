@@ -39,6 +39,11 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana,
 			},
 			Type: v1.ServiceTypeClusterIP,
 		}
+		// selected by the service monitor
+		if service.Labels == nil {
+			service.Labels = map[string]string{}
+		}
+		service.Labels["app"] = cr.Name
 		return v1beta1.Merge(service, cr.Spec.Service)
 	})
 
@@ -48,7 +53,11 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana,
 
 	// try to assign the admin url
 	if !cr.PreferIngress() {
-		status.AdminUrl = fmt.Sprintf("%v.%v.svc.cluster.local:%d", service.Name, cr.Namespace,
+		protocol := "http"
+		if cr.Spec.TLS != nil {
+			protocol = "https"
+		}
+		status.AdminUrl = fmt.Sprintf("%v://%v.%v.svc.cluster.local:%d", protocol, service.Name, cr.Namespace,
 			int32(GetGrafanaPort(cr)))
 	}
 
@@ -0,0 +1,122 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+)
+
+// conditionHighAvailability blocks the rollout of more than one replica until an external database is
+// configured, otherwise every pod would run its own sqlite database and send its own notifications
+const conditionHighAvailability = "HighAvailability"
+
+// HAReconciler creates the headless service unified alerting uses to discover the other replicas
+type HAReconciler struct {
+	client client.Client
+}
+
+func NewHAReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &HAReconciler{
+		client: client,
+	}
+}
+
+func (r *HAReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	service := model.GetGrafanaAlertingService(cr, scheme)
+
+	if cr.GetReplicas() <= 1 {
+		meta.RemoveStatusCondition(&status.Conditions, conditionHighAvailability)
+		err := r.client.Delete(ctx, service)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	if !hasExternalDatabase(cr) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionHighAvailability,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: cr.Generation,
+			Reason:             "ExternalDatabaseRequired",
+			Message:            "more than one replica requires a mysql or postgres database",
+		})
+		return v1beta1.OperatorStageResultFailed, fmt.Errorf("%d replicas requested without an external database", cr.GetReplicas())
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
+		service.Spec.ClusterIP = v1.ClusterIPNone
+		service.Spec.PublishNotReadyAddresses = true
+		service.Spec.Selector = map[string]string{
+			"app": cr.Name,
+		}
+		service.Spec.Ports = []v1.ServicePort{
+			{
+				Name:       config.GrafanaAlertingHAPortNameTCP,
+				Protocol:   v1.ProtocolTCP,
+				Port:       int32(config.GrafanaAlertingHAPort),
+				TargetPort: intstr.FromString(config.GrafanaAlertingHAPortNameTCP),
+			},
+			{
+				Name:       config.GrafanaAlertingHAPortNameUDP,
+				Protocol:   v1.ProtocolUDP,
+				Port:       int32(config.GrafanaAlertingHAPort),
+				TargetPort: intstr.FromString(config.GrafanaAlertingHAPortNameUDP),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionHighAvailability,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             "PeersConfigured",
+		Message:            fmt.Sprintf("unified alerting peers discovered through service %s", service.Name),
+	})
+
+	vars.HighAvailability = true
+	logger.Info("high availability configured", "replicas", cr.GetReplicas(), "service", service.Name)
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// hasExternalDatabase returns true if all replicas connect to the same mysql or postgres database, either
+// through the database spec or the database section of the config
+func hasExternalDatabase(cr *v1beta1.Grafana) bool {
+	if cr.Spec.Database != nil {
+		return true
+	}
+
+	database := cr.Spec.Config.Database
+	if database == nil {
+		return false
+	}
+	if database.Url != "" {
+		return strings.HasPrefix(database.Url, "mysql://") || strings.HasPrefix(database.Url, "postgres://")
+	}
+	return database.Type == "mysql" || database.Type == "postgres"
+}
+
+// getHAPeers returns the address unified alerting resolves to find the other replicas
+func getHAPeers(cr *v1beta1.Grafana, scheme *runtime.Scheme) string {
+	service := model.GetGrafanaAlertingService(cr, scheme)
+	return fmt.Sprintf("%s.%s.svc.cluster.local:%d", service.Name, cr.Namespace, config.GrafanaAlertingHAPort)
+}
@@ -0,0 +1,191 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	config2 "github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+)
+
+const (
+	RendererMemoryRequest = "128Mi"
+	RendererCpuRequest    = "100m"
+	RendererMemoryLimit   = "512Mi"
+	RendererCpuLimit      = "500m"
+)
+
+// ImageRendererReconciler runs the image renderer as a separate deployment and service, in sidecar mode the
+// deployment stage adds the renderer to the Grafana pod instead
+type ImageRendererReconciler struct {
+	client client.Client
+}
+
+func NewImageRendererReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &ImageRendererReconciler{
+		client: client,
+	}
+}
+
+func (r *ImageRendererReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	deployment := model.GetGrafanaImageRendererDeployment(cr, scheme)
+	service := model.GetGrafanaImageRendererService(cr, scheme)
+
+	// remove a standalone renderer left over from a previous mode
+	if !isImageRendererDeployment(cr) {
+		for _, obj := range []client.Object{deployment, service} {
+			err := r.client.Delete(ctx, obj)
+			if err != nil && !errors.IsNotFound(err) {
+				return v1beta1.OperatorStageResultFailed, err
+			}
+		}
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	labels := map[string]string{
+		"app": getImageRendererAppLabel(cr),
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, deployment, func() error {
+		deployment.Spec.Replicas = cr.Spec.ImageRenderer.Replicas
+		deployment.Spec.Selector = &v13.LabelSelector{
+			MatchLabels: labels,
+		}
+		deployment.Spec.Template.ObjectMeta.Labels = labels
+		deployment.Spec.Template.Spec.Containers = []v1.Container{getImageRendererContainer(cr)}
+		return nil
+	})
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
+		service.Spec.Selector = labels
+		service.Spec.Type = v1.ServiceTypeClusterIP
+		service.Spec.Ports = []v1.ServicePort{
+			{
+				Name:       config2.GrafanaImageRendererPortName,
+				Protocol:   "TCP",
+				Port:       int32(config2.GrafanaImageRendererPort),
+				TargetPort: intstr.FromString(config2.GrafanaImageRendererPortName),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	logger.Info("image renderer deployed", "deployment", deployment.Name, "service", service.Name)
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+func isImageRendererDeployment(cr *v1beta1.Grafana) bool {
+	return cr.Spec.ImageRenderer != nil && cr.Spec.ImageRenderer.Mode == v1beta1.ImageRendererModeDeployment
+}
+
+func isImageRendererSidecar(cr *v1beta1.Grafana) bool {
+	return cr.Spec.ImageRenderer != nil && !isImageRendererDeployment(cr)
+}
+
+func getImageRendererAppLabel(cr *v1beta1.Grafana) string {
+	return fmt.Sprintf("%s-renderer", cr.Name)
+}
+
+func getImageRendererResources(cr *v1beta1.Grafana) v1.ResourceRequirements {
+	if cr.Spec.ImageRenderer.Resources != nil {
+		return *cr.Spec.ImageRenderer.Resources
+	}
+
+	return v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse(RendererMemoryRequest),
+			v1.ResourceCPU:    resource.MustParse(RendererCpuRequest),
+		},
+		Limits: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse(RendererMemoryLimit),
+			v1.ResourceCPU:    resource.MustParse(RendererCpuLimit),
+		},
+	}
+}
+
+func getImageRendererContainer(cr *v1beta1.Grafana) v1.Container {
+	image := cr.Spec.ImageRenderer.Image
+	if image == "" {
+		image = fmt.Sprintf("%s:%s", config2.GrafanaImageRendererImage, config2.GrafanaImageRendererVersion)
+	}
+
+	env := []v1.EnvVar{
+		{
+			Name:  "HTTP_PORT",
+			Value: strconv.Itoa(config2.GrafanaImageRendererPort),
+		},
+	}
+	// the callback url doesn't match the names of the certificate
+	if cr.Spec.TLS != nil {
+		env = append(env, v1.EnvVar{
+			Name:  "IGNORE_HTTPS_ERRORS",
+			Value: "true",
+		})
+	}
+	env = append(env, cr.Spec.ImageRenderer.Env...)
+
+	return v1.Container{
+		Name:  "grafana-image-renderer",
+		Image: image,
+		Ports: []v1.ContainerPort{
+			{
+				Name:          config2.GrafanaImageRendererPortName,
+				ContainerPort: int32(config2.GrafanaImageRendererPort),
+				Protocol:      "TCP",
+			},
+		},
+		Env:                      env,
+		Resources:                getImageRendererResources(cr),
+		TerminationMessagePath:   "/dev/termination-log",
+		TerminationMessagePolicy: "File",
+		ImagePullPolicy:          "IfNotPresent",
+	}
+}
+
+// getImageRendererEnv returns the env vars pointing Grafana at the renderer, and the renderer back at Grafana
+func getImageRendererEnv(cr *v1beta1.Grafana, scheme *runtime.Scheme) []v1.EnvVar {
+	protocol := "http"
+	if cr.Spec.TLS != nil {
+		protocol = "https"
+	}
+
+	serverURL := fmt.Sprintf("http://localhost:%d/render", config2.GrafanaImageRendererPort)
+	callbackURL := fmt.Sprintf("%s://localhost:%d/", protocol, GetGrafanaPort(cr))
+
+	if isImageRendererDeployment(cr) {
+		renderer := model.GetGrafanaImageRendererService(cr, scheme)
+		grafana := model.GetGrafanaService(cr, scheme)
+		serverURL = fmt.Sprintf("http://%s.%s.svc:%d/render", renderer.Name, cr.Namespace, config2.GrafanaImageRendererPort)
+		callbackURL = fmt.Sprintf("%s://%s.%s.svc:%d/", protocol, grafana.Name, cr.Namespace, GetGrafanaPort(cr))
+	}
+
+	return []v1.EnvVar{
+		{
+			Name:  "GF_RENDERING_SERVER_URL",
+			Value: serverURL,
+		},
+		{
+			Name:  "GF_RENDERING_CALLBACK_URL",
+			Value: callbackURL,
+		},
+	}
+}
@@ -9,71 +9,93 @@ import (
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
 	routev1 "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-)
-
-const (
-	RouteKind = "Route"
+	"strings"
 )
 
 type IngressReconciler struct {
 	client    client.Client
-	discovery discovery.DiscoveryInterface
+	openshift bool
 }
 
-func NewIngressReconciler(client client.Client, discovery discovery.DiscoveryInterface) reconcilers.OperatorGrafanaReconciler {
+// NewIngressReconciler returns a reconciler creating a Route on OpenShift when requested, and an Ingress
+// everywhere else. The platform is detected once at startup.
+func NewIngressReconciler(client client.Client, openshift bool) reconcilers.OperatorGrafanaReconciler {
 	return &IngressReconciler{
 		client:    client,
-		discovery: discovery,
+		openshift: openshift,
 	}
 }
 
 func (r *IngressReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
 	logger := log.FromContext(ctx)
 
-	openshift, err := r.isOpenShift()
-	if err != nil {
-		logger.Error(err, "error determining platform")
-		return v1beta1.OperatorStageResultFailed, err
-	}
-
-	if openshift {
+	if r.openshift && cr.Spec.Route != nil {
 		logger.Info("platform is OpenShift, creating Route")
 		return r.reconcileRoute(ctx, cr, status, vars, scheme)
-	} else {
-		logger.Info("platform is Kubernetes, creating Ingress")
-		return r.reconcileIngress(ctx, cr, status, vars, scheme)
 	}
+
+	// the route api only exists on OpenShift
+	if r.openshift {
+		err := r.client.Delete(ctx, model.GetGrafanaRoute(cr, scheme))
+		if err != nil && !errors.IsNotFound(err) {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+	}
+
+	logger.Info("creating Ingress")
+	return r.reconcileIngress(ctx, cr, status, vars, scheme)
 }
 
 func (r *IngressReconciler) reconcileIngress(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
 	ingress := model.GetGrafanaIngress(cr, scheme)
 
+	// an ingress is only created when requested, remove it once the spec is gone
+	if cr.Spec.Ingress == nil {
+		err := r.client.Delete(ctx, ingress)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		status.ExternalUrl = ""
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
 	_, err := controllerutil.CreateOrUpdate(ctx, r.client, ingress, func() error {
 		ingress.Spec = getIngressSpec(cr, scheme)
-		return v1beta1.Merge(&ingress, &cr.Spec.Ingress)
+		ingress.ObjectMeta = cr.Spec.Ingress.ObjectMeta.Merge(ingress.ObjectMeta)
+		if len(cr.Spec.Ingress.Annotations) > 0 {
+			ingress.ObjectMeta = (&v1beta1.ObjectMeta{Annotations: cr.Spec.Ingress.Annotations}).Merge(ingress.ObjectMeta)
+		}
+		return v1beta1.Merge(ingress, cr.Spec.Ingress.Spec)
 	})
 
 	if err != nil {
 		return v1beta1.OperatorStageResultFailed, err
 	}
 
-	// try to assign the admin url
-	if cr.PreferIngress() {
-		if len(ingress.Status.LoadBalancer.Ingress) > 0 {
-			ingress := ingress.Status.LoadBalancer.Ingress[0]
-			if ingress.Hostname != "" {
-				status.AdminUrl = fmt.Sprintf("https://%v", ingress.Hostname)
-			}
-			status.AdminUrl = fmt.Sprintf("https://%v", ingress.IP)
+	status.ExternalUrl = getIngressExternalURL(cr)
+
+	// without a host the url is only known once the ingress controller assigned an address
+	if status.ExternalUrl == "" && len(ingress.Status.LoadBalancer.Ingress) > 0 {
+		address := ingress.Status.LoadBalancer.Ingress[0].IP
+		if ingress.Status.LoadBalancer.Ingress[0].Hostname != "" {
+			address = ingress.Status.LoadBalancer.Ingress[0].Hostname
+		}
+		if address != "" {
+			status.ExternalUrl = fmt.Sprintf("%s://%s%s", getIngressScheme(cr), address, getIngressPath(cr))
 		}
 	}
 
+	// try to assign the admin url
+	if cr.PreferIngress() && status.ExternalUrl != "" {
+		status.AdminUrl = status.ExternalUrl
+	}
+
 	return v1beta1.OperatorStageResultSuccess, nil
 }
 
@@ -81,51 +103,62 @@ func (r *IngressReconciler) reconcileRoute(ctx context.Context, cr *v1beta1.Graf
 	route := model.GetGrafanaRoute(cr, scheme)
 
 	_, err := controllerutil.CreateOrUpdate(ctx, r.client, route, func() error {
+		// keep the host assigned by the router
+		host := route.Spec.Host
 		route.Spec = getRouteSpec(cr, scheme)
-		err := v1beta1.Merge(route, cr.Spec.Route)
-		return err
+		route.Spec.Host = host
+		route.ObjectMeta = cr.Spec.Route.ObjectMeta.Merge(route.ObjectMeta)
+		return v1beta1.Merge(route, cr.Spec.Route.Spec)
 	})
 
 	if err != nil {
 		return v1beta1.OperatorStageResultFailed, err
 	}
 
+	status.ExternalUrl = ""
+	if route.Spec.Host != "" {
+		status.ExternalUrl = fmt.Sprintf("https://%v", route.Spec.Host)
+	}
+
 	// try to assign the admin url
-	if cr.PreferIngress() {
-		if route.Spec.Host != "" {
-			status.AdminUrl = fmt.Sprintf("https://%v", route.Spec.Host)
-		}
+	if cr.PreferIngress() && status.ExternalUrl != "" {
+		status.AdminUrl = status.ExternalUrl
 	}
 
 	return v1beta1.OperatorStageResultSuccess, nil
 }
 
-func (r *IngressReconciler) isOpenShift() (bool, error) {
-	apiGroupVersion := routev1.SchemeGroupVersion.String()
-
-	apiList, err := r.discovery.ServerResourcesForGroupVersion(apiGroupVersion)
-	if apiList == nil {
-		return false, nil
+func getRouteTLS(cr *v1beta1.Grafana) *routev1.TLSConfig {
+	termination := routev1.TLSTerminationEdge
+	if cr.Spec.Route.Termination == string(routev1.TLSTerminationReencrypt) {
+		termination = routev1.TLSTerminationReencrypt
 	}
-	if err != nil {
-		return false, err
+
+	tls := &routev1.TLSConfig{
+		Termination:                   termination,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
 	}
-	for _, r := range apiList.APIResources {
-		if r.Kind == RouteKind {
-			return true, nil
-		}
+	if termination == routev1.TLSTerminationReencrypt {
+		tls.DestinationCACertificate = cr.Spec.Route.DestinationCACertificate
 	}
-	return false, nil
+	return tls
 }
 
-func getRouteTLS(cr *v1beta1.Grafana) *routev1.TLSConfig {
-	return &routev1.TLSConfig{
-		Certificate:                   "",
-		Key:                           "",
-		CACertificate:                 "",
-		DestinationCACertificate:      "",
-		InsecureEdgeTerminationPolicy: "",
+// getRouteExternalURL returns the url of the instance behind the Route, using the host assigned by the
+// router when no host is requested
+func getRouteExternalURL(cr *v1beta1.Grafana) string {
+	if cr.Spec.Route == nil {
+		return ""
 	}
+
+	host := ""
+	if cr.Spec.Route.Spec != nil {
+		host = cr.Spec.Route.Spec.Host
+	}
+	if host == "" {
+		return cr.Status.ExternalUrl
+	}
+	return fmt.Sprintf("https://%s", host)
 }
 
 func GetIngressTargetPort(cr *v1beta1.Grafana) intstr.IntOrString {
@@ -163,14 +196,18 @@ func getIngressSpec(cr *v1beta1.Grafana, scheme *runtime.Scheme) v1.IngressSpec
 		assignedPort.Name = port.StrVal
 	}
 
-	return v1.IngressSpec{
+	pathType := v1.PathTypePrefix
+	spec := v1.IngressSpec{
+		IngressClassName: cr.Spec.Ingress.IngressClassName,
 		Rules: []v1.IngressRule{
 			{
+				Host: cr.Spec.Ingress.Host,
 				IngressRuleValue: v1.IngressRuleValue{
 					HTTP: &v1.HTTPIngressRuleValue{
 						Paths: []v1.HTTPIngressPath{
 							{
-								Path: "/",
+								Path:     getIngressPath(cr),
+								PathType: &pathType,
 								Backend: v1.IngressBackend{
 									Service: &v1.IngressServiceBackend{
 										Name: service.Name,
@@ -185,4 +222,39 @@ func getIngressSpec(cr *v1beta1.Grafana, scheme *runtime.Scheme) v1.IngressSpec
 			},
 		},
 	}
+
+	if cr.Spec.Ingress.TLSSecretName != "" {
+		tls := v1.IngressTLS{
+			SecretName: cr.Spec.Ingress.TLSSecretName,
+		}
+		if cr.Spec.Ingress.Host != "" {
+			tls.Hosts = []string{cr.Spec.Ingress.Host}
+		}
+		spec.TLS = []v1.IngressTLS{tls}
+	}
+
+	return spec
+}
+
+func getIngressPath(cr *v1beta1.Grafana) string {
+	if cr.Spec.Ingress == nil || cr.Spec.Ingress.Path == "" {
+		return "/"
+	}
+	return "/" + strings.TrimPrefix(cr.Spec.Ingress.Path, "/")
+}
+
+func getIngressScheme(cr *v1beta1.Grafana) string {
+	if cr.Spec.Ingress != nil && cr.Spec.Ingress.TLSSecretName != "" {
+		return "https"
+	}
+	return "http"
+}
+
+// getIngressExternalURL returns the url of the instance derived from the ingress host, empty when no host
+// is set
+func getIngressExternalURL(cr *v1beta1.Grafana) string {
+	if cr.Spec.Ingress == nil || cr.Spec.Ingress.Host == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s%s", getIngressScheme(cr), cr.Spec.Ingress.Host, getIngressPath(cr))
 }
@@ -0,0 +1,58 @@
+package grafana
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+)
+
+// LdapReconciler picks up the ldap secret maintained by the GrafanaLDAPConfig controller, the config and
+// deployment stages enable ldap and mount the secret when it exists
+type LdapReconciler struct {
+	client client.Client
+}
+
+func NewLdapReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &LdapReconciler{
+		client: client,
+	}
+}
+
+func (r *LdapReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	secret := model.GetGrafanaLdapSecret(cr, nil)
+	err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+	}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return v1beta1.OperatorStageResultSuccess, nil
+		}
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	content, ok := secret.Data[config.GrafanaLdapConfigKey]
+	if !ok {
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	hash := sha256.Sum256(content)
+	vars.LdapHash = fmt.Sprintf("%x", hash)
+
+	if val, ok := secret.Data[config.GrafanaLdapAllowSignUpKey]; ok {
+		allowSignUp, err := strconv.ParseBool(string(val))
+		if err == nil {
+			vars.LdapAllowSignUp = &allowSignUp
+		}
+	}
+
+	return v1beta1.OperatorStageResultSuccess, nil
+}
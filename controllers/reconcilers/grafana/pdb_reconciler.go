@@ -0,0 +1,65 @@
+package grafana
+
+import (
+	"context"
+	"errors"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type PodDisruptionBudgetReconciler struct {
+	client client.Client
+}
+
+func NewPodDisruptionBudgetReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &PodDisruptionBudgetReconciler{
+		client: client,
+	}
+}
+
+func (r *PodDisruptionBudgetReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	pdb := model.GetGrafanaPodDisruptionBudget(cr, scheme)
+
+	settings := cr.Spec.PodDisruptionBudget
+	if settings == nil {
+		err := r.client.Delete(ctx, pdb)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	if settings.MinAvailable != nil && settings.MaxUnavailable != nil {
+		return v1beta1.OperatorStageResultFailed, errors.New("only one of minAvailable and maxUnavailable can be set")
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, pdb, func() error {
+		pdb.Spec = policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   settings.MinAvailable,
+			MaxUnavailable: settings.MaxUnavailable,
+			Selector: &v13.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": cr.Name,
+				},
+			},
+		}
+		if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+			minAvailable := intstr.FromInt(1)
+			pdb.Spec.MinAvailable = &minAvailable
+		}
+		return nil
+	})
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	return v1beta1.OperatorStageResultSuccess, nil
+}
@@ -0,0 +1,151 @@
+package grafana
+
+import (
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	config2 "github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"path"
+)
+
+// the bundle directory is passed as env var and never interpolated into the scripts
+const (
+	pluginBundleCopyScript    = `set -e; cp "$BUNDLE_DIR"/*.zip ` + config2.PluginBundleMountPath + `/`
+	pluginBundleInstallScript = `set -e; mkdir -p "$PLUGINS_DIR"; for zip in "$BUNDLE_DIR"/*.zip; do [ -e "$zip" ] || continue; unzip -o -q "$zip" -d "$PLUGINS_DIR"; done`
+)
+
+func getPluginBundle(cr *v1beta1.Grafana) *v1beta1.GrafanaPluginBundle {
+	if cr.Spec.Plugins == nil {
+		return nil
+	}
+	return cr.Spec.Plugins.OfflineBundle
+}
+
+// validatePluginBundle requires exactly one source of an offline bundle
+func validatePluginBundle(cr *v1beta1.Grafana) error {
+	bundle := getPluginBundle(cr)
+	if bundle == nil {
+		return nil
+	}
+	if (bundle.Image == "") == (bundle.ClaimName == "") {
+		return fmt.Errorf("exactly one of image or claimName must be set for the offline plugin bundle")
+	}
+	return nil
+}
+
+func getInitResources() v1.ResourceRequirements {
+	return v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse(InitMemoryRequest),
+			v1.ResourceCPU:    resource.MustParse(InitCpuRequest),
+		},
+		Limits: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse(InitMemoryLimit),
+			v1.ResourceCPU:    resource.MustParse(InitCpuLimit),
+		},
+	}
+}
+
+// getPluginBundleVolume returns the claim of the bundle, or an empty dir the zips of a bundle image are
+// copied into
+func getPluginBundleVolume(cr *v1beta1.Grafana) []v1.Volume {
+	bundle := getPluginBundle(cr)
+	if bundle == nil {
+		return nil
+	}
+
+	source := v1.VolumeSource{
+		EmptyDir: &v1.EmptyDirVolumeSource{},
+	}
+	if bundle.ClaimName != "" {
+		source = v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+				ClaimName: bundle.ClaimName,
+				ReadOnly:  true,
+			},
+		}
+	}
+
+	return []v1.Volume{
+		{
+			Name:         config2.GrafanaPluginBundleVolumeName,
+			VolumeSource: source,
+		},
+	}
+}
+
+// getPluginBundleInitContainers unpacks the zips of an offline bundle into the plugins directory of the
+// data volume, zips of a bundle image are copied into a shared volume first
+func getPluginBundleInitContainers(cr *v1beta1.Grafana) []v1.Container {
+	bundle := getPluginBundle(cr)
+	if bundle == nil {
+		return nil
+	}
+
+	var containers []v1.Container
+	bundleDir := path.Join(config2.PluginBundleMountPath, bundle.Path)
+
+	if bundle.Image != "" {
+		imagePath := bundle.Path
+		if imagePath == "" {
+			imagePath = config2.PluginBundleImagePath
+		}
+		bundleDir = config2.PluginBundleMountPath
+
+		containers = append(containers, v1.Container{
+			Name:    "copy-plugin-bundle",
+			Image:   bundle.Image,
+			Command: []string{"sh", "-c", pluginBundleCopyScript},
+			Env: []v1.EnvVar{
+				{
+					Name:  "BUNDLE_DIR",
+					Value: imagePath,
+				},
+			},
+			Resources: getInitResources(),
+			VolumeMounts: []v1.VolumeMount{
+				{
+					Name:      config2.GrafanaPluginBundleVolumeName,
+					MountPath: config2.PluginBundleMountPath,
+				},
+			},
+			TerminationMessagePath:   "/dev/termination-log",
+			TerminationMessagePolicy: "File",
+			ImagePullPolicy:          "IfNotPresent",
+		})
+	}
+
+	containers = append(containers, v1.Container{
+		Name:    "install-plugin-bundle",
+		Image:   config2.PluginBundleInstallerImage,
+		Command: []string{"sh", "-c", pluginBundleInstallScript},
+		Env: []v1.EnvVar{
+			{
+				Name:  "BUNDLE_DIR",
+				Value: bundleDir,
+			},
+			{
+				Name:  "PLUGINS_DIR",
+				Value: config2.GrafanaPluginsPath,
+			},
+		},
+		Resources: getInitResources(),
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      config2.GrafanaPluginBundleVolumeName,
+				MountPath: config2.PluginBundleMountPath,
+				ReadOnly:  true,
+			},
+			{
+				Name:      config2.GrafanaDataVolumeName,
+				MountPath: config2.GrafanaDataPath,
+			},
+		},
+		TerminationMessagePath:   "/dev/termination-log",
+		TerminationMessagePolicy: "File",
+		ImagePullPolicy:          "IfNotPresent",
+	})
+
+	return containers
+}
@@ -0,0 +1,422 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"github.com/blang/semver"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/backup"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+	"time"
+)
+
+const (
+	conditionPluginsCompatible = "PluginsCompatible"
+	conditionPluginsVerified   = "PluginsVerified"
+	conditionPluginVersions    = "PluginVersions"
+)
+
+// pluginConflictError is returned for a plugin requested in different exact versions with the fail policy
+type pluginConflictError struct {
+	Name string
+}
+
+func (e *pluginConflictError) Error() string {
+	return fmt.Sprintf("conflicting versions of plugin %s", e.Name)
+}
+
+// incompatiblePluginError is returned for a plugin version whose grafanaDependency excludes the version of
+// Grafana
+type incompatiblePluginError struct {
+	Name       string
+	Version    string
+	Dependency string
+}
+
+func (e *incompatiblePluginError) Error() string {
+	return fmt.Sprintf("%s %s requires grafana %s", e.Name, e.Version, e.Dependency)
+}
+
+// unverifiedPluginError is returned for a plugin version whose checksum or signature doesn't match the
+// requested ones
+type unverifiedPluginError struct {
+	Name    string
+	Version string
+	Reason  string
+}
+
+func (e *unverifiedPluginError) Error() string {
+	return fmt.Sprintf("%s %s %s", e.Name, e.Version, e.Reason)
+}
+
+// pluginVersionResolver resolves the requested versions of the plugins of an instance
+type pluginVersionResolver struct {
+	now            time.Time
+	upgrade        bool
+	repository     string
+	conflictPolicy v1beta1.PluginVersionConflictPolicy
+	// nil if the compatibility of plugins is not checked
+	grafanaVersion *semver.Version
+}
+
+// getPluginCompatibilityVersion returns the version of a managed Grafana plugins are checked against, or nil
+// for external instances and versions that can't be parsed
+func getPluginCompatibilityVersion(cr *v1beta1.Grafana) *semver.Version {
+	if cr.IsExternal() || cr.IsCloudStack() {
+		return nil
+	}
+	parsed, ok := parseVersion(getGrafanaVersion(cr))
+	if !ok {
+		return nil
+	}
+	return &semver.Version{
+		Major: uint64(parsed[0]),
+		Minor: uint64(parsed[1]),
+		Patch: uint64(parsed[2]),
+	}
+}
+
+// isCompatible returns true if a plugin version supports the version of Grafana, versions without or with
+// an unknown dependency are assumed to be compatible
+func (r *pluginVersionResolver) isCompatible(version client2.CatalogPluginVersion) bool {
+	if r.grafanaVersion == nil || version.GrafanaDependency == "" {
+		return true
+	}
+	dependency, err := v1beta1.ParseVersionRange(version.GrafanaDependency)
+	if err != nil {
+		return true
+	}
+	return dependency(*r.grafanaVersion)
+}
+
+// checkCompatible returns an incompatiblePluginError if the catalog lists a version of a plugin as
+// incompatible with Grafana. Plugins are not checked while grafana.com is not reachable.
+func (r *pluginVersionResolver) checkCompatible(ctx context.Context, name string, version string) error {
+	if r.grafanaVersion == nil {
+		return nil
+	}
+
+	available, err := client2.GetCatalogPluginVersions(ctx, r.repository, name)
+	if err != nil {
+		log.FromContext(ctx).Info("plugin repository not reachable, skipping plugin compatibility check", "plugin", name, "version", version, "error", err.Error())
+		return nil
+	}
+	for _, candidate := range available {
+		if candidate.Version == version && !r.isCompatible(candidate) {
+			return &incompatiblePluginError{
+				Name:       name,
+				Version:    version,
+				Dependency: candidate.GrafanaDependency,
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the exact version requested for a plugin chosen by the conflict policy, which has to
+// satisfy all requested ranges. Plugins only requested as latest or by ranges are pinned to the newest version on grafana.com
+// satisfying all of them and compatible with Grafana, the pin is kept until an upgrade is due, the requested
+// versions change or the pinned version is incompatible with Grafana. A pin that still satisfies the
+// requested versions is used while grafana.com is not reachable.
+func (r *pluginVersionResolver) resolve(ctx context.Context, name string, versions []string, pinned *v1beta1.GrafanaResolvedPlugin) (string, *v1beta1.GrafanaResolvedPlugin, error) {
+	logger := log.FromContext(ctx)
+
+	var exact []semver.Version
+	var ranges []semver.Range
+	for _, version := range versions {
+		if parsed, err := semver.Parse(version); err == nil {
+			exact = append(exact, parsed)
+			continue
+		}
+		versionRange, err := v1beta1.ParseVersionRange(version)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid version %s of plugin %s: %w", version, name, err)
+		}
+		ranges = append(ranges, versionRange)
+	}
+
+	satisfies := func(version semver.Version) bool {
+		for _, versionRange := range ranges {
+			if !versionRange(version) {
+				return false
+			}
+		}
+		return true
+	}
+
+	requested := strings.Join(versions, ", ")
+	if len(exact) > 0 {
+		semver.Sort(exact)
+		chosen := exact[len(exact)-1]
+		if exact[0].NE(chosen) {
+			switch r.conflictPolicy {
+			case v1beta1.PluginVersionConflictLowest:
+				chosen = exact[0]
+			case v1beta1.PluginVersionConflictFail:
+				return "", nil, &pluginConflictError{Name: name}
+			}
+		}
+		if !satisfies(chosen) {
+			return "", nil, fmt.Errorf("version %s of plugin %s doesn't satisfy the requested versions %s", chosen, name, requested)
+		}
+		if err := r.checkCompatible(ctx, name, chosen.String()); err != nil {
+			return "", nil, err
+		}
+		return chosen.String(), nil, nil
+	}
+
+	// a pin is valid as long as it satisfies the requested versions
+	var current *v1beta1.GrafanaResolvedPlugin
+	if pinned != nil {
+		if version, err := semver.Parse(pinned.Version); err == nil && satisfies(version) {
+			current = pinned.DeepCopy()
+			current.Requested = requested
+		}
+	}
+	if current != nil && !r.upgrade {
+		if err := r.checkCompatible(ctx, name, current.Version); err == nil {
+			return current.Version, current, nil
+		}
+		logger.Info("pinned plugin version is incompatible with grafana", "plugin", name, "version", current.Version)
+		current = nil
+	}
+
+	available, err := client2.GetCatalogPluginVersions(ctx, r.repository, name)
+	if err != nil {
+		if current != nil {
+			logger.Info("plugin repository not reachable, keeping pinned plugin version", "plugin", name, "version", current.Version, "error", err.Error())
+			return current.Version, current, nil
+		}
+		return "", nil, fmt.Errorf("resolving versions of plugin %s: %w", name, err)
+	}
+
+	var candidates []semver.Version
+	var newestIncompatible *client2.CatalogPluginVersion
+	for i, version := range available {
+		parsed, err := semver.Parse(version.Version)
+		// prereleases are only installed when requested exactly
+		if err != nil || len(parsed.Pre) > 0 || !satisfies(parsed) {
+			continue
+		}
+		if !r.isCompatible(version) {
+			if newestIncompatible == nil || parsed.GT(semver.MustParse(newestIncompatible.Version)) {
+				newestIncompatible = &available[i]
+			}
+			continue
+		}
+		candidates = append(candidates, parsed)
+	}
+	if len(candidates) == 0 {
+		if newestIncompatible != nil {
+			return "", nil, &incompatiblePluginError{
+				Name:       name,
+				Version:    newestIncompatible.Version,
+				Dependency: newestIncompatible.GrafanaDependency,
+			}
+		}
+		return "", nil, fmt.Errorf("no version of plugin %s satisfies %s", name, requested)
+	}
+
+	semver.Sort(candidates)
+	version := candidates[len(candidates)-1].String()
+	if current != nil && current.Version == version {
+		return version, current, nil
+	}
+
+	logger.Info("pinned plugin version", "plugin", name, "requested", requested, "version", version)
+	return version, &v1beta1.GrafanaResolvedPlugin{
+		Name:      name,
+		Requested: requested,
+		Version:   version,
+		Time:      metav1.Time{Time: r.now},
+	}, nil
+}
+
+// resolveURLPlugin returns the plugin downloaded from a url, all requests of the plugin have to agree on the
+// exact version and url
+func resolveURLPlugin(name string, requests v1beta1.PluginList) (v1beta1.GrafanaPlugin, error) {
+	plugin := requests[0]
+	for _, request := range requests[1:] {
+		if request.Version != plugin.Version || request.URL != plugin.URL {
+			return v1beta1.GrafanaPlugin{}, fmt.Errorf("conflicting versions %s and %s of plugin %s downloaded from a url", plugin.Version, request.Version, name)
+		}
+	}
+	return v1beta1.GrafanaPlugin{
+		Name:    name,
+		Version: plugin.Version,
+		URL:     plugin.URL,
+	}, nil
+}
+
+// getRequiredSignatureLevel returns the strictest signature level requested for a plugin, or an empty level
+// if none is requested
+func getRequiredSignatureLevel(requests v1beta1.PluginList) v1beta1.PluginSignatureLevel {
+	var required v1beta1.PluginSignatureLevel
+	for _, plugin := range requests {
+		if plugin.SignatureLevel != "" && (required == "" || plugin.SignatureLevel.Rank() > required.Rank()) {
+			required = plugin.SignatureLevel
+		}
+	}
+	return required
+}
+
+// requiresVerification returns true if a checksum or signature level is requested for a plugin
+func requiresVerification(requests v1beta1.PluginList) bool {
+	for _, plugin := range requests {
+		if plugin.Sha256 != "" || plugin.SignatureLevel != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// verify checks the resolved version of a plugin against the checksums and signature levels of all
+// requests, an unverifiedPluginError is returned on a mismatch. Checksums pin the exact version, Grafana
+// verifies the downloaded archive against the checksum published on grafana.com.
+func (r *pluginVersionResolver) verify(ctx context.Context, name string, version string, requests v1beta1.PluginList) error {
+	if !requiresVerification(requests) {
+		return nil
+	}
+
+	available, err := client2.GetCatalogPluginVersions(ctx, r.repository, name)
+	if err != nil {
+		return fmt.Errorf("verifying plugin %s: %w", name, err)
+	}
+
+	var published *client2.CatalogPluginVersion
+	for i := range available {
+		if available[i].Version == version {
+			published = &available[i]
+			break
+		}
+	}
+	if published == nil {
+		return &unverifiedPluginError{Name: name, Version: version, Reason: "is not published in the plugin repository"}
+	}
+
+	for _, plugin := range requests {
+		if plugin.Sha256 == "" {
+			continue
+		}
+		if plugin.Version != version {
+			return &unverifiedPluginError{Name: name, Version: version, Reason: fmt.Sprintf("is requested, the checksum is given for %s", plugin.Version)}
+		}
+		if !published.HasChecksum(plugin.Sha256) {
+			return &unverifiedPluginError{Name: name, Version: version, Reason: fmt.Sprintf("doesn't match checksum %s", plugin.Sha256)}
+		}
+	}
+
+	required := getRequiredSignatureLevel(requests)
+	signature := v1beta1.PluginSignatureLevel(published.SignatureType)
+	if published.SignatureType == "" {
+		signature = v1beta1.PluginSignatureUnsigned
+	}
+	if required != "" && signature.Rank() < required.Rank() {
+		return &unverifiedPluginError{Name: name, Version: version, Reason: fmt.Sprintf("has signature %s, %s is required", signature, required)}
+	}
+	return nil
+}
+
+// setPluginsVerifiedCondition reports the plugins left out because their checksum or signature doesn't match,
+// the condition is only set while checksums or signature levels are requested
+func setPluginsVerifiedCondition(cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, verified bool, unverified []string) {
+	if !verified && len(unverified) == 0 {
+		meta.RemoveStatusCondition(&status.Conditions, conditionPluginsVerified)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionPluginsVerified,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             "Verified",
+		Message:            "checksums and signatures of all plugins match",
+	}
+	if len(unverified) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "VerificationFailed"
+		condition.Message = fmt.Sprintf("plugins not installed: %s", strings.Join(unverified, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// describePluginConflict lists the exact versions of a plugin with the resources requesting them
+func describePluginConflict(name string, requests v1beta1.PluginList, requestedBy []string) string {
+	var versions []string
+	for i, plugin := range requests {
+		if plugin.IsRange() {
+			continue
+		}
+		versions = append(versions, fmt.Sprintf("%s by %s", plugin.Version, requestedBy[i]))
+	}
+	return fmt.Sprintf("%s: %s", name, strings.Join(versions, ", "))
+}
+
+// setPluginVersionsCondition reports plugins requested in different versions, the condition is only set
+// with the fail policy
+func setPluginVersionsCondition(cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, conflicts []string) {
+	if cr.GetPluginConflictPolicy() != v1beta1.PluginVersionConflictFail {
+		meta.RemoveStatusCondition(&status.Conditions, conditionPluginVersions)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionPluginVersions,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             "NoConflicts",
+		Message:            "all resources request the same versions of plugins",
+	}
+	if len(conflicts) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "VersionConflicts"
+		condition.Message = fmt.Sprintf("plugins are not updated, conflicting versions of %s", strings.Join(conflicts, "; "))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// setPluginsCompatibleCondition reports the plugins left out because they don't support the version of
+// Grafana, the condition is removed when the version is not known
+func setPluginsCompatibleCondition(cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, grafanaVersion *semver.Version, incompatible []string) {
+	if grafanaVersion == nil {
+		meta.RemoveStatusCondition(&status.Conditions, conditionPluginsCompatible)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionPluginsCompatible,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             "Compatible",
+		Message:            fmt.Sprintf("all plugins support Grafana %s", grafanaVersion),
+	}
+	if len(incompatible) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "IncompatiblePlugins"
+		condition.Message = fmt.Sprintf("plugins not installed on Grafana %s: %s", grafanaVersion, strings.Join(incompatible, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// isPluginsUpgradeDue returns true if the auto upgrade schedule passed since the last upgrade
+func isPluginsUpgradeDue(cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, now time.Time) (bool, error) {
+	if cr.Spec.Plugins == nil || cr.Spec.Plugins.AutoUpgradeSchedule == "" {
+		return false, nil
+	}
+
+	schedule, err := backup.ParseSchedule(cr.Spec.Plugins.AutoUpgradeSchedule)
+	if err != nil {
+		return false, err
+	}
+
+	last := cr.CreationTimestamp
+	if status.PluginsUpgradeTime != nil {
+		last = *status.PluginsUpgradeTime
+	}
+	next := schedule.Next(last.Time)
+	return !next.IsZero() && !now.Before(next), nil
+}
@@ -3,35 +3,59 @@ package grafana
 import (
 	"context"
 	"encoding/json"
+	errors2 "errors"
+	"fmt"
 	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sort"
+	"strings"
+	"time"
 )
 
+const (
+	conditionPluginsAPI       = "PluginsAPI"
+	conditionPluginsOutOfSync = "PluginsOutOfSync"
+)
+
+// InstanceSelector returns true if a resource selects an instance with its label selector
+type InstanceSelector func(grafana *v1beta1.Grafana, cr client.Object, selector *metav1.LabelSelector) bool
+
 type PluginsReconciler struct {
-	client client.Client
+	client           client.Client
+	instanceSelected InstanceSelector
 }
 
-func NewPluginsReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+func NewPluginsReconciler(client client.Client, instanceSelected InstanceSelector) reconcilers.OperatorGrafanaReconciler {
 	return &PluginsReconciler{
-		client: client,
+		client:           client,
+		instanceSelected: instanceSelected,
 	}
 }
 
 func (r *PluginsReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
 	logger := log.FromContext(ctx)
 
+	err := validatePluginBundle(cr)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
 	plugins := model.GetPluginsConfigMap(cr, scheme)
 	selector := client.ObjectKey{
 		Namespace: plugins.Namespace,
 		Name:      plugins.Name,
 	}
 
-	err := r.client.Get(ctx, selector, plugins)
+	err = r.client.Get(ctx, selector, plugins)
 
 	// plugins config map not found, we need to create it
 	if err != nil && errors.IsNotFound(err) {
@@ -43,6 +67,7 @@ func (r *PluginsReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana,
 
 		// no plugins yet, assign plugins to empty string
 		vars.Plugins = ""
+		status.ConsolidatedPlugins = nil
 
 		return v1beta1.OperatorStageResultSuccess, nil
 	} else if err != nil {
@@ -50,51 +75,456 @@ func (r *PluginsReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana,
 		return v1beta1.OperatorStageResultFailed, err
 	}
 
-	// plugins config map found, but may be empty
-	if plugins.BinaryData == nil || len(plugins.BinaryData) == 0 {
-		vars.Plugins = ""
-		return v1beta1.OperatorStageResultSuccess, nil
+	if cr.Spec.Plugins != nil && cr.Spec.Plugins.RemoveUnused {
+		err = r.removeUnusedPlugins(ctx, cr, plugins)
+		if err != nil {
+			logger.Error(err, "error removing unused plugins", "name", plugins.Name, "namespace", plugins.Namespace)
+			return v1beta1.OperatorStageResultFailed, err
+		}
 	}
 
-	var consolidatedPlugins v1beta1.PluginList
-	for dashboard, plugins := range plugins.BinaryData {
+	// plugins config map found, but may be empty. The requested versions are collected in a stable order,
+	// so that the env var doesn't change between reconciles.
+	keys := make([]string, 0, len(plugins.BinaryData))
+	for key := range plugins.BinaryData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	requested := map[string]v1beta1.PluginList{}
+	requestedBy := map[string][]string{}
+	for _, dashboard := range keys {
 		var dashboardPlugins v1beta1.PluginList
-		err = json.Unmarshal(plugins, &dashboardPlugins)
+		err = json.Unmarshal(plugins.BinaryData[dashboard], &dashboardPlugins)
 		if err != nil {
 			logger.Error(err, "error consolidating plugins", "dashboard", dashboard)
 			return v1beta1.OperatorStageResultFailed, err
 		}
 
 		for _, plugin := range dashboardPlugins {
-			// new plugin
-			if !consolidatedPlugins.HasSomeVersionOf(&plugin) {
-				consolidatedPlugins = append(consolidatedPlugins, plugin)
-				continue
-			}
+			requested[plugin.Name] = append(requested[plugin.Name], plugin)
+			requestedBy[plugin.Name] = append(requestedBy[plugin.Name], dashboard)
+		}
+	}
+
+	names := make([]string, 0, len(requested))
+	for name := range requested {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	upgrade, err := isPluginsUpgradeDue(cr, status, now)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
 
-			// newer version of plugin already installed
-			hasNewer, err := consolidatedPlugins.HasNewerVersionOf(&plugin)
+	// plugins incompatible with the version of Grafana are left out, Grafana fails to start otherwise
+	resolver := &pluginVersionResolver{
+		now:            now,
+		upgrade:        upgrade,
+		repository:     cr.GetPluginRepository(),
+		conflictPolicy: cr.GetPluginConflictPolicy(),
+		grafanaVersion: getPluginCompatibilityVersion(cr),
+	}
+
+	var consolidatedPlugins v1beta1.PluginList
+	var resolved []v1beta1.GrafanaResolvedPlugin
+	var conflicts []string
+	var incompatible []string
+	var unverified []string
+	var unsigned []string
+	verified := false
+	for _, name := range names {
+		// plugins downloaded from a url are not resolved through the plugin repository
+		if requested[name].HasURLs() {
+			plugin, err := resolveURLPlugin(name, requested[name])
 			if err != nil {
-				logger.Error(err, "error checking existing plugins", "dashboard", dashboard)
+				logger.Error(err, "error consolidating plugins", "plugin", name)
 				return v1beta1.OperatorStageResultFailed, err
 			}
-
-			if hasNewer {
-				logger.Info("skipping plugin", "dashboard", dashboard, "plugin",
-					plugin.Name, "version", plugin.Version)
-				continue
+			consolidatedPlugins = append(consolidatedPlugins, plugin)
+			if getRequiredSignatureLevel(requested[name]) == v1beta1.PluginSignatureUnsigned {
+				unsigned = append(unsigned, name)
 			}
+			continue
+		}
 
-			// duplicate plugin
-			if consolidatedPlugins.HasExactVersionOf(&plugin) {
-				continue
-			}
+		versions := make([]string, 0, len(requested[name]))
+		for _, plugin := range requested[name] {
+			versions = append(versions, plugin.Version)
+		}
 
-			// some version is installed, but it is not newer and it is not the same: must be older
-			consolidatedPlugins.Update(&plugin)
+		version, pinned, err := resolver.resolve(ctx, name, versions, status.GetResolvedPlugin(name))
+		var conflictErr *pluginConflictError
+		if errors2.As(err, &conflictErr) {
+			conflicts = append(conflicts, describePluginConflict(name, requested[name], requestedBy[name]))
+			continue
+		}
+		var incompatibleErr *incompatiblePluginError
+		if errors2.As(err, &incompatibleErr) {
+			incompatible = append(incompatible, incompatibleErr.Error())
+			continue
+		}
+		if err != nil {
+			logger.Error(err, "error consolidating plugins", "plugin", name)
+			return v1beta1.OperatorStageResultFailed, err
+		}
+
+		// plugins are only installed once checksums and signatures match
+		verified = verified || requiresVerification(requested[name])
+		err = resolver.verify(ctx, name, version, requested[name])
+		var unverifiedErr *unverifiedPluginError
+		if errors2.As(err, &unverifiedErr) {
+			unverified = append(unverified, unverifiedErr.Error())
+			continue
+		}
+		if err != nil {
+			logger.Error(err, "error verifying plugins", "plugin", name)
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		if getRequiredSignatureLevel(requested[name]) == v1beta1.PluginSignatureUnsigned {
+			unsigned = append(unsigned, name)
+		}
+		consolidatedPlugins = append(consolidatedPlugins, v1beta1.GrafanaPlugin{
+			Name:    name,
+			Version: version,
+		})
+		if pinned != nil {
+			resolved = append(resolved, *pinned)
 		}
 	}
 
+	// conflicts keep the plugins of the running instance
+	setPluginVersionsCondition(cr, status, conflicts)
+	if len(conflicts) > 0 {
+		return v1beta1.OperatorStageResultFailed, fmt.Errorf("conflicting plugin versions of %s", strings.Join(conflicts, "; "))
+	}
+
+	status.ResolvedPlugins = resolved
+	setPluginsCompatibleCondition(cr, status, resolver.grafanaVersion, incompatible)
+	setPluginsVerifiedCondition(cr, status, verified, unverified)
+	if upgrade {
+		status.PluginsUpgradeTime = &metav1.Time{Time: now}
+	}
+
 	vars.Plugins = consolidatedPlugins.String()
+	vars.UnsignedPlugins = unsigned
+	status.ConsolidatedPlugins = consolidatedPlugins
+
+	result, err := r.installConsolidatedPlugins(ctx, cr, status, vars, consolidatedPlugins)
+	if err != nil {
+		return result, err
+	}
+
+	r.reportPlugins(ctx, cr, status, consolidatedPlugins)
+	return result, nil
+}
+
+func (r *PluginsReconciler) installConsolidatedPlugins(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, plugins v1beta1.PluginList) (v1beta1.OperatorStageStatus, error) {
+	// external instances are not restarted with GF_INSTALL_PLUGINS, the plugins are installed through the api
+	if cr.IsCloudStack() {
+		return r.installCloudStackPlugins(ctx, cr, plugins)
+	}
+	if cr.IsExternal() {
+		return r.installPlugins(ctx, cr, status, plugins)
+	}
+	if cr.InstallsPluginsThroughAPI() {
+		return r.installManagedPlugins(ctx, cr, status, vars, plugins)
+	}
+
+	meta.RemoveStatusCondition(&status.Conditions, conditionPluginsAPI)
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// reportPlugins publishes the plugins reported by the instance and whether they diverge from the requested
+// plugins. Plugins installed by other means are listed but don't count as divergence. Managed instances
+// pick up plugins installed through GF_INSTALL_PLUGINS after the restart by the deployment stage.
+func (r *PluginsReconciler) reportPlugins(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, plugins v1beta1.PluginList) {
+	logger := log.FromContext(ctx)
+
+	if status.AdminUrl == "" {
+		return
+	}
+
+	var installed map[string]string
+	if cr.IsCloudStack() {
+		cloudClient, err := client2.NewGrafanaCloudClient(ctx, r.client, cr)
+		if err == nil {
+			installed, err = cloudClient.GetStackPlugins(cr.Spec.Cloud.Slug)
+		}
+		if err != nil {
+			logger.Info("cloud stack not reachable, plugins are reported later", "error", err.Error())
+			return
+		}
+	} else {
+		grafanaClient, err := client2.NewGrafanaClient(ctx, r.client, cr)
+		if err == nil {
+			installed, err = grafanaClient.GetInstalledPlugins()
+		}
+		if err != nil {
+			logger.Info("grafana not reachable, plugins are reported later", "error", err.Error())
+			return
+		}
+	}
+
+	names := make([]string, 0, len(installed))
+	for name := range installed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	status.Plugins = nil
+	for _, name := range names {
+		status.Plugins = append(status.Plugins, v1beta1.GrafanaPlugin{
+			Name:    name,
+			Version: installed[name],
+		})
+	}
+
+	var diverged []string
+	for _, plugin := range plugins {
+		version, ok := installed[plugin.Name]
+		if !ok {
+			diverged = append(diverged, fmt.Sprintf("%s is missing", plugin.Name))
+		} else if version != plugin.Version {
+			diverged = append(diverged, fmt.Sprintf("%s %s instead of %s", plugin.Name, version, plugin.Version))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionPluginsOutOfSync,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: cr.Generation,
+		Reason:             "InSync",
+		Message:            "all requested plugins are installed",
+	}
+	if len(diverged) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Diverged"
+		condition.Message = strings.Join(diverged, ", ")
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// removeUnusedPlugins removes the entries of the plugins config map whose dashboard or datasource no longer
+// requests plugins from the instance, including the entries keyed by name by previous versions
+func (r *PluginsReconciler) removeUnusedPlugins(ctx context.Context, cr *v1beta1.Grafana, plugins *v1.ConfigMap) error {
+	logger := log.FromContext(ctx)
+
+	used := map[string]bool{}
+
+	var dashboards v1beta1.GrafanaDashboardList
+	err := r.client.List(ctx, &dashboards)
+	if err != nil {
+		return err
+	}
+	for i := range dashboards.Items {
+		dashboard := &dashboards.Items[i]
+		requested := append(append(v1beta1.PluginList{}, dashboard.Spec.Plugins...), dashboard.Status.DerivedPlugins...)
+		if r.requestsPlugins(cr, dashboard, dashboard.Spec.InstanceSelector, requested) {
+			used[model.GetPluginsKey(model.PluginsKindDashboard, dashboard.Namespace, dashboard.Name)] = true
+		}
+	}
+
+	var datasources v1beta1.GrafanaDatasourceList
+	err = r.client.List(ctx, &datasources)
+	if err != nil {
+		return err
+	}
+	for i := range datasources.Items {
+		datasource := &datasources.Items[i]
+		requested := append(append(v1beta1.PluginList{}, datasource.Spec.Plugins...), datasource.Status.DerivedPlugins...)
+		if r.requestsPlugins(cr, datasource, datasource.Spec.InstanceSelector, requested) {
+			used[model.GetPluginsKey(model.PluginsKindDatasource, datasource.Namespace, datasource.Name)] = true
+		}
+	}
+
+	removed := false
+	for key := range plugins.BinaryData {
+		if used[key] {
+			continue
+		}
+		logger.Info("removing plugins of a deleted resource", "key", key)
+		delete(plugins.BinaryData, key)
+		removed = true
+	}
+	if !removed {
+		return nil
+	}
+	return r.client.Update(ctx, plugins)
+}
+
+// requestsPlugins returns true if a resource that is not being deleted selects the instance and requests
+// plugins
+func (r *PluginsReconciler) requestsPlugins(cr *v1beta1.Grafana, object client.Object, selector *metav1.LabelSelector, plugins v1beta1.PluginList) bool {
+	if object.GetDeletionTimestamp() != nil || len(plugins) == 0 {
+		return false
+	}
+	return r.instanceSelected(cr, object, selector)
+}
+
+// installManagedPlugins installs the plugins of a managed instance through the api, so that changes don't
+// restart Grafana. The api only reaches a single pod, instances with more than one replica or a disabled
+// plugin catalog keep installing plugins through GF_INSTALL_PLUGINS.
+func (r *PluginsReconciler) installManagedPlugins(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, plugins v1beta1.PluginList) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:               conditionPluginsAPI,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: cr.Generation,
+	}
+
+	if cr.GetReplicas() > 1 {
+		condition.Reason = "MultipleReplicas"
+		condition.Message = "plugins are installed on startup of each replica"
+		meta.SetStatusCondition(&status.Conditions, condition)
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	// the api only installs plugins from grafana.com
+	if plugins.HasURLs() || cr.GetPluginRepository() != "" {
+		condition.Reason = "CustomRepository"
+		condition.Message = "plugins downloaded from a url or plugin repository are installed on startup"
+		meta.SetStatusCondition(&status.Conditions, condition)
+		vars.Plugins = plugins.String()
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	// keep the env var while the catalog is known to be disabled
+	if meta.IsStatusConditionFalse(status.Conditions, conditionPluginsAPI) {
+		vars.Plugins = plugins.String()
+	} else {
+		vars.Plugins = ""
+	}
+
+	// plugins are installed once Grafana is running
+	if status.AdminUrl == "" {
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.client, cr)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	enabled, err := grafanaClient.IsPluginCatalogEnabled()
+	if err != nil {
+		logger.Info("grafana not reachable, installing plugins later", "error", err.Error())
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+	if !enabled {
+		condition.Reason = "CatalogDisabled"
+		condition.Message = "plugin_admin_enabled is disabled, plugins are installed on startup"
+		meta.SetStatusCondition(&status.Conditions, condition)
+		vars.Plugins = plugins.String()
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	vars.Plugins = ""
+	result, err := r.installPlugins(ctx, cr, status, plugins)
+	if err != nil {
+		return result, err
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "Installed"
+	condition.Message = ""
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return result, nil
+}
+
+// installPlugins installs the plugins through the plugin catalog api and uninstalls plugins installed by
+// the operator that are no longer requested
+func (r *PluginsReconciler) installPlugins(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, plugins v1beta1.PluginList) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.client, cr)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	installed, err := grafanaClient.GetInstalledPlugins()
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	// plugins installed before by other means are not recorded, so that they are never uninstalled
+	managed := map[string]bool{}
+	for _, name := range status.InstalledPlugins {
+		managed[name] = true
+	}
+
+	requested := map[string]bool{}
+	for _, plugin := range plugins {
+		if plugin.URL != "" {
+			logger.Info("plugins downloaded from a url can't be installed through the api", "plugin", plugin.Name, "url", plugin.URL)
+			continue
+		}
+		requested[plugin.Name] = true
+		if installed[plugin.Name] == plugin.Version {
+			continue
+		}
+
+		logger.Info("installing plugin", "plugin", plugin.Name, "version", plugin.Version)
+		err = grafanaClient.InstallPlugin(plugin, installed[plugin.Name])
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		managed[plugin.Name] = true
+	}
+
+	for _, name := range status.InstalledPlugins {
+		if requested[name] {
+			continue
+		}
+		if _, ok := installed[name]; ok {
+			logger.Info("uninstalling plugin", "plugin", name)
+			err = grafanaClient.UninstallPlugin(name)
+			if err != nil && !client2.IsNotFound(err) {
+				return v1beta1.OperatorStageResultFailed, err
+			}
+		}
+		delete(managed, name)
+	}
+
+	status.InstalledPlugins = nil
+	for name := range managed {
+		status.InstalledPlugins = append(status.InstalledPlugins, name)
+	}
+	sort.Strings(status.InstalledPlugins)
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+func (r *PluginsReconciler) installCloudStackPlugins(ctx context.Context, cr *v1beta1.Grafana, plugins v1beta1.PluginList) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	cloudClient, err := client2.NewGrafanaCloudClient(ctx, r.client, cr)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	installed, err := cloudClient.GetStackPlugins(cr.Spec.Cloud.Slug)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	for _, plugin := range plugins {
+		if plugin.URL != "" {
+			logger.Info("plugins downloaded from a url can't be installed in cloud stacks", "plugin", plugin.Name, "url", plugin.URL)
+			continue
+		}
+		if installed[plugin.Name] == plugin.Version {
+			continue
+		}
+
+		logger.Info("installing plugin in cloud stack", "plugin", plugin.Name, "version", plugin.Version, "stack", cr.Spec.Cloud.Slug)
+		err = cloudClient.InstallStackPlugin(cr.Spec.Cloud.Slug, plugin, installed[plugin.Name])
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+	}
+
 	return v1beta1.OperatorStageResultSuccess, nil
 }
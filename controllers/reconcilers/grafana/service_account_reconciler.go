@@ -2,6 +2,7 @@ package grafana
 
 import (
 	"context"
+	"fmt"
 	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
@@ -23,8 +24,31 @@ func NewServiceAccountReconciler(client client.Client) reconcilers.OperatorGrafa
 func (r *ServiceAccountReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
 	sa := model.GetGrafanaServiceAccount(cr, scheme)
 
+	// an existing service account is used as is
+	if !cr.Spec.ServiceAccount.IsCreated() {
+		err := r.client.Get(ctx, client.ObjectKey{
+			Namespace: sa.Namespace,
+			Name:      sa.Name,
+		}, sa)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, fmt.Errorf("service account %s/%s: %w", sa.Namespace, sa.Name, err)
+		}
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
 	_, err := controllerutil.CreateOrUpdate(ctx, r.client, sa, func() error {
-		return v1beta1.Merge(sa, cr.Spec.ServiceAccount)
+		if cr.Spec.ServiceAccount == nil {
+			return nil
+		}
+		sa.ObjectMeta = cr.Spec.ServiceAccount.ObjectMeta.Merge(sa.ObjectMeta)
+		if len(cr.Spec.ServiceAccount.Annotations) > 0 {
+			sa.ObjectMeta = (&v1beta1.ObjectMeta{Annotations: cr.Spec.ServiceAccount.Annotations}).Merge(sa.ObjectMeta)
+		}
+		return v1beta1.Merge(sa, &v1beta1.ServiceAccountV1{
+			Secrets:                      cr.Spec.ServiceAccount.Secrets,
+			ImagePullSecrets:             cr.Spec.ServiceAccount.ImagePullSecrets,
+			AutomountServiceAccountToken: cr.Spec.ServiceAccount.AutomountServiceAccountToken,
+		})
 	})
 
 	if err != nil {
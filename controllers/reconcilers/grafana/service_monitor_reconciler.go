@@ -0,0 +1,155 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	ServiceMonitorGroupVersion = "monitoring.coreos.com/v1"
+	ServiceMonitorKind         = "ServiceMonitor"
+)
+
+// ServiceMonitorReconciler creates a ServiceMonitor scraping the Grafana service. The crd is looked up on
+// every reconcile, so prometheus-operator can be installed after the operator.
+type ServiceMonitorReconciler struct {
+	client    client.Client
+	discovery discovery.DiscoveryInterface
+}
+
+func NewServiceMonitorReconciler(client client.Client, discovery discovery.DiscoveryInterface) reconcilers.OperatorGrafanaReconciler {
+	return &ServiceMonitorReconciler{
+		client:    client,
+		discovery: discovery,
+	}
+}
+
+func (r *ServiceMonitorReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	available, err := r.isServiceMonitorAvailable()
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+	if !available {
+		if cr.Spec.Metrics != nil && cr.Spec.Metrics.ServiceMonitor != nil {
+			logger.Info("ServiceMonitor crd not installed, skipping service monitor")
+		}
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	serviceMonitor := model.GetGrafanaServiceMonitor(cr, scheme)
+
+	if cr.Spec.Metrics == nil || cr.Spec.Metrics.ServiceMonitor == nil {
+		err = r.client.Delete(ctx, serviceMonitor)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	spec, err := getServiceMonitorSpec(cr, scheme)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, serviceMonitor, func() error {
+		serviceMonitor.SetLabels(cr.Spec.Metrics.ServiceMonitor.Labels)
+		serviceMonitor.Object["spec"] = spec
+		return nil
+	})
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+func (r *ServiceMonitorReconciler) isServiceMonitorAvailable() (bool, error) {
+	apiList, err := r.discovery.ServerResourcesForGroupVersion(ServiceMonitorGroupVersion)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range apiList.APIResources {
+		if resource.Kind == ServiceMonitorKind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getServiceMonitorSpec returns the spec scraping /metrics of the Grafana service, in the shape of the
+// prometheus-operator api
+func getServiceMonitorSpec(cr *v1beta1.Grafana, scheme *runtime.Scheme) (map[string]interface{}, error) {
+	settings := cr.Spec.Metrics.ServiceMonitor
+	service := model.GetGrafanaService(cr, scheme)
+
+	endpoint := map[string]interface{}{
+		"port":   config.GrafanaHttpPortName,
+		"path":   "/metrics",
+		"scheme": "http",
+	}
+	if settings.Interval != "" {
+		endpoint["interval"] = settings.Interval
+	}
+	if settings.ScrapeTimeout != "" {
+		endpoint["scrapeTimeout"] = settings.ScrapeTimeout
+	}
+
+	if cr.Spec.TLS != nil {
+		optional := true
+		endpoint["scheme"] = "https"
+		endpoint["tlsConfig"] = map[string]interface{}{
+			"serverName": fmt.Sprintf("%s.%s.svc", service.Name, cr.Namespace),
+			"ca": map[string]interface{}{
+				"secret": &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: cr.TLSSecretName(),
+					},
+					Key:      v1.ServiceAccountRootCAKey,
+					Optional: &optional,
+				},
+			},
+		}
+	}
+
+	if len(settings.Relabelings) > 0 {
+		endpoint["relabelings"] = settings.Relabelings
+	}
+	if len(settings.MetricRelabelings) > 0 {
+		endpoint["metricRelabelings"] = settings.MetricRelabelings
+	}
+
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				"app": cr.Name,
+			},
+		},
+		"endpoints": []interface{}{endpoint},
+	}
+
+	// unstructured objects only hold json values
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var converted map[string]interface{}
+	err = json.Unmarshal(data, &converted)
+	return converted, err
+}
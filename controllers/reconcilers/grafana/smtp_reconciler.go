@@ -0,0 +1,141 @@
+package grafana
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"net"
+	"net/smtp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+const (
+	conditionSMTPReady = "SMTPReady"
+
+	smtpDialTimeout = 5 * time.Second
+)
+
+// SMTPReconciler resolves the smtp password and checks that Grafana will be able to send email. An
+// unreachable server is reported in the SMTPReady condition but doesn't block the rollout.
+type SMTPReconciler struct {
+	client client.Client
+}
+
+func NewSMTPReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &SMTPReconciler{
+		client: client,
+	}
+}
+
+func (r *SMTPReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	settings := cr.Spec.SMTP
+	if settings == nil {
+		meta.RemoveStatusCondition(&status.Conditions, conditionSMTPReady)
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	password := ""
+	if settings.PasswordSecretRef != nil {
+		secret := &v1.Secret{}
+		err := r.client.Get(ctx, client.ObjectKey{
+			Namespace: cr.Namespace,
+			Name:      settings.PasswordSecretRef.Name,
+		}, secret)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		value, ok := secret.Data[settings.PasswordSecretRef.Key]
+		if !ok {
+			return v1beta1.OperatorStageResultFailed, fmt.Errorf("secret %s/%s does not contain key %s", cr.Namespace, secret.Name, settings.PasswordSecretRef.Key)
+		}
+		password = string(value)
+		vars.SMTPSecretVersion = secretVersion(secret)
+	}
+
+	// check again after spec changes and while the server is not reachable
+	current := meta.FindStatusCondition(status.Conditions, conditionSMTPReady)
+	if current != nil && current.Status == metav1.ConditionTrue && current.ObservedGeneration == cr.Generation {
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionSMTPReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             "Connected",
+		Message:            fmt.Sprintf("connected to %s", settings.Host),
+	}
+
+	err := checkSMTPServer(settings, password)
+	if err != nil {
+		logger.Info("smtp server check failed", "host", settings.Host, "error", err.Error())
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ConnectionFailed"
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// checkSMTPServer connects to the server the same way Grafana does and authenticates when a user is set
+func checkSMTPServer(settings *v1beta1.GrafanaSMTP, password string) error {
+	host, _, err := net.SplitHostPort(settings.Host)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", settings.Host, smtpDialTimeout)
+	if err != nil {
+		return err
+	}
+	err = conn.SetDeadline(time.Now().Add(smtpDialTimeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if settings.StartTLS != "NoStartTLS" {
+		supported, _ := c.Extension("STARTTLS")
+		if supported {
+			// #nosec G402 explicitly requested for the instance
+			err = c.StartTLS(&tls.Config{
+				ServerName:         host,
+				InsecureSkipVerify: settings.SkipVerify,
+				MinVersion:         tls.VersionTLS12,
+			})
+			if err != nil {
+				return err
+			}
+		} else if settings.StartTLS == "MandatoryStartTLS" {
+			return errors.New("server does not support STARTTLS")
+		}
+	}
+
+	if settings.User != "" {
+		err = c.Auth(smtp.PlainAuth("", settings.User, password, host))
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,129 @@
+package grafana
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sort"
+)
+
+// TLSReconciler requests the certificate of the instance from cert-manager and waits for the tls secret,
+// the config and deployment stages switch Grafana to https once it exists
+type TLSReconciler struct {
+	client client.Client
+}
+
+func NewTLSReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &TLSReconciler{
+		client: client,
+	}
+}
+
+func (r *TLSReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	certificate := model.GetGrafanaCertificate(cr, scheme)
+
+	if cr.Spec.TLS == nil || cr.Spec.TLS.IssuerRef == nil {
+		// clusters without cert-manager don't know the kind
+		err := r.client.Delete(ctx, certificate)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		if cr.Spec.TLS == nil {
+			return v1beta1.OperatorStageResultSuccess, nil
+		}
+	} else {
+		_, err := controllerutil.CreateOrUpdate(ctx, r.client, certificate, func() error {
+			return unstructured.SetNestedMap(certificate.Object, getCertificateSpec(cr, scheme), "spec")
+		})
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+	}
+
+	secret := &v1.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      cr.TLSSecretName(),
+	}, secret)
+	if err != nil {
+		// cert-manager creates the secret once the certificate is issued
+		if errors.IsNotFound(err) && cr.Spec.TLS.IssuerRef != nil {
+			logger.Info("waiting for certificate", "secret", cr.TLSSecretName())
+			return v1beta1.OperatorStageResultInProgress, nil
+		}
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	for _, key := range []string{v1.TLSCertKey, v1.TLSPrivateKeyKey} {
+		if _, ok := secret.Data[key]; !ok {
+			return v1beta1.OperatorStageResultFailed, fmt.Errorf("secret %s/%s does not contain key %s", secret.Namespace, secret.Name, key)
+		}
+	}
+
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write(secret.Data[key])
+	}
+	vars.TLSHash = fmt.Sprintf("%x", hash.Sum(nil))
+
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// getCertificateSpec requests a certificate valid for all names of the Grafana service, the ingress host
+// and the additional dns names
+func getCertificateSpec(cr *v1beta1.Grafana, scheme *runtime.Scheme) map[string]interface{} {
+	service := model.GetGrafanaService(cr, scheme)
+
+	dnsNames := []interface{}{
+		service.Name,
+		fmt.Sprintf("%s.%s", service.Name, cr.Namespace),
+		fmt.Sprintf("%s.%s.svc", service.Name, cr.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, cr.Namespace),
+	}
+	if cr.Spec.Ingress != nil && cr.Spec.Ingress.Host != "" {
+		dnsNames = append(dnsNames, cr.Spec.Ingress.Host)
+	}
+	for _, name := range cr.Spec.TLS.DNSNames {
+		dnsNames = append(dnsNames, name)
+	}
+
+	issuerRef := cr.Spec.TLS.IssuerRef
+	kind := issuerRef.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+	group := issuerRef.Group
+	if group == "" {
+		group = "cert-manager.io"
+	}
+
+	return map[string]interface{}{
+		"secretName": cr.TLSSecretName(),
+		"dnsNames":   dnsNames,
+		"issuerRef": map[string]interface{}{
+			"name":  issuerRef.Name,
+			"kind":  kind,
+			"group": group,
+		},
+	}
+}
@@ -0,0 +1,275 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/backup"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/model"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/reconcilers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UpgradeReconciler gates changes of the Grafana version. The running instance is backed up and the new
+// version is only handed to the deployment stage once it passed the version check. The upgrade completes
+// when all pods run the new version and the health api reports the migrated database.
+type UpgradeReconciler struct {
+	client client.Client
+}
+
+func NewUpgradeReconciler(client client.Client) reconcilers.OperatorGrafanaReconciler {
+	return &UpgradeReconciler{
+		client: client,
+	}
+}
+
+func (r *UpgradeReconciler) Reconcile(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, vars *v1beta1.OperatorReconcileVars, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	target := getGrafanaVersion(cr)
+	current := status.Version
+	if current == "" {
+		running, err := r.getRunningVersion(ctx, cr, scheme)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, err
+		}
+		// nothing to upgrade on the first install
+		if running == "" {
+			status.Version = target
+			return v1beta1.OperatorStageResultSuccess, nil
+		}
+		current = running
+		status.Version = running
+	}
+
+	if current == target {
+		if cr.IsUpgrading() {
+			return r.checkUpgrade(ctx, cr, status, target, scheme)
+		}
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	upgrade := cr.Spec.Upgrade
+	if upgrade == nil {
+		upgrade = &v1beta1.GrafanaUpgrade{}
+	}
+
+	if !upgrade.AllowDowngrade {
+		comparison, ok := compareVersions(target, current)
+		if !ok {
+			return v1beta1.OperatorStageResultFailed, fmt.Errorf("can't compare version %s with the running version %s, set spec.upgrade.allowDowngrade to roll it out", target, current)
+		}
+		if comparison < 0 {
+			return v1beta1.OperatorStageResultFailed, fmt.Errorf("version %s is older than the running version %s, set spec.upgrade.allowDowngrade to roll it out", target, current)
+		}
+	}
+
+	if !upgrade.SkipBackup {
+		location, err := r.backupInstance(ctx, cr, status, current, scheme)
+		if err != nil {
+			return v1beta1.OperatorStageResultFailed, fmt.Errorf("pre-upgrade backup failed: %w", err)
+		}
+		status.UpgradeBackup = location
+	}
+
+	logger.Info("upgrading grafana", "from", current, "to", target)
+	status.PreviousVersion = current
+	status.Version = target
+	status.UpgradePhase = v1beta1.UpgradePhaseRollingOut
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// checkUpgrade completes an upgrade once the workload is rolled out and the health api reports the new
+// version with a working database. The stage doesn't block while the upgrade is running.
+func (r *UpgradeReconciler) checkUpgrade(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, target string, scheme *runtime.Scheme) (v1beta1.OperatorStageStatus, error) {
+	logger := log.FromContext(ctx)
+
+	rolledOut, err := r.isRolledOut(ctx, cr, scheme)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+	if !rolledOut || status.AdminUrl == "" {
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.client, cr)
+	if err != nil {
+		return v1beta1.OperatorStageResultFailed, err
+	}
+
+	// the health api returns an error while migrations are running
+	health, err := grafanaClient.GetHealth()
+	if err != nil {
+		logger.Info("waiting for grafana to become healthy", "version", target, "error", err.Error())
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+	if health.Database != "ok" {
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+	if comparison, ok := compareVersions(health.Version, target); ok && comparison != 0 {
+		return v1beta1.OperatorStageResultSuccess, nil
+	}
+
+	logger.Info("grafana upgrade completed", "version", target)
+	status.UpgradePhase = v1beta1.UpgradePhaseCompleted
+	return v1beta1.OperatorStageResultSuccess, nil
+}
+
+// backupInstance exports the running instance into the upgrade backup secret and returns its name
+func (r *UpgradeReconciler) backupInstance(ctx context.Context, cr *v1beta1.Grafana, status *v1beta1.GrafanaStatus, version string, scheme *runtime.Scheme) (string, error) {
+	if status.AdminUrl == "" {
+		return "", fmt.Errorf("instance is not reachable")
+	}
+
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.client, cr)
+	if err != nil {
+		return "", err
+	}
+
+	files, err := grafanaClient.ExportBackup()
+	if err != nil {
+		return "", err
+	}
+
+	tarball, err := backup.Archive(files, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if len(tarball) > config.UpgradeBackupMaxSize {
+		return "", fmt.Errorf("backup of %d bytes exceeds the size of a secret, back up with a GrafanaBackup and set spec.upgrade.skipBackup", len(tarball))
+	}
+
+	secret := model.GetGrafanaUpgradeBackupSecret(cr, scheme)
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+		secret.Data = map[string][]byte{
+			config.UpgradeBackupKey:        tarball,
+			config.UpgradeBackupVersionKey: []byte(version),
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return secret.Name, nil
+}
+
+// getRunningVersion returns the version of the grafana container of an existing workload, or an empty
+// string if Grafana is not deployed yet
+func (r *UpgradeReconciler) getRunningVersion(ctx context.Context, cr *v1beta1.Grafana, scheme *runtime.Scheme) (string, error) {
+	var template v1.PodTemplateSpec
+	if cr.Spec.Persistence != nil {
+		statefulSet := model.GetGrafanaStatefulSet(cr, scheme)
+		err := r.client.Get(ctx, client.ObjectKeyFromObject(statefulSet), statefulSet)
+		if err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+		template = statefulSet.Spec.Template
+	} else {
+		deployment := model.GetGrafanaDeployment(cr, scheme)
+		err := r.client.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)
+		if err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+		template = deployment.Spec.Template
+	}
+
+	for _, container := range template.Spec.Containers {
+		if container.Name == "grafana" {
+			return getImageVersion(container.Image), nil
+		}
+	}
+	return "", nil
+}
+
+// isRolledOut returns true once all pods of the workload run the current template
+func (r *UpgradeReconciler) isRolledOut(ctx context.Context, cr *v1beta1.Grafana, scheme *runtime.Scheme) (bool, error) {
+	if cr.Spec.Persistence != nil {
+		statefulSet := model.GetGrafanaStatefulSet(cr, scheme)
+		err := r.client.Get(ctx, client.ObjectKeyFromObject(statefulSet), statefulSet)
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		replicas := int32(1)
+		if statefulSet.Spec.Replicas != nil {
+			replicas = *statefulSet.Spec.Replicas
+		}
+		return statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+			statefulSet.Status.UpdateRevision == statefulSet.Status.CurrentRevision &&
+			statefulSet.Status.ReadyReplicas == replicas, nil
+	}
+
+	deployment := model.GetGrafanaDeployment(cr, scheme)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas, nil
+}
+
+// compareVersions compares two versions of the form major.minor.patch, suffixes like -ubuntu are ignored.
+// The second return value is false if either version can't be parsed.
+func compareVersions(a string, b string) (int, bool) {
+	va, ok := parseVersion(a)
+	if !ok {
+		return 0, false
+	}
+	vb, ok := parseVersion(b)
+	if !ok {
+		return 0, false
+	}
+
+	for i := range va {
+		if va[i] != vb[i] {
+			if va[i] < vb[i] {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+
+	version = strings.TrimPrefix(version, "v")
+	if suffix := strings.IndexAny(version, "-+"); suffix >= 0 {
+		version = version[:suffix]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
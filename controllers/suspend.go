@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	conditionSuspended = "Suspended"
+	reasonSuspended    = "Suspended"
+)
+
+// kinds that handle spec.suspend in their reconciler
+var selfSuspendingKinds = map[string]bool{
+	// suspended backups keep reporting their schedule
+	"GrafanaBackup": true,
+}
+
+// suspendReconciler skips the reconciles of crs with spec.suspend set and reports them with the Suspended
+// condition, the other conditions keep the state of the last reconcile. Deleted crs are still finalized.
+type suspendReconciler struct {
+	client     client.Client
+	kind       schema.GroupVersionKind
+	reconciler reconcile.Reconciler
+}
+
+func (r *suspendReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj, err := r.client.Scheme().New(r.kind)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	cr := obj.(client.Object)
+	err = r.client.Get(ctx, req.NamespacedName, cr)
+	if err != nil || cr.GetDeletionTimestamp() != nil {
+		// crs that can't be fetched are left to the reconciler
+		return r.reconciler.Reconcile(ctx, req)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	suspended, _, _ := unstructured.NestedBool(content, "spec", "suspend")
+	conditions, err := getUnstructuredConditions(content)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	condition := meta.FindStatusCondition(conditions, conditionSuspended)
+
+	if !suspended {
+		if condition != nil {
+			meta.RemoveStatusCondition(&conditions, conditionSuspended)
+			// the next reconcile gets the cr without the condition from the cache
+			return reconcile.Result{Requeue: true}, r.updateConditions(ctx, cr, content, conditions)
+		}
+		return r.reconciler.Reconcile(ctx, req)
+	}
+
+	if condition != nil && condition.ObservedGeneration == cr.GetGeneration() {
+		return reconcile.Result{}, nil
+	}
+	log.FromContext(ctx).Info("reconciliation is suspended", "kind", r.kind.Kind, "name", req.NamespacedName)
+	setSuspendedCondition(&conditions, cr.GetGeneration(), "changes are not applied until spec.suspend is removed")
+	return reconcile.Result{}, r.updateConditions(ctx, cr, content, conditions)
+}
+
+func setSuspendedCondition(conditions *[]v1.Condition, generation int64, message string) {
+	meta.SetStatusCondition(conditions, v1.Condition{
+		Type:               conditionSuspended,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             reasonSuspended,
+		Message:            message,
+	})
+}
+
+// updateConditions writes the conditions to the status of a cr fetched as content
+func (r *suspendReconciler) updateConditions(ctx context.Context, cr client.Object, content map[string]interface{}, conditions []v1.Condition) error {
+	raw, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+	var values []interface{}
+	err = json.Unmarshal(raw, &values)
+	if err != nil {
+		return err
+	}
+
+	err = unstructured.SetNestedSlice(content, values, "status", "conditions")
+	if err == nil {
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, cr)
+	}
+	if err != nil {
+		return err
+	}
+	return r.client.Status().Update(ctx, cr)
+}
+
+func getUnstructuredConditions(content map[string]interface{}) ([]v1.Condition, error) {
+	values, _, err := unstructured.NestedSlice(content, "status", "conditions")
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	var conditions []v1.Condition
+	err = json.Unmarshal(raw, &conditions)
+	return conditions, err
+}
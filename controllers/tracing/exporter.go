@@ -0,0 +1,213 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	exportInterval  = time.Second * 5
+	exportBatchSize = 512
+	exportQueueSize = 4096
+)
+
+// Exporter sends spans in batches to the traces endpoint of an OTLP collector, using the json encoding of
+// OTLP over http. Spans are dropped while the queue is full or the collector is not reachable.
+type Exporter struct {
+	url        string
+	service    string
+	httpClient *http.Client
+	spans      chan otlpSpan
+}
+
+// NewExporter returns an exporter for an OTLP endpoint like http://collector:4318, /v1/traces is appended to
+// endpoints without path
+func NewExporter(endpoint string, service string) *Exporter {
+	url := strings.TrimSuffix(endpoint, "/")
+	if parts := strings.SplitN(url, "://", 2); len(parts) == 2 && !strings.Contains(parts[1], "/") {
+		url += "/v1/traces"
+	}
+	return &Exporter{
+		url:     url,
+		service: service,
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+		spans: make(chan otlpSpan, exportQueueSize),
+	}
+}
+
+// Start sends the queued spans until the context is done
+func (e *Exporter) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("tracing")
+	logger.Info("exporting traces", "url", e.url)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	var batch []otlpSpan
+	for {
+		flush := false
+		select {
+		case <-ctx.Done():
+			// the remaining spans are sent with a fresh context
+			flushCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+			return e.send(flushCtx, batch)
+		case span := <-e.spans:
+			batch = append(batch, span)
+			flush = len(batch) >= exportBatchSize
+		case <-ticker.C:
+			flush = len(batch) > 0
+		}
+
+		if flush {
+			err := e.send(ctx, batch)
+			if err != nil {
+				logger.Error(err, "error exporting traces", "spans", len(batch))
+			}
+			batch = nil
+		}
+	}
+}
+
+// NeedLeaderElection returns false, every replica of the operator exports its own spans
+func (e *Exporter) NeedLeaderElection() bool {
+	return false
+}
+
+func (e *Exporter) export(span *Span, end time.Time) {
+	exported := otlpSpan{
+		TraceID:           hex.EncodeToString(span.traceID[:]),
+		SpanID:            hex.EncodeToString(span.spanID[:]),
+		Name:              span.name,
+		Kind:              span.kind,
+		StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:        toAttributes(span.attributes),
+	}
+	if span.parentID != [8]byte{} {
+		exported.ParentSpanID = hex.EncodeToString(span.parentID[:])
+	}
+	if span.err != "" {
+		exported.Status = &otlpStatus{
+			Code:    2,
+			Message: span.err,
+		}
+	}
+
+	select {
+	case e.spans <- exported:
+	default:
+	}
+}
+
+func (e *Exporter) send(ctx context.Context, spans []otlpSpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: toAttributes([][2]string{{"service.name", e.service}}),
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: e.service},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("otlp endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func toAttributes(attributes [][2]string) []otlpAttribute {
+	converted := make([]otlpAttribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		converted = append(converted, otlpAttribute{
+			Key:   attribute[0],
+			Value: otlpValue{StringValue: attribute[1]},
+		})
+	}
+	return converted
+}
+
+// json encoding of an OTLP ExportTraceServiceRequest
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,126 @@
+// Package tracing records spans of the reconciles and exports them to an OTLP endpoint. Spans are only
+// recorded once an exporter is set, all functions are no-ops otherwise.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
+)
+
+// span kinds of the OTLP protocol
+const (
+	kindInternal = 1
+	kindClient   = 3
+)
+
+// exporter receives the ended spans, nil while tracing is disabled
+var exporter *Exporter
+
+// SetExporter enables tracing, it has to be called before the controllers are started
+func SetExporter(e *Exporter) {
+	exporter = e
+}
+
+type spanKey struct{}
+
+// Span is a timed operation of a trace, nil spans are returned while tracing is disabled and ignore all calls
+type Span struct {
+	traceID    [16]byte
+	spanID     [8]byte
+	parentID   [8]byte
+	name       string
+	kind       int
+	start      time.Time
+	attributes [][2]string
+	err        string
+}
+
+// Start starts a span as child of the span in the context, attributes are passed as key value pairs
+func Start(ctx context.Context, name string, attributes ...string) (context.Context, *Span) {
+	return start(ctx, name, kindInternal, attributes)
+}
+
+// StartClient starts a span of a request to another service
+func StartClient(ctx context.Context, name string, attributes ...string) (context.Context, *Span) {
+	return start(ctx, name, kindClient, attributes)
+}
+
+func start(ctx context.Context, name string, kind int, attributes []string) (context.Context, *Span) {
+	if exporter == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		name:  name,
+		kind:  kind,
+		start: time.Now(),
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else {
+		rand.Read(span.traceID[:]) // nolint
+	}
+	rand.Read(span.spanID[:]) // nolint
+	for i := 0; i+1 < len(attributes); i += 2 {
+		span.SetAttribute(attributes[i], attributes[i+1])
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SetAttribute adds an attribute to the span
+func (s *Span) SetAttribute(key string, value string) {
+	if s == nil {
+		return
+	}
+	s.attributes = append(s.attributes, [2]string{key, value})
+}
+
+// SetError marks the span as failed, nil errors are ignored
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.err = err.Error()
+}
+
+// End passes the span to the exporter
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	exporter.export(s, time.Now())
+}
+
+// Traceparent returns the W3C trace context header of the span in the context, empty without span
+func Traceparent(ctx context.Context) string {
+	span, ok := ctx.Value(spanKey{}).(*Span)
+	if !ok || span == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%x-%x-01", span.traceID, span.spanID)
+}
+
+// NewReconciler records a span for every reconcile of a controller, the spans of a reconcile are its children
+func NewReconciler(kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &tracingReconciler{
+		kind:       kind,
+		reconciler: r,
+	}
+}
+
+type tracingReconciler struct {
+	kind       string
+	reconciler reconcile.Reconciler
+}
+
+func (r *tracingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := Start(ctx, "reconcile "+r.kind, "k8s.namespace.name", req.Namespace, "k8s.object.name", req.Name)
+	result, err := r.reconciler.Reconcile(ctx, req)
+	span.SetError(err)
+	span.End()
+	return result, err
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"strings"
+)
+
+//+kubebuilder:webhook:path=/validate-unique-grafana-integreatly-org-v1beta1-grafanadashboard,mutating=false,failurePolicy=fail,sideEffects=None,groups=grafana.integreatly.org,resources=grafanadashboards,verbs=create;update,versions=v1beta1,name=vuniquegrafanadashboard.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-unique-grafana-integreatly-org-v1beta1-grafanadatasource,mutating=false,failurePolicy=fail,sideEffects=None,groups=grafana.integreatly.org,resources=grafanadatasources,verbs=create;update,versions=v1beta1,name=vuniquegrafanadatasource.kb.io,admissionReviewVersions=v1
+
+// SetupUniquenessWebhooksWithManager registers the webhooks rejecting dashboards and datasources whose uid or
+// name is already used by another cr on the same instance, which the reconcilers would otherwise report
+// only once the cr is reconciled
+func SetupUniquenessWebhooksWithManager(mgr ctrl.Manager) error {
+	validator := &uniquenessValidator{client: mgr.GetClient()}
+	server := mgr.GetWebhookServer()
+	server.Register("/validate-unique-grafana-integreatly-org-v1beta1-grafanadashboard", admission.WithCustomValidator(&grafanav1beta1.GrafanaDashboard{}, validator))
+	server.Register("/validate-unique-grafana-integreatly-org-v1beta1-grafanadatasource", admission.WithCustomValidator(&grafanav1beta1.GrafanaDatasource{}, validator))
+	return nil
+}
+
+// uniquenessValidator checks dashboards and datasources against the crs that were imported into the same
+// organization of an instance before, using the checks of the reconcilers
+type uniquenessValidator struct {
+	client client.Client
+}
+
+var _ admission.CustomValidator = &uniquenessValidator{}
+
+func (v *uniquenessValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(ctx, obj)
+}
+
+// ValidateUpdate only checks changes of the spec, so that crs that are in conflict already can still be
+// changed by the reconcilers and deleted
+func (v *uniquenessValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	switch cr := newObj.(type) {
+	case *grafanav1beta1.GrafanaDashboard:
+		previous, ok := oldObj.(*grafanav1beta1.GrafanaDashboard)
+		if cr.DeletionTimestamp != nil || (ok && equality.Semantic.DeepEqual(previous.Spec, cr.Spec)) {
+			return nil
+		}
+	case *grafanav1beta1.GrafanaDatasource:
+		previous, ok := oldObj.(*grafanav1beta1.GrafanaDatasource)
+		if cr.DeletionTimestamp != nil || (ok && equality.Semantic.DeepEqual(previous.Spec, cr.Spec)) {
+			return nil
+		}
+	}
+	return v.validate(ctx, newObj)
+}
+
+func (v *uniquenessValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v *uniquenessValidator) validate(ctx context.Context, obj runtime.Object) error {
+	switch cr := obj.(type) {
+	case *grafanav1beta1.GrafanaDashboard:
+		// the uid of dashboards downloaded or loaded from a ConfigMap is only known once reconciled
+		if cr.Spec.InstanceSelector == nil || strings.TrimSpace(cr.Spec.Json) == "" {
+			return nil
+		}
+		uid, err := cr.DashboardUID()
+		if err != nil {
+			// invalid json is rejected by the validating webhook of the dashboard
+			return nil
+		}
+		return v.validateInstances(ctx, "GrafanaDashboard", cr, cr.Spec.InstanceSelector, field.NewPath("spec", "json"), func(grafana *grafanav1beta1.Grafana) error {
+			return checkDashboardConflicts(ctx, v.client, grafana, cr, uid)
+		})
+	case *grafanav1beta1.GrafanaDatasource:
+		if cr.Spec.InstanceSelector == nil {
+			return nil
+		}
+		return v.validateInstances(ctx, "GrafanaDatasource", cr, cr.Spec.InstanceSelector, field.NewPath("spec", "datasource"), func(grafana *grafanav1beta1.Grafana) error {
+			return checkDatasourceConflicts(ctx, v.client, grafana, cr)
+		})
+	}
+	return nil
+}
+
+// validateInstances runs a conflict check for every instance a cr is imported into, the error names the
+// conflicting cr and instance
+func (v *uniquenessValidator) validateInstances(ctx context.Context, kind string, cr client.Object, selector *metav1.LabelSelector, path *field.Path, check func(grafana *grafanav1beta1.Grafana) error) error {
+	instances, err := GetMatchingInstances(ctx, v.client, cr, selector)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	var errs field.ErrorList
+	for i := range instances.Items {
+		err = check(&instances.Items[i])
+		if err != nil {
+			errs = append(errs, field.Forbidden(path, err.Error()))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(grafanav1beta1.GroupVersion.WithKind(kind).GroupKind(), cr.GetName(), errs)
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
+)
+
+// kinds of the index keys of referenced secrets and configmaps
+const (
+	referenceSecret    = "secret"
+	referenceConfigMap = "configmap"
+)
+
+// referenceKey returns the index key of a secret or configmap referenced by a cr
+func referenceKey(kind string, namespace string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// getValueFromKeys returns the index keys of the secrets and configmaps of value sources in a namespace
+func getValueFromKeys(namespace string, sources ...grafanav1beta1.ValueFromSource) []string {
+	var keys []string
+	for _, source := range sources {
+		if source.SecretKeyRef != nil {
+			keys = append(keys, referenceKey(referenceSecret, namespace, source.SecretKeyRef.Name))
+		}
+		if source.ConfigMapKeyRef != nil {
+			keys = append(keys, referenceKey(referenceConfigMap, namespace, source.ConfigMapKeyRef.Name))
+		}
+	}
+	return keys
+}
+
+// requestsForReference returns the crs of a list whose index contains a changed secret or configmap, so that
+// rotated credentials are applied right away and only by the crs using them
+func requestsForReference(k8sClient client.Client, newList func() client.ObjectList, index string, kind string) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		list := newList()
+		err := k8sClient.List(context.Background(), list, client.MatchingFields{
+			index: referenceKey(kind, object.GetNamespace(), object.GetName()),
+		})
+		if err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		_ = meta.EachListItem(list, func(item runtime.Object) error {
+			cr, ok := item.(client.Object)
+			if ok {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: cr.GetNamespace(),
+					Name:      cr.GetName(),
+				}})
+			}
+			return nil
+		})
+		return requests
+	}
+}
+
+// getReferencedValue reads the value of a Secret or ConfigMap key in the given namespace
+func getReferencedValue(ctx context.Context, k8sClient client.Client, namespace string, source grafanav1beta1.ValueFromSource) (string, error) {
+	if source.SecretKeyRef != nil {
+		secret := &v1.Secret{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.SecretKeyRef.Name}, secret)
+		if err != nil {
+			return "", err
+		}
+		if val, ok := secret.Data[source.SecretKeyRef.Key]; ok {
+			return string(val), nil
+		}
+		return "", fmt.Errorf("secret %s/%s does not contain key %s", namespace, source.SecretKeyRef.Name, source.SecretKeyRef.Key)
+	}
+
+	if source.ConfigMapKeyRef != nil {
+		configMap := &v1.ConfigMap{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.ConfigMapKeyRef.Name}, configMap)
+		if err != nil {
+			return "", err
+		}
+		if val, ok := configMap.Data[source.ConfigMapKeyRef.Key]; ok {
+			return val, nil
+		}
+		return "", fmt.Errorf("config map %s/%s does not contain key %s", namespace, source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key)
+	}
+
+	return "", fmt.Errorf("value source must reference either a secret or a config map")
+}
+
+// applyValuesFrom resolves all values and sets them at their target paths in a json document
+func applyValuesFrom(ctx context.Context, k8sClient client.Client, namespace string, document []byte, values []grafanav1beta1.ValueFrom) ([]byte, error) {
+	if len(values) == 0 {
+		return document, nil
+	}
+
+	content := map[string]interface{}{}
+	if len(document) > 0 {
+		err := json.Unmarshal(document, &content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, value := range values {
+		resolved, err := getReferencedValue(ctx, k8sClient, namespace, value.ValueFrom)
+		if err != nil {
+			return nil, err
+		}
+
+		err = setPath(content, value.TargetPath, resolved)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(content)
+}
+
+// setPath sets a value in nested maps, creating intermediate maps as needed
+func setPath(content map[string]interface{}, path string, value interface{}) error {
+	keys := strings.Split(path, ".")
+	current := content
+	for i, key := range keys {
+		if key == "" {
+			return fmt.Errorf("invalid target path %s", path)
+		}
+
+		if i == len(keys)-1 {
+			current[key] = value
+			return nil
+		}
+
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			if current[key] != nil {
+				return fmt.Errorf("target path %s: %s is not an object", path, key)
+			}
+			next = map[string]interface{}{}
+			current[key] = next
+		}
+		current = next
+	}
+	return nil
+}
@@ -36,6 +36,11 @@ import (
 
 	grafanav1beta1 "github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/audit"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/config"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/plugincache"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/tracing"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -62,6 +67,37 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&client.CatalogCacheTTL, "plugin-catalog-cache-ttl", client.CatalogCacheTTL,
+		"How long plugin versions requested from grafana.com are cached.")
+	var pluginCacheAddr string
+	var pluginCacheDir string
+	flag.StringVar(&pluginCacheAddr, "plugin-cache-bind-address", "",
+		"The address the plugin cache binds to, the plugin cache is disabled if empty.")
+	flag.StringVar(&pluginCacheDir, "plugin-cache-dir", "/tmp/plugin-cache", "The directory plugin archives are cached in.")
+	flag.StringVar(&plugincache.URL, "plugin-cache-url", "",
+		"The url managed Grafana instances reach the plugin cache at, e.g. the url of a service of the operator.")
+	flag.DurationVar(&controllers.DefaultResyncPeriod, "resync-period", controllers.DefaultResyncPeriod,
+		"How often dashboards, datasources and folders are compared to their state in Grafana and restored.")
+	flag.BoolVar(&controllers.AllowCrossNamespaceImport, "allow-cross-namespace-import", false,
+		"Allow resources to be imported into Grafana instances of other namespaces when both sides opt in.")
+	var otlpEndpoint string
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		"The OTLP http endpoint traces of the reconciles are exported to, e.g. http://collector:4318. Tracing is disabled if empty.")
+	var migrateV1alpha1 bool
+	flag.BoolVar(&migrateV1alpha1, "migrate-v1alpha1", false,
+		"Convert the Grafana, GrafanaDashboard and GrafanaDataSource resources of grafana-operator v4 into v1beta1 resources. "+
+			"Requires the v1alpha1 crds, the v4 operator has to be stopped.")
+	var enableWebhooks bool
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", os.Getenv("ENABLE_WEBHOOKS") == "true",
+		"Serve the admission webhooks validating dashboards, rejecting dashboard uids and datasource names already "+
+			"used on an instance and defaulting dashboards, datasources and folders from the annotations of their "+
+			"namespace, requires a serving certificate.")
+	var grafanaReadinessCheck bool
+	flag.BoolVar(&grafanaReadinessCheck, "grafana-readiness-check", false,
+		"Report the operator as ready only while the health api of at least one Grafana instance is reachable.")
+	var auditLogPath string
+	flag.StringVar(&auditLogPath, "audit-log-path", "",
+		"The file changes applied to Grafana are recorded in as json lines, - for stdout. The audit log is disabled if empty.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -83,23 +119,290 @@ func main() {
 		os.Exit(1)
 	}
 
+	discoveryClient := discovery2.NewDiscoveryClientForConfigOrDie(ctrl.GetConfigOrDie())
+	isOpenShift, err := controllers.IsOpenShift(discoveryClient)
+	if err != nil {
+		setupLog.Error(err, "unable to detect the platform")
+		os.Exit(1)
+	}
+	setupLog.Info("detected platform", "openshift", isOpenShift)
+
 	if err = (&controllers.GrafanaReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Discovery: discovery2.NewDiscoveryClientForConfigOrDie(ctrl.GetConfigOrDie()),
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Recorder:    mgr.GetEventRecorderFor("grafana-operator"),
+		Discovery:   discoveryClient,
+		IsOpenShift: isOpenShift,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Grafana")
 		os.Exit(1)
 	}
 	if err = (&controllers.GrafanaDashboardReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GrafanaDashboard")
 		os.Exit(1)
 	}
+	if err = (&controllers.GrafanaFolderReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaFolder")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaContactPointReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaContactPoint")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaNotificationPolicyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaNotificationPolicy")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaMuteTimingReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaMuteTiming")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaTeamReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaTeam")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaUserReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaUser")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaOrganizationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaOrganization")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaDatasourceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaDatasource")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaServiceAccountReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaServiceAccount")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaLibraryPanelReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaLibraryPanel")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaAnnotationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaAnnotation")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaDashboardPermissionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaDashboardPermission")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaFolderPermissionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaFolderPermission")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaPublicDashboardReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaPublicDashboard")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaCorrelationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaCorrelation")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaLDAPConfigReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaLDAPConfig")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaRoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaRole")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaRoleBindingReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaRoleBinding")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaDashboardFolderReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaDashboardFolder")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaSnapshotReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaSnapshot")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaPreferencesReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaPreferences")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaDatasourcePermissionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaDatasourcePermission")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaBackupReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+		Config:   mgr.GetConfig(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaBackup")
+		os.Exit(1)
+	}
+	if err = (&controllers.GrafanaRestoreReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("grafana-operator"),
+		Config:   mgr.GetConfig(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaRestore")
+		os.Exit(1)
+	}
+	if migrateV1alpha1 {
+		if err = controllers.SetupMigrationWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Migration")
+			os.Exit(1)
+		}
+	}
+	if enableWebhooks {
+		grafanav1beta1.AllowCrossNamespaceConfigMaps = controllers.AllowCrossNamespaceImport
+		if err = (&grafanav1beta1.GrafanaDashboard{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GrafanaDashboard")
+			os.Exit(1)
+		}
+		if err = (&grafanav1beta1.GrafanaDatasource{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GrafanaDatasource")
+			os.Exit(1)
+		}
+		if err = (&grafanav1beta1.GrafanaFolder{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GrafanaFolder")
+			os.Exit(1)
+		}
+		if err = controllers.SetupUniquenessWebhooksWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Uniqueness")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	if pluginCacheAddr != "" {
+		if plugincache.URL == "" {
+			setupLog.Error(nil, "the plugin cache requires --plugin-cache-url")
+			os.Exit(1)
+		}
+		if err := mgr.Add(plugincache.NewServer(pluginCacheAddr, pluginCacheDir, config.GrafanaPluginRepository)); err != nil {
+			setupLog.Error(err, "unable to set up plugin cache")
+			os.Exit(1)
+		}
+	} else {
+		// instances only download from a cache served by the operator
+		plugincache.URL = ""
+	}
+
+	if otlpEndpoint != "" {
+		exporter := tracing.NewExporter(otlpEndpoint, "grafana-operator")
+		if err := mgr.Add(exporter); err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		tracing.SetExporter(exporter)
+	}
+
+	switch auditLogPath {
+	case "":
+	case "-":
+		audit.SetOutput(os.Stdout)
+	default:
+		auditLog, err := os.OpenFile(auditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			setupLog.Error(err, "unable to open audit log", "path", auditLogPath)
+			os.Exit(1)
+		}
+		defer auditLog.Close()
+		audit.SetOutput(auditLog)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -108,6 +411,12 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if grafanaReadinessCheck {
+		if err := mgr.AddReadyzCheck("grafana", controllers.NewGrafanaHealthCheck(mgr.GetClient())); err != nil {
+			setupLog.Error(err, "unable to set up grafana ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {